@@ -537,16 +537,25 @@ const (
 	RolloutCondition
 	// ReadyCondition indicates whether whole application processing is successful.
 	ReadyCondition
+	// DeployWindowCondition indicates whether the deploy is allowed by the deploy windows.
+	DeployWindowCondition
+	// ResourceQuotaCondition indicates whether the resources of application are within the quota.
+	ResourceQuotaCondition
+	// DependencyCondition indicates whether the depended applications are ready.
+	DependencyCondition
 )
 
 var conditions = map[ApplicationConditionType]string{
-	ParsedCondition:   "Parsed",
-	RevisionCondition: "Revision",
-	PolicyCondition:   "Policy",
-	RenderCondition:   "Render",
-	WorkflowCondition: "Workflow",
-	RolloutCondition:  "Rollout",
-	ReadyCondition:    "Ready",
+	ParsedCondition:        "Parsed",
+	RevisionCondition:      "Revision",
+	PolicyCondition:        "Policy",
+	RenderCondition:        "Render",
+	WorkflowCondition:      "Workflow",
+	RolloutCondition:       "Rollout",
+	ReadyCondition:         "Ready",
+	DeployWindowCondition:  "DeployWindow",
+	ResourceQuotaCondition: "ResourceQuota",
+	DependencyCondition:    "Dependency",
 }
 
 // String returns the string corresponding to the condition type.
@@ -0,0 +1,57 @@
+/*
+Copyright 2022 The KubeVela Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+const (
+	// DeployWindowPolicyType refers to the type of deploy-window policy
+	DeployWindowPolicyType = "deploy-window"
+	// DeployWindowKindAllow marks a window in which deploy is allowed
+	DeployWindowKindAllow DeployWindowKind = "allow"
+	// DeployWindowKindDeny marks a window in which deploy is denied
+	DeployWindowKindDeny DeployWindowKind = "deny"
+)
+
+// DeployWindowKind is a string that marks whether the window allows or denies deploy
+type DeployWindowKind string
+
+// DeployWindowPolicySpec defines the spec of deploy-window policy
+type DeployWindowPolicySpec struct {
+	Rules []DeployWindowRule `json:"rules"`
+}
+
+// DeployWindowRule defines the windows applied to a set of topologies
+type DeployWindowRule struct {
+	// Topologies is the names of the topology policies that the rule applies to.
+	// If empty, the rule applies to all deploy steps.
+	// +optional
+	Topologies []string `json:"topologies,omitempty"`
+	// Windows is the list of time windows of the rule
+	Windows []DeployWindow `json:"windows"`
+}
+
+// DeployWindow describes a recurring time window
+type DeployWindow struct {
+	// Kind decides whether deploy is allowed or denied inside the window, allow/deny
+	Kind DeployWindowKind `json:"kind"`
+	// Schedule is the cron expression of the window start, like '0 22 * * 1-5'
+	Schedule string `json:"schedule"`
+	// Duration is the length of the window, like '8h'
+	Duration string `json:"duration"`
+	// TimeZone is the IANA time zone of the schedule, like 'Asia/Shanghai'. Default to UTC.
+	// +optional
+	TimeZone string `json:"timeZone,omitempty"`
+}
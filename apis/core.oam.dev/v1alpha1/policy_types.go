@@ -29,6 +29,15 @@ const (
 	SharedResourcePolicyType = "shared-resource"
 	// ReplicationPolicyType refers to the type of replication policy
 	ReplicationPolicyType = "replication"
+	// ServerSideApplyPolicyType refers to the type of server-side-apply policy
+	ServerSideApplyPolicyType = "server-side-apply"
+)
+
+const (
+	// ServerSideApplyConflictForce overrides the fields owned by other field managers when conflicts happen
+	ServerSideApplyConflictForce ServerSideApplyConflictStrategy = "force"
+	// ServerSideApplyConflictAbort aborts the apply when conflicts happen
+	ServerSideApplyConflictAbort ServerSideApplyConflictStrategy = "abort"
 )
 
 // TopologyPolicySpec defines the spec of topology policy
@@ -84,6 +93,50 @@ func (in SharedResourcePolicySpec) FindStrategy(manifest *unstructured.Unstructu
 	return false
 }
 
+// ServerSideApplyConflictStrategy is a string that decides how to handle the conflicts in server-side apply
+type ServerSideApplyConflictStrategy string
+
+// ServerSideApplyPolicySpec defines the spec of server-side-apply policy
+type ServerSideApplyPolicySpec struct {
+	// FieldManager is the field manager to apply resources. Default to be `kubevela/<app-namespace>/<app-name>`.
+	// +optional
+	FieldManager string `json:"fieldManager,omitempty"`
+	// Conflict decides how to handle the conflicts with other field managers, force or abort. Default to be force.
+	// +optional
+	Conflict ServerSideApplyConflictStrategy `json:"conflict,omitempty"`
+	// Rules select the resources to use server-side apply. If empty, all resources will use server-side apply.
+	// +optional
+	Rules []ServerSideApplyPolicyRule `json:"rules,omitempty"`
+}
+
+// ServerSideApplyPolicyRule defines the rule for selecting resources to use server-side apply
+type ServerSideApplyPolicyRule struct {
+	Selector ResourcePolicyRuleSelector `json:"selector"`
+	// Conflict overrides the conflict strategy of the policy for the selected resources
+	// +optional
+	Conflict ServerSideApplyConflictStrategy `json:"conflict,omitempty"`
+}
+
+// FindStrategy return if the target resource should be applied by server-side apply and the conflict strategy
+func (in ServerSideApplyPolicySpec) FindStrategy(manifest *unstructured.Unstructured) (bool, ServerSideApplyConflictStrategy) {
+	conflict := in.Conflict
+	if conflict == "" {
+		conflict = ServerSideApplyConflictForce
+	}
+	if len(in.Rules) == 0 {
+		return true, conflict
+	}
+	for _, rule := range in.Rules {
+		if rule.Selector.Match(manifest) {
+			if rule.Conflict != "" {
+				return true, rule.Conflict
+			}
+			return true, conflict
+		}
+	}
+	return false, ""
+}
+
 // ReplicationPolicySpec defines the spec of replication policy
 // Override policy should be used together with replication policy to select the deployment target components
 type ReplicationPolicySpec struct {
@@ -67,3 +67,50 @@ func TestSharedResourcePolicySpec_FindStrategy(t *testing.T) {
 		})
 	}
 }
+
+func TestServerSideApplyPolicySpec_FindStrategy(t *testing.T) {
+	deploy := &unstructured.Unstructured{Object: map[string]interface{}{
+		"kind":     "Deployment",
+		"metadata": map[string]interface{}{"name": "example"},
+	}}
+	testCases := map[string]struct {
+		spec     ServerSideApplyPolicySpec
+		matched  bool
+		conflict ServerSideApplyConflictStrategy
+	}{
+		"no rules select all resources with default conflict strategy": {
+			spec:     ServerSideApplyPolicySpec{},
+			matched:  true,
+			conflict: ServerSideApplyConflictForce,
+		},
+		"policy conflict strategy": {
+			spec: ServerSideApplyPolicySpec{Conflict: ServerSideApplyConflictAbort, Rules: []ServerSideApplyPolicyRule{{
+				Selector: ResourcePolicyRuleSelector{ResourceTypes: []string{"Deployment"}},
+			}}},
+			matched:  true,
+			conflict: ServerSideApplyConflictAbort,
+		},
+		"rule conflict strategy overrides policy": {
+			spec: ServerSideApplyPolicySpec{Conflict: ServerSideApplyConflictAbort, Rules: []ServerSideApplyPolicyRule{{
+				Selector: ResourcePolicyRuleSelector{ResourceNames: []string{"example"}},
+				Conflict: ServerSideApplyConflictForce,
+			}}},
+			matched:  true,
+			conflict: ServerSideApplyConflictForce,
+		},
+		"rule mismatch": {
+			spec: ServerSideApplyPolicySpec{Rules: []ServerSideApplyPolicyRule{{
+				Selector: ResourcePolicyRuleSelector{ResourceTypes: []string{"ConfigMap"}},
+			}}},
+			matched: false,
+		},
+	}
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			r := require.New(t)
+			matched, conflict := tc.spec.FindStrategy(deploy)
+			r.Equal(tc.matched, matched)
+			r.Equal(tc.conflict, conflict)
+		})
+	}
+}
@@ -54,7 +54,14 @@ var (
 	WorkflowGroupVersionKind = SchemeGroupVersion.WithKind(WorkflowKind)
 )
 
+// ResourceTopologyRule meta
+var (
+	ResourceTopologyRuleKind             = "ResourceTopologyRule"
+	ResourceTopologyRuleGroupVersionKind = SchemeGroupVersion.WithKind(ResourceTopologyRuleKind)
+)
+
 func init() {
 	SchemeBuilder.Register(&Policy{}, &PolicyList{})
+	SchemeBuilder.Register(&ResourceTopologyRule{}, &ResourceTopologyRuleList{})
 	SchemeBuilder.Register(&workflowv1alpha1.Workflow{}, &workflowv1alpha1.WorkflowList{})
 }
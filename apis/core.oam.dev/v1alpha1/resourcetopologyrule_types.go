@@ -0,0 +1,118 @@
+/*
+ Copyright 2022. The KubeVela Authors.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// TopologyRuleMode decides how the rule works with the other rules of the same parent resource type
+type TopologyRuleMode string
+
+const (
+	// TopologyRuleModeMerge adds the children resource types to the rules with the lower priority and the built-in rule
+	TopologyRuleModeMerge TopologyRuleMode = "Merge"
+	// TopologyRuleModeOverride ignores the rules with the lower priority and the built-in rule
+	TopologyRuleModeOverride TopologyRuleMode = "Override"
+)
+
+// TopologyChildMatcher decides how the children resources are matched with the parent resource
+type TopologyChildMatcher string
+
+const (
+	// TopologyChildMatcherOwnerReference matches the children resources whose ownerReferences contain the parent resource
+	TopologyChildMatcherOwnerReference TopologyChildMatcher = "OwnerReference"
+	// TopologyChildMatcherLabel matches the children resources by the labels in the namespace of the parent resource
+	TopologyChildMatcherLabel TopologyChildMatcher = "Label"
+)
+
+// TopologyParentResourceType is the type of the parent resource
+type TopologyParentResourceType struct {
+	// Group is the API group of the parent resource, empty for the core group
+	// +optional
+	Group string `json:"group,omitempty"`
+	// Kind is the kind of the parent resource
+	// +kubebuilder:validation:MinLength=1
+	Kind string `json:"kind"`
+}
+
+// TopologyChildResourceType is the type of the children resources and how they are matched
+type TopologyChildResourceType struct {
+	// APIVersion is the api version of the children resources
+	// +kubebuilder:validation:MinLength=1
+	APIVersion string `json:"apiVersion"`
+	// Kind is the kind of the children resources
+	// +kubebuilder:validation:MinLength=1
+	Kind string `json:"kind"`
+	// Matcher is how the children resources are matched with the parent resource, OwnerReference by default
+	// +kubebuilder:validation:Enum=OwnerReference;Label
+	// +optional
+	Matcher TopologyChildMatcher `json:"matcher,omitempty"`
+	// SelectorPath is the path of the label selector in the parent resource, like `spec.selector`. Both the
+	// label selector and the plain labels are supported. It's used by the Label matcher.
+	// +optional
+	SelectorPath string `json:"selectorPath,omitempty"`
+	// MatchLabels is the labels of the children resources, the values could refer the fields of the parent
+	// resource in the go template like `{{ .metadata.name }}`. It's used by the Label matcher.
+	// +optional
+	MatchLabels map[string]string `json:"matchLabels,omitempty"`
+}
+
+// ResourceTopologyRuleSpec defines the spec of ResourceTopologyRule
+type ResourceTopologyRuleSpec struct {
+	// ParentResourceType is the type of the resource to discover the children resources for
+	ParentResourceType TopologyParentResourceType `json:"parentResourceType"`
+	// ChildrenResourceTypes is the types of the children resources
+	// +kubebuilder:validation:MinItems=1
+	ChildrenResourceTypes []TopologyChildResourceType `json:"childrenResourceTypes"`
+	// Priority decides the precedence of the rules of the same parent resource type, the rule with the
+	// higher priority takes precedence.
+	// +optional
+	Priority int32 `json:"priority,omitempty"`
+	// Mode decides whether to merge the children resource types with the rules of the lower priority and the
+	// built-in rule, or to override them. Merge by default.
+	// +kubebuilder:validation:Enum=Merge;Override
+	// +optional
+	Mode TopologyRuleMode `json:"mode,omitempty"`
+}
+
+// ResourceTopologyRule defines how to discover the children resources of a type of resources in the
+// resource topology of the applications.
+// +kubebuilder:object:root=true
+// +kubebuilder:resource:scope=Cluster,categories={oam},shortName=rtr
+// +kubebuilder:printcolumn:name="GROUP",type=string,JSONPath=`.spec.parentResourceType.group`
+// +kubebuilder:printcolumn:name="KIND",type=string,JSONPath=`.spec.parentResourceType.kind`
+// +kubebuilder:printcolumn:name="PRIORITY",type=integer,JSONPath=`.spec.priority`
+// +kubebuilder:printcolumn:name="MODE",type=string,JSONPath=`.spec.mode`
+// +kubebuilder:printcolumn:name="AGE",type=date,JSONPath=".metadata.creationTimestamp"
+// +genclient
+// +genclient:nonNamespaced
+type ResourceTopologyRule struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec ResourceTopologyRuleSpec `json:"spec"`
+}
+
+// +kubebuilder:object:root=true
+
+// ResourceTopologyRuleList contains a list of ResourceTopologyRule
+type ResourceTopologyRuleList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []ResourceTopologyRule `json:"items"`
+}
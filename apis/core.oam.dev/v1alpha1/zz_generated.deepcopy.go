@@ -105,6 +105,68 @@ func (in *ClusterConnection) DeepCopy() *ClusterConnection {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DeployWindow) DeepCopyInto(out *DeployWindow) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DeployWindow.
+func (in *DeployWindow) DeepCopy() *DeployWindow {
+	if in == nil {
+		return nil
+	}
+	out := new(DeployWindow)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DeployWindowPolicySpec) DeepCopyInto(out *DeployWindowPolicySpec) {
+	*out = *in
+	if in.Rules != nil {
+		in, out := &in.Rules, &out.Rules
+		*out = make([]DeployWindowRule, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DeployWindowPolicySpec.
+func (in *DeployWindowPolicySpec) DeepCopy() *DeployWindowPolicySpec {
+	if in == nil {
+		return nil
+	}
+	out := new(DeployWindowPolicySpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DeployWindowRule) DeepCopyInto(out *DeployWindowRule) {
+	*out = *in
+	if in.Topologies != nil {
+		in, out := &in.Topologies, &out.Topologies
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Windows != nil {
+		in, out := &in.Windows, &out.Windows
+		*out = make([]DeployWindow, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DeployWindowRule.
+func (in *DeployWindowRule) DeepCopy() *DeployWindowRule {
+	if in == nil {
+		return nil
+	}
+	out := new(DeployWindowRule)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *EnvBindingSpec) DeepCopyInto(out *EnvBindingSpec) {
 	*out = *in
@@ -682,6 +744,125 @@ func (in *ResourcePolicyRuleSelector) DeepCopy() *ResourcePolicyRuleSelector {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ResourceTopologyRule) DeepCopyInto(out *ResourceTopologyRule) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ResourceTopologyRule.
+func (in *ResourceTopologyRule) DeepCopy() *ResourceTopologyRule {
+	if in == nil {
+		return nil
+	}
+	out := new(ResourceTopologyRule)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ResourceTopologyRule) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ResourceTopologyRuleList) DeepCopyInto(out *ResourceTopologyRuleList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]ResourceTopologyRule, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ResourceTopologyRuleList.
+func (in *ResourceTopologyRuleList) DeepCopy() *ResourceTopologyRuleList {
+	if in == nil {
+		return nil
+	}
+	out := new(ResourceTopologyRuleList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ResourceTopologyRuleList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ResourceTopologyRuleSpec) DeepCopyInto(out *ResourceTopologyRuleSpec) {
+	*out = *in
+	out.ParentResourceType = in.ParentResourceType
+	if in.ChildrenResourceTypes != nil {
+		in, out := &in.ChildrenResourceTypes, &out.ChildrenResourceTypes
+		*out = make([]TopologyChildResourceType, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ResourceTopologyRuleSpec.
+func (in *ResourceTopologyRuleSpec) DeepCopy() *ResourceTopologyRuleSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ResourceTopologyRuleSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ServerSideApplyPolicyRule) DeepCopyInto(out *ServerSideApplyPolicyRule) {
+	*out = *in
+	in.Selector.DeepCopyInto(&out.Selector)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ServerSideApplyPolicyRule.
+func (in *ServerSideApplyPolicyRule) DeepCopy() *ServerSideApplyPolicyRule {
+	if in == nil {
+		return nil
+	}
+	out := new(ServerSideApplyPolicyRule)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ServerSideApplyPolicySpec) DeepCopyInto(out *ServerSideApplyPolicySpec) {
+	*out = *in
+	if in.Rules != nil {
+		in, out := &in.Rules, &out.Rules
+		*out = make([]ServerSideApplyPolicyRule, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ServerSideApplyPolicySpec.
+func (in *ServerSideApplyPolicySpec) DeepCopy() *ServerSideApplyPolicySpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ServerSideApplyPolicySpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *SharedResourcePolicyRule) DeepCopyInto(out *SharedResourcePolicyRule) {
 	*out = *in
@@ -720,6 +901,43 @@ func (in *SharedResourcePolicySpec) DeepCopy() *SharedResourcePolicySpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TopologyChildResourceType) DeepCopyInto(out *TopologyChildResourceType) {
+	*out = *in
+	if in.MatchLabels != nil {
+		in, out := &in.MatchLabels, &out.MatchLabels
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TopologyChildResourceType.
+func (in *TopologyChildResourceType) DeepCopy() *TopologyChildResourceType {
+	if in == nil {
+		return nil
+	}
+	out := new(TopologyChildResourceType)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TopologyParentResourceType) DeepCopyInto(out *TopologyParentResourceType) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TopologyParentResourceType.
+func (in *TopologyParentResourceType) DeepCopy() *TopologyParentResourceType {
+	if in == nil {
+		return nil
+	}
+	out := new(TopologyParentResourceType)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *TopologyPolicySpec) DeepCopyInto(out *TopologyPolicySpec) {
 	*out = *in
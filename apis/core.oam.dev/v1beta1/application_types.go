@@ -74,9 +74,37 @@ type ApplicationSpec struct {
 	// - should mark "finish" phase in status.conditions.
 	Workflow *Workflow `json:"workflow,omitempty"`
 
+	// DependsOn defines the applications that this application depends on. The workflow of the application will
+	// not start until all the depended applications are ready.
+	DependsOn []AppDependency `json:"dependsOn,omitempty"`
+
 	// TODO(wonderflow): we should have application level scopes supported here
 }
 
+// AppDependencyCondition is the readiness condition of the depended application
+type AppDependencyCondition string
+
+const (
+	// AppDependencyConditionHealthy requires the workflow of the depended application succeeded and all its
+	// components healthy, which means the application is running
+	AppDependencyConditionHealthy AppDependencyCondition = "healthy"
+	// AppDependencyConditionWorkflowSucceeded requires the workflow of the depended application succeeded
+	AppDependencyConditionWorkflowSucceeded AppDependencyCondition = "workflowSucceeded"
+)
+
+// AppDependency defines the dependency on another application
+type AppDependency struct {
+	// Name is the name of the depended application
+	Name string `json:"name"`
+	// Namespace is the namespace of the depended application. Default to be the namespace of this application.
+	// +optional
+	Namespace string `json:"namespace,omitempty"`
+	// Condition is the readiness condition of the depended application, healthy or workflowSucceeded.
+	// Default to be healthy.
+	// +optional
+	Condition AppDependencyCondition `json:"condition,omitempty"`
+}
+
 // +kubebuilder:object:root=true
 
 // Application is the Schema for the applications API
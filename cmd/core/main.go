@@ -34,6 +34,7 @@ import (
 	"k8s.io/klog/v2"
 	"k8s.io/klog/v2/klogr"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
 	"sigs.k8s.io/controller-runtime/pkg/healthz"
 
 	velaclient "github.com/kubevela/pkg/controller/client"
@@ -48,6 +49,7 @@ import (
 	commonconfig "github.com/oam-dev/kubevela/pkg/controller/common"
 	oamcontroller "github.com/oam-dev/kubevela/pkg/controller/core.oam.dev"
 	oamv1alpha2 "github.com/oam-dev/kubevela/pkg/controller/core.oam.dev/v1alpha2"
+	"github.com/oam-dev/kubevela/pkg/controller/sharding"
 	"github.com/oam-dev/kubevela/pkg/controller/utils"
 	"github.com/oam-dev/kubevela/pkg/features"
 	_ "github.com/oam-dev/kubevela/pkg/monitor/metrics"
@@ -144,6 +146,7 @@ func main() {
 	flag.BoolVar(&controllerArgs.IgnoreDefinitionWithoutControllerRequirement, "ignore-definition-without-controller-version", false, "If true, trait/component/workflowstep definition controller will not process the definition without 'definition.oam.dev/controller-version-require' annotation")
 	standardcontroller.AddOptimizeFlags()
 	standardcontroller.AddAdmissionFlags()
+	standardcontroller.AddShardingFlags()
 	flag.IntVar(&resourcekeeper.MaxDispatchConcurrent, "max-dispatch-concurrent", 10, "Set the max dispatch concurrent number, default is 10")
 	flag.IntVar(&wfTypes.MaxWorkflowWaitBackoffTime, "max-workflow-wait-backoff-time", 60, "Set the max workflow wait backoff time, default is 60")
 	flag.IntVar(&wfTypes.MaxWorkflowFailedBackoffTime, "max-workflow-failed-backoff-time", 300, "Set the max workflow wait backoff time, default is 300")
@@ -210,6 +213,17 @@ func main() {
 	}
 
 	leaderElectionID := util.GenerateLeaderElectionID(types.KubeVelaName, controllerArgs.IgnoreAppWithoutControllerRequirement)
+	var newCache cache.NewCacheFunc
+	if sharding.EnableSharding {
+		if err := sharding.InitShardID(); err != nil {
+			klog.ErrorS(err, "Unable to initialize the controller shard")
+			os.Exit(1)
+		}
+		klog.InfoS("Enable sharding", "shard-id", sharding.GetShardID())
+		// all shards share one leader election for the non-sharded controllers, each shard only caches the
+		// applications scheduled to it
+		newCache = sharding.BuildCache(sharding.GetShardID())
+	}
 	mgr, err := ctrl.NewManager(restConfig, ctrl.Options{
 		Scheme:                     scheme,
 		MetricsBindAddress:         metricsAddr,
@@ -230,6 +244,7 @@ func main() {
 		// controller but also all other controllers like definition controller. Therefore, for
 		// functionalities like state-keep, they should be invented in other ways.
 		NewClient: velaclient.DefaultNewControllerClient,
+		NewCache:  newCache,
 	})
 	if err != nil {
 		klog.ErrorS(err, "Unable to create a controller manager")
@@ -261,6 +276,19 @@ func main() {
 	}
 	controllerArgs.PackageDiscover = pd
 
+	if sharding.EnableSharding {
+		if sharding.DefaultApplicationMetadataCache, err = sharding.NewApplicationMetadataCache(mgr); err != nil {
+			klog.ErrorS(err, "Unable to create the application cache of controller shards")
+			os.Exit(1)
+		}
+		sharding.DefaultCoordinator = sharding.NewCoordinator(mgr.GetClient(), mgr.GetAPIReader(), sharding.GetShardID()).
+			WithApplicationCache(sharding.DefaultApplicationMetadataCache)
+		if err := mgr.Add(sharding.DefaultCoordinator); err != nil {
+			klog.ErrorS(err, "Unable to add the coordinator of controller shards")
+			os.Exit(1)
+		}
+	}
+
 	if useWebhook {
 		klog.InfoS("Enable webhook", "server port", strconv.Itoa(webhookPort))
 		oamwebhook.Register(mgr, controllerArgs)
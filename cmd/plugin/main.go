@@ -30,6 +30,6 @@ func main() {
 	command := cli.NewCommand()
 
 	if err := command.Execute(); err != nil {
-		os.Exit(1)
+		os.Exit(cli.GetExitCode(err))
 	}
 }
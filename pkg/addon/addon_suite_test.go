@@ -45,7 +45,6 @@ import (
 	"github.com/oam-dev/kubevela/pkg/oam/util"
 	addonutil "github.com/oam-dev/kubevela/pkg/utils/addon"
 	"github.com/oam-dev/kubevela/pkg/utils/apply"
-	"github.com/oam-dev/kubevela/references/cli/top/model"
 )
 
 var _ = Describe("Addon test", func() {
@@ -433,7 +432,7 @@ var _ = Describe("test dry-run addon from local dir", func() {
 				}
 				Expect(err).Should(BeNil())
 			}
-			Expect(obj.GetNamespace()).Should(BeEquivalentTo(model.VelaSystemNS))
+			Expect(obj.GetNamespace()).Should(BeEquivalentTo(types.DefaultKubeVelaNS))
 			Expect(k8sClient.Create(ctx, obj)).Should(BeNil())
 		}
 
@@ -19,6 +19,8 @@ package service
 import (
 	"context"
 	"encoding/base64"
+	"encoding/json"
+	"errors"
 	"strings"
 
 	"k8s.io/client-go/rest"
@@ -34,9 +36,12 @@ import (
 	"github.com/oam-dev/kubevela/pkg/velaql"
 )
 
+// velaQLTotalKey is the key of the total numbers of the items of the lists in the paginated response
+const velaQLTotalKey = "total"
+
 // VelaQLService velaQL service
 type VelaQLService interface {
-	QueryView(context.Context, string) (*apis.VelaQLViewResponse, error)
+	QueryView(ctx context.Context, velaQL string, page, pageSize int, filter string) (*apis.VelaQLViewResponse, error)
 }
 
 type velaQLServiceImpl struct {
@@ -44,6 +49,7 @@ type velaQLServiceImpl struct {
 	KubeConfig *rest.Config  `inject:"kubeConfig"`
 	dm         discoverymapper.DiscoveryMapper
 	pd         *packages.PackageDiscover
+	cache      *velaql.ViewCache
 }
 
 // NewVelaQLService new velaQL service
@@ -58,20 +64,25 @@ func NewVelaQLService() VelaQLService {
 		log.Logger.Fatalf("get package discover failure %s", err.Error())
 	}
 	return &velaQLServiceImpl{
-		dm: dm,
-		pd: pd,
+		dm:    dm,
+		pd:    pd,
+		cache: velaql.NewViewCache(velaql.DefaultViewCacheTTL),
 	}
 }
 
-// QueryView get the view query results
-func (v *velaQLServiceImpl) QueryView(ctx context.Context, velaQL string) (*apis.VelaQLViewResponse, error) {
+// QueryView get the view query results, the lists in the results are filtered by the keyword and paginated if the page is specified
+func (v *velaQLServiceImpl) QueryView(ctx context.Context, velaQL string, page, pageSize int, filter string) (*apis.VelaQLViewResponse, error) {
 	query, err := velaql.ParseVelaQL(velaQL)
 	if err != nil {
 		return nil, bcode.ErrParseVelaQL
 	}
 
-	queryValue, err := velaql.NewViewHandler(v.KubeClient, v.KubeConfig, v.dm, v.pd).QueryView(ctx, query)
+	queryValue, err := velaql.NewViewHandler(v.KubeClient, v.KubeConfig, v.dm, v.pd).WithCache(v.cache).QueryView(ctx, query)
 	if err != nil {
+		var paramErr *velaql.ParameterError
+		if errors.As(err, &paramErr) {
+			return nil, bcode.ErrInvalidViewParameter.SetMessage(paramErr.Error())
+		}
 		log.Logger.Errorf("fail to query the view %s", err.Error())
 		return nil, bcode.ErrViewQuery
 	}
@@ -91,5 +102,46 @@ func (v *velaQLServiceImpl) QueryView(ctx context.Context, velaQL string) (*apis
 			resp["logs"] = ""
 		}
 	}
+	if page > 0 || filter != "" {
+		paginateViewResponse(resp, page, pageSize, filter)
+	}
 	return &resp, err
 }
+
+// paginateViewResponse filters the items of the lists at the top level of the response by the keyword, and
+// keeps the items of the page if the page is specified. The total numbers of the filtered items are recorded
+// by the names of the lists in the `total` field.
+func paginateViewResponse(resp apis.VelaQLViewResponse, page, pageSize int, filter string) {
+	total := map[string]int{}
+	keyword := strings.ToLower(filter)
+	for key, field := range resp {
+		items, ok := field.([]interface{})
+		if !ok {
+			continue
+		}
+		filtered := make([]interface{}, 0, len(items))
+		for _, item := range items {
+			if keyword != "" {
+				data, err := json.Marshal(item)
+				if err != nil || !strings.Contains(strings.ToLower(string(data)), keyword) {
+					continue
+				}
+			}
+			filtered = append(filtered, item)
+		}
+		total[key] = len(filtered)
+		if page > 0 && pageSize > 0 {
+			start := (page - 1) * pageSize
+			if start > len(filtered) {
+				start = len(filtered)
+			}
+			end := start + pageSize
+			if end > len(filtered) {
+				end = len(filtered)
+			}
+			filtered = filtered[start:end]
+		}
+		resp[key] = filtered
+	}
+	resp[velaQLTotalKey] = total
+}
@@ -0,0 +1,54 @@
+/*
+Copyright 2022 The KubeVela Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package service
+
+import (
+	"testing"
+
+	"gotest.tools/assert"
+
+	apis "github.com/oam-dev/kubevela/pkg/apiserver/interfaces/api/dto/v1"
+)
+
+func TestPaginateViewResponse(t *testing.T) {
+	newResponse := func() apis.VelaQLViewResponse {
+		return apis.VelaQLViewResponse{
+			"podList": []interface{}{
+				map[string]interface{}{"name": "web-1", "phase": "Running"},
+				map[string]interface{}{"name": "web-2", "phase": "Pending"},
+				map[string]interface{}{"name": "worker-1", "phase": "Running"},
+			},
+			"message": "ok",
+		}
+	}
+
+	resp := newResponse()
+	paginateViewResponse(resp, 2, 2, "")
+	assert.DeepEqual(t, resp["podList"], []interface{}{map[string]interface{}{"name": "worker-1", "phase": "Running"}})
+	assert.DeepEqual(t, resp["total"], map[string]int{"podList": 3})
+	assert.Equal(t, resp["message"], "ok")
+
+	resp = newResponse()
+	paginateViewResponse(resp, 0, 10, "RUNNING")
+	assert.Equal(t, len(resp["podList"].([]interface{})), 2)
+	assert.DeepEqual(t, resp["total"], map[string]int{"podList": 2})
+
+	resp = newResponse()
+	paginateViewResponse(resp, 3, 5, "web")
+	assert.DeepEqual(t, resp["podList"], []interface{}{})
+	assert.DeepEqual(t, resp["total"], map[string]int{"podList": 2})
+}
@@ -22,6 +22,7 @@ import (
 
 	"github.com/oam-dev/kubevela/pkg/apiserver/domain/service"
 	apis "github.com/oam-dev/kubevela/pkg/apiserver/interfaces/api/dto/v1"
+	"github.com/oam-dev/kubevela/pkg/apiserver/utils"
 	"github.com/oam-dev/kubevela/pkg/apiserver/utils/bcode"
 )
 
@@ -50,6 +51,9 @@ func (v *velaQLAPIInterface) GetWebServiceRoute() *restful.WebService {
 		// TODO: VelaQL is an open data query API that is currently not compatible with RBAC.
 		// Filter(v.RbacService.CheckPerm("application", "detail")).
 		Param(ws.QueryParameter("velaql", "velaql query statement").DataType("string")).
+		Param(ws.QueryParameter("page", "query the page number of the lists in the result, all items are returned if it's 0").DataType("integer")).
+		Param(ws.QueryParameter("pageSize", "query the page size number").DataType("integer")).
+		Param(ws.QueryParameter("filter", "filter the items of the lists in the result by the keyword").DataType("string")).
 		Returns(200, "OK", apis.VelaQLViewResponse{}).
 		Returns(400, "Bad Request", bcode.Bcode{}).
 		Writes(apis.VelaQLViewResponse{}))
@@ -60,8 +64,13 @@ func (v *velaQLAPIInterface) GetWebServiceRoute() *restful.WebService {
 
 func (v *velaQLAPIInterface) queryView(req *restful.Request, res *restful.Response) {
 	velaQL := req.QueryParameter("velaql")
+	page, pageSize, err := utils.ExtractPagingParams(req, minPageSize, maxPageSize)
+	if err != nil {
+		bcode.ReturnError(req, res, err)
+		return
+	}
 
-	qlResp, err := v.VelaQLService.QueryView(req.Request.Context(), velaQL)
+	qlResp, err := v.VelaQLService.QueryView(req.Request.Context(), velaQL, page, pageSize, req.QueryParameter("filter"))
 	if err != nil {
 		bcode.ReturnError(req, res, err)
 		return
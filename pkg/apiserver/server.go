@@ -45,6 +45,7 @@ import (
 	pkgconfig "github.com/oam-dev/kubevela/pkg/config"
 	pkgUtils "github.com/oam-dev/kubevela/pkg/utils"
 	"github.com/oam-dev/kubevela/pkg/utils/apply"
+	"github.com/oam-dev/kubevela/pkg/velaql/providers/query"
 )
 
 // APIServer interface for call api server
@@ -157,6 +158,14 @@ func (s *restServer) Run(ctx context.Context, errChan chan error) error {
 
 	s.RegisterAPIRoute()
 
+	// watch the resource topology rules, so the changes take effect in the resource tree of the applications immediately,
+	// the watch is retried with the backoff until the CRD is installed
+	kubeConfig, err := clients.GetKubeConfig()
+	if err != nil {
+		return err
+	}
+	go query.WatchResourceTopologyRules(ctx, kubeConfig)
+
 	l, err := s.setupLeaderElection(errChan)
 	if err != nil {
 		return err
@@ -24,3 +24,6 @@ var ErrViewQuery = NewBcode(400, 60002, "view query failed")
 
 // ErrParseQuery2Json failed to parse query result to response
 var ErrParseQuery2Json = NewBcode(400, 60003, "fail to parse query result to json format")
+
+// ErrInvalidViewParameter the parameter of the query does not match the parameter schema of the view
+var ErrInvalidViewParameter = NewBcode(400, 60004, "the parameter of the view is invalid")
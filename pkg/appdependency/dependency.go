@@ -0,0 +1,175 @@
+/*
+Copyright 2022 The KubeVela Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package appdependency
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	workflowv1alpha1 "github.com/kubevela/workflow/api/v1alpha1"
+	"github.com/pkg/errors"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/oam-dev/kubevela/apis/core.oam.dev/common"
+	"github.com/oam-dev/kubevela/apis/core.oam.dev/v1beta1"
+)
+
+// Key return the key of the depended application, the namespace of the dependency is default to be the namespace
+// of the application
+func Key(app *v1beta1.Application, dep v1beta1.AppDependency) client.ObjectKey {
+	namespace := dep.Namespace
+	if namespace == "" {
+		namespace = app.Namespace
+	}
+	return client.ObjectKey{Namespace: namespace, Name: dep.Name}
+}
+
+// Keys return the keys of all the applications that the application depends on
+func Keys(app *v1beta1.Application) []string {
+	var keys []string
+	for _, dep := range app.Spec.DependsOn {
+		keys = append(keys, Key(app, dep).String())
+	}
+	return keys
+}
+
+// IsReady check if the depended application satisfies the condition. If not, the reason will be returned.
+func IsReady(app *v1beta1.Application, cond v1beta1.AppDependencyCondition) (bool, string) {
+	if app.Status.ObservedGeneration != app.Generation {
+		return false, "the latest spec is not observed"
+	}
+	workflowSucceeded := app.Status.Workflow != nil && app.Status.Workflow.Finished &&
+		app.Status.Workflow.Phase == workflowv1alpha1.WorkflowStateSucceeded
+	switch cond {
+	case v1beta1.AppDependencyConditionWorkflowSucceeded:
+		if !workflowSucceeded && app.Status.Phase != common.ApplicationRunning {
+			return false, fmt.Sprintf("workflow is not succeeded (phase: %s)", app.Status.Phase)
+		}
+	case v1beta1.AppDependencyConditionHealthy, "":
+		if app.Status.Phase != common.ApplicationRunning {
+			return false, fmt.Sprintf("application is not healthy (phase: %s)", app.Status.Phase)
+		}
+	default:
+		return false, fmt.Sprintf("unknown dependency condition %q", cond)
+	}
+	return true, ""
+}
+
+// Check return the messages of the dependencies that are not ready. Empty result means all dependencies are ready.
+func Check(ctx context.Context, cli client.Reader, app *v1beta1.Application) ([]string, error) {
+	var blocking []string
+	for _, dep := range app.Spec.DependsOn {
+		key := Key(app, dep)
+		depApp := &v1beta1.Application{}
+		if err := cli.Get(ctx, key, depApp); err != nil {
+			if kerrors.IsNotFound(err) {
+				blocking = append(blocking, fmt.Sprintf("%s: not found", key))
+				continue
+			}
+			return nil, errors.Wrapf(err, "failed to get depended application %s", key)
+		}
+		if depApp.DeletionTimestamp != nil {
+			blocking = append(blocking, fmt.Sprintf("%s: deleting", key))
+			continue
+		}
+		if ready, reason := IsReady(depApp, dep.Condition); !ready {
+			blocking = append(blocking, fmt.Sprintf("%s: %s", key, reason))
+		}
+	}
+	return blocking, nil
+}
+
+// IndexKey is the field index of the applications by the keys of their depended applications
+const IndexKey = "spec.dependsOn"
+
+// IndexFunc index the application by the keys of its depended applications
+func IndexFunc(obj client.Object) []string {
+	if app, ok := obj.(*v1beta1.Application); ok {
+		return Keys(app)
+	}
+	return nil
+}
+
+// FindDependents return the applications that depend on the given application. The reader must have the
+// applications indexed by IndexKey, like the cache of the controller manager.
+func FindDependents(ctx context.Context, cli client.Reader, app *v1beta1.Application) ([]v1beta1.Application, error) {
+	apps := &v1beta1.ApplicationList{}
+	if err := cli.List(ctx, apps, client.MatchingFields{IndexKey: client.ObjectKeyFromObject(app).String()}); err != nil {
+		return nil, err
+	}
+	return apps.Items, nil
+}
+
+// ListDependents return the applications that depend on the given application by listing all the applications. It
+// is used with the reader without the index, like the uncached reader.
+func ListDependents(ctx context.Context, cli client.Reader, app *v1beta1.Application) ([]v1beta1.Application, error) {
+	apps := &v1beta1.ApplicationList{}
+	if err := cli.List(ctx, apps); err != nil {
+		return nil, err
+	}
+	key := client.ObjectKeyFromObject(app).String()
+	var dependents []v1beta1.Application
+	for _, item := range apps.Items {
+		for _, depKey := range Keys(item.DeepCopy()) {
+			if depKey == key {
+				dependents = append(dependents, item)
+				break
+			}
+		}
+	}
+	return dependents, nil
+}
+
+// DetectCycle walks through the dependencies of the application and return the cycle path if the application
+// depends on itself directly or indirectly. The given application is used instead of the stored one, so that the
+// cycle can be detected before the application is created or updated.
+func DetectCycle(ctx context.Context, cli client.Reader, app *v1beta1.Application) ([]string, error) {
+	root := client.ObjectKeyFromObject(app).String()
+	visited := map[string]bool{}
+	var walk func(current *v1beta1.Application, path []string) ([]string, error)
+	walk = func(current *v1beta1.Application, path []string) ([]string, error) {
+		for _, dep := range current.Spec.DependsOn {
+			key := Key(current, dep)
+			if key.String() == root {
+				return append(path, root), nil
+			}
+			if visited[key.String()] {
+				continue
+			}
+			visited[key.String()] = true
+			depApp := &v1beta1.Application{}
+			if err := cli.Get(ctx, key, depApp); err != nil {
+				if kerrors.IsNotFound(err) {
+					continue
+				}
+				return nil, errors.Wrapf(err, "failed to get depended application %s", key)
+			}
+			if cycle, err := walk(depApp, append(path, key.String())); err != nil || cycle != nil {
+				return cycle, err
+			}
+		}
+		return nil, nil
+	}
+	return walk(app, []string{root})
+}
+
+// FormatCycle format the cycle path for display
+func FormatCycle(cycle []string) string {
+	return strings.Join(cycle, " -> ")
+}
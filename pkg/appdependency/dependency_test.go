@@ -0,0 +1,175 @@
+/*
+Copyright 2022 The KubeVela Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package appdependency
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	workflowv1alpha1 "github.com/kubevela/workflow/api/v1alpha1"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/oam-dev/kubevela/apis/core.oam.dev/common"
+	"github.com/oam-dev/kubevela/apis/core.oam.dev/v1beta1"
+	velacommon "github.com/oam-dev/kubevela/pkg/utils/common"
+)
+
+func newApp(namespace, name string, phase common.ApplicationPhase, deps ...v1beta1.AppDependency) *v1beta1.Application {
+	app := &v1beta1.Application{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace, Generation: 1},
+		Spec:       v1beta1.ApplicationSpec{DependsOn: deps},
+	}
+	app.Status.Phase = phase
+	app.Status.ObservedGeneration = 1
+	return app
+}
+
+func TestIsReady(t *testing.T) {
+	succeeded := newApp("default", "app", common.ApplicationUnhealthy)
+	succeeded.Status.Workflow = &common.WorkflowStatus{Finished: true, Phase: workflowv1alpha1.WorkflowStateSucceeded}
+	outdated := newApp("default", "app", common.ApplicationRunning)
+	outdated.Generation = 2
+	testCases := map[string]struct {
+		app   *v1beta1.Application
+		cond  v1beta1.AppDependencyCondition
+		ready bool
+	}{
+		"running-healthy":                {app: newApp("default", "app", common.ApplicationRunning), ready: true},
+		"unhealthy":                      {app: newApp("default", "app", common.ApplicationUnhealthy), cond: v1beta1.AppDependencyConditionHealthy},
+		"workflow-succeeded":             {app: succeeded, cond: v1beta1.AppDependencyConditionWorkflowSucceeded, ready: true},
+		"workflow-succeeded-not-healthy": {app: succeeded, cond: v1beta1.AppDependencyConditionHealthy},
+		"running-workflow-succeeded":     {app: newApp("default", "app", common.ApplicationRunning), cond: v1beta1.AppDependencyConditionWorkflowSucceeded, ready: true},
+		"generation-not-observed":        {app: outdated},
+		"unknown-condition":              {app: newApp("default", "app", common.ApplicationRunning), cond: "unknown"},
+	}
+	for name, tt := range testCases {
+		t.Run(name, func(t *testing.T) {
+			ready, reason := IsReady(tt.app, tt.cond)
+			require.Equal(t, tt.ready, ready)
+			require.Equal(t, tt.ready, reason == "")
+		})
+	}
+}
+
+func TestCheck(t *testing.T) {
+	r := require.New(t)
+	ctx := context.Background()
+	cli := fake.NewClientBuilder().WithScheme(velacommon.Scheme).WithObjects(
+		newApp("default", "db", common.ApplicationRunning),
+		newApp("infra", "network", common.ApplicationRunningWorkflow),
+	).Build()
+
+	app := newApp("default", "web", common.ApplicationStarting, v1beta1.AppDependency{Name: "db"})
+	blocking, err := Check(ctx, cli, app)
+	r.NoError(err)
+	r.Empty(blocking)
+
+	app.Spec.DependsOn = append(app.Spec.DependsOn,
+		v1beta1.AppDependency{Name: "network", Namespace: "infra"},
+		v1beta1.AppDependency{Name: "cache"})
+	blocking, err = Check(ctx, cli, app)
+	r.NoError(err)
+	r.Equal([]string{
+		"infra/network: application is not healthy (phase: runningWorkflow)",
+		"default/cache: not found",
+	}, blocking)
+}
+
+// indexedReader lists the applications by the dependency index, as the fake client ignores the field selector
+type indexedReader struct {
+	client.Reader
+	apps []v1beta1.Application
+}
+
+func (r indexedReader) List(ctx context.Context, list client.ObjectList, opts ...client.ListOption) error {
+	listOpts := &client.ListOptions{}
+	listOpts.ApplyOptions(opts)
+	key, found := listOpts.FieldSelector.RequiresExactMatch(IndexKey)
+	if !found {
+		return fmt.Errorf("index %s is not used", IndexKey)
+	}
+	apps := list.(*v1beta1.ApplicationList)
+	for i := range r.apps {
+		for _, k := range IndexFunc(&r.apps[i]) {
+			if k == key {
+				apps.Items = append(apps.Items, r.apps[i])
+			}
+		}
+	}
+	return nil
+}
+
+func TestFindDependents(t *testing.T) {
+	r := require.New(t)
+	ctx := context.Background()
+	db := newApp("default", "db", common.ApplicationRunning)
+	apps := []*v1beta1.Application{
+		db,
+		newApp("default", "web", common.ApplicationRunning, v1beta1.AppDependency{Name: "db"}),
+		newApp("other", "api", common.ApplicationRunning, v1beta1.AppDependency{Name: "db", Namespace: "default"}),
+		newApp("other", "job", common.ApplicationRunning, v1beta1.AppDependency{Name: "db"}),
+	}
+	names := func(apps []v1beta1.Application) []string {
+		var names []string
+		for _, app := range apps {
+			names = append(names, app.Namespace+"/"+app.Name)
+		}
+		return names
+	}
+
+	reader := indexedReader{}
+	builder := fake.NewClientBuilder().WithScheme(velacommon.Scheme)
+	for _, app := range apps {
+		reader.apps = append(reader.apps, *app)
+		builder = builder.WithObjects(app)
+	}
+	dependents, err := FindDependents(ctx, reader, db)
+	r.NoError(err)
+	r.ElementsMatch([]string{"default/web", "other/api"}, names(dependents))
+
+	dependents, err = ListDependents(ctx, builder.Build(), db)
+	r.NoError(err)
+	r.ElementsMatch([]string{"default/web", "other/api"}, names(dependents))
+}
+
+func TestDetectCycle(t *testing.T) {
+	r := require.New(t)
+	ctx := context.Background()
+	cli := fake.NewClientBuilder().WithScheme(velacommon.Scheme).WithObjects(
+		newApp("default", "a", common.ApplicationRunning, v1beta1.AppDependency{Name: "b"}),
+		newApp("default", "b", common.ApplicationRunning, v1beta1.AppDependency{Name: "c", Namespace: "infra"}),
+		newApp("infra", "c", common.ApplicationRunning),
+	).Build()
+
+	cycle, err := DetectCycle(ctx, cli, newApp("default", "a", "", v1beta1.AppDependency{Name: "b"}))
+	r.NoError(err)
+	r.Nil(cycle)
+
+	// c is updated to depend on a
+	cycle, err = DetectCycle(ctx, cli, newApp("infra", "c", "", v1beta1.AppDependency{Name: "a", Namespace: "default"}))
+	r.NoError(err)
+	r.Equal("infra/c -> default/a -> default/b -> infra/c", FormatCycle(cycle))
+
+	// missing dependencies are not treated as cycles
+	cycle, err = DetectCycle(ctx, cli, newApp("default", "d", "", v1beta1.AppDependency{Name: "missing"}))
+	r.NoError(err)
+	r.Nil(cycle)
+}
@@ -0,0 +1,272 @@
+/*
+Copyright 2022 The KubeVela Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package dryrun
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+
+	"cuelang.org/go/cue"
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/oam-dev/kubevela/apis/core.oam.dev/common"
+	"github.com/oam-dev/kubevela/apis/core.oam.dev/v1alpha1"
+	"github.com/oam-dev/kubevela/apis/core.oam.dev/v1beta1"
+	oamutil "github.com/oam-dev/kubevela/pkg/oam/util"
+	velacommon "github.com/oam-dev/kubevela/pkg/utils/common"
+)
+
+// InferComponent finds the component definition among the candidates that reproduces the
+// live object best. The properties are reverse inferred from the live object by locating
+// where the string parameters of the definition are rendered. A definition is a match
+// only if every field it renders, except the metadata, equals the one in the live object,
+// so that adopting the object with the component does not change (or restart) it. The
+// definition reproducing most fields wins. Nil is returned if no definition matches.
+func (d *Option) InferComponent(ctx context.Context, app *v1beta1.Application, obj *unstructured.Unstructured, definitions []string) (*common.ApplicationComponent, error) {
+	base := app.DeepCopy()
+	base.Spec.Components = nil
+	base.Spec.Policies = nil
+	base.Spec.Workflow = nil
+	ctx = contextWithAppNamespace(ctx, base)
+	r, _, err := d.newWorkflowDryRunner(ctx, base)
+	if err != nil {
+		return nil, err
+	}
+	live, err := normalizeJSON(obj.Object)
+	if err != nil {
+		return nil, err
+	}
+	var best *common.ApplicationComponent
+	bestScore := 0
+	for _, def := range definitions {
+		// definitions that cannot be rendered with the inferred properties are not matched
+		comp, score, err := r.inferComponent(ctx, obj, live.(map[string]interface{}), def)
+		if err != nil || comp == nil {
+			continue
+		}
+		if score > bestScore {
+			best, bestScore = comp, score
+		}
+	}
+	return best, nil
+}
+
+func (r *workflowDryRunner) inferComponent(ctx context.Context, obj *unstructured.Unstructured, live map[string]interface{}, definition string) (*common.ApplicationComponent, int, error) {
+	comp := common.ApplicationComponent{Name: obj.GetName(), Type: definition}
+	wl, err := r.parser.ParseWorkload(ctx, comp)
+	if err != nil {
+		return nil, 0, err
+	}
+	if wl.FullTemplate == nil || wl.FullTemplate.TemplateStr == "" {
+		return nil, 0, errors.Errorf("definition %s has no cue template", definition)
+	}
+	param, err := velacommon.GetCUEParameterValue(wl.FullTemplate.TemplateStr, r.PackageDiscover)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	probes := map[string]*parameterProbe{}
+	props := probeParameters(param, nil, probes)
+	comp.Properties = oamutil.Object2RawExtension(props)
+	rendered, err := r.renderWorkload(ctx, comp)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	inferred := map[string]interface{}{}
+	paths := map[string]valuePath{}
+	scalarPaths(rendered.Object, nil, paths)
+	for _, p := range paths {
+		token, ok := getValue(rendered.Object, p).(string)
+		if !ok || probes[token] == nil {
+			continue
+		}
+		probe := probes[token]
+		if probe.list {
+			// the probe list has a single element, the whole list is copied if the
+			// element is rendered at index 0
+			if idx, ok := p[len(p)-1].(int); !ok || idx != 0 {
+				continue
+			}
+			p = p[:len(p)-1]
+		}
+		val := getValue(live, p)
+		if !probe.accepts(val) {
+			continue
+		}
+		if err = unstructured.SetNestedField(inferred, val, probe.path...); err != nil {
+			return nil, 0, err
+		}
+	}
+
+	comp.Properties = oamutil.Object2RawExtension(inferred)
+	if rendered, err = r.renderWorkload(ctx, comp); err != nil {
+		return nil, 0, err
+	}
+	if rendered.GroupVersionKind() != obj.GroupVersionKind() {
+		return nil, 0, nil
+	}
+	normalized, err := normalizeJSON(rendered.Object)
+	if err != nil {
+		return nil, 0, err
+	}
+	score := 0
+	for key, val := range normalized.(map[string]interface{}) {
+		switch key {
+		case "apiVersion", "kind", "metadata", "status":
+			continue
+		}
+		n, ok := reproduces(val, live[key])
+		if !ok {
+			return nil, 0, nil
+		}
+		score += n
+	}
+	return &comp, score, nil
+}
+
+func (r *workflowDryRunner) renderWorkload(ctx context.Context, comp common.ApplicationComponent) (*unstructured.Unstructured, error) {
+	manifests, err := r.renderComponents(ctx, []common.ApplicationComponent{comp}, v1alpha1.PlacementDecision{})
+	if err != nil {
+		return nil, err
+	}
+	if len(manifests) == 0 || manifests[0].StandardWorkload == nil {
+		return nil, errors.Errorf("component %s renders no workload", comp.Name)
+	}
+	return manifests[0].StandardWorkload, nil
+}
+
+// parameterProbe records the parameter that a probe value is set to
+type parameterProbe struct {
+	path []string
+	// list indicates the parameter is a list of strings
+	list bool
+}
+
+func (p *parameterProbe) accepts(val interface{}) bool {
+	if !p.list {
+		_, ok := val.(string)
+		return ok
+	}
+	items, ok := val.([]interface{})
+	if !ok || len(items) == 0 {
+		return false
+	}
+	for _, item := range items {
+		if _, ok = item.(string); !ok {
+			return false
+		}
+	}
+	return true
+}
+
+// probeParameters sets a unique probe value to each string and string list parameter
+// in the schema. Optional structs are not entered as setting their fields changes the
+// rendered result besides the probed fields.
+func probeParameters(v cue.Value, prefix []string, probes map[string]*parameterProbe) map[string]interface{} {
+	iter, err := v.Fields(cue.Optional(true))
+	if err != nil {
+		return nil
+	}
+	props := map[string]interface{}{}
+	for iter.Next() {
+		if iter.Selector().IsDefinition() {
+			continue
+		}
+		field := iter.Selector().Unquoted()
+		path := append(append([]string{}, prefix...), field)
+		val := iter.Value()
+		token := fmt.Sprintf("vela-adopt-probe-%d", len(probes))
+		switch val.IncompleteKind() {
+		case cue.StringKind:
+			if unifiable(val, strconv.Quote(token)) {
+				probes[token] = &parameterProbe{path: path}
+				props[field] = token
+			}
+		case cue.ListKind:
+			if unifiable(val, "["+strconv.Quote(token)+"]") {
+				probes[token] = &parameterProbe{path: path, list: true}
+				props[field] = []interface{}{token}
+			}
+		case cue.StructKind:
+			if iter.IsOptional() {
+				continue
+			}
+			if sub := probeParameters(val, path, probes); len(sub) > 0 {
+				props[field] = sub
+			}
+		}
+	}
+	return props
+}
+
+func unifiable(v cue.Value, expr string) bool {
+	return v.Unify(v.Context().CompileString(expr)).Validate(cue.Concrete(true)) == nil
+}
+
+// reproduces checks if every field in the rendered value equals the live one, lists must
+// have the same length. It returns the number of scalar fields compared.
+func reproduces(rendered, live interface{}) (int, bool) {
+	switch r := rendered.(type) {
+	case map[string]interface{}:
+		l, ok := live.(map[string]interface{})
+		if !ok {
+			return 0, false
+		}
+		count := 0
+		for k, v := range r {
+			n, ok := reproduces(v, l[k])
+			if !ok {
+				return 0, false
+			}
+			count += n
+		}
+		return count, true
+	case []interface{}:
+		l, ok := live.([]interface{})
+		if !ok || len(l) != len(r) {
+			return 0, false
+		}
+		count := 0
+		for i := range r {
+			n, ok := reproduces(r[i], l[i])
+			if !ok {
+				return 0, false
+			}
+			count += n
+		}
+		return count, true
+	case nil:
+		return 0, true
+	default:
+		return 1, rendered == live
+	}
+}
+
+// normalizeJSON converts the value to its JSON form so that the numbers are compared as float64
+func normalizeJSON(v interface{}) (interface{}, error) {
+	bs, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	var out interface{}
+	err = json.Unmarshal(bs, &out)
+	return out, err
+}
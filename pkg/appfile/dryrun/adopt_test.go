@@ -0,0 +1,87 @@
+/*
+Copyright 2022 The KubeVela Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package dryrun
+
+import (
+	"context"
+	"testing"
+
+	"github.com/kubevela/workflow/pkg/cue/packages"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/yaml"
+
+	"github.com/oam-dev/kubevela/apis/core.oam.dev/v1beta1"
+	"github.com/oam-dev/kubevela/pkg/oam"
+	oamutil "github.com/oam-dev/kubevela/pkg/oam/util"
+	"github.com/oam-dev/kubevela/pkg/utils/common"
+)
+
+const adoptDeployment = `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: worker
+  namespace: default
+  labels:
+    team: legacy
+spec:
+  selector:
+    matchLabels:
+      app.oam.dev/component: worker
+  template:
+    metadata:
+      labels:
+        app.oam.dev/component: worker
+    spec:
+      containers:
+        - name: worker
+          image: busybox:1.35
+          command: ["sleep", "3600"]
+status:
+  replicas: 1
+`
+
+func TestInferComponent(t *testing.T) {
+	r := require.New(t)
+	def, err := oamutil.UnMarshalStringToComponentDefinition(readDataFromFile("./testdata/cd-myworker.yaml"))
+	r.NoError(err)
+	cd, err := oamutil.Object2Unstructured(def)
+	r.NoError(err)
+	cli := fake.NewClientBuilder().WithScheme(common.Scheme).Build()
+	opt := NewDryRunOption(cli, nil, nil, &packages.PackageDiscover{}, []oam.Object{cd}, false)
+	app := &v1beta1.Application{ObjectMeta: metav1.ObjectMeta{Name: "app", Namespace: "default"}}
+
+	obj := &unstructured.Unstructured{}
+	r.NoError(yaml.Unmarshal([]byte(adoptDeployment), &obj.Object))
+	comp, err := opt.InferComponent(context.Background(), app, obj, []string{"not-exist", "myworker"})
+	r.NoError(err)
+	r.NotNil(comp)
+	r.Equal("worker", comp.Name)
+	r.Equal("myworker", comp.Type)
+	props := map[string]interface{}{}
+	r.NoError(yaml.Unmarshal(comp.Properties.Raw, &props))
+	r.Equal(map[string]interface{}{"image": "busybox:1.35", "cmd": []interface{}{"sleep", "3600"}}, props)
+
+	// the definition cannot reproduce the selector of the live object
+	r.NoError(unstructured.SetNestedStringMap(obj.Object, map[string]string{"app": "worker"}, "spec", "selector", "matchLabels"))
+	comp, err = opt.InferComponent(context.Background(), app, obj, []string{"myworker"})
+	r.NoError(err)
+	r.Nil(comp)
+}
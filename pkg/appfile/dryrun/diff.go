@@ -69,6 +69,19 @@ type DiffEntry struct {
 	Subs     []*DiffEntry         `json:"subs,omitempty"`
 }
 
+// HasChanges checks if the object or any of its sub-resources is changed
+func (d *DiffEntry) HasChanges() bool {
+	if d.DiffType != NoDiff {
+		return true
+	}
+	for _, sub := range d.Subs {
+		if sub.HasChanges() {
+			return true
+		}
+	}
+	return false
+}
+
 // DiffType enums the type of diff
 type DiffType string
 
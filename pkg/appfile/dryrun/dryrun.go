@@ -162,37 +162,44 @@ func (d *Option) ExecuteDryRun(ctx context.Context, application *v1beta1.Applica
 
 // PrintDryRun will print the result of dry-run
 func (d *Option) PrintDryRun(buff *bytes.Buffer, appName string, comps []*types.ComponentManifest, policies []*unstructured.Unstructured) error {
-	var components = make(map[string]*unstructured.Unstructured)
-	for _, comp := range comps {
-		components[comp.Name] = comp.StandardWorkload
-	}
 	for _, c := range comps {
-		if _, err := fmt.Fprintf(buff, "---\n# Application(%s) -- Component(%s) \n---\n\n", appName, c.Name); err != nil {
-			return errors.Wrap(err, "fail to write buff")
+		if err := printComponent(buff, appName, fmt.Sprintf("Component(%s)", c.Name), c); err != nil {
+			return err
+		}
+	}
+	return printPolicies(buff, appName, policies)
+}
+
+func printComponent(buff *bytes.Buffer, appName string, header string, c *types.ComponentManifest) error {
+	if _, err := fmt.Fprintf(buff, "---\n# Application(%s) -- %s \n---\n\n", appName, header); err != nil {
+		return errors.Wrap(err, "fail to write buff")
+	}
+	result, err := yaml.Marshal(c.StandardWorkload)
+	if err != nil {
+		return errors.New("marshal result for component " + c.Name + " object in yaml format")
+	}
+	buff.Write(result)
+	buff.WriteString("\n---\n")
+	for _, t := range c.Traits {
+		traitType := t.GetLabels()[oam.TraitTypeLabel]
+		switch {
+		case traitType == definition.AuxiliaryWorkload:
+			buff.WriteString("## From the auxiliary workload \n")
+		case traitType != "":
+			buff.WriteString(fmt.Sprintf("## From the trait %s \n", traitType))
 		}
-		result, err := yaml.Marshal(components[c.Name])
+		result, err := yaml.Marshal(t)
 		if err != nil {
-			return errors.New("marshal result for component " + c.Name + " object in yaml format")
+			return errors.New("marshal result for Component " + c.Name + " trait " + t.GetName() + " object in yaml format")
 		}
 		buff.Write(result)
 		buff.WriteString("\n---\n")
-		for _, t := range c.Traits {
-			traitType := t.GetLabels()[oam.TraitTypeLabel]
-			switch {
-			case traitType == definition.AuxiliaryWorkload:
-				buff.WriteString("## From the auxiliary workload \n")
-			case traitType != "":
-				buff.WriteString(fmt.Sprintf("## From the trait %s \n", traitType))
-			}
-			result, err := yaml.Marshal(t)
-			if err != nil {
-				return errors.New("marshal result for Component " + c.Name + " trait " + t.GetName() + " object in yaml format")
-			}
-			buff.Write(result)
-			buff.WriteString("\n---\n")
-		}
-		buff.WriteString("\n")
 	}
+	buff.WriteString("\n")
+	return nil
+}
+
+func printPolicies(buff *bytes.Buffer, appName string, policies []*unstructured.Unstructured) error {
 	for _, plc := range policies {
 		if _, err := fmt.Fprintf(buff, "---\n# Application(%s) -- Policy(%s) \n---\n\n", appName, plc.GetName()); err != nil {
 			return errors.Wrap(err, "fail to write buff")
@@ -206,3 +213,69 @@ func (d *Option) PrintDryRun(buff *bytes.Buffer, appName string, comps []*types.
 	}
 	return nil
 }
+
+// RenderedObject is an object rendered by dry-run along with where it comes from in the application
+type RenderedObject struct {
+	Component string `json:"component,omitempty"`
+	// Trait is the type of the trait which renders the object, empty if the object is the workload
+	Trait        string                     `json:"trait,omitempty"`
+	Policy       string                     `json:"policy,omitempty"`
+	WorkflowStep string                     `json:"workflowStep,omitempty"`
+	Cluster      string                     `json:"cluster,omitempty"`
+	ReplicaKey   string                     `json:"replicaKey,omitempty"`
+	Object       *unstructured.Unstructured `json:"object"`
+}
+
+// DryRunReport is the machine-readable result of dry-run
+type DryRunReport struct {
+	Application string            `json:"application"`
+	Objects     []*RenderedObject `json:"objects"`
+}
+
+// NewDryRunReport converts the rendered components and policies into machine-readable report
+func NewDryRunReport(appName string, comps []*types.ComponentManifest, policies []*unstructured.Unstructured) *DryRunReport {
+	report := &DryRunReport{Application: appName, Objects: []*RenderedObject{}}
+	for _, c := range comps {
+		report.Objects = append(report.Objects, componentObjects(c, RenderedObject{})...)
+	}
+	report.Objects = append(report.Objects, policyObjects(policies)...)
+	return report
+}
+
+// NewWorkflowDryRunReport converts the result of workflow dry-run into machine-readable report
+func NewWorkflowDryRunReport(appName string, steps []*WorkflowStepManifests, policies []*unstructured.Unstructured) *DryRunReport {
+	report := &DryRunReport{Application: appName, Objects: []*RenderedObject{}}
+	for _, s := range steps {
+		for _, placement := range s.Placements {
+			for _, c := range placement.Components {
+				origin := RenderedObject{WorkflowStep: s.Name, Cluster: placement.Cluster, ReplicaKey: c.ReplicaKey}
+				report.Objects = append(report.Objects, componentObjects(c.ComponentManifest, origin)...)
+			}
+		}
+	}
+	report.Objects = append(report.Objects, policyObjects(policies)...)
+	return report
+}
+
+func componentObjects(c *types.ComponentManifest, origin RenderedObject) []*RenderedObject {
+	var objs []*RenderedObject
+	if c.StandardWorkload != nil {
+		obj := origin
+		obj.Component, obj.Object = c.Name, c.StandardWorkload
+		objs = append(objs, &obj)
+	}
+	for _, t := range c.Traits {
+		obj := origin
+		obj.Component, obj.Trait, obj.Object = c.Name, t.GetLabels()[oam.TraitTypeLabel], t
+		objs = append(objs, &obj)
+	}
+	return objs
+}
+
+func policyObjects(policies []*unstructured.Unstructured) []*RenderedObject {
+	var objs []*RenderedObject
+	for _, plc := range policies {
+		objs = append(objs, &RenderedObject{Policy: plc.GetName(), Object: plc})
+	}
+	return objs
+}
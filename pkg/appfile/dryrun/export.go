@@ -0,0 +1,997 @@
+/*
+Copyright 2022 The KubeVela Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package dryrun
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"path"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	"cuelang.org/go/cue"
+	pkgmulticluster "github.com/kubevela/pkg/multicluster"
+	"github.com/pkg/errors"
+	"gomodules.xyz/jsonpatch/v2"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/yaml"
+
+	"github.com/oam-dev/kubevela/apis/core.oam.dev/common"
+	"github.com/oam-dev/kubevela/apis/core.oam.dev/v1alpha1"
+	"github.com/oam-dev/kubevela/apis/core.oam.dev/v1beta1"
+	"github.com/oam-dev/kubevela/pkg/oam"
+	velacommon "github.com/oam-dev/kubevela/pkg/utils/common"
+)
+
+// ExportTarget is the target format to export the rendered resources of an application
+type ExportTarget string
+
+const (
+	// ExportTargetHelm exports the application as a Helm chart
+	ExportTargetHelm ExportTarget = "helm"
+	// ExportTargetKustomize exports the application as a Kustomize base with overlays
+	ExportTargetKustomize ExportTarget = "kustomize"
+)
+
+// ExportApplication packages the rendered resources of the application into files of the
+// export target, the returned map is keyed by the relative path of each file
+func (d *Option) ExportApplication(ctx context.Context, app *v1beta1.Application, target ExportTarget) (map[string][]byte, error) {
+	switch target {
+	case ExportTargetHelm:
+		return d.ExportHelmChart(ctx, app)
+	case ExportTargetKustomize:
+		return d.ExportKustomize(ctx, app)
+	default:
+		return nil, errors.Errorf("export target %s is not supported, only %s and %s are supported", target, ExportTargetHelm, ExportTargetKustomize)
+	}
+}
+
+// ExportHelmChart packages the rendered resources of the application into a Helm chart.
+// The properties of each component are merged with the defaults in the parameter schema of
+// its definition and lifted into values.yaml, fields of the rendered resources which are
+// copied from a scalar property are replaced by the reference to the value. For each deploy
+// step in the workflow, or each placement of the step if it has several placements, a values
+// file is generated from the resources rendered with the topology, override and replication
+// policies used in the step, the differences which cannot be expressed by the values are
+// listed in the comments of the file.
+func (d *Option) ExportHelmChart(ctx context.Context, app *v1beta1.Application) (map[string][]byte, error) {
+	ctx = contextWithAppNamespace(ctx, app)
+	r, policies, err := d.newWorkflowDryRunner(ctx, app)
+	if err != nil {
+		return nil, err
+	}
+	chart := map[string]interface{}{
+		"apiVersion":  "v2",
+		"name":        r.app.Name,
+		"description": fmt.Sprintf("A Helm chart exported from KubeVela application %s", r.app.Name),
+		"type":        "application",
+		"version":     "0.1.0",
+	}
+	values := map[string]interface{}{}
+	files := map[string][]byte{}
+	names := newFileNamer()
+	var baseObjs []*unstructured.Unstructured
+	baseRefs := map[string]helmRefs{}
+	for _, comp := range r.app.Spec.Components {
+		compValues, err := r.componentValues(ctx, comp)
+		if err != nil {
+			return nil, err
+		}
+		values[comp.Name] = compValues
+		objs, refs, err := r.liftComponentValues(ctx, comp, compValues)
+		if err != nil {
+			return nil, errors.WithMessagef(err, "lift values for component %s", comp.Name)
+		}
+		for i, obj := range objs {
+			obj.SetNamespace(templateNamespace(obj.GetNamespace(), r.app.Namespace))
+			bs, err := renderHelmTemplate(obj, refs[i])
+			if err != nil {
+				return nil, err
+			}
+			files[path.Join("templates", names.name(comp.Name, obj))] = bs
+			baseObjs = append(baseObjs, obj)
+			baseRefs[objectKey(obj)] = refs[i]
+		}
+	}
+	for _, plc := range policies {
+		plc.SetNamespace(templateNamespace(plc.GetNamespace(), r.app.Namespace))
+		bs, err := renderHelmTemplate(plc, nil)
+		if err != nil {
+			return nil, err
+		}
+		files[path.Join("templates", names.name("policy", plc))] = bs
+	}
+	if files["Chart.yaml"], err = yaml.Marshal(chart); err != nil {
+		return nil, err
+	}
+	if files["values.yaml"], err = yaml.Marshal(values); err != nil {
+		return nil, err
+	}
+
+	steps, err := r.dryRunDeploySteps(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, s := range steps {
+		for _, placement := range s.Placements {
+			name := "values-" + s.Name
+			if len(s.Placements) > 1 {
+				name += "-" + placementDirName(placement.PlacementDecision)
+			}
+			placed, diffs, err := placementValues(values, baseObjs, baseRefs, placement)
+			if err != nil {
+				return nil, errors.WithMessagef(err, "generate values for workflow step %s in %s", s.Name, placement.String())
+			}
+			if files[name+".yaml"], err = marshalPlacementValues(s.Name, placement, placed, diffs); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return files, nil
+}
+
+// ExportKustomize packages the rendered resources of the application into a Kustomize base
+// and generates one overlay for each deploy step in the workflow, or one overlay for each
+// placement under the directory of the step if it has several placements. The overlay sets the
+// namespace selected by the topology policies and patches the base with the differences
+// made by the override and replication policies used in the step.
+func (d *Option) ExportKustomize(ctx context.Context, app *v1beta1.Application) (map[string][]byte, error) {
+	ctx = contextWithAppNamespace(ctx, app)
+	r, policies, err := d.newWorkflowDryRunner(ctx, app)
+	if err != nil {
+		return nil, err
+	}
+	local := v1alpha1.PlacementDecision{Cluster: pkgmulticluster.Local}
+	comps, err := r.renderComponents(ctx, r.app.Spec.Components, local)
+	if err != nil {
+		return nil, err
+	}
+	baseObjs := append(replicaObjects(comps), policies...)
+	files := map[string][]byte{}
+	names := newFileNamer()
+	baseFiles := map[string]string{}
+	var resources []string
+	for _, obj := range baseObjs {
+		name := names.name(obj.GetLabels()[oam.LabelAppComponent], obj)
+		bs, err := yaml.Marshal(obj)
+		if err != nil {
+			return nil, err
+		}
+		files[path.Join("base", name)] = bs
+		baseFiles[objectKey(obj)] = name
+		resources = append(resources, name)
+	}
+	if files["base/kustomization.yaml"], err = marshalKustomization(map[string]interface{}{"resources": resources}); err != nil {
+		return nil, err
+	}
+
+	overlays, err := r.dryRunDeploySteps(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, overlay := range overlays {
+		for _, placement := range overlay.Placements {
+			dir := path.Join("overlays", overlay.Name)
+			if len(overlay.Placements) > 1 {
+				dir = path.Join(dir, placementDirName(placement.PlacementDecision))
+			}
+			basePath := strings.Repeat("../", strings.Count(dir, "/")+1) + "base"
+			overlayFiles, err := kustomizeOverlay(baseObjs, baseFiles, placement, basePath)
+			if err != nil {
+				return nil, errors.WithMessagef(err, "generate overlay for workflow step %s in %s", overlay.Name, placement.String())
+			}
+			for name, bs := range overlayFiles {
+				files[path.Join(dir, name)] = bs
+			}
+		}
+	}
+	return files, nil
+}
+
+// dryRunDeploySteps dry-runs the workflow and returns the deploy steps with the components
+// rendered for each placement
+func (r *workflowDryRunner) dryRunDeploySteps(ctx context.Context) ([]*WorkflowStepManifests, error) {
+	steps, err := generateWorkflowSteps(r.app)
+	if err != nil {
+		return nil, errors.WithMessage(err, "cannot generate workflow steps")
+	}
+	var results []*WorkflowStepManifests
+	for _, s := range steps {
+		stepResults, err := r.dryRunStep(ctx, s.WorkflowStepBase, s.SubSteps)
+		if err != nil {
+			return nil, errors.WithMessagef(err, "dry-run workflow step %s", s.Name)
+		}
+		for _, result := range stepResults {
+			if result.Type == "deploy" && len(result.Placements) > 0 {
+				results = append(results, result)
+			}
+		}
+	}
+	return results, nil
+}
+
+// placementDirName returns the directory name of the overlay for the placement if the step has more than one placement
+func placementDirName(placement v1alpha1.PlacementDecision) string {
+	if placement.Namespace == "" {
+		return placement.Cluster
+	}
+	return placement.Cluster + "-" + placement.Namespace
+}
+
+func kustomizeOverlay(baseObjs []*unstructured.Unstructured, baseFiles map[string]string, placement *PlacementManifests, basePath string) (map[string][]byte, error) {
+	kustomization := map[string]interface{}{}
+	if placement.Namespace != "" {
+		kustomization["namespace"] = placement.Namespace
+	}
+	resources := []string{basePath}
+	var patches []interface{}
+	files := map[string][]byte{}
+	names := newFileNamer()
+	found := map[string]bool{}
+	for _, obj := range replicaObjects(placement.Components) {
+		key := objectKey(obj)
+		var base *unstructured.Unstructured
+		for _, baseObj := range baseObjs {
+			if objectKey(baseObj) == key {
+				base = baseObj
+				break
+			}
+		}
+		if base == nil {
+			name := names.name(obj.GetLabels()[oam.LabelAppComponent], obj)
+			bs, err := yaml.Marshal(obj)
+			if err != nil {
+				return nil, err
+			}
+			files[name] = bs
+			resources = append(resources, name)
+			continue
+		}
+		found[key] = true
+		// the namespace is set by the kustomization instead of the patch
+		obj.SetNamespace(base.GetNamespace())
+		patch, err := jsonPatch(base, obj)
+		if err != nil {
+			return nil, err
+		}
+		if len(patch) == 0 {
+			continue
+		}
+		name := "patch-" + baseFiles[key]
+		if files[name], err = yaml.Marshal(patch); err != nil {
+			return nil, err
+		}
+		patches = append(patches, map[string]interface{}{"path": name, "target": patchTarget(base)})
+	}
+	for _, base := range baseObjs {
+		key := objectKey(base)
+		if found[key] || base.GetLabels()[oam.LabelAppComponent] == "" {
+			continue
+		}
+		name := "delete-" + baseFiles[key]
+		bs, err := yaml.Marshal(map[string]interface{}{
+			"apiVersion": base.GetAPIVersion(),
+			"kind":       base.GetKind(),
+			"metadata":   map[string]interface{}{"name": base.GetName()},
+			"$patch":     "delete",
+		})
+		if err != nil {
+			return nil, err
+		}
+		files[name] = bs
+		patches = append(patches, map[string]interface{}{"path": name})
+	}
+	kustomization["resources"] = resources
+	if len(patches) > 0 {
+		kustomization["patches"] = patches
+	}
+	var err error
+	if files["kustomization.yaml"], err = marshalKustomization(kustomization); err != nil {
+		return nil, err
+	}
+	return files, nil
+}
+
+func marshalKustomization(kustomization map[string]interface{}) ([]byte, error) {
+	kustomization["apiVersion"] = "kustomize.config.k8s.io/v1beta1"
+	kustomization["kind"] = "Kustomization"
+	return yaml.Marshal(kustomization)
+}
+
+func jsonPatch(base, modified *unstructured.Unstructured) ([]jsonpatch.Operation, error) {
+	baseJSON, err := json.Marshal(base.Object)
+	if err != nil {
+		return nil, err
+	}
+	modifiedJSON, err := json.Marshal(modified.Object)
+	if err != nil {
+		return nil, err
+	}
+	patch, err := jsonpatch.CreatePatch(baseJSON, modifiedJSON)
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(patch, func(i, j int) bool { return patch[i].Path < patch[j].Path })
+	return patch, nil
+}
+
+func patchTarget(obj *unstructured.Unstructured) map[string]interface{} {
+	gvk := obj.GroupVersionKind()
+	target := map[string]interface{}{"version": gvk.Version, "kind": gvk.Kind, "name": obj.GetName()}
+	if gvk.Group != "" {
+		target["group"] = gvk.Group
+	}
+	return target
+}
+
+// replicaObjects returns the workloads and traits of the components, the cluster label
+// set for the placement is removed as it is meaningless outside KubeVela
+func replicaObjects(comps []*ReplicaManifest) []*unstructured.Unstructured {
+	var objs []*unstructured.Unstructured
+	for _, comp := range comps {
+		for _, obj := range append([]*unstructured.Unstructured{comp.StandardWorkload}, comp.Traits...) {
+			if obj == nil {
+				continue
+			}
+			labels := obj.GetLabels()
+			delete(labels, oam.LabelAppCluster)
+			obj.SetLabels(labels)
+			objs = append(objs, obj)
+		}
+	}
+	return objs
+}
+
+func objectKey(obj *unstructured.Unstructured) string {
+	return obj.GetAPIVersion() + "/" + obj.GetKind() + "/" + obj.GetName()
+}
+
+// fileNamer generates unique file names for the exported objects
+type fileNamer map[string]bool
+
+func newFileNamer() fileNamer {
+	return fileNamer{}
+}
+
+func (n fileNamer) name(prefix string, obj *unstructured.Unstructured) string {
+	base := strings.ToLower(obj.GetKind()) + "-" + obj.GetName()
+	if prefix != "" && prefix != obj.GetName() {
+		base = prefix + "-" + base
+	}
+	name := base + ".yaml"
+	for i := 1; n[name]; i++ {
+		name = fmt.Sprintf("%s-%d.yaml", base, i)
+	}
+	n[name] = true
+	return name
+}
+
+func templateNamespace(ns string, appNamespace string) string {
+	if ns == "" || ns == appNamespace {
+		return "{{ .Release.Namespace }}"
+	}
+	return ns
+}
+
+// componentValues merges the properties of the component with the defaults declared in
+// the parameter schema of the component definition
+func (r *workflowDryRunner) componentValues(ctx context.Context, comp common.ApplicationComponent) (map[string]interface{}, error) {
+	wl, err := r.parser.ParseWorkload(ctx, comp)
+	if err != nil {
+		return nil, errors.WithMessagef(err, "parse component %s", comp.Name)
+	}
+	values := map[string]interface{}{}
+	if wl.FullTemplate != nil && wl.FullTemplate.TemplateStr != "" {
+		if param, err := velacommon.GetCUEParameterValue(wl.FullTemplate.TemplateStr, r.PackageDiscover); err == nil {
+			if defaults, ok := parameterDefaults(param).(map[string]interface{}); ok {
+				values = defaults
+			}
+		}
+	}
+	if comp.Properties != nil && len(comp.Properties.Raw) > 0 {
+		props := map[string]interface{}{}
+		if err = json.Unmarshal(comp.Properties.Raw, &props); err != nil {
+			return nil, errors.Wrapf(err, "invalid properties of component %s", comp.Name)
+		}
+		values = mergeValues(values, props)
+	}
+	return values, nil
+}
+
+// parameterDefaults returns the default value declared in the parameter schema, nil if
+// there is no default value. Optional fields are skipped as setting them changes the
+// rendered result.
+func parameterDefaults(v cue.Value) interface{} {
+	if d, ok := v.Default(); ok && d.IsConcrete() && d.Kind() != cue.StructKind {
+		// open list has an implicit default of empty list
+		if d.Kind() == cue.ListKind {
+			if l, err := d.List(); err != nil || !l.Next() {
+				return nil
+			}
+		}
+		var val interface{}
+		if err := d.Decode(&val); err == nil {
+			return val
+		}
+		return nil
+	}
+	if v.IncompleteKind() != cue.StructKind {
+		return nil
+	}
+	iter, err := v.Fields()
+	if err != nil {
+		return nil
+	}
+	values := map[string]interface{}{}
+	for iter.Next() {
+		if val := parameterDefaults(iter.Value()); val != nil {
+			values[iter.Selector().Unquoted()] = val
+		}
+	}
+	if len(values) == 0 {
+		return nil
+	}
+	return values
+}
+
+func mergeValues(base, patch map[string]interface{}) map[string]interface{} {
+	merged := make(map[string]interface{}, len(base))
+	for k, v := range base {
+		merged[k] = v
+	}
+	for k, v := range patch {
+		baseMap, ok1 := merged[k].(map[string]interface{})
+		patchMap, ok2 := v.(map[string]interface{})
+		if ok1 && ok2 {
+			merged[k] = mergeValues(baseMap, patchMap)
+			continue
+		}
+		merged[k] = v
+	}
+	return merged
+}
+
+// valuePath is the path of a scalar field in the values or in the rendered object,
+// each element is either a string key or an int index
+type valuePath []interface{}
+
+func (p valuePath) String() string {
+	var s []string
+	for _, e := range p {
+		s = append(s, fmt.Sprint(e))
+	}
+	return strings.Join(s, ".")
+}
+
+// helmRef returns the Helm template expression that references the value
+func (p valuePath) helmRef() string {
+	ref := "index .Values"
+	for _, e := range p {
+		if idx, ok := e.(int); ok {
+			ref += " " + strconv.Itoa(idx)
+		} else {
+			ref += " " + strconv.Quote(fmt.Sprint(e))
+		}
+	}
+	return ref
+}
+
+func scalarPaths(v interface{}, prefix valuePath, out map[string]valuePath) {
+	switch t := v.(type) {
+	case map[string]interface{}:
+		for k, sub := range t {
+			scalarPaths(sub, append(append(valuePath{}, prefix...), k), out)
+		}
+	case []interface{}:
+		for i, sub := range t {
+			scalarPaths(sub, append(append(valuePath{}, prefix...), i), out)
+		}
+	case string, bool, float64:
+		out[prefix.String()] = prefix
+	}
+}
+
+func getValue(v interface{}, p valuePath) interface{} {
+	for _, e := range p {
+		switch t := v.(type) {
+		case map[string]interface{}:
+			v = t[fmt.Sprint(e)]
+		case []interface{}:
+			idx, ok := e.(int)
+			if !ok || idx >= len(t) {
+				return nil
+			}
+			v = t[idx]
+		default:
+			return nil
+		}
+	}
+	return v
+}
+
+func setValue(v interface{}, p valuePath, val interface{}) {
+	for i, e := range p {
+		last := i == len(p)-1
+		switch t := v.(type) {
+		case map[string]interface{}:
+			if last {
+				t[fmt.Sprint(e)] = val
+				return
+			}
+			v = t[fmt.Sprint(e)]
+		case []interface{}:
+			if last {
+				t[e.(int)] = val
+				return
+			}
+			v = t[e.(int)]
+		}
+	}
+}
+
+// probeValue returns a different value of the same type to detect which fields of the
+// rendered objects are copied from the value. The probe values of strings and numbers are
+// unique by the index so that all of them can be probed in one render, while the probe values
+// of booleans can only be told apart if they are probed one by one.
+func probeValue(v interface{}, index int) interface{} {
+	switch t := v.(type) {
+	case string:
+		return fmt.Sprintf("%s-vela-probe-%d-end", t, index)
+	case bool:
+		return !t
+	case float64:
+		return t + float64(index+1)
+	}
+	return nil
+}
+
+// helmRefs records the Helm template expressions of the fields in one rendered object
+type helmRefs map[string]*helmRef
+
+type helmRef struct {
+	path valuePath
+	// parts are the literal strings and the referenced value paths which compose the field
+	parts []interface{}
+	// quote indicates the field is a string
+	quote bool
+}
+
+func (ref *helmRef) template() string {
+	if len(ref.parts) == 1 {
+		if p, ok := ref.parts[0].(valuePath); ok {
+			if ref.quote {
+				return "{{ " + p.helmRef() + " | quote }}"
+			}
+			return "{{ " + p.helmRef() + " }}"
+		}
+	}
+	args := make([]string, 0, len(ref.parts))
+	for _, part := range ref.parts {
+		if p, ok := part.(valuePath); ok {
+			args = append(args, "("+p.helmRef()+")")
+		} else {
+			args = append(args, strconv.Quote(part.(string)))
+		}
+	}
+	return fmt.Sprintf("{{ printf %s %s | quote }}", strconv.Quote(strings.Repeat("%v", len(args))), strings.Join(args, " "))
+}
+
+// liftComponentValues renders the component with the values, then renders it again with all
+// the scalar values replaced by the probe values and diffs the rendered objects to find the
+// fields which are copied from each value. The values which cannot be told apart in the combined
+// render, or all the values if the probe values violate the constraints in the definition, are
+// probed one by one.
+func (r *workflowDryRunner) liftComponentValues(ctx context.Context, comp common.ApplicationComponent, values map[string]interface{}) ([]*unstructured.Unstructured, []helmRefs, error) {
+	render := func(values map[string]interface{}) ([]map[string]interface{}, []*unstructured.Unstructured, error) {
+		c := comp.DeepCopy()
+		c.Properties = &runtime.RawExtension{}
+		var err error
+		if c.Properties.Raw, err = json.Marshal(values); err != nil {
+			return nil, nil, err
+		}
+		comps, err := r.renderComponents(ctx, []common.ApplicationComponent{*c}, v1alpha1.PlacementDecision{Cluster: pkgmulticluster.Local})
+		if err != nil {
+			return nil, nil, err
+		}
+		objs := replicaObjects(comps)
+		var data []map[string]interface{}
+		for _, obj := range objs {
+			m, err := objectData(obj)
+			if err != nil {
+				return nil, nil, err
+			}
+			data = append(data, m)
+		}
+		return data, objs, nil
+	}
+	origin, objs, err := render(values)
+	if err != nil {
+		return nil, nil, err
+	}
+	refs := make([]helmRefs, len(objs))
+	fields := make([]map[string]valuePath, len(objs))
+	for i := range objs {
+		refs[i] = helmRefs{}
+		fields[i] = map[string]valuePath{}
+		scalarPaths(origin[i], nil, fields[i])
+	}
+	paths := map[string]valuePath{}
+	scalarPaths(values, nil, paths)
+	keys := make([]string, 0, len(paths))
+	for k := range paths {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	// probe returns the values which cannot be told apart from the others in the render, the
+	// render fails if the probe values violate the constraints in the definition
+	probe := func(indexes []int) ([]int, error) {
+		probeValues, err := copyValues(values)
+		if err != nil {
+			return nil, err
+		}
+		vals, probes := map[int]interface{}{}, map[int]interface{}{}
+		for _, idx := range indexes {
+			vals[idx] = getValue(values, paths[keys[idx]])
+			probes[idx] = probeValue(vals[idx], idx)
+			setValue(probeValues, paths[keys[idx]], probes[idx])
+		}
+		probed, _, err := render(probeValues)
+		if err != nil {
+			return nil, err
+		}
+		if len(probed) != len(origin) {
+			return nil, errors.New("the probe values change the rendered objects")
+		}
+		ambiguous := map[int]bool{}
+		for i := range origin {
+			fieldKeys := make([]string, 0, len(fields[i]))
+			for k := range fields[i] {
+				fieldKeys = append(fieldKeys, k)
+			}
+			sort.Strings(fieldKeys)
+			for _, fieldKey := range fieldKeys {
+				fieldPath := fields[i][fieldKey]
+				before, after := getValue(origin[i], fieldPath), getValue(probed[i], fieldPath)
+				if reflect.DeepEqual(before, after) {
+					continue
+				}
+				// the values with the same value and probe value cannot be told apart
+				matched := map[string][]int{}
+				var order []string
+				for _, idx := range indexes {
+					if matchProbe(vals[idx], probes[idx], before, after) {
+						key := fmt.Sprint(vals[idx], probes[idx])
+						if _, ok := matched[key]; !ok {
+							order = append(order, key)
+						}
+						matched[key] = append(matched[key], idx)
+					}
+				}
+				for _, key := range order {
+					if len(matched[key]) > 1 {
+						for _, idx := range matched[key] {
+							ambiguous[idx] = true
+						}
+						continue
+					}
+					idx := matched[key][0]
+					bindHelmRef(refs[i], fieldKey, fieldPath, append(valuePath{comp.Name}, paths[keys[idx]]...), vals[idx], before)
+				}
+			}
+		}
+		var retries []int
+		for _, idx := range indexes {
+			if ambiguous[idx] {
+				retries = append(retries, idx)
+			}
+		}
+		return retries, nil
+	}
+	all := make([]int, len(keys))
+	for i := range keys {
+		all[i] = i
+	}
+	if len(all) == 0 {
+		return objs, refs, nil
+	}
+	retries, err := probe(all)
+	if err != nil && len(all) > 1 {
+		retries = all
+	}
+	for _, idx := range retries {
+		// the fields of the value violating the constraints are kept as they are
+		_, _ = probe([]int{idx})
+	}
+	return objs, refs, nil
+}
+
+// matchProbe tells whether the field is copied from the value, or embeds the value in a string,
+// by comparing the field rendered with the value and with the probe value
+func matchProbe(val, probe, before, after interface{}) bool {
+	if reflect.DeepEqual(before, val) && reflect.DeepEqual(after, probe) {
+		return true
+	}
+	beforeStr, ok1 := before.(string)
+	afterStr, ok2 := after.(string)
+	valStr, probeStr := fmt.Sprint(val), fmt.Sprint(probe)
+	return ok1 && ok2 && valStr != "" && strings.Count(beforeStr, valStr) == 1 && strings.Count(afterStr, probeStr) == 1
+}
+
+func bindHelmRef(refs helmRefs, fieldKey string, fieldPath valuePath, ref valuePath, val, before interface{}) {
+	existing := refs[fieldKey]
+	_, isString := before.(string)
+	if reflect.DeepEqual(before, val) {
+		if existing == nil {
+			refs[fieldKey] = &helmRef{path: fieldPath, parts: []interface{}{ref}, quote: isString}
+		}
+		return
+	}
+	// the field may embed the value in a string, e.g. image: nginx:{{ tag }}
+	beforeStr, _ := before.(string)
+	valStr := fmt.Sprint(val)
+	if existing == nil {
+		existing = &helmRef{path: fieldPath, parts: []interface{}{beforeStr}, quote: true}
+		refs[fieldKey] = existing
+	}
+	count, index := 0, -1
+	for i, part := range existing.parts {
+		if literal, ok := part.(string); ok && strings.Contains(literal, valStr) {
+			count += strings.Count(literal, valStr)
+			index = i
+		}
+	}
+	if count != 1 {
+		return
+	}
+	prefix, suffix, _ := strings.Cut(existing.parts[index].(string), valStr)
+	var parts []interface{}
+	parts = append(parts, existing.parts[:index]...)
+	if prefix != "" {
+		parts = append(parts, prefix)
+	}
+	parts = append(parts, ref)
+	if suffix != "" {
+		parts = append(parts, suffix)
+	}
+	existing.parts = append(parts, existing.parts[index+1:]...)
+}
+
+// renderHelmTemplate marshals the object into Helm template with the fields replaced by value references
+func renderHelmTemplate(obj *unstructured.Unstructured, refs helmRefs) ([]byte, error) {
+	obj = obj.DeepCopy()
+	var tokens []string
+	keys := make([]string, 0, len(refs))
+	for k := range refs {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for i, k := range keys {
+		// the token is delimited so that no token is the prefix of another one
+		token := fmt.Sprintf("VELA-HELM-VALUE-%d-END", i)
+		tokens = append(tokens, token)
+		setValue(obj.Object, refs[k].path, token)
+	}
+	bs, err := yaml.Marshal(obj.Object)
+	if err != nil {
+		return nil, err
+	}
+	s := string(bs)
+	for i, token := range tokens {
+		s = strings.ReplaceAll(s, token, refs[keys[i]].template())
+	}
+	s = strings.ReplaceAll(s, "'{{ .Release.Namespace }}'", "{{ .Release.Namespace }}")
+	return []byte(s), nil
+}
+
+// evaluate returns the field rendered by the Helm template expression with the values
+func (ref *helmRef) evaluate(values map[string]interface{}) interface{} {
+	if len(ref.parts) == 1 {
+		if p, ok := ref.parts[0].(valuePath); ok {
+			return getValue(values, p)
+		}
+	}
+	var sb strings.Builder
+	for _, part := range ref.parts {
+		if p, ok := part.(valuePath); ok {
+			sb.WriteString(fmt.Sprint(getValue(values, p)))
+		} else {
+			sb.WriteString(part.(string))
+		}
+	}
+	return sb.String()
+}
+
+// lift sets the values referenced by the Helm template expression so that it renders the field,
+// returns false if the field cannot be rendered by the expression
+func (ref *helmRef) lift(values map[string]interface{}, field interface{}) bool {
+	if len(ref.parts) == 1 {
+		if p, ok := ref.parts[0].(valuePath); ok {
+			setValue(values, p, field)
+			return true
+		}
+	}
+	rest, ok := field.(string)
+	if !ok {
+		return false
+	}
+	for i, part := range ref.parts {
+		p, ok := part.(valuePath)
+		if !ok {
+			if !strings.HasPrefix(rest, part.(string)) {
+				return false
+			}
+			rest = rest[len(part.(string)):]
+			continue
+		}
+		end := len(rest)
+		if i+1 < len(ref.parts) {
+			// adjacent values cannot be split
+			next, ok := ref.parts[i+1].(string)
+			if !ok {
+				return false
+			}
+			if end = strings.Index(rest, next); end < 0 {
+				return false
+			}
+		}
+		var val interface{} = rest[:end]
+		switch getValue(values, p).(type) {
+		case float64:
+			f, err := strconv.ParseFloat(rest[:end], 64)
+			if err != nil {
+				return false
+			}
+			val = f
+		case bool:
+			b, err := strconv.ParseBool(rest[:end])
+			if err != nil {
+				return false
+			}
+			val = b
+		}
+		setValue(values, p, val)
+		rest = rest[end:]
+	}
+	return rest == ""
+}
+
+// placementValues derives the values which render the templates into the objects dry-run for
+// the placement. The differences which cannot be expressed by the values are returned, such as
+// the added or removed objects and the fields which are not copied from the values.
+func placementValues(values map[string]interface{}, baseObjs []*unstructured.Unstructured, baseRefs map[string]helmRefs, placement *PlacementManifests) (map[string]interface{}, []string, error) {
+	placed, err := copyValues(values)
+	if err != nil {
+		return nil, nil, err
+	}
+	type placedObject struct {
+		name string
+		data map[string]interface{}
+		refs helmRefs
+	}
+	var diffs []string
+	var objs []placedObject
+	found := map[string]bool{}
+	for _, obj := range replicaObjects(placement.Components) {
+		key := objectKey(obj)
+		name := obj.GetKind() + " " + obj.GetName()
+		var base *unstructured.Unstructured
+		for _, baseObj := range baseObjs {
+			if objectKey(baseObj) == key {
+				base = baseObj
+				break
+			}
+		}
+		if base == nil {
+			diffs = append(diffs, name+" is added")
+			continue
+		}
+		found[key] = true
+		// the namespace is set by the release instead of the values
+		obj.SetNamespace(base.GetNamespace())
+		patch, err := jsonPatch(base, obj)
+		if err != nil {
+			return nil, nil, err
+		}
+		data, err := objectData(obj)
+		if err != nil {
+			return nil, nil, err
+		}
+		refs := baseRefs[key]
+		for _, op := range patch {
+			ref := refs[pointerKey(op.Path)]
+			if ref == nil || op.Operation != "replace" {
+				diffs = append(diffs, fmt.Sprintf("%s: %s %s", name, op.Operation, op.Path))
+				continue
+			}
+			ref.lift(placed, getValue(data, ref.path))
+		}
+		objs = append(objs, placedObject{name: name, data: data, refs: refs})
+	}
+	for _, base := range baseObjs {
+		if !found[objectKey(base)] && base.GetLabels()[oam.LabelAppComponent] != "" {
+			diffs = append(diffs, base.GetKind()+" "+base.GetName()+" is removed")
+		}
+	}
+	// the values lifted from one field may not render the others referencing the same value
+	for _, obj := range objs {
+		keys := make([]string, 0, len(obj.refs))
+		for k := range obj.refs {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			ref := obj.refs[k]
+			if !reflect.DeepEqual(ref.evaluate(placed), getValue(obj.data, ref.path)) {
+				diffs = append(diffs, fmt.Sprintf("%s: %s conflicts with the values", obj.name, k))
+			}
+		}
+	}
+	return placed, diffs, nil
+}
+
+func marshalPlacementValues(stepName string, placement *PlacementManifests, values map[string]interface{}, diffs []string) ([]byte, error) {
+	bs, err := yaml.Marshal(values)
+	if err != nil {
+		return nil, err
+	}
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "# Values of workflow step %s for cluster %s\n", stepName, placement.Cluster)
+	if placement.Namespace != "" {
+		fmt.Fprintf(&sb, "# Install the chart into namespace %s\n", placement.Namespace)
+	}
+	if len(diffs) > 0 {
+		sb.WriteString("# The following differences cannot be expressed by the values:\n")
+		for _, diff := range diffs {
+			sb.WriteString("#   - " + diff + "\n")
+		}
+	}
+	sb.Write(bs)
+	return []byte(sb.String()), nil
+}
+
+// pointerKey converts the JSON pointer into the key of the field path
+func pointerKey(pointer string) string {
+	segments := strings.Split(strings.TrimPrefix(pointer, "/"), "/")
+	for i, segment := range segments {
+		segments[i] = strings.NewReplacer("~1", "/", "~0", "~").Replace(segment)
+	}
+	return strings.Join(segments, ".")
+}
+
+// objectData converts the object into the JSON representation of the values
+func objectData(obj *unstructured.Unstructured) (map[string]interface{}, error) {
+	bs, err := json.Marshal(obj.Object)
+	if err != nil {
+		return nil, err
+	}
+	data := map[string]interface{}{}
+	return data, json.Unmarshal(bs, &data)
+}
+
+func copyValues(values map[string]interface{}) (map[string]interface{}, error) {
+	bs, err := json.Marshal(values)
+	if err != nil {
+		return nil, err
+	}
+	copied := map[string]interface{}{}
+	return copied, json.Unmarshal(bs, &copied)
+}
@@ -0,0 +1,208 @@
+/*
+Copyright 2022 The KubeVela Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package dryrun
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/kubevela/workflow/pkg/cue/packages"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/yaml"
+
+	"github.com/oam-dev/kubevela/apis/core.oam.dev/v1beta1"
+	"github.com/oam-dev/kubevela/apis/types"
+	"github.com/oam-dev/kubevela/pkg/oam"
+	oamutil "github.com/oam-dev/kubevela/pkg/oam/util"
+	"github.com/oam-dev/kubevela/pkg/utils/common"
+)
+
+const exportApp = `
+apiVersion: core.oam.dev/v1beta1
+kind: Application
+metadata:
+  name: app
+  namespace: default
+spec:
+  components:
+    - name: worker
+      type: myworker
+      properties:
+        image: busybox
+  policies:
+    - name: topology-staging
+      type: topology
+      properties:
+        clusters: ["local"]
+        namespace: staging
+    - name: topology-prod
+      type: topology
+      properties:
+        clusters: ["local"]
+        namespace: prod
+    - name: override-prod
+      type: override
+      properties:
+        components:
+          - name: worker
+            properties:
+              image: busybox:prod
+  workflow:
+    steps:
+      - name: staging
+        type: deploy
+        properties:
+          policies: ["topology-staging"]
+      - name: prod
+        type: deploy
+        properties:
+          policies: ["topology-prod", "override-prod"]
+      - name: all
+        type: deploy
+        properties:
+          policies: ["topology-staging", "topology-prod"]
+`
+
+func TestExportApplication(t *testing.T) {
+	r := require.New(t)
+	def, err := oamutil.UnMarshalStringToComponentDefinition(readDataFromFile("./testdata/cd-myworker.yaml"))
+	r.NoError(err)
+	cd, err := oamutil.Object2Unstructured(def)
+	r.NoError(err)
+	cli := fake.NewClientBuilder().WithScheme(common.Scheme).Build()
+	opt := NewDryRunOption(cli, nil, nil, &packages.PackageDiscover{}, []oam.Object{cd}, false)
+	app := &v1beta1.Application{}
+	r.NoError(yaml.Unmarshal([]byte(exportApp), app))
+	ctx := context.Background()
+
+	files, err := opt.ExportApplication(ctx, app, ExportTargetHelm)
+	r.NoError(err)
+	r.Contains(files, "Chart.yaml")
+	values := map[string]interface{}{}
+	r.NoError(yaml.Unmarshal(files["values.yaml"], &values))
+	r.Equal(map[string]interface{}{"worker": map[string]interface{}{"image": "busybox"}}, values)
+	r.Contains(files, "templates/deployment-worker.yaml")
+	template := string(files["templates/deployment-worker.yaml"])
+	r.Contains(template, `image: {{ index .Values "worker" "image" | quote }}`)
+	r.Contains(template, `namespace: {{ .Release.Namespace }}`)
+	r.Contains(string(files["values-staging.yaml"]), "# Install the chart into namespace staging")
+	r.Contains(string(files["values-staging.yaml"]), "image: busybox")
+	r.Contains(string(files["values-prod.yaml"]), "# Install the chart into namespace prod")
+	r.Contains(string(files["values-prod.yaml"]), "image: busybox:prod")
+	r.NotContains(string(files["values-prod.yaml"]), "cannot be expressed")
+	r.Contains(files, "values-all-local-staging.yaml")
+	r.Contains(files, "values-all-local-prod.yaml")
+
+	files, err = opt.ExportApplication(ctx, app, ExportTargetKustomize)
+	r.NoError(err)
+	r.Contains(files, "base/kustomization.yaml")
+	r.Contains(files, "base/deployment-worker.yaml")
+	r.Contains(string(files["overlays/staging/kustomization.yaml"]), "namespace: staging")
+	r.NotContains(string(files["overlays/staging/kustomization.yaml"]), "patches")
+	r.Contains(string(files["overlays/prod/kustomization.yaml"]), "namespace: prod")
+	r.Contains(string(files["overlays/prod/patch-deployment-worker.yaml"]), "value: busybox:prod")
+	r.Contains(string(files["overlays/all/local-staging/kustomization.yaml"]), "namespace: staging")
+	r.Contains(string(files["overlays/all/local-staging/kustomization.yaml"]), "../../../base")
+	r.Contains(string(files["overlays/all/local-prod/kustomization.yaml"]), "namespace: prod")
+	r.NotContains(files, "overlays/all/kustomization.yaml")
+
+	_, err = opt.ExportApplication(ctx, app, "unknown")
+	r.Error(err)
+}
+
+func TestRenderHelmTemplate(t *testing.T) {
+	r := require.New(t)
+	data := map[string]interface{}{}
+	refs := helmRefs{}
+	for i := 0; i < 12; i++ {
+		key := fmt.Sprintf("key%d", i)
+		data[key] = "value"
+		path := valuePath{"data", key}
+		refs[path.String()] = &helmRef{path: path, parts: []interface{}{valuePath{key}}, quote: true}
+	}
+	obj := &unstructured.Unstructured{Object: map[string]interface{}{"apiVersion": "v1", "kind": "ConfigMap", "data": data}}
+	bs, err := renderHelmTemplate(obj, refs)
+	r.NoError(err)
+	for i := 0; i < 12; i++ {
+		r.Contains(string(bs), fmt.Sprintf(`key%d: {{ index .Values "key%d" | quote }}`, i, i))
+	}
+	r.NotContains(string(bs), "VELA-HELM-VALUE")
+}
+
+func TestPlacementValues(t *testing.T) {
+	r := require.New(t)
+	image := valuePath{"worker", "image"}
+	tag := valuePath{"worker", "tag"}
+	values := map[string]interface{}{"worker": map[string]interface{}{"image": "nginx", "tag": "1.21", "replicas": float64(1)}}
+	newDeployment := func(image string, replicas int64) *unstructured.Unstructured {
+		return &unstructured.Unstructured{Object: map[string]interface{}{
+			"apiVersion": "apps/v1",
+			"kind":       "Deployment",
+			"metadata": map[string]interface{}{
+				"name":   "worker",
+				"labels": map[string]interface{}{oam.LabelAppComponent: "worker"},
+			},
+			"spec": map[string]interface{}{
+				"replicas": replicas,
+				"template": map[string]interface{}{"spec": map[string]interface{}{
+					"containers": []interface{}{map[string]interface{}{"image": image}},
+				}},
+			},
+		}}
+	}
+	base := newDeployment("nginx:1.21", 1)
+	refs := helmRefs{
+		"spec.template.spec.containers.0.image": &helmRef{
+			path:  valuePath{"spec", "template", "spec", "containers", 0, "image"},
+			parts: []interface{}{image, ":", tag},
+			quote: true,
+		},
+	}
+	baseRefs := map[string]helmRefs{objectKey(base): refs}
+	placement := &PlacementManifests{Components: []*ReplicaManifest{{
+		ComponentManifest: &types.ComponentManifest{StandardWorkload: newDeployment("nginx:1.23", 3)},
+	}}}
+
+	placed, diffs, err := placementValues(values, []*unstructured.Unstructured{base}, baseRefs, placement)
+	r.NoError(err)
+	r.Equal("1.23", getValue(placed, tag))
+	r.Equal("nginx", getValue(placed, image))
+	r.Equal([]string{"Deployment worker: replace /spec/replicas"}, diffs)
+	r.Equal("1.21", getValue(values, tag))
+}
+
+func TestMatchProbe(t *testing.T) {
+	r := require.New(t)
+	repo, tag := "nginx", "1.21"
+	repoProbe, tagProbe := probeValue(repo, 0), probeValue(tag, 1)
+	before := "nginx:1.21"
+	after := fmt.Sprintf("%s:%s", repoProbe, tagProbe)
+	r.True(matchProbe(repo, repoProbe, before, after))
+	r.True(matchProbe(tag, tagProbe, before, after))
+	r.False(matchProbe("other", probeValue("other", 2), before, after))
+	r.True(matchProbe(float64(3), probeValue(float64(3), 4), float64(3), float64(8)))
+	r.False(matchProbe(float64(3), probeValue(float64(3), 4), float64(3), float64(4)))
+
+	refs := helmRefs{}
+	fieldPath := valuePath{"image"}
+	bindHelmRef(refs, "image", fieldPath, valuePath{"worker", "repo"}, repo, before)
+	bindHelmRef(refs, "image", fieldPath, valuePath{"worker", "tag"}, tag, before)
+	r.Equal(`{{ printf "%v%v%v" (index .Values "worker" "repo") ":" (index .Values "worker" "tag") | quote }}`, refs["image"].template())
+}
@@ -0,0 +1,361 @@
+/*
+Copyright 2022 The KubeVela Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package dryrun
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"strings"
+
+	pkgmulticluster "github.com/kubevela/pkg/multicluster"
+	prismclusterv1alpha1 "github.com/kubevela/prism/pkg/apis/cluster/v1alpha1"
+	workflowv1alpha1 "github.com/kubevela/workflow/api/v1alpha1"
+	wftypes "github.com/kubevela/workflow/pkg/types"
+	clusterv1alpha1 "github.com/oam-dev/cluster-gateway/pkg/apis/cluster/v1alpha1"
+	clustercommon "github.com/oam-dev/cluster-gateway/pkg/common"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/oam-dev/kubevela/apis/core.oam.dev/common"
+	"github.com/oam-dev/kubevela/apis/core.oam.dev/v1alpha1"
+	"github.com/oam-dev/kubevela/apis/core.oam.dev/v1beta1"
+	"github.com/oam-dev/kubevela/apis/types"
+	"github.com/oam-dev/kubevela/pkg/appfile"
+	velaprocess "github.com/oam-dev/kubevela/pkg/cue/process"
+	"github.com/oam-dev/kubevela/pkg/oam"
+	oamutil "github.com/oam-dev/kubevela/pkg/oam/util"
+	pkgpolicy "github.com/oam-dev/kubevela/pkg/policy"
+	"github.com/oam-dev/kubevela/pkg/utils"
+	"github.com/oam-dev/kubevela/pkg/workflow/providers/multicluster"
+	"github.com/oam-dev/kubevela/pkg/workflow/step"
+)
+
+// FakeCluster is a cluster used in dry-run, topology policies select clusters from
+// the fake clusters instead of the clusters joined to the control plane
+type FakeCluster struct {
+	Name   string            `json:"name"`
+	Labels map[string]string `json:"labels,omitempty"`
+}
+
+// ParseFakeCluster parse fake cluster from the format `name` or `name:key1=value1,key2=value2`
+func ParseFakeCluster(s string) (FakeCluster, error) {
+	name, labelStr, _ := strings.Cut(s, ":")
+	cluster := FakeCluster{Name: strings.TrimSpace(name)}
+	if cluster.Name == "" {
+		return cluster, errors.Errorf("invalid cluster %q, cluster name must not be empty", s)
+	}
+	if labelStr == "" {
+		return cluster, nil
+	}
+	cluster.Labels = map[string]string{}
+	for _, kv := range strings.Split(labelStr, ",") {
+		k, v, found := strings.Cut(kv, "=")
+		if !found || strings.TrimSpace(k) == "" {
+			return cluster, errors.Errorf("invalid label %q for cluster %s, expect key=value", kv, cluster.Name)
+		}
+		cluster.Labels[strings.TrimSpace(k)] = strings.TrimSpace(v)
+	}
+	return cluster, nil
+}
+
+// Secret returns the cluster secret that registers the fake cluster, it can be
+// preloaded into a fake client so that clusters can be discovered offline
+func (c FakeCluster) Secret() *corev1.Secret {
+	labels := map[string]string{
+		clustercommon.LabelKeyClusterEndpointType:   string(clusterv1alpha1.ClusterEndpointTypeConst),
+		clustercommon.LabelKeyClusterCredentialType: string(clusterv1alpha1.CredentialTypeX509Certificate),
+	}
+	for k, v := range c.Labels {
+		labels[k] = v
+	}
+	return &corev1.Secret{
+		TypeMeta: metav1.TypeMeta{APIVersion: "v1", Kind: "Secret"},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      c.Name,
+			Namespace: prismclusterv1alpha1.StorageNamespace,
+			Labels:    labels,
+		},
+	}
+}
+
+// WorkflowStepManifests contains the manifests rendered by one workflow step
+type WorkflowStepManifests struct {
+	Name string
+	Type string
+	// Placements contains the components rendered for each cluster and namespace,
+	// it is empty if the step does not dispatch components
+	Placements []*PlacementManifests
+}
+
+// PlacementManifests contains the components rendered for one placement
+type PlacementManifests struct {
+	v1alpha1.PlacementDecision
+	Components []*ReplicaManifest
+}
+
+// ReplicaManifest is the component rendered with the replica key set by replication policies
+type ReplicaManifest struct {
+	*types.ComponentManifest
+	ReplicaKey string
+}
+
+// RequireWorkflowDryRun checks if the application needs to be dry-run with its workflow,
+// which is true when it has explicit workflow steps or policies that change the dispatched
+// components per cluster
+func RequireWorkflowDryRun(app *v1beta1.Application) bool {
+	if app.Spec.Workflow != nil && len(app.Spec.Workflow.Steps) > 0 {
+		return true
+	}
+	for _, policy := range app.Spec.Policies {
+		if isPlacementPolicy(policy.Type) {
+			return true
+		}
+	}
+	return false
+}
+
+func isPlacementPolicy(policyType string) bool {
+	switch policyType {
+	case v1alpha1.TopologyPolicyType, v1alpha1.OverridePolicyType, v1alpha1.ReplicationPolicyType:
+		return true
+	default:
+		return false
+	}
+}
+
+// ExecuteWorkflowDryRun simulates the workflow of the application. For each deploy and
+// apply-component step, topology policies are evaluated against the clusters registered
+// in the client, override and replication policies are applied to the components and the
+// components are rendered for each selected cluster and namespace. Steps of other types
+// are returned without placements.
+func (d *Option) ExecuteWorkflowDryRun(ctx context.Context, application *v1beta1.Application) ([]*WorkflowStepManifests, []*unstructured.Unstructured, error) {
+	ctx = contextWithAppNamespace(ctx, application)
+	r, policyManifests, err := d.newWorkflowDryRunner(ctx, application)
+	if err != nil {
+		return nil, nil, err
+	}
+	steps, err := generateWorkflowSteps(r.app)
+	if err != nil {
+		return nil, nil, errors.WithMessage(err, "cannot generate workflow steps")
+	}
+	var results []*WorkflowStepManifests
+	for _, s := range steps {
+		stepResults, err := r.dryRunStep(ctx, s.WorkflowStepBase, s.SubSteps)
+		if err != nil {
+			return nil, nil, errors.WithMessagef(err, "dry-run workflow step %s", s.Name)
+		}
+		results = append(results, stepResults...)
+	}
+	return results, policyManifests, nil
+}
+
+func (d *Option) newWorkflowDryRunner(ctx context.Context, application *v1beta1.Application) (*workflowDryRunner, []*unstructured.Unstructured, error) {
+	app := application.DeepCopy()
+	if app.Namespace == "" {
+		app.Namespace = corev1.NamespaceDefault
+	}
+	parser := appfile.NewDryRunApplicationParser(d.Client, d.DiscoveryMapper, d.PackageDiscover, d.Auxiliaries)
+
+	// placement related policies and workflow steps are evaluated by dry-run itself,
+	// the appfile is only used to render components and the other policies
+	base := app.DeepCopy()
+	base.Spec.Components = nil
+	base.Spec.Workflow = nil
+	base.Spec.Policies = nil
+	for _, policy := range app.Spec.Policies {
+		if !isPlacementPolicy(policy.Type) {
+			base.Spec.Policies = append(base.Spec.Policies, policy)
+		}
+	}
+	af, err := parser.GenerateAppFileFromApp(ctx, base)
+	if err != nil {
+		return nil, nil, errors.WithMessage(err, "cannot generate appFile from application")
+	}
+	af.Components = app.Spec.Components
+	policyManifests, err := af.GeneratePolicyManifests(ctx)
+	if err != nil {
+		return nil, nil, errors.WithMessage(err, "cannot generate manifests from policies")
+	}
+	return &workflowDryRunner{Option: d, parser: parser, af: af, app: app}, policyManifests, nil
+}
+
+func contextWithAppNamespace(ctx context.Context, app *v1beta1.Application) context.Context {
+	if app.Namespace == "" {
+		return oamutil.SetNamespaceInCtx(ctx, corev1.NamespaceDefault)
+	}
+	return oamutil.SetNamespaceInCtx(ctx, app.Namespace)
+}
+
+func generateWorkflowSteps(app *v1beta1.Application) ([]workflowv1alpha1.WorkflowStep, error) {
+	var steps []workflowv1alpha1.WorkflowStep
+	if app.Spec.Workflow != nil {
+		if app.Spec.Workflow.Ref != "" {
+			return nil, errors.Errorf("workflow ref %s is not supported in dry-run", app.Spec.Workflow.Ref)
+		}
+		steps = app.Spec.Workflow.Steps
+	}
+	return step.NewChainWorkflowStepGenerator(
+		&step.DeployWorkflowStepGenerator{},
+		&step.ApplyComponentWorkflowStepGenerator{},
+	).Generate(app, steps)
+}
+
+type workflowDryRunner struct {
+	*Option
+	parser *appfile.Parser
+	af     *appfile.Appfile
+	app    *v1beta1.Application
+}
+
+func (r *workflowDryRunner) dryRunStep(ctx context.Context, s workflowv1alpha1.WorkflowStepBase, subSteps []workflowv1alpha1.WorkflowStepBase) ([]*WorkflowStepManifests, error) {
+	result := &WorkflowStepManifests{Name: s.Name, Type: s.Type}
+	var components []common.ApplicationComponent
+	var placements []v1alpha1.PlacementDecision
+	switch s.Type {
+	case wftypes.WorkflowStepTypeStepGroup:
+		results := []*WorkflowStepManifests{result}
+		for _, sub := range subSteps {
+			subResults, err := r.dryRunStep(ctx, sub, nil)
+			if err != nil {
+				return nil, errors.WithMessagef(err, "dry-run sub step %s", sub.Name)
+			}
+			results = append(results, subResults...)
+		}
+		return results, nil
+	case "deploy":
+		spec := &step.DeployWorkflowStepSpec{}
+		if s.Properties != nil {
+			if err := utils.StrictUnmarshal(s.Properties.Raw, spec); err != nil {
+				return nil, errors.Wrapf(err, "failed to parse deploy step properties")
+			}
+		}
+		policies, err := multicluster.SelectPolicies(r.app.Spec.Policies, spec.Policies)
+		if err != nil {
+			return nil, err
+		}
+		if placements, err = pkgpolicy.GetPlacementsFromTopologyPolicies(ctx, r.Client, r.app.Namespace, policies, true); err != nil {
+			return nil, err
+		}
+		if components, err = multicluster.OverrideConfiguration(policies, r.app.Spec.Components); err != nil {
+			return nil, err
+		}
+		if components, err = pkgpolicy.ReplicateComponents(policies, components); err != nil {
+			return nil, err
+		}
+	case wftypes.WorkflowStepTypeApplyComponent, wftypes.WorkflowStepTypeBuiltinApplyComponent:
+		spec := struct {
+			Component string `json:"component"`
+			Cluster   string `json:"cluster,omitempty"`
+			Namespace string `json:"namespace,omitempty"`
+		}{}
+		if s.Properties != nil {
+			if err := utils.StrictUnmarshal(s.Properties.Raw, &spec); err != nil {
+				return nil, errors.Wrapf(err, "failed to parse apply-component step properties")
+			}
+		}
+		for _, comp := range r.app.Spec.Components {
+			if comp.Name == spec.Component {
+				components = append(components, comp)
+			}
+		}
+		if len(components) == 0 {
+			return nil, errors.Errorf("component %s not found", spec.Component)
+		}
+		if spec.Cluster == "" {
+			spec.Cluster = pkgmulticluster.Local
+		}
+		placements = []v1alpha1.PlacementDecision{{Cluster: spec.Cluster, Namespace: spec.Namespace}}
+	default:
+		return []*WorkflowStepManifests{result}, nil
+	}
+	for _, placement := range placements {
+		comps, err := r.renderComponents(ctx, components, placement)
+		if err != nil {
+			return nil, errors.WithMessagef(err, "render components for cluster %s", placement.Cluster)
+		}
+		result.Placements = append(result.Placements, &PlacementManifests{PlacementDecision: placement, Components: comps})
+	}
+	return []*WorkflowStepManifests{result}, nil
+}
+
+func (r *workflowDryRunner) renderComponents(ctx context.Context, components []common.ApplicationComponent, placement v1alpha1.PlacementDecision) ([]*ReplicaManifest, error) {
+	var manifests []*ReplicaManifest
+	for _, comp := range components {
+		wl, err := r.parser.ParseWorkload(ctx, comp)
+		if err != nil {
+			return nil, errors.WithMessagef(err, "parse component %s", comp.Name)
+		}
+		manifest, err := r.af.GenerateComponentManifest(wl, func(ctxData *velaprocess.ContextData) {
+			if placement.Namespace != "" {
+				ctxData.Namespace = placement.Namespace
+			}
+			ctxData.Cluster = placement.Cluster
+			ctxData.ReplicaKey = comp.ReplicaKey
+		})
+		if err != nil {
+			return nil, errors.WithMessagef(err, "generate manifests for component %s", comp.Name)
+		}
+		if err = r.af.SetOAMContract(manifest); err != nil {
+			return nil, err
+		}
+		for _, obj := range append([]*unstructured.Unstructured{manifest.StandardWorkload}, manifest.Traits...) {
+			if obj == nil {
+				continue
+			}
+			oam.SetCluster(obj, placement.Cluster)
+			if placement.Namespace != "" {
+				obj.SetNamespace(placement.Namespace)
+			}
+		}
+		manifests = append(manifests, &ReplicaManifest{ComponentManifest: manifest, ReplicaKey: comp.ReplicaKey})
+	}
+	return manifests, nil
+}
+
+// PrintWorkflowDryRun prints the result of workflow dry-run grouped by workflow step and placement
+func (d *Option) PrintWorkflowDryRun(buff *bytes.Buffer, app *v1beta1.Application, steps []*WorkflowStepManifests, policies []*unstructured.Unstructured) error {
+	for _, s := range steps {
+		if len(s.Placements) == 0 {
+			if _, err := fmt.Fprintf(buff, "---\n# Application(%s) -- WorkflowStep(%s) -- Type(%s) dispatches no components in dry-run \n---\n\n", app.Name, s.Name, s.Type); err != nil {
+				return errors.Wrap(err, "fail to write buff")
+			}
+			continue
+		}
+		for _, placement := range s.Placements {
+			namespace := placement.Namespace
+			if namespace == "" {
+				namespace = app.Namespace
+			}
+			if namespace == "" {
+				namespace = corev1.NamespaceDefault
+			}
+			for _, c := range placement.Components {
+				header := fmt.Sprintf("WorkflowStep(%s) -- Cluster(%s) -- Namespace(%s) -- Component(%s)", s.Name, placement.Cluster, namespace, c.Name)
+				if c.ReplicaKey != "" {
+					header += fmt.Sprintf(" -- Replica(%s)", c.ReplicaKey)
+				}
+				if err := printComponent(buff, app.Name, header, c.ComponentManifest); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	return printPolicies(buff, app.Name, policies)
+}
@@ -0,0 +1,172 @@
+/*
+Copyright 2022 The KubeVela Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package dryrun
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/kubevela/workflow/pkg/cue/packages"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/yaml"
+
+	"github.com/oam-dev/kubevela/apis/core.oam.dev/v1beta1"
+	"github.com/oam-dev/kubevela/pkg/oam"
+	oamutil "github.com/oam-dev/kubevela/pkg/oam/util"
+	"github.com/oam-dev/kubevela/pkg/utils/common"
+)
+
+func TestParseFakeCluster(t *testing.T) {
+	testCases := map[string]struct {
+		input    string
+		expected FakeCluster
+		hasError bool
+	}{
+		"name-only":     {input: "beijing", expected: FakeCluster{Name: "beijing"}},
+		"with-labels":   {input: "hangzhou:region=east, env=prod", expected: FakeCluster{Name: "hangzhou", Labels: map[string]string{"region": "east", "env": "prod"}}},
+		"empty-name":    {input: ":region=east", hasError: true},
+		"invalid-label": {input: "hangzhou:region", hasError: true},
+	}
+	for name, tt := range testCases {
+		t.Run(name, func(t *testing.T) {
+			cluster, err := ParseFakeCluster(tt.input)
+			if tt.hasError {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.expected, cluster)
+		})
+	}
+}
+
+const workflowDryRunApp = `
+apiVersion: core.oam.dev/v1beta1
+kind: Application
+metadata:
+  name: app
+spec:
+  components:
+    - name: worker
+      type: myworker
+      properties:
+        image: busybox
+  policies:
+    - name: topology-east
+      type: topology
+      properties:
+        clusterLabelSelector:
+          region: east
+        namespace: prod
+    - name: override-prod
+      type: override
+      properties:
+        components:
+          - name: worker
+            properties:
+              image: busybox:prod
+    - name: replication
+      type: replication
+      properties:
+        keys: ["a", "b"]
+        selector: ["worker"]
+  workflow:
+    steps:
+      - name: apply-local
+        type: apply-component
+        properties:
+          component: worker
+      - name: approve
+        type: suspend
+      - name: group
+        type: step-group
+        subSteps:
+          - name: deploy-east
+            type: deploy
+            properties:
+              policies: ["topology-east", "override-prod", "replication"]
+`
+
+func TestExecuteWorkflowDryRun(t *testing.T) {
+	r := require.New(t)
+	def, err := oamutil.UnMarshalStringToComponentDefinition(readDataFromFile("./testdata/cd-myworker.yaml"))
+	r.NoError(err)
+	cd, err := oamutil.Object2Unstructured(def)
+	r.NoError(err)
+	cli := fake.NewClientBuilder().WithScheme(common.Scheme).WithObjects(
+		FakeCluster{Name: "hangzhou", Labels: map[string]string{"region": "east"}}.Secret(),
+		FakeCluster{Name: "shanghai", Labels: map[string]string{"region": "east"}}.Secret(),
+		FakeCluster{Name: "beijing", Labels: map[string]string{"region": "north"}}.Secret(),
+	).Build()
+	opt := NewDryRunOption(cli, nil, nil, &packages.PackageDiscover{}, []oam.Object{cd}, false)
+
+	app := &v1beta1.Application{}
+	r.NoError(yaml.Unmarshal([]byte(workflowDryRunApp), app))
+	r.True(RequireWorkflowDryRun(app))
+	steps, _, err := opt.ExecuteWorkflowDryRun(context.Background(), app)
+	r.NoError(err)
+	r.Len(steps, 4)
+
+	r.Equal("apply-local", steps[0].Name)
+	r.Len(steps[0].Placements, 1)
+	r.Equal("local", steps[0].Placements[0].Cluster)
+	workload := steps[0].Placements[0].Components[0].StandardWorkload
+	r.Equal("default", workload.GetNamespace())
+	r.Equal("busybox", containerImage(t, workload))
+
+	r.Empty(steps[1].Placements)
+	r.Empty(steps[2].Placements)
+
+	r.Equal("deploy-east", steps[3].Name)
+	r.Len(steps[3].Placements, 2)
+	for _, placement := range steps[3].Placements {
+		r.Contains([]string{"hangzhou", "shanghai"}, placement.Cluster)
+		r.Len(placement.Components, 2)
+		for _, comp := range placement.Components {
+			r.Contains([]string{"a", "b"}, comp.ReplicaKey)
+			r.Equal("prod", comp.StandardWorkload.GetNamespace())
+			r.Equal(placement.Cluster, oam.GetCluster(comp.StandardWorkload))
+			r.Equal("busybox:prod", containerImage(t, comp.StandardWorkload))
+		}
+	}
+
+	buff := &bytes.Buffer{}
+	r.NoError(opt.PrintWorkflowDryRun(buff, app, steps, nil))
+	r.Contains(buff.String(), "# Application(app) -- WorkflowStep(deploy-east) -- Cluster(hangzhou) -- Namespace(prod) -- Component(worker) -- Replica(a)")
+	r.Contains(buff.String(), "# Application(app) -- WorkflowStep(approve) -- Type(suspend) dispatches no components in dry-run")
+
+	report := NewWorkflowDryRunReport(app.Name, steps, nil)
+	r.Len(report.Objects, 5)
+	r.Equal("apply-local", report.Objects[0].WorkflowStep)
+	r.Equal("worker", report.Objects[0].Component)
+	r.Equal("deploy-east", report.Objects[1].WorkflowStep)
+	r.NotEmpty(report.Objects[1].ReplicaKey)
+
+	app.Spec.Policies[0].Properties = oamutil.Object2RawExtension(map[string]interface{}{"clusterLabelSelector": map[string]string{"region": "west"}})
+	_, _, err = opt.ExecuteWorkflowDryRun(context.Background(), app)
+	r.Error(err)
+}
+
+func containerImage(t *testing.T, workload *unstructured.Unstructured) string {
+	containers, _, err := unstructured.NestedSlice(workload.Object, "spec", "template", "spec", "containers")
+	require.NoError(t, err)
+	require.NotEmpty(t, containers)
+	return containers[0].(map[string]interface{})["image"].(string)
+}
@@ -17,6 +17,7 @@ limitations under the License.
 package dryrun
 
 import (
+	"bytes"
 	"fmt"
 	"io"
 	"math"
@@ -96,7 +97,47 @@ func (r *ReportDiffOption) printDiffReport(diff *DiffEntry, prefix string) {
 	}
 }
 
+// DiffReport is the machine-readable report of the diff result
+type DiffReport struct {
+	// HasChanges indicates if there is any object added, modified or removed
+	HasChanges bool             `json:"hasChanges"`
+	Diff       *DiffReportEntry `json:"diff"`
+}
+
+// DiffReportEntry is the machine-readable diff of one OAM object and its sub-resources
+type DiffReportEntry struct {
+	Name     string       `json:"name"`
+	Kind     ManifestKind `json:"kind"`
+	DiffType DiffType     `json:"diffType,omitempty"`
+	// Diff is the diff of the object in unified format
+	Diff string             `json:"diff,omitempty"`
+	Subs []*DiffReportEntry `json:"subs,omitempty"`
+}
+
+// NewDiffReport converts the diff result into machine-readable report, the diff of each
+// object only contains lines within the given number of context lines around changes
+func NewDiffReport(diff *DiffEntry, ctx int) *DiffReport {
+	return &DiffReport{HasChanges: diff.HasChanges(), Diff: newDiffReportEntry(diff, ctx)}
+}
+
+func newDiffReportEntry(diff *DiffEntry, ctx int) *DiffReportEntry {
+	entry := &DiffReportEntry{Name: diff.Name, Kind: diff.Kind, DiffType: diff.DiffType}
+	if diff.DiffType != NoDiff {
+		buff := &bytes.Buffer{}
+		writeDiffs(diff.Diffs, ctx, buff, writeDiffRecord)
+		entry.Diff = buff.String()
+	}
+	for _, sub := range diff.Subs {
+		entry.Subs = append(entry.Subs, newDiffReportEntry(sub, ctx))
+	}
+	return entry
+}
+
 func printDiffs(diffs []difflib.DiffRecord, context int, to io.Writer) {
+	writeDiffs(diffs, context, to, printDiffRecord)
+}
+
+func writeDiffs(diffs []difflib.DiffRecord, context int, to io.Writer, writeRecord func(io.Writer, difflib.DiffRecord)) {
 	if context > 0 {
 		ctx := calculateContext(diffs)
 		skip := false
@@ -104,7 +145,7 @@ func printDiffs(diffs []difflib.DiffRecord, context int, to io.Writer) {
 			if ctx[i] <= context {
 				// only print the line whose distance to a closest diff is less
 				// than context
-				printDiffRecord(to, diff)
+				writeRecord(to, diff)
 				skip = false
 			} else if !skip {
 				fmt.Fprint(to, "...\n")
@@ -115,7 +156,7 @@ func printDiffs(diffs []difflib.DiffRecord, context int, to io.Writer) {
 		}
 	} else {
 		for _, diff := range diffs {
-			printDiffRecord(to, diff)
+			writeRecord(to, diff)
 		}
 	}
 }
@@ -164,3 +205,15 @@ func printDiffRecord(to io.Writer, diff difflib.DiffRecord) {
 		_, _ = fmt.Fprintf(to, "  %s\n", data)
 	}
 }
+
+// writeDiffRecord writes the diff record without color
+func writeDiffRecord(to io.Writer, diff difflib.DiffRecord) {
+	switch diff.Delta {
+	case difflib.RightOnly:
+		_, _ = fmt.Fprintf(to, "+ %s\n", diff.Payload)
+	case difflib.LeftOnly:
+		_, _ = fmt.Fprintf(to, "- %s\n", diff.Payload)
+	case difflib.Common:
+		_, _ = fmt.Fprintf(to, "  %s\n", diff.Payload)
+	}
+}
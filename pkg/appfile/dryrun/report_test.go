@@ -0,0 +1,60 @@
+/*
+Copyright 2022 The KubeVela Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package dryrun
+
+import (
+	"testing"
+
+	"github.com/aryann/difflib"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewDiffReport(t *testing.T) {
+	r := require.New(t)
+	diff := &DiffEntry{Name: "app", Kind: AppKind, Subs: []*DiffEntry{{
+		Name: "web",
+		Kind: AppConfigCompKind,
+		Subs: []*DiffEntry{{
+			Name:     "web",
+			Kind:     RawCompKind,
+			DiffType: ModifyDiff,
+			Diffs: []difflib.DiffRecord{
+				{Payload: "kind: Deployment", Delta: difflib.Common},
+				{Payload: "metadata:", Delta: difflib.Common},
+				{Payload: "  name: web", Delta: difflib.Common},
+				{Payload: "replicas: 1", Delta: difflib.LeftOnly},
+				{Payload: "replicas: 2", Delta: difflib.RightOnly},
+			},
+		}, {
+			Name: "web-ingress",
+			Kind: TraitKind,
+		}},
+	}}}
+	r.True(diff.HasChanges())
+	report := NewDiffReport(diff, 1)
+	r.True(report.HasChanges)
+	r.Equal("app", report.Diff.Name)
+	r.Empty(report.Diff.Diff)
+	comp := report.Diff.Subs[0].Subs[0]
+	r.Equal(ModifyDiff, comp.DiffType)
+	r.Equal("...\n    name: web\n- replicas: 1\n+ replicas: 2\n", comp.Diff)
+	r.Empty(report.Diff.Subs[0].Subs[1].Diff)
+
+	diff.Subs[0].Subs[0].DiffType = NoDiff
+	r.False(diff.HasChanges())
+	r.False(NewDiffReport(diff, -1).HasChanges)
+}
@@ -119,7 +119,7 @@ func (p *Parser) GenerateAppFileFromApp(ctx context.Context, app *v1beta1.Applic
 
 	var wds []*Workload
 	for _, comp := range app.Spec.Components {
-		wd, err := p.parseWorkload(ctx, comp)
+		wd, err := p.ParseWorkload(ctx, comp)
 		if err != nil {
 			return nil, err
 		}
@@ -389,6 +389,8 @@ func (p *Parser) parsePoliciesFromRevision(ctx context.Context, af *Appfile) (er
 		case v1alpha1.GarbageCollectPolicyType:
 		case v1alpha1.ApplyOncePolicyType:
 		case v1alpha1.SharedResourcePolicyType:
+		case v1alpha1.DeployWindowPolicyType:
+		case v1alpha1.ServerSideApplyPolicyType:
 		case v1alpha1.EnvBindingPolicyType:
 		case v1alpha1.TopologyPolicyType:
 		case v1alpha1.OverridePolicyType:
@@ -418,6 +420,8 @@ func (p *Parser) parsePolicies(ctx context.Context, af *Appfile) (err error) {
 		case v1alpha1.GarbageCollectPolicyType:
 		case v1alpha1.ApplyOncePolicyType:
 		case v1alpha1.SharedResourcePolicyType:
+		case v1alpha1.DeployWindowPolicyType:
+		case v1alpha1.ServerSideApplyPolicyType:
 		case v1alpha1.EnvBindingPolicyType:
 		case v1alpha1.TopologyPolicyType:
 		case v1alpha1.ReplicationPolicyType:
@@ -562,9 +566,9 @@ func (p *Parser) convertTemplate2Workload(name, typ string, props *runtime.RawEx
 	}, nil
 }
 
-// parseWorkload resolve an ApplicationComponent and generate a Workload
+// ParseWorkload resolve an ApplicationComponent and generate a Workload
 // containing ALL information required by an Appfile.
-func (p *Parser) parseWorkload(ctx context.Context, comp common.ApplicationComponent) (*Workload, error) {
+func (p *Parser) ParseWorkload(ctx context.Context, comp common.ApplicationComponent) (*Workload, error) {
 	workload, err := p.makeWorkload(ctx, comp.Name, comp.Type, types.TypeComponentDefinition, comp.Properties)
 	if err != nil {
 		return nil, err
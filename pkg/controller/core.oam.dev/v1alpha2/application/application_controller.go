@@ -52,10 +52,12 @@ import (
 	"github.com/oam-dev/kubevela/apis/core.oam.dev/condition"
 	"github.com/oam-dev/kubevela/apis/core.oam.dev/v1beta1"
 	velatypes "github.com/oam-dev/kubevela/apis/types"
+	"github.com/oam-dev/kubevela/pkg/appdependency"
 	"github.com/oam-dev/kubevela/pkg/appfile"
 	"github.com/oam-dev/kubevela/pkg/auth"
 	common2 "github.com/oam-dev/kubevela/pkg/controller/common"
 	core "github.com/oam-dev/kubevela/pkg/controller/core.oam.dev"
+	"github.com/oam-dev/kubevela/pkg/controller/sharding"
 	"github.com/oam-dev/kubevela/pkg/features"
 	"github.com/oam-dev/kubevela/pkg/monitor/metrics"
 	"github.com/oam-dev/kubevela/pkg/oam"
@@ -89,10 +91,11 @@ var (
 // Reconciler reconciles an Application object
 type Reconciler struct {
 	client.Client
-	dm       discoverymapper.DiscoveryMapper
-	pd       *packages.PackageDiscover
-	Scheme   *runtime.Scheme
-	Recorder event.Recorder
+	apiReader client.Reader
+	dm        discoverymapper.DiscoveryMapper
+	pd        *packages.PackageDiscover
+	Scheme    *runtime.Scheme
+	Recorder  event.Recorder
 	options
 }
 
@@ -131,6 +134,11 @@ func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Resu
 		return ctrl.Result{}, nil
 	}
 
+	if sharding.EnableSharding && sharding.GetScheduledShardID(app) != sharding.GetShardID() {
+		logCtx.Info("skip app: not scheduled to the current controller shard")
+		return ctrl.Result{}, nil
+	}
+
 	timeReporter := timeReconcile(app)
 	defer timeReporter()
 
@@ -187,6 +195,10 @@ func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Resu
 	}
 	logCtx.Info("Successfully apply application revision")
 
+	if endReconcile, result, err := r.checkDependencies(logCtx, app, handler.currentAppRev.Name); endReconcile {
+		return result, err
+	}
+
 	if err := handler.ApplyPolicies(logCtx, appFile); err != nil {
 		logCtx.Error(err, "[Handle ApplyPolicies]")
 		r.Recorder.Event(app, event.Warning(velatypes.ReasonFailedApply, err))
@@ -377,6 +389,9 @@ func (r *Reconciler) handleFinalizers(ctx monitorContext.Context, app *v1beta1.A
 				metrics.HandleFinalizersDurationHistogram.WithLabelValues("application", "remove").Observe(v)
 			}))
 			defer subCtx.Commit("finish remove finalizers")
+			if endReconcile, result, err := r.waitDependentsDeleted(subCtx, app); endReconcile {
+				return true, result, err
+			}
 			rootRT, currentRT, historyRTs, cvRT, err := resourcetracker.ListApplicationResourceTrackers(ctx, r.Client, app)
 			if err != nil {
 				return r.result(err).end(true)
@@ -483,10 +498,24 @@ func isHealthy(services []common.ApplicationComponentStatus) bool {
 
 // SetupWithManager install to manager
 func (r *Reconciler) SetupWithManager(mgr ctrl.Manager) error {
+	if err := mgr.GetFieldIndexer().IndexField(context.Background(), &v1beta1.Application{}, appdependency.IndexKey, appdependency.IndexFunc); err != nil {
+		return err
+	}
+	var dependencySource source.Source = &source.Kind{Type: &v1beta1.Application{}}
+	if sharding.EnableSharding {
+		// every shard reconciles the applications scheduled to it regardless of the leader election
+		mgr = sharding.WithoutLeaderElection(mgr)
+		// the depended applications might be scheduled to other shards, so the metadata of all the applications
+		// are watched to enqueue the dependents on the current shard
+		if sharding.DefaultApplicationMetadataCache != nil {
+			dependencySource = source.NewKindWithCache(sharding.ApplicationMetadata(), sharding.DefaultApplicationMetadataCache)
+		}
+	}
 	return ctrl.NewControllerManagedBy(mgr).
 		Watches(&source.Kind{
 			Type: &v1beta1.ResourceTracker{},
 		}, ctrlHandler.EnqueueRequestsFromMapFunc(findObjectForResourceTracker)).
+		Watches(dependencySource, ctrlHandler.EnqueueRequestsFromMapFunc(r.findDependentApplications)).
 		WithOptions(controller.Options{
 			MaxConcurrentReconciles: r.concurrentReconciles,
 		}).
@@ -552,12 +581,13 @@ func (r *Reconciler) SetupWithManager(mgr ctrl.Manager) error {
 // Setup adds a controller that reconciles AppRollout.
 func Setup(mgr ctrl.Manager, args core.Args) error {
 	reconciler := Reconciler{
-		Client:   mgr.GetClient(),
-		Scheme:   mgr.GetScheme(),
-		Recorder: event.NewAPIRecorder(mgr.GetEventRecorderFor("Application")),
-		dm:       args.DiscoveryMapper,
-		pd:       args.PackageDiscover,
-		options:  parseOptions(args),
+		Client:    mgr.GetClient(),
+		apiReader: mgr.GetAPIReader(),
+		Scheme:    mgr.GetScheme(),
+		Recorder:  event.NewAPIRecorder(mgr.GetEventRecorderFor("Application")),
+		dm:        args.DiscoveryMapper,
+		pd:        args.PackageDiscover,
+		options:   parseOptions(args),
 	}
 	return reconciler.SetupWithManager(mgr)
 }
@@ -0,0 +1,150 @@
+/*
+Copyright 2022 The KubeVela Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package application
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	monitorContext "github.com/kubevela/pkg/monitor/context"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"github.com/oam-dev/kubevela/apis/core.oam.dev/common"
+	"github.com/oam-dev/kubevela/apis/core.oam.dev/condition"
+	"github.com/oam-dev/kubevela/apis/core.oam.dev/v1beta1"
+	"github.com/oam-dev/kubevela/pkg/appdependency"
+	"github.com/oam-dev/kubevela/pkg/controller/sharding"
+)
+
+const (
+	// dependencyCheckWaitTime is the time to wait before re-checking the depended applications which are not ready,
+	// in case the changes of the depended applications are missed by the watch
+	dependencyCheckWaitTime = 10 * time.Second
+
+	// ReasonDependencyNotReady means the depended applications are not ready
+	ReasonDependencyNotReady condition.ConditionReason = "DependencyNotReady"
+	// ReasonWaitingDependents means the application is waiting for the deleting dependents to be deleted
+	ReasonWaitingDependents condition.ConditionReason = "WaitingDependents"
+)
+
+// dependencyReader return the reader for reading the depended applications. In sharding mode, the depended
+// applications might be scheduled to other shards and not exist in the cache.
+func (r *Reconciler) dependencyReader() client.Reader {
+	if sharding.EnableSharding && r.apiReader != nil {
+		return r.apiReader
+	}
+	return r.Client
+}
+
+// findDependents return the applications depending on the application. In sharding mode, the dependents might be
+// scheduled to other shards, so all the applications are listed through the uncached reader.
+func (r *Reconciler) findDependents(ctx context.Context, app *v1beta1.Application) ([]v1beta1.Application, error) {
+	if sharding.EnableSharding && r.apiReader != nil {
+		return appdependency.ListDependents(ctx, r.apiReader, app)
+	}
+	return appdependency.FindDependents(ctx, r.Client, app)
+}
+
+// checkDependencies ensures the depended applications are ready before the workflow starts. The dependencies are
+// only checked when the workflow is going to (re)start, so that the running workflow will not be blocked.
+func (r *Reconciler) checkDependencies(ctx monitorContext.Context, app *v1beta1.Application, revName string) (bool, ctrl.Result, error) {
+	condType := condition.ConditionType(common.DependencyCondition.String())
+	if len(app.Spec.DependsOn) == 0 {
+		removeCondition(app, condType)
+		return false, ctrl.Result{}, nil
+	}
+	if _, restart := needRestart(app, revName); !restart {
+		return false, ctrl.Result{}, nil
+	}
+	blocking, err := appdependency.Check(ctx, r.dependencyReader(), app)
+	if err != nil {
+		result, err := r.endWithNegativeCondition(ctx, app, condition.ErrorCondition(common.DependencyCondition.String(), err), common.ApplicationStarting)
+		return true, result, err
+	}
+	if len(blocking) > 0 {
+		ctx.Info("Waiting for depended applications", "blocking", blocking)
+		app.Status.SetConditions(condition.Condition{
+			Type:               condType,
+			Status:             corev1.ConditionFalse,
+			LastTransitionTime: metav1.Now(),
+			Reason:             ReasonDependencyNotReady,
+			Message:            "waiting for " + strings.Join(blocking, "; "),
+		})
+		return r.result(r.patchStatus(ctx, app, common.ApplicationStarting)).requeue(dependencyCheckWaitTime).end(true)
+	}
+	app.Status.SetConditions(condition.ReadyCondition(common.DependencyCondition.String()))
+	return false, ctrl.Result{}, nil
+}
+
+// waitDependentsDeleted makes the application deleted after the applications depending on it, if they are deleted
+// at the same time. The dependents which are not being deleted will not block the deletion.
+func (r *Reconciler) waitDependentsDeleted(ctx monitorContext.Context, app *v1beta1.Application) (bool, ctrl.Result, error) {
+	dependents, err := r.findDependents(ctx, app)
+	if err != nil {
+		return r.result(err).end(true)
+	}
+	var deleting []string
+	for _, dependent := range dependents {
+		if dependent.DeletionTimestamp != nil {
+			deleting = append(deleting, client.ObjectKeyFromObject(&dependent).String())
+		}
+	}
+	if len(deleting) == 0 {
+		return false, ctrl.Result{}, nil
+	}
+	ctx.Info("Waiting for dependents to be deleted", "dependents", deleting)
+	app.Status.SetConditions(condition.Condition{
+		Type:               condition.ConditionType(common.DependencyCondition.String()),
+		Status:             corev1.ConditionFalse,
+		LastTransitionTime: metav1.Now(),
+		Reason:             ReasonWaitingDependents,
+		Message:            fmt.Sprintf("waiting for dependents to be deleted: %s", strings.Join(deleting, ", ")),
+	})
+	return r.result(r.patchStatus(ctx, app, common.ApplicationDeleting)).requeue(baseGCBackoffWaitTime).end(true)
+}
+
+// findDependentApplications enqueue the applications depending on the changed application. The changed application
+// might be the metadata only in sharding mode, and only the dependents on the current shard are enqueued.
+func (r *Reconciler) findDependentApplications(obj client.Object) []reconcile.Request {
+	app := &v1beta1.Application{}
+	app.SetName(obj.GetName())
+	app.SetNamespace(obj.GetNamespace())
+	dependents, err := appdependency.FindDependents(context.Background(), r.Client, app)
+	if err != nil {
+		return nil
+	}
+	var requests []reconcile.Request
+	for _, app := range dependents {
+		requests = append(requests, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(&app)})
+	}
+	return requests
+}
+
+func removeCondition(app *v1beta1.Application, condType condition.ConditionType) {
+	for i, cond := range app.Status.Conditions {
+		if cond.Type == condType {
+			app.Status.Conditions = append(app.Status.Conditions[:i], app.Status.Conditions[i+1:]...)
+			return
+		}
+	}
+}
@@ -0,0 +1,118 @@
+/*
+Copyright 2022 The KubeVela Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package application
+
+import (
+	"context"
+	"testing"
+
+	monitorContext "github.com/kubevela/pkg/monitor/context"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/oam-dev/kubevela/apis/core.oam.dev/common"
+	"github.com/oam-dev/kubevela/apis/core.oam.dev/condition"
+	"github.com/oam-dev/kubevela/apis/core.oam.dev/v1beta1"
+	"github.com/oam-dev/kubevela/pkg/controller/sharding"
+	utilcommon "github.com/oam-dev/kubevela/pkg/utils/common"
+)
+
+func TestCheckDependencies(t *testing.T) {
+	r := require.New(t)
+	ctx := monitorContext.NewTraceContext(context.Background(), "")
+	db := &v1beta1.Application{
+		ObjectMeta: metav1.ObjectMeta{Name: "db", Namespace: "default", Generation: 1},
+		Status:     common.AppStatus{ObservedGeneration: 1, Phase: common.ApplicationRunningWorkflow},
+	}
+	web := &v1beta1.Application{
+		ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "default", Generation: 1},
+		Spec:       v1beta1.ApplicationSpec{DependsOn: []v1beta1.AppDependency{{Name: "db"}}},
+	}
+	cli := fake.NewClientBuilder().WithScheme(utilcommon.Scheme).WithObjects(db, web).Build()
+	reconciler := &Reconciler{Client: cli}
+
+	// the workflow is blocked and re-checked later while the depended application is not ready
+	endReconcile, result, err := reconciler.checkDependencies(ctx, web, "web-v1")
+	r.NoError(err)
+	r.True(endReconcile)
+	r.Equal(dependencyCheckWaitTime, result.RequeueAfter)
+	cond := web.Status.GetCondition(condition.ConditionType(common.DependencyCondition.String()))
+	r.Equal(corev1.ConditionFalse, cond.Status)
+	r.Equal(ReasonDependencyNotReady, cond.Reason)
+	r.Contains(cond.Message, "default/db: application is not healthy")
+	r.NoError(cli.Get(ctx, client.ObjectKeyFromObject(web), web))
+	r.Equal(common.ApplicationStarting, web.Status.Phase)
+
+	// the workflow goes on once the depended application is ready
+	db.Status.Phase = common.ApplicationRunning
+	r.NoError(cli.Status().Update(ctx, db))
+	endReconcile, _, err = reconciler.checkDependencies(ctx, web, "web-v1")
+	r.NoError(err)
+	r.False(endReconcile)
+	cond = web.Status.GetCondition(condition.ConditionType(common.DependencyCondition.String()))
+	r.Equal(corev1.ConditionTrue, cond.Status)
+
+	// the dependencies are not checked again for the running workflow
+	web.Status.Workflow = &common.WorkflowStatus{AppRevision: "web-v1"}
+	db.Status.Phase = common.ApplicationRunningWorkflow
+	r.NoError(cli.Status().Update(ctx, db))
+	endReconcile, _, err = reconciler.checkDependencies(ctx, web, "web-v1")
+	r.NoError(err)
+	r.False(endReconcile)
+}
+
+func TestWaitDependentsDeleted(t *testing.T) {
+	r := require.New(t)
+	enableSharding := sharding.EnableSharding
+	sharding.EnableSharding = true
+	defer func() { sharding.EnableSharding = enableSharding }()
+
+	ctx := monitorContext.NewTraceContext(context.Background(), "")
+	now := metav1.Now()
+	db := &v1beta1.Application{ObjectMeta: metav1.ObjectMeta{
+		Name: "db", Namespace: "default", DeletionTimestamp: &now, Finalizers: []string{resourceTrackerFinalizer}}}
+	web := &v1beta1.Application{
+		ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "default", DeletionTimestamp: &now, Finalizers: []string{resourceTrackerFinalizer}},
+		Spec:       v1beta1.ApplicationSpec{DependsOn: []v1beta1.AppDependency{{Name: "db"}}},
+	}
+	cli := fake.NewClientBuilder().WithScheme(utilcommon.Scheme).WithObjects(db, web).Build()
+	reconciler := &Reconciler{Client: cli, apiReader: cli}
+
+	// the depended application waits for the deleting dependents
+	endReconcile, result, err := reconciler.waitDependentsDeleted(ctx, db)
+	r.NoError(err)
+	r.True(endReconcile)
+	r.Equal(baseGCBackoffWaitTime, result.RequeueAfter)
+	cond := db.Status.GetCondition(condition.ConditionType(common.DependencyCondition.String()))
+	r.Equal(ReasonWaitingDependents, cond.Reason)
+	r.Contains(cond.Message, "default/web")
+
+	// the dependent has no dependents to wait for
+	endReconcile, _, err = reconciler.waitDependentsDeleted(ctx, web)
+	r.NoError(err)
+	r.False(endReconcile)
+
+	// the depended application goes on deleting once the dependents are gone
+	web.Finalizers = nil
+	r.NoError(cli.Update(ctx, web))
+	endReconcile, _, err = reconciler.waitDependentsDeleted(ctx, db)
+	r.NoError(err)
+	r.False(endReconcile)
+}
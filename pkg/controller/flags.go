@@ -26,6 +26,7 @@ import (
 	"github.com/oam-dev/kubevela/pkg/auth"
 	"github.com/oam-dev/kubevela/pkg/component"
 	"github.com/oam-dev/kubevela/pkg/controller/core.oam.dev/v1alpha2/application"
+	"github.com/oam-dev/kubevela/pkg/controller/sharding"
 	"github.com/oam-dev/kubevela/pkg/resourcekeeper"
 	"github.com/oam-dev/kubevela/pkg/resourcetracker"
 	"github.com/oam-dev/kubevela/pkg/workflow"
@@ -54,6 +55,11 @@ func AddOptimizeFlags() {
 func AddAdmissionFlags() {
 	flag.BoolVar(&resourcekeeper.AllowCrossNamespaceResource, "allow-cross-namespace-resource", true, "If set to false, application can only apply resources within its namespace. Default to be true.")
 	flag.StringVar(&resourcekeeper.AllowResourceTypes, "allow-resource-types", "", "If not empty, application can only apply resources with specified types. For example, --allow-resource-types=whitelist:Deployment.v1.apps,Job.v1.batch")
+	flag.StringVar(&resourcekeeper.ApplicationQuotaCPU, "application-quota-cpu", "", "If not empty, the total cpu requests of the workloads dispatched by one application cannot exceed it. For example, --application-quota-cpu=4")
+	flag.StringVar(&resourcekeeper.ApplicationQuotaMemory, "application-quota-memory", "", "If not empty, the total memory requests of the workloads dispatched by one application cannot exceed it. For example, --application-quota-memory=8Gi")
+	flag.Int64Var(&resourcekeeper.ApplicationQuotaReplicas, "application-quota-replicas", 0, "If larger than 0, the total replicas of the workloads dispatched by one application cannot exceed it.")
+	flag.Int64Var(&resourcekeeper.ApplicationQuotaManagedResources, "application-quota-managed-resources", 0, "If larger than 0, the number of resources managed by one application cannot exceed it.")
+	flag.StringVar(&resourcekeeper.ApplicationQuotaConfigMapName, "application-quota-configmap", resourcekeeper.ApplicationQuotaConfigMapName, "The name of the ConfigMap in the system namespace that overrides the application quota for each namespace. The key is the namespace and the value is the quota in YAML format, like `cpu: 4`. Set it to empty to disable the per-namespace quota.")
 	flag.StringVar(&component.RefObjectsAvailableScope, "ref-objects-available-scope", component.RefObjectsAvailableScopeGlobal, "The available scope for ref-objects component to refer objects. Should be one of `namespace`, `cluster`, `global`")
 
 	// auth flags
@@ -61,3 +67,12 @@ func AddAdmissionFlags() {
 	flag.StringVar(&auth.AuthenticationDefaultUser, "authentication-default-user", types.KubeVelaName+":"+types.VelaCoreName, "The User to impersonate when the User of application is not set.")
 	flag.StringVar(&auth.AuthenticationGroupPattern, "authentication-group-pattern", auth.DefaultAuthenticateGroupPattern, "During authentication, only groups with specified pattern will be carried on application. Resource requests will be impersonated as these selected groups.")
 }
+
+// AddShardingFlags add flags
+func AddShardingFlags() {
+	flag.BoolVar(&sharding.EnableSharding, "enable-sharding", false, "Enable the sharding mode of the application controller. Each controller replica only reconciles the applications scheduled to it, and the applications are rebalanced automatically when replicas join or leave.")
+	flag.StringVar(&sharding.ShardID, "shard-id", "", "The id of the current controller shard. Default to be the hostname, which is the pod name in Kubernetes.")
+	flag.DurationVar(&sharding.LeaseDuration, "shard-lease-duration", sharding.LeaseDuration, "The duration that a controller shard is regarded as alive after its last heartbeat.")
+	flag.DurationVar(&sharding.HeartbeatInterval, "shard-heartbeat-interval", sharding.HeartbeatInterval, "The interval for a controller shard to renew its lease and refresh the alive shards. It should be smaller than the shard-lease-duration.")
+	flag.DurationVar(&sharding.RebalanceInterval, "shard-rebalance-interval", sharding.RebalanceInterval, "The interval for re-checking the scheduling of all applications when the alive shards are not changed.")
+}
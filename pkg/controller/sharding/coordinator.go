@@ -0,0 +1,325 @@
+/*
+Copyright 2022 The KubeVela Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sharding
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	coordinationv1 "k8s.io/api/coordination/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	toolscache "k8s.io/client-go/tools/cache"
+	"k8s.io/klog/v2"
+	"k8s.io/utils/pointer"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/oam-dev/kubevela/apis/core.oam.dev/v1beta1"
+	"github.com/oam-dev/kubevela/pkg/oam"
+)
+
+const shardLeasePrefix = "vela-shard-"
+
+var (
+	// LeaseDuration the duration that the shard is regarded as alive after its last heartbeat
+	LeaseDuration = 30 * time.Second
+	// HeartbeatInterval the interval for the shard to renew its lease and refresh the alive shards
+	HeartbeatInterval = 10 * time.Second
+	// RebalanceInterval the interval for re-checking the scheduling of all applications, even if the shards
+	// are not changed. This picks up the applications missed by the mutating webhook and the application cache.
+	RebalanceInterval = 5 * time.Minute
+)
+
+// Coordinator coordinates the controller shards through leases. Each shard renews its own lease as heartbeat,
+// and the shards with unexpired leases are regarded as alive. When alive shards change, the alive shard with the
+// smallest id rebalances the applications, which moves the applications on the left shards and the hash-scheduled
+// applications that should be moved to the joined shards.
+type Coordinator struct {
+	cli    client.Client
+	reader client.Reader
+	apps   cache.Cache
+	id     string
+
+	mu            sync.RWMutex
+	synced        bool
+	shards        []string
+	lastRebalance time.Time
+	now           func() time.Time
+}
+
+// NewCoordinator create coordinator for the given shard. The reader should be an uncached reader since the cache
+// of the shard only contains the applications scheduled to it.
+func NewCoordinator(cli client.Client, reader client.Reader, shardID string) *Coordinator {
+	return &Coordinator{cli: cli, reader: reader, id: shardID, now: time.Now}
+}
+
+// WithApplicationCache set the cache which watches the metadata of all the applications, so that the applications
+// created without the shard label, like when the mutating webhook is disabled, are scheduled as soon as they are
+// seen instead of waiting for the next rebalance
+func (c *Coordinator) WithApplicationCache(apps cache.Cache) *Coordinator {
+	c.apps = apps
+	return c
+}
+
+// NeedLeaderElection implements manager.LeaderElectionRunnable, all shards run the coordinator
+func (c *Coordinator) NeedLeaderElection() bool {
+	return false
+}
+
+// Start runs the heartbeat loop until the context is done, then releases the lease so that the applications on
+// this shard can be moved to other shards immediately
+func (c *Coordinator) Start(ctx context.Context) error {
+	if c.apps != nil {
+		informer, err := c.apps.GetInformer(ctx, ApplicationMetadata())
+		if err != nil {
+			return errors.Wrapf(err, "failed to get the informer of applications")
+		}
+		informer.AddEventHandler(toolscache.ResourceEventHandlerFuncs{
+			AddFunc:    func(obj interface{}) { c.scheduleNewApplication(ctx, obj) },
+			UpdateFunc: func(_, obj interface{}) { c.scheduleNewApplication(ctx, obj) },
+		})
+	}
+	ticker := time.NewTicker(HeartbeatInterval)
+	defer ticker.Stop()
+	for {
+		if err := c.sync(ctx); err != nil {
+			klog.ErrorS(err, "failed to sync controller shards", "shard", c.id)
+		}
+		select {
+		case <-ctx.Done():
+			c.release()
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// Shards return the alive shards
+func (c *Coordinator) Shards() []string {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return append([]string{}, c.shards...)
+}
+
+// IsAlive check if the shard is alive
+func (c *Coordinator) IsAlive(shardID string) bool {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return containsShard(c.shards, shardID)
+}
+
+// Schedule return the shard that the application should be scheduled to. The shard label set on the application
+// is respected if the shard is alive, otherwise the shard is picked by the hash of the application namespace and
+// name. Empty string will be returned if there is no alive shard.
+func (c *Coordinator) Schedule(app *v1beta1.Application) string {
+	if shardID := GetScheduledShardID(app); shardID != "" && c.IsAlive(shardID) {
+		return shardID
+	}
+	return Rendezvous(client.ObjectKeyFromObject(app).String(), c.Shards())
+}
+
+// scheduleNewApplication schedules the application without the shard label. Like the rebalancing, only the alive
+// shard with the smallest id does it to avoid conflicts. The applications seen before the first sync are left to
+// the rebalancing on the first sync.
+func (c *Coordinator) scheduleNewApplication(ctx context.Context, obj interface{}) {
+	app, ok := obj.(client.Object)
+	if !ok || GetScheduledShardID(app) != "" || app.GetDeletionTimestamp() != nil {
+		return
+	}
+	shards := c.Shards()
+	if len(shards) == 0 || shards[0] != c.id {
+		return
+	}
+	key := client.ObjectKeyFromObject(app).String()
+	target := Rendezvous(key, shards)
+	if err := c.setScheduledShardID(ctx, app.DeepCopyObject().(client.Object), target); err != nil {
+		klog.ErrorS(err, "failed to schedule application", "application", key, "shard", target)
+	}
+}
+
+func (c *Coordinator) sync(ctx context.Context) error {
+	if err := c.heartbeat(ctx); err != nil {
+		return errors.Wrapf(err, "failed to renew lease")
+	}
+	shards, err := c.listAliveShards(ctx)
+	if err != nil {
+		return errors.Wrapf(err, "failed to list alive shards")
+	}
+	c.mu.Lock()
+	previous := c.shards
+	if !c.synced {
+		// regard the current shard as joined on the first sync
+		previous = nil
+		for _, shard := range shards {
+			if shard != c.id {
+				previous = append(previous, shard)
+			}
+		}
+		c.synced = true
+	}
+	c.shards = shards
+	changed := !equalShards(previous, shards)
+	due := c.now().Sub(c.lastRebalance) >= RebalanceInterval
+	c.mu.Unlock()
+	if changed {
+		klog.InfoS("controller shards changed", "shard", c.id, "previous", previous, "current", shards)
+	}
+	// only the alive shard with the smallest id does the rebalancing to avoid conflicts
+	if len(shards) == 0 || shards[0] != c.id || (!changed && !due) {
+		return nil
+	}
+	if err = c.rebalance(ctx, previous, shards); err != nil {
+		return errors.Wrapf(err, "failed to rebalance applications")
+	}
+	c.mu.Lock()
+	c.lastRebalance = c.now()
+	c.mu.Unlock()
+	return nil
+}
+
+func (c *Coordinator) heartbeat(ctx context.Context) error {
+	lease := &coordinationv1.Lease{}
+	key := client.ObjectKey{Namespace: oam.SystemDefinitionNamespace, Name: shardLeasePrefix + c.id}
+	now := metav1.NewMicroTime(c.now())
+	if err := c.reader.Get(ctx, key, lease); err != nil {
+		if !kerrors.IsNotFound(err) {
+			return err
+		}
+		lease = &coordinationv1.Lease{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      key.Name,
+				Namespace: key.Namespace,
+				Labels:    map[string]string{oam.LabelControllerShard: "true"},
+			},
+			Spec: coordinationv1.LeaseSpec{
+				HolderIdentity:       pointer.String(c.id),
+				LeaseDurationSeconds: pointer.Int32(int32(LeaseDuration.Seconds())),
+				AcquireTime:          &now,
+				RenewTime:            &now,
+			},
+		}
+		return c.cli.Create(ctx, lease)
+	}
+	lease.Spec.HolderIdentity = pointer.String(c.id)
+	lease.Spec.LeaseDurationSeconds = pointer.Int32(int32(LeaseDuration.Seconds()))
+	lease.Spec.RenewTime = &now
+	return c.cli.Update(ctx, lease)
+}
+
+func (c *Coordinator) release() {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	lease := &coordinationv1.Lease{ObjectMeta: metav1.ObjectMeta{Namespace: oam.SystemDefinitionNamespace, Name: shardLeasePrefix + c.id}}
+	if err := c.cli.Delete(ctx, lease); client.IgnoreNotFound(err) != nil {
+		klog.ErrorS(err, "failed to release the lease of controller shard", "shard", c.id)
+	}
+}
+
+func (c *Coordinator) listAliveShards(ctx context.Context) ([]string, error) {
+	leases := &coordinationv1.LeaseList{}
+	if err := c.reader.List(ctx, leases, client.InNamespace(oam.SystemDefinitionNamespace), client.MatchingLabels{oam.LabelControllerShard: "true"}); err != nil {
+		return nil, err
+	}
+	var shards []string
+	for _, lease := range leases.Items {
+		spec := lease.Spec
+		if spec.HolderIdentity == nil || spec.RenewTime == nil || spec.LeaseDurationSeconds == nil {
+			continue
+		}
+		if spec.RenewTime.Add(time.Duration(*spec.LeaseDurationSeconds) * time.Second).After(c.now()) {
+			shards = append(shards, *spec.HolderIdentity)
+		}
+	}
+	sort.Strings(shards)
+	return shards, nil
+}
+
+// rebalance reschedules the applications that are not scheduled, scheduled to the shards not alive, or scheduled
+// by hash but the hash result changes due to joined shards. The applications scheduled manually to alive shards
+// will not be moved.
+func (c *Coordinator) rebalance(ctx context.Context, previous []string, shards []string) error {
+	apps := &v1beta1.ApplicationList{}
+	if err := c.reader.List(ctx, apps); err != nil {
+		return err
+	}
+	moved := 0
+	for i := range apps.Items {
+		app := &apps.Items[i]
+		key := client.ObjectKeyFromObject(app).String()
+		current := GetScheduledShardID(app)
+		target := Rendezvous(key, shards)
+		if current == target || (containsShard(shards, current) && current != Rendezvous(key, previous)) {
+			continue
+		}
+		if err := c.reschedule(ctx, app, target); err != nil {
+			return errors.Wrapf(err, "failed to reschedule application %s from shard %q to %q", key, current, target)
+		}
+		moved++
+	}
+	if moved > 0 {
+		klog.InfoS("applications rebalanced", "shard", c.id, "moved", moved, "shards", shards)
+	}
+	return nil
+}
+
+// reschedule moves the resourcetrackers of the application first, then the application itself. Therefore, once
+// the application is seen by the target shard, its resourcetrackers are already there.
+func (c *Coordinator) reschedule(ctx context.Context, app *v1beta1.Application, shardID string) error {
+	rts := &v1beta1.ResourceTrackerList{}
+	if err := c.reader.List(ctx, rts, client.MatchingLabels{
+		oam.LabelAppName:      app.Name,
+		oam.LabelAppNamespace: app.Namespace,
+	}); err != nil {
+		return err
+	}
+	for i := range rts.Items {
+		if err := c.setScheduledShardID(ctx, &rts.Items[i], shardID); err != nil {
+			return err
+		}
+	}
+	return c.setScheduledShardID(ctx, app, shardID)
+}
+
+func (c *Coordinator) setScheduledShardID(ctx context.Context, obj client.Object, shardID string) error {
+	if GetScheduledShardID(obj) == shardID {
+		return nil
+	}
+	patch := client.MergeFrom(obj.DeepCopyObject().(client.Object))
+	SetScheduledShardID(obj, shardID)
+	return client.IgnoreNotFound(c.cli.Patch(ctx, obj, patch))
+}
+
+func containsShard(shards []string, shardID string) bool {
+	idx := sort.SearchStrings(shards, shardID)
+	return idx < len(shards) && shards[idx] == shardID
+}
+
+func equalShards(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
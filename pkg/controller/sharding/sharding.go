@@ -0,0 +1,173 @@
+/*
+Copyright 2022 The KubeVela Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sharding
+
+import (
+	"hash/fnv"
+	"os"
+
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/selection"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+
+	"github.com/oam-dev/kubevela/apis/core.oam.dev/v1beta1"
+	"github.com/oam-dev/kubevela/pkg/oam"
+)
+
+var (
+	// EnableSharding enable the sharding mode of the application controller. In sharding mode, each controller
+	// replica only reconciles the applications scheduled to it.
+	EnableSharding = false
+	// ShardID the id of the current controller shard. If empty, the hostname will be used.
+	ShardID = ""
+	// DefaultCoordinator the coordinator of the current controller shard, only set in sharding mode
+	DefaultCoordinator *Coordinator
+	// DefaultApplicationMetadataCache the cache of the metadata of all the applications regardless of the shards,
+	// only set in sharding mode
+	DefaultApplicationMetadataCache cache.Cache
+)
+
+// InitShardID initialize the id of the current controller shard with the hostname if it is not set. It should be called
+// once at startup before the controllers run.
+func InitShardID() error {
+	if ShardID != "" {
+		return nil
+	}
+	hostname, err := os.Hostname()
+	if err != nil {
+		return errors.Wrapf(err, "failed to get hostname as the shard id")
+	}
+	ShardID = hostname
+	return nil
+}
+
+// GetShardID return the id of the current controller shard
+func GetShardID() string {
+	return ShardID
+}
+
+// WithoutLeaderElection wraps the manager so that the controllers set up with it run on every shard instead of only
+// on the leader. It is used by the application controller in sharding mode, while the other controllers still run on
+// the leader only.
+func WithoutLeaderElection(mgr manager.Manager) manager.Manager {
+	return nonLeaderElectionManager{Manager: mgr}
+}
+
+type nonLeaderElectionManager struct {
+	manager.Manager
+}
+
+// Add adds the runnable to the manager without leader election
+func (m nonLeaderElectionManager) Add(r manager.Runnable) error {
+	return m.Manager.Add(nonLeaderElectionRunnable{Runnable: r})
+}
+
+type nonLeaderElectionRunnable struct {
+	manager.Runnable
+}
+
+// NeedLeaderElection implements manager.LeaderElectionRunnable
+func (r nonLeaderElectionRunnable) NeedLeaderElection() bool {
+	return false
+}
+
+// GetScheduledShardID return the id of the shard that the object is scheduled to
+func GetScheduledShardID(obj client.Object) string {
+	if labels := obj.GetLabels(); labels != nil {
+		return labels[oam.LabelScheduledShardID]
+	}
+	return ""
+}
+
+// SetScheduledShardID set the id of the shard that the object is scheduled to
+func SetScheduledShardID(obj client.Object, shardID string) {
+	labels := obj.GetLabels()
+	if labels == nil {
+		labels = map[string]string{}
+	}
+	labels[oam.LabelScheduledShardID] = shardID
+	obj.SetLabels(labels)
+}
+
+// PropagateScheduledShardID copy the scheduled shard id from the source object (the application) to the target
+// object (like the resourcetracker), so that the target can be watched by the cache of the same shard
+func PropagateScheduledShardID(from client.Object, to client.Object) {
+	if shardID := GetScheduledShardID(from); shardID != "" {
+		SetScheduledShardID(to, shardID)
+	}
+}
+
+// BuildCache return the cache builder which only watches the applications and resourcetrackers scheduled to the
+// given shard. Other types of objects are not affected.
+func BuildCache(shardID string) cache.NewCacheFunc {
+	requirement, _ := labels.NewRequirement(oam.LabelScheduledShardID, selection.Equals, []string{shardID})
+	selector := cache.ObjectSelector{Label: labels.NewSelector().Add(*requirement)}
+	return cache.BuilderWithOptions(cache.Options{
+		SelectorsByObject: cache.SelectorsByObject{
+			&v1beta1.Application{}:     selector,
+			&v1beta1.ResourceTracker{}: selector,
+		},
+	})
+}
+
+// NewApplicationMetadataCache create the cache which watches the metadata of all the applications regardless of the
+// shards and add it to the manager. The cache of the manager only contains the applications scheduled to the current
+// shard, while the changes of the applications on the other shards are still needed, like for enqueueing the
+// dependents of the changed application and scheduling the new applications.
+func NewApplicationMetadataCache(mgr manager.Manager) (cache.Cache, error) {
+	c, err := cache.New(mgr.GetConfig(), cache.Options{Scheme: mgr.GetScheme(), Mapper: mgr.GetRESTMapper()})
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to create the cache of application metadata")
+	}
+	return c, mgr.Add(nonLeaderElectionRunnable{Runnable: c})
+}
+
+// ApplicationMetadata return the metadata-only object of the application to be watched through the metadata cache
+func ApplicationMetadata() *metav1.PartialObjectMetadata {
+	obj := &metav1.PartialObjectMetadata{}
+	obj.SetGroupVersionKind(v1beta1.SchemeGroupVersion.WithKind(v1beta1.ApplicationKind))
+	return obj
+}
+
+// Rendezvous return the shard for the key by the rendezvous (highest random weight) hashing. Comparing to the
+// modulo hashing, only the keys on the changed shard will be moved when shards join or leave.
+func Rendezvous(key string, shards []string) string {
+	var target string
+	var highest uint64
+	for _, shard := range shards {
+		h := fnv.New64a()
+		_, _ = h.Write([]byte(shard + "/" + key))
+		if weight := mix(h.Sum64()); target == "" || weight > highest || (weight == highest && shard < target) {
+			target, highest = shard, weight
+		}
+	}
+	return target
+}
+
+// mix is the finalizer of murmur3, which spreads the fnv hash of similar keys
+func mix(h uint64) uint64 {
+	h ^= h >> 33
+	h *= 0xff51afd7ed558ccd
+	h ^= h >> 33
+	h *= 0xc4ceb9fe1a85ec53
+	h ^= h >> 33
+	return h
+}
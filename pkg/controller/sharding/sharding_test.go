@@ -0,0 +1,231 @@
+/*
+Copyright 2022 The KubeVela Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sharding
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	coordinationv1 "k8s.io/api/coordination/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+
+	"github.com/oam-dev/kubevela/apis/core.oam.dev/v1beta1"
+	"github.com/oam-dev/kubevela/pkg/oam"
+	"github.com/oam-dev/kubevela/pkg/utils/common"
+)
+
+func TestRendezvous(t *testing.T) {
+	r := require.New(t)
+	r.Equal("", Rendezvous("default/app", nil))
+	shards := []string{"s1", "s2", "s3"}
+	moved, counts := 0, map[string]int{}
+	for i := 0; i < 300; i++ {
+		key := fmt.Sprintf("default/app-%d", i)
+		before := Rendezvous(key, shards)
+		r.Equal(before, Rendezvous(key, []string{"s3", "s1", "s2"}))
+		counts[before]++
+		// only the keys moved to the joined shard change
+		after := Rendezvous(key, append(shards, "s4"))
+		if after != before {
+			r.Equal("s4", after)
+			moved++
+		}
+	}
+	for _, shard := range shards {
+		r.Greater(counts[shard], 50)
+	}
+	r.Greater(moved, 30)
+	r.Less(moved, 120)
+}
+
+func newTestLease(shardID string, renewTime time.Time) *coordinationv1.Lease {
+	t := metav1.NewMicroTime(renewTime)
+	return &coordinationv1.Lease{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      shardLeasePrefix + shardID,
+			Namespace: oam.SystemDefinitionNamespace,
+			Labels:    map[string]string{oam.LabelControllerShard: "true"},
+		},
+		Spec: coordinationv1.LeaseSpec{
+			HolderIdentity:       &shardID,
+			LeaseDurationSeconds: &[]int32{30}[0],
+			RenewTime:            &t,
+		},
+	}
+}
+
+func newTestApp(name string, shardID string) *v1beta1.Application {
+	app := &v1beta1.Application{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default"}}
+	if shardID != "" {
+		SetScheduledShardID(app, shardID)
+	}
+	return app
+}
+
+func TestCoordinator(t *testing.T) {
+	r := require.New(t)
+	ctx := context.Background()
+	now := time.Now()
+	rt := &v1beta1.ResourceTracker{ObjectMeta: metav1.ObjectMeta{Name: "app-on-dead-default", Labels: map[string]string{
+		oam.LabelAppName:      "app-on-dead",
+		oam.LabelAppNamespace: "default",
+	}}}
+	SetScheduledShardID(rt, "s9")
+	cli := fake.NewClientBuilder().WithScheme(common.Scheme).WithObjects(
+		newTestLease("s2", now.Add(-10*time.Second)),
+		newTestLease("s9", now.Add(-time.Minute)),
+		newTestApp("app-pinned", "s2"),
+		newTestApp("app-on-dead", "s9"),
+		newTestApp("app-unscheduled", ""),
+		rt,
+	).Build()
+	c := NewCoordinator(cli, cli, "s1")
+	c.now = func() time.Time { return now }
+
+	r.NoError(c.sync(ctx))
+	r.Equal([]string{"s1", "s2"}, c.Shards())
+	r.True(c.IsAlive("s2"))
+	r.False(c.IsAlive("s9"))
+	lease := &coordinationv1.Lease{}
+	r.NoError(cli.Get(ctx, client.ObjectKey{Namespace: oam.SystemDefinitionNamespace, Name: shardLeasePrefix + "s1"}, lease))
+	r.Equal("s1", *lease.Spec.HolderIdentity)
+
+	// s1 has the smallest id and rebalances the applications
+	shards := []string{"s1", "s2"}
+	app := &v1beta1.Application{}
+	r.NoError(cli.Get(ctx, client.ObjectKey{Namespace: "default", Name: "app-pinned"}, app))
+	r.Equal("s2", GetScheduledShardID(app))
+	r.NoError(cli.Get(ctx, client.ObjectKey{Namespace: "default", Name: "app-on-dead"}, app))
+	r.Equal(Rendezvous("default/app-on-dead", shards), GetScheduledShardID(app))
+	r.NoError(cli.Get(ctx, client.ObjectKey{Name: "app-on-dead-default"}, rt))
+	r.Equal(Rendezvous("default/app-on-dead", shards), GetScheduledShardID(rt))
+	r.NoError(cli.Get(ctx, client.ObjectKey{Namespace: "default", Name: "app-unscheduled"}, app))
+	r.Equal(Rendezvous("default/app-unscheduled", shards), GetScheduledShardID(app))
+
+	// scheduling respects the label of alive shard
+	r.Equal("s2", c.Schedule(newTestApp("new", "s2")))
+	r.Equal(Rendezvous("default/new", shards), c.Schedule(newTestApp("new", "s9")))
+
+	// lease is released when stopped
+	c.release()
+	r.Error(cli.Get(ctx, client.ObjectKey{Namespace: oam.SystemDefinitionNamespace, Name: shardLeasePrefix + "s1"}, lease))
+}
+
+func TestCoordinatorRebalanceOnJoin(t *testing.T) {
+	r := require.New(t)
+	ctx := context.Background()
+	now := time.Now()
+	objs := []client.Object{newTestLease("s2", now), newTestLease("s3", now)}
+	for i := 0; i < 50; i++ {
+		name := fmt.Sprintf("app-%d", i)
+		objs = append(objs, newTestApp(name, Rendezvous("default/"+name, []string{"s2", "s3"})))
+	}
+	// manually scheduled application is not moved
+	objs = append(objs, newTestApp("app-manual", "s3"))
+	cli := fake.NewClientBuilder().WithScheme(common.Scheme).WithObjects(objs...).Build()
+	c := NewCoordinator(cli, cli, "s1")
+	c.now = func() time.Time { return now }
+	r.NoError(c.sync(ctx))
+
+	apps := &v1beta1.ApplicationList{}
+	r.NoError(cli.List(ctx, apps))
+	moved := 0
+	for _, app := range apps.Items {
+		if app.Name == "app-manual" {
+			r.Equal("s3", GetScheduledShardID(&app))
+			continue
+		}
+		r.Equal(Rendezvous("default/"+app.Name, []string{"s1", "s2", "s3"}), GetScheduledShardID(&app))
+		if GetScheduledShardID(&app) == "s1" {
+			moved++
+		}
+	}
+	r.Greater(moved, 0)
+}
+
+func TestCoordinatorScheduleNewApplication(t *testing.T) {
+	r := require.New(t)
+	ctx := context.Background()
+	now := time.Now()
+	cli := fake.NewClientBuilder().WithScheme(common.Scheme).WithObjects(
+		newTestLease("s1", now), newTestLease("s2", now),
+		newTestApp("new", ""), newTestApp("manual", "s2"),
+	).Build()
+	other := NewCoordinator(cli, cli, "s2")
+	other.now = func() time.Time { return now }
+	c := NewCoordinator(cli, cli, "s1")
+	c.now = func() time.Time { return now }
+	getShard := func(name string) string {
+		app := &v1beta1.Application{}
+		r.NoError(cli.Get(ctx, client.ObjectKey{Namespace: "default", Name: name}, app))
+		return GetScheduledShardID(app)
+	}
+
+	// the application seen before the first sync is left to the rebalancing
+	c.scheduleNewApplication(ctx, newTestApp("new", ""))
+	r.Equal("", getShard("new"))
+
+	// only the shard with the smallest id schedules the new application
+	c.shards, other.shards = []string{"s1", "s2"}, []string{"s1", "s2"}
+	other.scheduleNewApplication(ctx, newTestApp("new", ""))
+	r.Equal("", getShard("new"))
+	c.scheduleNewApplication(ctx, newTestApp("new", ""))
+	r.Equal(Rendezvous("default/new", []string{"s1", "s2"}), getShard("new"))
+
+	// the scheduled application is not moved
+	c.scheduleNewApplication(ctx, newTestApp("manual", "s2"))
+	r.Equal("s2", getShard("manual"))
+}
+
+type runnableRecorder struct {
+	manager.Manager
+	runnables []manager.Runnable
+}
+
+func (m *runnableRecorder) Add(r manager.Runnable) error {
+	m.runnables = append(m.runnables, r)
+	return nil
+}
+
+func TestWithoutLeaderElection(t *testing.T) {
+	r := require.New(t)
+	recorder := &runnableRecorder{}
+	r.NoError(WithoutLeaderElection(recorder).Add(manager.RunnableFunc(func(ctx context.Context) error { return nil })))
+	r.Len(recorder.runnables, 1)
+	runnable, ok := recorder.runnables[0].(manager.LeaderElectionRunnable)
+	r.True(ok)
+	r.False(runnable.NeedLeaderElection())
+}
+
+func TestInitShardID(t *testing.T) {
+	defer func() { ShardID = "" }()
+	r := require.New(t)
+	ShardID = "shard-1"
+	r.NoError(InitShardID())
+	r.Equal("shard-1", GetShardID())
+	ShardID = ""
+	r.NoError(InitShardID())
+	hostname, _ := os.Hostname()
+	r.Equal(hostname, GetShardID())
+}
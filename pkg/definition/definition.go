@@ -345,6 +345,29 @@ func (def *Definition) FromYAML(data []byte) error {
 
 // FromCUEString converts cue string into Definition
 func (def *Definition) FromCUEString(cueString string, config *rest.Config) error {
+	return def.fromCUEString(cueString, func(templateString string) error {
+		if config != nil {
+			pd, err := packages.NewPackageDiscover(config)
+			if err != nil {
+				return err
+			}
+			_, err = value.NewValue(templateString+"\n"+velacue.BaseTemplate, pd, "")
+			return err
+		}
+		return cuecontext.New().CompileString(templateString + "\n" + velacue.BaseTemplate).Err()
+	})
+}
+
+// FromLocalCUEString converts cue string into Definition without connecting to the cluster, the template
+// is validated with the builtin packages like vela/op, which can be imported by the template
+func (def *Definition) FromLocalCUEString(cueString string) error {
+	return def.fromCUEString(cueString, func(templateString string) error {
+		_, err := value.NewValue(templateString+"\n"+velacue.BaseTemplate, nil, "")
+		return err
+	})
+}
+
+func (def *Definition) fromCUEString(cueString string, validate func(templateString string) error) error {
 	cuectx := cuecontext.New()
 	f, err := parser.ParseFile("-", cueString, parser.ParseComments)
 	if err != nil {
@@ -404,16 +427,8 @@ func (def *Definition) FromCUEString(cueString string, config *rest.Config) erro
 		return err
 	}
 	// validate template
-	if config != nil {
-		pd, err := packages.NewPackageDiscover(config)
-		if err != nil {
-			return err
-		}
-		if _, err = value.NewValue(templateString+"\n"+velacue.BaseTemplate, pd, ""); err != nil {
-			return err
-		}
-	} else if val := cuectx.CompileString(templateString + "\n" + velacue.BaseTemplate); val.Err() != nil {
-		return val.Err()
+	if err = validate(templateString); err != nil {
+		return err
 	}
 	return def.FromCUE(&inst, templateString)
 }
@@ -218,3 +218,35 @@ func TestDefinitionRevisionSearch(t *testing.T) {
 	_, err = GetDefinitionFromDefinitionRevision(&defrev)
 	assert.NoError(t, err)
 }
+
+func TestFromLocalCUEString(t *testing.T) {
+	cueString := `
+"apply-and-wait": {
+	type: "workflow-step"
+	annotations: {}
+	labels: {}
+	description: "Apply the component and wait"
+}
+template: {
+	apply: op.#ApplyComponent & {
+		component: parameter.component
+	}
+	parameter: component: string
+}
+`
+	withImport := "import (\n\t\"vela/op\"\n)\n" + cueString
+	def := &Definition{Unstructured: unstructured.Unstructured{}}
+	if err := def.FromLocalCUEString(withImport); err != nil {
+		t.Fatalf("unexpected error when setting from cue with builtin package imported: %v", err)
+	}
+	if _type := def.GetType(); _type != "workflow-step" {
+		t.Fatalf("set type from cue invalid, expected workflow-step got %s", _type)
+	}
+	if err := def.FromLocalCUEString("abc: {}\n" + withImport); err == nil {
+		t.Fatalf("should encounter duplicated object name error but not found error")
+	}
+	// the builtin packages are not resolved without the cluster config for the other callers
+	if err := (&Definition{}).FromCUEString(withImport, nil); err == nil {
+		t.Fatalf("should encounter undefined builtin package error but not found error")
+	}
+}
@@ -45,6 +45,8 @@ type Field struct {
 	// GoType is the same to parameter.Type but can be print in Go
 	GoType    string
 	OmitEmpty bool
+	// HasDefault means the field has default value in cue, so it is not required to be set
+	HasDefault bool
 }
 
 //nolint:gochecknoglobals
@@ -138,6 +140,9 @@ func parseParameters(paraValue cue.Value, paramKey string) error {
 			name := fi.Name
 			subParam.Name = name
 			subParam.OmitEmpty = fi.IsOptional
+			if def, ok := val.Default(); ok && def.IsConcrete() {
+				subParam.HasDefault = true
+			}
 			switch val.IncompleteKind() {
 			case cue.StructKind:
 				if subField, err := val.Struct(); err == nil && subField.Len() == 0 { // err cannot be not nil,so ignore it
@@ -259,6 +264,7 @@ type AbbrFieldNamer struct {
 // SetPrefix set a prefix to namer.
 func (a *AbbrFieldNamer) SetPrefix(s string) {
 	a.Prefix = s
+	a.prefixWithFirstCharCapitalized = ""
 }
 
 // FieldName implements FieldNamer.FieldName.
@@ -250,7 +250,7 @@ var (
 			Fields: []Field{
 				{Name: "name", GoType: "string"},
 				{Name: "mountPath", GoType: "string"},
-				{Name: "medium", GoType: "string"},
+				{Name: "medium", GoType: "string", HasDefault: true},
 			},
 		},
 		{
@@ -260,8 +260,9 @@ var (
 			},
 			Fields: []Field{
 				{
-					Name:   "emptyDir",
-					GoType: "[]EmptyDir",
+					Name:       "emptyDir",
+					GoType:     "[]EmptyDir",
+					HasDefault: true,
 				},
 			},
 		},
@@ -0,0 +1,826 @@
+/*
+Copyright 2022 The KubeVela Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package definition
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"go/format"
+	"sort"
+	"strings"
+	"unicode"
+
+	"cuelang.org/go/cue"
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/kubevela/workflow/pkg/cue/packages"
+
+	"github.com/oam-dev/kubevela/apis/core.oam.dev/v1beta1"
+	velacue "github.com/oam-dev/kubevela/pkg/cue"
+	"github.com/oam-dev/kubevela/pkg/utils/common"
+)
+
+// SDKLanguage is the language of the generated SDK
+type SDKLanguage string
+
+const (
+	// SDKLanguageGo generates Go structs
+	SDKLanguageGo SDKLanguage = "go"
+	// SDKLanguageTypeScript generates TypeScript interfaces and builder functions
+	SDKLanguageTypeScript SDKLanguage = "typescript"
+	// SDKLanguageJava generates Java classes and builder methods
+	SDKLanguageJava SDKLanguage = "java"
+	// SDKLanguageJSONSchema generates the JSON schema of the Application
+	SDKLanguageJSONSchema SDKLanguage = "jsonschema"
+
+	sdkHeader = "Code generated by vela def gen-sdk. DO NOT EDIT."
+)
+
+// SDKLanguages return the supported languages of the SDK generator
+func SDKLanguages() []string {
+	return []string{string(SDKLanguageGo), string(SDKLanguageTypeScript), string(SDKLanguageJava), string(SDKLanguageJSONSchema)}
+}
+
+// sdkDefinitionTypes is the definition types supported by the SDK generator, in the order of generation
+var sdkDefinitionTypes = []string{"component", "trait", "policy", "workflow-step"}
+
+// sdkDefinition is the definition to generate SDK for
+type sdkDefinition struct {
+	name        string
+	defType     string
+	description string
+	parameter   *cue.Value
+
+	// the fields below are resolved when generating
+	prefix   string
+	typeName string
+	funcName string
+	structs  []StructParameter
+}
+
+// SDKGenerator generates the SDK of the definitions in the specified language. Besides the parameter types of each
+// definition, the builder helpers for the Application, components, traits, policies and workflow steps are
+// generated as well, except for Go.
+type SDKGenerator struct {
+	Language SDKLanguage
+	// Package is the package name of the generated code, only used for Go and Java
+	Package string
+	// ClassName is the name of the outer class of the generated Java code
+	ClassName string
+
+	definitions []*sdkDefinition
+}
+
+// NewSDKGenerator create the SDK generator for the language
+func NewSDKGenerator(lang SDKLanguage, pkg string) (*SDKGenerator, error) {
+	g := &SDKGenerator{Language: lang, Package: pkg, ClassName: "Vela"}
+	switch lang {
+	case SDKLanguageGo:
+		if g.Package == "" {
+			g.Package = "main"
+		}
+	case SDKLanguageJava:
+		if g.Package == "" {
+			g.Package = "dev.oam.vela"
+		}
+	case SDKLanguageTypeScript, SDKLanguageJSONSchema:
+	default:
+		return nil, errors.Errorf("unsupported language %s, must be one of %s", lang, strings.Join(SDKLanguages(), ", "))
+	}
+	return g, nil
+}
+
+// AddDefinition add the definition to generate SDK for. The definitions without parameter or with the parameter
+// that cannot be parsed are generated with free-form parameter type.
+func (g *SDKGenerator) AddDefinition(def *Definition, pd *packages.PackageDiscover) error {
+	defType := def.GetType()
+	supported := false
+	for _, t := range sdkDefinitionTypes {
+		supported = supported || t == defType
+	}
+	if !supported {
+		return errors.Errorf("definition type %s is not supported", defType)
+	}
+	for _, d := range g.definitions {
+		if d.name == def.GetName() && d.defType == defType {
+			return errors.Errorf("duplicated %s definition %s", defType, def.GetName())
+		}
+	}
+	template, _, err := unstructured.NestedString(def.Object, DefinitionTemplateKeys...)
+	if err != nil {
+		return err
+	}
+	d := &sdkDefinition{name: def.GetName(), defType: defType, description: def.GetAnnotations()[DescriptionKey]}
+	param, err := common.GetCUEParameterValue(template, pd)
+	switch {
+	case err == nil:
+		d.parameter = &param
+	case errors.Is(err, velacue.ErrParameterNotExist):
+	default:
+		return errors.Wrapf(err, "failed to get parameter of %s definition %s", defType, def.GetName())
+	}
+	g.definitions = append(g.definitions, d)
+	return nil
+}
+
+// Generate generate the SDK code, return the file name and the content
+func (g *SDKGenerator) Generate() (string, []byte, error) {
+	if err := g.resolve(); err != nil {
+		return "", nil, err
+	}
+	switch g.Language {
+	case SDKLanguageGo:
+		code, err := g.genGo()
+		return g.Package + ".go", code, err
+	case SDKLanguageTypeScript:
+		return "index.ts", g.genTypeScript(), nil
+	case SDKLanguageJava:
+		return g.ClassName + ".java", g.genJava(), nil
+	default:
+		bs, err := g.genJSONSchema()
+		return "application.schema.json", bs, err
+	}
+}
+
+// resolve sort the definitions and parse the parameter structs with stable names. The types of each definition are
+// prefixed by the definition name, and the type of definition is appended if the names of definitions conflict.
+func (g *SDKGenerator) resolve() error {
+	order := map[string]int{}
+	for i, t := range sdkDefinitionTypes {
+		order[t] = i
+	}
+	sort.SliceStable(g.definitions, func(i, j int) bool {
+		if g.definitions[i].defType != g.definitions[j].defType {
+			return order[g.definitions[i].defType] < order[g.definitions[j].defType]
+		}
+		return g.definitions[i].name < g.definitions[j].name
+	})
+	namer := NewFieldNamer("")
+	used := map[string]bool{}
+	prefix := DefaultNamer.(*AbbrFieldNamer).Prefix
+	defer DefaultNamer.SetPrefix(prefix)
+	for _, d := range g.definitions {
+		d.prefix = namer.FieldName(d.name)
+		d.funcName = lowerCamel(d.prefix)
+		if used[d.prefix] {
+			d.prefix = namer.FieldName(d.name + "-" + d.defType)
+			d.funcName = lowerCamel(d.prefix)
+		}
+		if reservedWords[d.funcName] {
+			d.funcName = lowerCamel(namer.FieldName(d.name + "-" + d.defType))
+		}
+		used[d.prefix] = true
+
+		DefaultNamer.SetPrefix(d.prefix)
+		d.typeName = DefaultNamer.FieldName("Parameter")
+		d.structs = nil
+		if d.parameter == nil {
+			continue
+		}
+		structs, err := GeneratorParameterStructs(*d.parameter)
+		if err != nil {
+			// fallback to free-form parameter if the parameter cannot be parsed
+			d.structs = nil
+			continue
+		}
+		names := map[string]bool{}
+		for _, s := range structs {
+			name := DefaultNamer.FieldName(s.Name)
+			if names[name] {
+				continue
+			}
+			names[name] = true
+			s.Name = name
+			d.structs = append(d.structs, s)
+		}
+	}
+	return nil
+}
+
+func (g *SDKGenerator) knownTypes() map[string]bool {
+	known := map[string]bool{}
+	for _, d := range g.definitions {
+		for _, s := range d.structs {
+			known[s.Name] = true
+		}
+	}
+	return known
+}
+
+// typeExpr is the parsed Go type of the parameter field, which can be rendered into other languages
+type typeExpr struct {
+	// kind is one of string, integer, number, boolean, list, map, ref and any
+	kind string
+	elem *typeExpr
+	ref  string
+}
+
+func parseGoType(t string, known map[string]bool) *typeExpr {
+	switch {
+	case strings.HasPrefix(t, "[]"):
+		return &typeExpr{kind: "list", elem: parseGoType(strings.TrimPrefix(t, "[]"), known)}
+	case strings.HasPrefix(t, "map[string]"):
+		return &typeExpr{kind: "map", elem: parseGoType(strings.TrimPrefix(t, "map[string]"), known)}
+	case t == "string" || t == "bytes":
+		return &typeExpr{kind: "string"}
+	case t == "int":
+		return &typeExpr{kind: "integer"}
+	case t == "float" || t == "number":
+		return &typeExpr{kind: "number"}
+	case t == "bool":
+		return &typeExpr{kind: "boolean"}
+	case known[t]:
+		return &typeExpr{kind: "ref", ref: t}
+	default:
+		return &typeExpr{kind: "any"}
+	}
+}
+
+// structType return the type of the struct parameter, which is nil if it's a normal struct with fields
+func structType(s StructParameter, known map[string]bool) *typeExpr {
+	if s.Type == cue.StructKind && !strings.HasPrefix(s.GoType, "map[string]") {
+		return nil
+	}
+	return parseGoType(s.GoType, known)
+}
+
+func (g *SDKGenerator) genGo() ([]byte, error) {
+	known := g.knownTypes()
+	namer := NewFieldNamer("")
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "// %s\n\npackage %s\n", sdkHeader, g.Package)
+	for _, d := range g.definitions {
+		if d.structs == nil {
+			fmt.Fprintf(&buf, "\n// %s\ntype %s map[string]interface{}\n", definitionComment(d), d.typeName)
+		}
+		for _, s := range d.structs {
+			if comment := structComment(d, s); comment != "" {
+				fmt.Fprintf(&buf, "\n// %s\n", comment)
+			} else {
+				fmt.Fprintf(&buf, "\n// %s -\n", s.Name)
+			}
+			if t := structType(s, known); t != nil {
+				fmt.Fprintf(&buf, "type %s %s\n", s.Name, goType(t))
+				continue
+			}
+			fmt.Fprintf(&buf, "type %s struct {\n", s.Name)
+			for _, f := range s.Fields {
+				tag := f.Name
+				if f.OmitEmpty {
+					tag += ",omitempty"
+				}
+				fmt.Fprintf(&buf, "%s %s `json:\"%s\"`\n", namer.FieldName(f.Name), goType(parseGoType(f.GoType, known)), tag)
+			}
+			buf.WriteString("}\n")
+		}
+	}
+	return format.Source(buf.Bytes())
+}
+
+func goType(t *typeExpr) string {
+	switch t.kind {
+	case "integer":
+		return "int"
+	case "number":
+		return "float64"
+	case "boolean":
+		return "bool"
+	case "string":
+		return "string"
+	case "list":
+		return "[]" + goType(t.elem)
+	case "map":
+		return "map[string]" + goType(t.elem)
+	case "ref":
+		return t.ref
+	default:
+		return "interface{}"
+	}
+}
+
+func (g *SDKGenerator) genTypeScript() []byte {
+	known := g.knownTypes()
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "// %s\n\n", sdkHeader)
+	buf.WriteString(tsBaseTypes)
+	for _, d := range g.definitions {
+		buf.WriteString("\n")
+		if d.structs == nil {
+			fmt.Fprintf(&buf, "/** %s */\nexport type %s = Record<string, any>;\n", definitionComment(d), d.typeName)
+		}
+		for _, s := range d.structs {
+			if comment := structComment(d, s); comment != "" {
+				fmt.Fprintf(&buf, "/** %s */\n", comment)
+			}
+			if t := structType(s, known); t != nil {
+				fmt.Fprintf(&buf, "export type %s = %s;\n", s.Name, tsType(t))
+				continue
+			}
+			fmt.Fprintf(&buf, "export interface %s {\n", s.Name)
+			for _, f := range s.Fields {
+				optional := ""
+				if f.OmitEmpty || f.HasDefault {
+					optional = "?"
+				}
+				fmt.Fprintf(&buf, "  %s%s: %s;\n", tsFieldName(f.Name), optional, tsType(parseGoType(f.GoType, known)))
+			}
+			buf.WriteString("}\n")
+		}
+		switch d.defType {
+		case "component":
+			fmt.Fprintf(&buf, "export function %s(name: string, properties: %s, traits?: ApplicationTrait[]): ApplicationComponent {\n  return { name, type: %q, properties, traits };\n}\n", d.funcName, d.typeName, d.name)
+		case "trait":
+			fmt.Fprintf(&buf, "export function %s(properties: %s): ApplicationTrait {\n  return { type: %q, properties };\n}\n", d.funcName, d.typeName, d.name)
+		case "policy":
+			fmt.Fprintf(&buf, "export function %s(name: string, properties: %s): AppPolicy {\n  return { name, type: %q, properties };\n}\n", d.funcName, d.typeName, d.name)
+		case "workflow-step":
+			fmt.Fprintf(&buf, "export function %s(name: string, properties: %s, dependsOn?: string[]): WorkflowStep {\n  return { name, type: %q, properties, dependsOn };\n}\n", d.funcName, d.typeName, d.name)
+		}
+	}
+	return buf.Bytes()
+}
+
+const tsBaseTypes = `export interface ApplicationComponent {
+  name: string;
+  type: string;
+  properties?: Record<string, any>;
+  traits?: ApplicationTrait[];
+  dependsOn?: string[];
+}
+
+export interface ApplicationTrait {
+  type: string;
+  properties?: Record<string, any>;
+}
+
+export interface AppPolicy {
+  name: string;
+  type: string;
+  properties?: Record<string, any>;
+}
+
+export interface WorkflowStep {
+  name: string;
+  type: string;
+  properties?: Record<string, any>;
+  dependsOn?: string[];
+}
+
+export interface Application {
+  apiVersion: "core.oam.dev/v1beta1";
+  kind: "Application";
+  metadata: {
+    name: string;
+    namespace?: string;
+    labels?: Record<string, string>;
+    annotations?: Record<string, string>;
+  };
+  spec: {
+    components: ApplicationComponent[];
+    policies?: AppPolicy[];
+    workflow?: { steps: WorkflowStep[] };
+  };
+}
+
+export interface ApplicationOptions {
+  namespace?: string;
+  labels?: Record<string, string>;
+  annotations?: Record<string, string>;
+  policies?: AppPolicy[];
+  workflowSteps?: WorkflowStep[];
+}
+
+export function application(name: string, components: ApplicationComponent[], options: ApplicationOptions = {}): Application {
+  return {
+    apiVersion: "core.oam.dev/v1beta1",
+    kind: "Application",
+    metadata: { name, namespace: options.namespace, labels: options.labels, annotations: options.annotations },
+    spec: {
+      components,
+      policies: options.policies,
+      workflow: options.workflowSteps ? { steps: options.workflowSteps } : undefined,
+    },
+  };
+}
+`
+
+func tsType(t *typeExpr) string {
+	switch t.kind {
+	case "string":
+		return "string"
+	case "integer", "number":
+		return "number"
+	case "boolean":
+		return "boolean"
+	case "list":
+		elem := tsType(t.elem)
+		if strings.Contains(elem, " ") {
+			return fmt.Sprintf("Array<%s>", elem)
+		}
+		return elem + "[]"
+	case "map":
+		return fmt.Sprintf("Record<string, %s>", tsType(t.elem))
+	case "ref":
+		return t.ref
+	default:
+		return "any"
+	}
+}
+
+func tsFieldName(name string) string {
+	for i, r := range name {
+		if !(unicode.IsLetter(r) || r == '_' || r == '$' || (i > 0 && unicode.IsDigit(r))) {
+			return fmt.Sprintf("%q", name)
+		}
+	}
+	return name
+}
+
+func (g *SDKGenerator) genJava() []byte {
+	known := g.knownTypes()
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "// %s\n\npackage %s;\n\n", sdkHeader, g.Package)
+	buf.WriteString(javaImports)
+	fmt.Fprintf(&buf, "public final class %s {\n\n  private %s() {\n  }\n", g.ClassName, g.ClassName)
+	buf.WriteString(javaBaseTypes)
+	for _, d := range g.definitions {
+		buf.WriteString("\n")
+		if d.structs == nil {
+			fmt.Fprintf(&buf, "  /** %s */\n  public static class %s extends HashMap<String, Object> {\n  }\n", definitionComment(d), d.typeName)
+		}
+		for _, s := range d.structs {
+			if comment := structComment(d, s); comment != "" {
+				fmt.Fprintf(&buf, "  /** %s */\n", comment)
+			}
+			if t := structType(s, known); t != nil {
+				if t.kind == "map" {
+					fmt.Fprintf(&buf, "  public static class %s extends HashMap<String, %s> {\n  }\n", s.Name, javaType(t.elem))
+				} else {
+					fmt.Fprintf(&buf, "  public static class %s extends HashMap<String, Object> {\n  }\n", s.Name)
+				}
+				continue
+			}
+			fmt.Fprintf(&buf, "  @JsonInclude(JsonInclude.Include.NON_NULL)\n  public static class %s {\n", s.Name)
+			for _, f := range s.Fields {
+				fmt.Fprintf(&buf, "    @JsonProperty(%q)\n    public %s %s;\n", f.Name, javaType(parseGoType(f.GoType, known)), javaFieldName(f.Name))
+			}
+			for _, f := range s.Fields {
+				field := javaFieldName(f.Name)
+				fmt.Fprintf(&buf, "\n    public %s %s(%s %s) {\n      this.%s = %s;\n      return this;\n    }\n",
+					s.Name, field, javaType(parseGoType(f.GoType, known)), field, field, field)
+			}
+			buf.WriteString("  }\n")
+		}
+		switch d.defType {
+		case "component":
+			fmt.Fprintf(&buf, "\n  public static ApplicationComponent %s(String name, %s properties, ApplicationTrait... traits) {\n    return new ApplicationComponent(name, %q, properties, traits);\n  }\n", d.funcName, d.typeName, d.name)
+		case "trait":
+			fmt.Fprintf(&buf, "\n  public static ApplicationTrait %s(%s properties) {\n    return new ApplicationTrait(%q, properties);\n  }\n", d.funcName, d.typeName, d.name)
+		case "policy":
+			fmt.Fprintf(&buf, "\n  public static AppPolicy %s(String name, %s properties) {\n    return new AppPolicy(name, %q, properties);\n  }\n", d.funcName, d.typeName, d.name)
+		case "workflow-step":
+			fmt.Fprintf(&buf, "\n  public static WorkflowStep %s(String name, %s properties, String... dependsOn) {\n    return new WorkflowStep(name, %q, properties, dependsOn);\n  }\n", d.funcName, d.typeName, d.name)
+		}
+	}
+	buf.WriteString("}\n")
+	return buf.Bytes()
+}
+
+const javaImports = `import com.fasterxml.jackson.annotation.JsonInclude;
+import com.fasterxml.jackson.annotation.JsonProperty;
+import java.util.ArrayList;
+import java.util.Arrays;
+import java.util.HashMap;
+import java.util.List;
+import java.util.Map;
+
+`
+
+const javaBaseTypes = `
+  @JsonInclude(JsonInclude.Include.NON_EMPTY)
+  public static class ApplicationComponent {
+    public String name;
+    public String type;
+    public Object properties;
+    public List<ApplicationTrait> traits;
+    public List<String> dependsOn;
+
+    public ApplicationComponent(String name, String type, Object properties, ApplicationTrait... traits) {
+      this.name = name;
+      this.type = type;
+      this.properties = properties;
+      this.traits = new ArrayList<>(Arrays.asList(traits));
+    }
+
+    public ApplicationComponent dependsOn(String... components) {
+      this.dependsOn = Arrays.asList(components);
+      return this;
+    }
+  }
+
+  @JsonInclude(JsonInclude.Include.NON_EMPTY)
+  public static class ApplicationTrait {
+    public String type;
+    public Object properties;
+
+    public ApplicationTrait(String type, Object properties) {
+      this.type = type;
+      this.properties = properties;
+    }
+  }
+
+  @JsonInclude(JsonInclude.Include.NON_EMPTY)
+  public static class AppPolicy {
+    public String name;
+    public String type;
+    public Object properties;
+
+    public AppPolicy(String name, String type, Object properties) {
+      this.name = name;
+      this.type = type;
+      this.properties = properties;
+    }
+  }
+
+  @JsonInclude(JsonInclude.Include.NON_EMPTY)
+  public static class WorkflowStep {
+    public String name;
+    public String type;
+    public Object properties;
+    public List<String> dependsOn;
+
+    public WorkflowStep(String name, String type, Object properties, String... dependsOn) {
+      this.name = name;
+      this.type = type;
+      this.properties = properties;
+      this.dependsOn = Arrays.asList(dependsOn);
+    }
+  }
+
+  @JsonInclude(JsonInclude.Include.NON_EMPTY)
+  public static class Metadata {
+    public String name;
+    public String namespace;
+    public Map<String, String> labels;
+    public Map<String, String> annotations;
+  }
+
+  @JsonInclude(JsonInclude.Include.NON_EMPTY)
+  public static class Workflow {
+    public List<WorkflowStep> steps = new ArrayList<>();
+  }
+
+  @JsonInclude(JsonInclude.Include.NON_EMPTY)
+  public static class ApplicationSpec {
+    public List<ApplicationComponent> components = new ArrayList<>();
+    public List<AppPolicy> policies = new ArrayList<>();
+    public Workflow workflow;
+  }
+
+  public static class Application {
+    public final String apiVersion = "core.oam.dev/v1beta1";
+    public final String kind = "Application";
+    public Metadata metadata = new Metadata();
+    public ApplicationSpec spec = new ApplicationSpec();
+
+    public Application addPolicy(AppPolicy policy) {
+      this.spec.policies.add(policy);
+      return this;
+    }
+
+    public Application addWorkflowStep(WorkflowStep step) {
+      if (this.spec.workflow == null) {
+        this.spec.workflow = new Workflow();
+      }
+      this.spec.workflow.steps.add(step);
+      return this;
+    }
+  }
+
+  public static Application application(String name, String namespace, ApplicationComponent... components) {
+    Application app = new Application();
+    app.metadata.name = name;
+    app.metadata.namespace = namespace;
+    app.spec.components.addAll(Arrays.asList(components));
+    return app;
+  }
+`
+
+func javaType(t *typeExpr) string {
+	switch t.kind {
+	case "string":
+		return "String"
+	case "integer":
+		return "Long"
+	case "number":
+		return "Double"
+	case "boolean":
+		return "Boolean"
+	case "list":
+		return fmt.Sprintf("List<%s>", javaType(t.elem))
+	case "map":
+		return fmt.Sprintf("Map<String, %s>", javaType(t.elem))
+	case "ref":
+		return t.ref
+	default:
+		return "Object"
+	}
+}
+
+func javaFieldName(name string) string {
+	field := lowerCamel(NewFieldNamer("").FieldName(name))
+	if reservedWords[field] {
+		return field + "_"
+	}
+	return field
+}
+
+func (g *SDKGenerator) genJSONSchema() ([]byte, error) {
+	known := g.knownTypes()
+	defs := map[string]interface{}{}
+	var conditions = map[string][]interface{}{}
+	var names = map[string][]interface{}{}
+	for _, d := range g.definitions {
+		if d.structs == nil {
+			defs[d.typeName] = map[string]interface{}{"type": "object", "description": definitionComment(d)}
+		}
+		for _, s := range d.structs {
+			schema := map[string]interface{}{}
+			if t := structType(s, known); t != nil {
+				schema = jsonSchemaType(t)
+			} else {
+				properties := map[string]interface{}{}
+				var required []string
+				for _, f := range s.Fields {
+					properties[f.Name] = jsonSchemaType(parseGoType(f.GoType, known))
+					if !f.OmitEmpty && !f.HasDefault {
+						required = append(required, f.Name)
+					}
+				}
+				schema["type"] = "object"
+				schema["properties"] = properties
+				if len(required) > 0 {
+					schema["required"] = required
+				}
+			}
+			if s.Usage != "" {
+				schema["description"] = s.Usage
+			}
+			if s.Name == d.typeName {
+				schema["description"] = definitionComment(d)
+			}
+			defs[s.Name] = schema
+		}
+		names[d.defType] = append(names[d.defType], d.name)
+		conditions[d.defType] = append(conditions[d.defType], map[string]interface{}{
+			"if":   map[string]interface{}{"properties": map[string]interface{}{"type": map[string]interface{}{"const": d.name}}},
+			"then": map[string]interface{}{"properties": map[string]interface{}{"properties": map[string]interface{}{"$ref": "#/$defs/" + d.typeName}}},
+		})
+	}
+	stringType := map[string]interface{}{"type": "string"}
+	stringList := map[string]interface{}{"type": "array", "items": stringType}
+	ref := func(name string) map[string]interface{} { return map[string]interface{}{"$ref": "#/$defs/" + name} }
+	entity := func(defType string, named bool, extra map[string]interface{}) map[string]interface{} {
+		typ := map[string]interface{}{"type": "string"}
+		if len(names[defType]) > 0 {
+			typ["enum"] = names[defType]
+		}
+		properties := map[string]interface{}{"type": typ, "properties": map[string]interface{}{"type": "object"}}
+		required := []string{"type"}
+		if named {
+			properties["name"] = stringType
+			required = []string{"name", "type"}
+		}
+		for k, v := range extra {
+			properties[k] = v
+		}
+		schema := map[string]interface{}{"type": "object", "required": required, "properties": properties}
+		if len(conditions[defType]) > 0 {
+			schema["allOf"] = conditions[defType]
+		}
+		return schema
+	}
+	defs["ApplicationComponent"] = entity("component", true, map[string]interface{}{
+		"traits":    map[string]interface{}{"type": "array", "items": ref("ApplicationTrait")},
+		"dependsOn": stringList,
+	})
+	defs["ApplicationTrait"] = entity("trait", false, nil)
+	defs["AppPolicy"] = entity("policy", true, nil)
+	defs["WorkflowStep"] = entity("workflow-step", true, map[string]interface{}{"dependsOn": stringList})
+	stringMap := map[string]interface{}{"type": "object", "additionalProperties": stringType}
+	schema := map[string]interface{}{
+		"$schema":  "https://json-schema.org/draft/2020-12/schema",
+		"title":    "Application",
+		"type":     "object",
+		"required": []string{"apiVersion", "kind", "metadata", "spec"},
+		"properties": map[string]interface{}{
+			"apiVersion": map[string]interface{}{"const": v1beta1.SchemeGroupVersion.String()},
+			"kind":       map[string]interface{}{"const": v1beta1.ApplicationKind},
+			"metadata": map[string]interface{}{
+				"type":     "object",
+				"required": []string{"name"},
+				"properties": map[string]interface{}{
+					"name": stringType, "namespace": stringType, "labels": stringMap, "annotations": stringMap,
+				},
+			},
+			"spec": map[string]interface{}{
+				"type":     "object",
+				"required": []string{"components"},
+				"properties": map[string]interface{}{
+					"components": map[string]interface{}{"type": "array", "items": ref("ApplicationComponent")},
+					"policies":   map[string]interface{}{"type": "array", "items": ref("AppPolicy")},
+					"workflow": map[string]interface{}{
+						"type":       "object",
+						"properties": map[string]interface{}{"steps": map[string]interface{}{"type": "array", "items": ref("WorkflowStep")}},
+					},
+				},
+			},
+		},
+		"$defs": defs,
+	}
+	bs, err := json.MarshalIndent(schema, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append(bs, '\n'), nil
+}
+
+func jsonSchemaType(t *typeExpr) map[string]interface{} {
+	switch t.kind {
+	case "list":
+		return map[string]interface{}{"type": "array", "items": jsonSchemaType(t.elem)}
+	case "map":
+		return map[string]interface{}{"type": "object", "additionalProperties": jsonSchemaType(t.elem)}
+	case "ref":
+		return map[string]interface{}{"$ref": "#/$defs/" + t.ref}
+	case "any":
+		return map[string]interface{}{}
+	default:
+		return map[string]interface{}{"type": t.kind}
+	}
+}
+
+// structComment return the comment of the struct, the comment of the definition is used for the root parameter
+func structComment(d *sdkDefinition, s StructParameter) string {
+	switch {
+	case s.Name == d.typeName:
+		return definitionComment(d)
+	case s.Usage != "":
+		return s.Name + " " + s.Usage
+	default:
+		return ""
+	}
+}
+
+func definitionComment(d *sdkDefinition) string {
+	comment := fmt.Sprintf("%s is the parameter of %s %s", d.typeName, d.defType, d.name)
+	if d.description != "" {
+		comment += ". " + strings.TrimSuffix(d.description, ".")
+	}
+	return comment
+}
+
+// lowerCamel lowers the leading upper case letters of the name, like HTTPRoute to httpRoute
+func lowerCamel(name string) string {
+	runes := []rune(name)
+	for i := range runes {
+		if !unicode.IsUpper(runes[i]) {
+			break
+		}
+		if i > 0 && i+1 < len(runes) && unicode.IsLower(runes[i+1]) {
+			break
+		}
+		runes[i] = unicode.ToLower(runes[i])
+	}
+	return string(runes)
+}
+
+// reservedWords is the reserved words of TypeScript and Java which cannot be used as identifier
+var reservedWords = map[string]bool{
+	"abstract": true, "assert": true, "await": true, "boolean": true, "break": true, "byte": true, "case": true,
+	"catch": true, "char": true, "class": true, "const": true, "continue": true, "debugger": true, "default": true,
+	"delete": true, "do": true, "double": true, "else": true, "enum": true, "export": true, "extends": true,
+	"false": true, "final": true, "finally": true, "float": true, "for": true, "function": true, "goto": true,
+	"if": true, "implements": true, "import": true, "in": true, "instanceof": true, "int": true, "interface": true,
+	"let": true, "long": true, "native": true, "new": true, "null": true, "package": true, "private": true,
+	"protected": true, "public": true, "return": true, "short": true, "static": true, "strictfp": true,
+	"super": true, "switch": true, "synchronized": true, "this": true, "throw": true, "throws": true,
+	"transient": true, "true": true, "try": true, "typeof": true, "var": true, "void": true, "volatile": true,
+	"while": true, "with": true, "yield": true, "application": true,
+}
@@ -0,0 +1,142 @@
+/*
+Copyright 2022 The KubeVela Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package definition
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+const testWorkflowStepDef = `
+import "vela/op"
+
+export: {
+	type: "workflow-step"
+}
+template: {
+	wait: op.#ConditionalWait & {continue: true}
+}
+`
+
+const testServiceTraitDef = `
+worker: {
+	type: "trait"
+}
+template: {
+	patch: {}
+	parameter: {
+		// +usage=The ports to expose
+		ports: [...{
+			port:      int
+			protocol?: string
+		}]
+		"host-name": string
+	}
+}
+`
+
+func newTestSDKGenerator(t *testing.T, lang SDKLanguage) *SDKGenerator {
+	g, err := NewSDKGenerator(lang, "")
+	require.NoError(t, err)
+	for _, cueString := range []string{testServiceTraitDef, testComponentDef, testWorkflowStepDef, testTraitDef} {
+		def := &Definition{Unstructured: unstructured.Unstructured{}}
+		require.NoError(t, def.FromLocalCUEString(cueString))
+		require.NoError(t, g.AddDefinition(def, nil))
+	}
+	return g
+}
+
+func TestSDKGenerator(t *testing.T) {
+	r := require.New(t)
+	_, err := NewSDKGenerator("python", "")
+	r.Error(err)
+	g := newTestSDKGenerator(t, SDKLanguageTypeScript)
+	r.Error(g.AddDefinition(loadTestDefinition(t, testComponentDef), nil))
+
+	testCases := map[SDKLanguage]struct {
+		filename string
+		contains []string
+	}{
+		SDKLanguageTypeScript: {
+			filename: "index.ts",
+			contains: []string{
+				"export interface WorkerParameter {\n  replicas?: number;\n  image: string;\n}",
+				"export function worker(name: string, properties: WorkerParameter, traits?: ApplicationTrait[]): ApplicationComponent {",
+				"export type LabelsParameter = Record<string, string>;",
+				"export interface WorkerTraitPorts {\n  port: number;\n  protocol?: string;\n}",
+				"  \"host-name\": string;",
+				"export function workerTrait(properties: WorkerTraitParameter): ApplicationTrait {",
+				"export type ExportParameter = Record<string, any>;",
+				"export function exportWorkflowStep(name: string, properties: ExportParameter, dependsOn?: string[]): WorkflowStep {",
+			},
+		},
+		SDKLanguageJava: {
+			filename: "Vela.java",
+			contains: []string{
+				"package dev.oam.vela;",
+				"    @JsonProperty(\"host-name\")\n    public String hostName;",
+				"    public WorkerTraitParameter hostName(String hostName) {",
+				"  public static class LabelsParameter extends HashMap<String, String> {",
+				"  public static ApplicationComponent worker(String name, WorkerParameter properties, ApplicationTrait... traits) {",
+			},
+		},
+		SDKLanguageGo: {
+			filename: "main.go",
+			contains: []string{
+				"package main",
+				"type WorkerTraitParameter struct {\n\tPorts    []WorkerTraitPorts `json:\"ports\"`\n\tHostName string             `json:\"host-name\"`\n}",
+				"type LabelsParameter map[string]string",
+			},
+		},
+	}
+	for lang, tt := range testCases {
+		t.Run(string(lang), func(t *testing.T) {
+			filename, code, err := newTestSDKGenerator(t, lang).Generate()
+			require.NoError(t, err)
+			require.Equal(t, tt.filename, filename)
+			for _, s := range tt.contains {
+				require.Contains(t, string(code), s)
+			}
+		})
+	}
+
+	filename, code, err := newTestSDKGenerator(t, SDKLanguageJSONSchema).Generate()
+	r.NoError(err)
+	r.Equal("application.schema.json", filename)
+	schema := map[string]interface{}{}
+	r.NoError(json.Unmarshal(code, &schema))
+	defs := schema["$defs"].(map[string]interface{})
+	r.Equal([]interface{}{"image"}, defs["WorkerParameter"].(map[string]interface{})["required"])
+	component := defs["ApplicationComponent"].(map[string]interface{})
+	r.Equal([]interface{}{"worker"}, component["properties"].(map[string]interface{})["type"].(map[string]interface{})["enum"])
+	r.Len(component["allOf"], 1)
+}
+
+func TestLowerCamel(t *testing.T) {
+	for input, expected := range map[string]string{
+		"Webservice":  "webservice",
+		"HTTPRoute":   "httpRoute",
+		"K8sObjects":  "k8sObjects",
+		"CPU":         "cpu",
+		"ApplyObject": "applyObject",
+	} {
+		require.Equal(t, expected, lowerCamel(input))
+	}
+}
@@ -0,0 +1,365 @@
+/*
+Copyright 2022 The KubeVela Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package definition
+
+import (
+	"context"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/yaml"
+
+	"github.com/kubevela/workflow/pkg/cue/packages"
+
+	"github.com/oam-dev/kubevela/apis/core.oam.dev/v1beta1"
+	cuedefinition "github.com/oam-dev/kubevela/pkg/cue/definition"
+	"github.com/oam-dev/kubevela/pkg/cue/process"
+)
+
+const (
+	// TestSuiteFileSuffix the suffix of the test suite file, which locates alongside the definition file
+	TestSuiteFileSuffix = ".test.yaml"
+	// testWorkloadName the name of the workload rendered for the trait test cases
+	testWorkloadName = "test-workload"
+)
+
+// TestSuite the test cases for a definition
+type TestSuite struct {
+	Cases []TestCase `json:"cases"`
+}
+
+// TestCase describe the input of rendering the definition and the expectation of the rendered result
+type TestCase struct {
+	Name      string                 `json:"name"`
+	Parameter map[string]interface{} `json:"parameter,omitempty"`
+	Context   TestContext            `json:"context,omitempty"`
+	// Workload is the workload to be patched by the trait, only used for testing TraitDefinition
+	Workload map[string]interface{} `json:"workload,omitempty"`
+	Expect   TestExpectation        `json:"expect"`
+}
+
+// TestContext the context used for rendering the definition, like `context.name` and `context.namespace`
+type TestContext struct {
+	Name           string            `json:"name,omitempty"`
+	Namespace      string            `json:"namespace,omitempty"`
+	AppName        string            `json:"appName,omitempty"`
+	AppRevision    string            `json:"appRevision,omitempty"`
+	Cluster        string            `json:"cluster,omitempty"`
+	AppLabels      map[string]string `json:"appLabels,omitempty"`
+	AppAnnotations map[string]string `json:"appAnnotations,omitempty"`
+}
+
+// TestExpectation the assertions on the rendered result. Output and Outputs are partially matched, which means only
+// the specified fields are compared. Golden is the file containing the full rendered result. Error is the expected
+// substring of the rendering error.
+type TestExpectation struct {
+	Output  map[string]interface{}            `json:"output,omitempty"`
+	Outputs map[string]map[string]interface{} `json:"outputs,omitempty"`
+	Golden  string                            `json:"golden,omitempty"`
+	Error   string                            `json:"error,omitempty"`
+}
+
+// RenderedResult the result of rendering the definition
+type RenderedResult struct {
+	Output  map[string]interface{}            `json:"output,omitempty"`
+	Outputs map[string]map[string]interface{} `json:"outputs,omitempty"`
+}
+
+// TestResult the result of running a test case
+type TestResult struct {
+	Name     string
+	Failures []string
+	Duration time.Duration
+}
+
+// Passed check if the test case passed
+func (r TestResult) Passed() bool {
+	return len(r.Failures) == 0
+}
+
+// LoadTestSuite load the test suite from the file
+func LoadTestSuite(path string) (*TestSuite, error) {
+	bs, err := os.ReadFile(filepath.Clean(path))
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to read test suite %s", path)
+	}
+	suite := &TestSuite{}
+	if err = yaml.Unmarshal(bs, suite); err != nil {
+		return nil, errors.Wrapf(err, "failed to parse test suite %s", path)
+	}
+	for i := range suite.Cases {
+		if suite.Cases[i].Name == "" {
+			suite.Cases[i].Name = fmt.Sprintf("case-%d", i)
+		}
+	}
+	return suite, nil
+}
+
+// RenderTestCase render the definition with the parameter and context of the test case. The rendering is fully
+// offline, so the templates which import the kube packages are not supported.
+func (def *Definition) RenderTestCase(tc TestCase) (*RenderedResult, error) {
+	template, _, err := unstructured.NestedString(def.Object, DefinitionTemplateKeys...)
+	if err != nil {
+		return nil, err
+	}
+	name := tc.Context.Name
+	if name == "" {
+		name = def.GetName()
+	}
+	ctx := process.NewContext(process.ContextData{
+		Namespace:       tc.Context.Namespace,
+		Cluster:         tc.Context.Cluster,
+		AppName:         tc.Context.AppName,
+		CompName:        name,
+		AppRevisionName: tc.Context.AppRevision,
+		AppLabels:       tc.Context.AppLabels,
+		AppAnnotations:  tc.Context.AppAnnotations,
+		Ctx:             context.Background(),
+	})
+	pd := &packages.PackageDiscover{}
+	var params interface{}
+	if tc.Parameter != nil {
+		params = tc.Parameter
+	}
+	switch def.GetKind() {
+	case v1beta1.ComponentDefinitionKind:
+		if err = cuedefinition.NewWorkloadAbstractEngine(def.GetName(), pd).Complete(ctx, template, params); err != nil {
+			return nil, err
+		}
+	case v1beta1.TraitDefinitionKind:
+		if tc.Workload != nil {
+			bs, err := json.Marshal(tc.Workload)
+			if err != nil {
+				return nil, err
+			}
+			if err = cuedefinition.NewWorkloadAbstractEngine(testWorkloadName, pd).Complete(ctx, fmt.Sprintf("output: %s", string(bs)), nil); err != nil {
+				return nil, errors.Wrapf(err, "invalid workload")
+			}
+		}
+		if err = cuedefinition.NewTraitAbstractEngine(def.GetName(), pd).Complete(ctx, template, params); err != nil {
+			return nil, err
+		}
+	default:
+		return nil, errors.Errorf("testing %s is not supported", def.GetKind())
+	}
+	result := &RenderedResult{}
+	base, auxiliaries := ctx.Output()
+	if base != nil {
+		obj, err := base.Unstructured()
+		if err != nil {
+			return nil, err
+		}
+		result.Output = obj.Object
+	}
+	for _, aux := range auxiliaries {
+		obj, err := aux.Ins.Unstructured()
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid outputs %s", aux.Name)
+		}
+		if result.Outputs == nil {
+			result.Outputs = map[string]map[string]interface{}{}
+		}
+		result.Outputs[aux.Name] = obj.Object
+	}
+	return result, nil
+}
+
+// RunTestSuite run all the test cases of the test suite. The golden files are located relative to the baseDir, and
+// will be overwritten by the rendered result if update is set.
+func (def *Definition) RunTestSuite(suite *TestSuite, baseDir string, update bool) []TestResult {
+	var results []TestResult
+	for _, tc := range suite.Cases {
+		start := time.Now()
+		failures := def.runTestCase(tc, baseDir, update)
+		results = append(results, TestResult{Name: tc.Name, Failures: failures, Duration: time.Since(start)})
+	}
+	return results
+}
+
+func (def *Definition) runTestCase(tc TestCase, baseDir string, update bool) []string {
+	result, err := def.RenderTestCase(tc)
+	if tc.Expect.Error != "" {
+		if err == nil {
+			return []string{fmt.Sprintf("expect error containing %q, but rendered successfully", tc.Expect.Error)}
+		}
+		if !strings.Contains(err.Error(), tc.Expect.Error) {
+			return []string{fmt.Sprintf("expect error containing %q, got: %s", tc.Expect.Error, err.Error())}
+		}
+		return nil
+	}
+	if err != nil {
+		return []string{fmt.Sprintf("failed to render: %s", err.Error())}
+	}
+	rendered, err := normalize(result)
+	if err != nil {
+		return []string{err.Error()}
+	}
+	var failures []string
+	if tc.Expect.Output != nil {
+		failures = append(failures, PartialMatch("output", tc.Expect.Output, rendered["output"])...)
+	}
+	for name, expected := range tc.Expect.Outputs {
+		var actual interface{}
+		if outputs, ok := rendered["outputs"].(map[string]interface{}); ok {
+			actual = outputs[name]
+		}
+		failures = append(failures, PartialMatch("outputs."+name, expected, actual)...)
+	}
+	if tc.Expect.Golden != "" {
+		failures = append(failures, checkGolden(filepath.Join(baseDir, tc.Expect.Golden), result, rendered, update)...)
+	}
+	return failures
+}
+
+func checkGolden(path string, result *RenderedResult, rendered interface{}, update bool) []string {
+	if update {
+		bs, err := yaml.Marshal(result)
+		if err != nil {
+			return []string{err.Error()}
+		}
+		if err = os.WriteFile(path, bs, 0600); err != nil {
+			return []string{fmt.Sprintf("failed to update golden file %s: %s", path, err.Error())}
+		}
+		return nil
+	}
+	bs, err := os.ReadFile(filepath.Clean(path))
+	if err != nil {
+		return []string{fmt.Sprintf("failed to read golden file %s: %s", path, err.Error())}
+	}
+	golden := map[string]interface{}{}
+	if err = yaml.Unmarshal(bs, &golden); err != nil {
+		return []string{fmt.Sprintf("failed to parse golden file %s: %s", path, err.Error())}
+	}
+	if !reflect.DeepEqual(golden, rendered) {
+		actual, _ := yaml.Marshal(result)
+		return []string{fmt.Sprintf("rendered result mismatches golden file %s, got:\n%s", path, string(actual))}
+	}
+	return nil
+}
+
+// normalize convert the object into the generic json form, so that it can be compared with the one parsed from yaml
+func normalize(obj interface{}) (map[string]interface{}, error) {
+	bs, err := json.Marshal(obj)
+	if err != nil {
+		return nil, err
+	}
+	m := map[string]interface{}{}
+	return m, json.Unmarshal(bs, &m)
+}
+
+// PartialMatch compare the expected value with the actual one, only the fields set in the expected map are compared.
+// Lists must have the same length, and their elements are compared in order. The mismatched paths are returned.
+func PartialMatch(path string, expected, actual interface{}) []string {
+	switch exp := expected.(type) {
+	case map[string]interface{}:
+		act, ok := actual.(map[string]interface{})
+		if !ok {
+			return []string{fmt.Sprintf("%s: expect object, got %s", path, formatValue(actual))}
+		}
+		keys := make([]string, 0, len(exp))
+		for k := range exp {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		var failures []string
+		for _, k := range keys {
+			failures = append(failures, PartialMatch(path+"."+k, exp[k], act[k])...)
+		}
+		return failures
+	case []interface{}:
+		act, ok := actual.([]interface{})
+		if !ok || len(act) != len(exp) {
+			return []string{fmt.Sprintf("%s: expect %s, got %s", path, formatValue(expected), formatValue(actual))}
+		}
+		var failures []string
+		for i := range exp {
+			failures = append(failures, PartialMatch(fmt.Sprintf("%s[%d]", path, i), exp[i], act[i])...)
+		}
+		return failures
+	default:
+		if !reflect.DeepEqual(expected, actual) {
+			return []string{fmt.Sprintf("%s: expect %s, got %s", path, formatValue(expected), formatValue(actual))}
+		}
+		return nil
+	}
+}
+
+func formatValue(v interface{}) string {
+	if v == nil {
+		return "<nil>"
+	}
+	bs, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprintf("%v", v)
+	}
+	return string(bs)
+}
+
+// JUnitTestSuites the root of the JUnit report
+type JUnitTestSuites struct {
+	XMLName xml.Name         `xml:"testsuites"`
+	Suites  []JUnitTestSuite `xml:"testsuite"`
+}
+
+// JUnitTestSuite the JUnit test suite, one for each definition
+type JUnitTestSuite struct {
+	Name     string          `xml:"name,attr"`
+	Tests    int             `xml:"tests,attr"`
+	Failures int             `xml:"failures,attr"`
+	Time     string          `xml:"time,attr"`
+	Cases    []JUnitTestCase `xml:"testcase"`
+}
+
+// JUnitTestCase the JUnit test case
+type JUnitTestCase struct {
+	Name      string        `xml:"name,attr"`
+	ClassName string        `xml:"classname,attr"`
+	Time      string        `xml:"time,attr"`
+	Failure   *JUnitFailure `xml:"failure,omitempty"`
+}
+
+// JUnitFailure the failure of the JUnit test case
+type JUnitFailure struct {
+	Message string `xml:"message,attr"`
+	Content string `xml:",chardata"`
+}
+
+// NewJUnitTestSuite convert the test results of the definition into the JUnit test suite
+func NewJUnitTestSuite(name string, results []TestResult) JUnitTestSuite {
+	suite := JUnitTestSuite{Name: name, Tests: len(results)}
+	var total time.Duration
+	for _, result := range results {
+		total += result.Duration
+		tc := JUnitTestCase{Name: result.Name, ClassName: name, Time: fmt.Sprintf("%.3f", result.Duration.Seconds())}
+		if !result.Passed() {
+			suite.Failures++
+			tc.Failure = &JUnitFailure{Message: result.Failures[0], Content: strings.Join(result.Failures, "\n")}
+		}
+		suite.Cases = append(suite.Cases, tc)
+	}
+	suite.Time = fmt.Sprintf("%.3f", total.Seconds())
+	return suite
+}
@@ -0,0 +1,158 @@
+/*
+Copyright 2022 The KubeVela Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package definition
+
+import (
+	"encoding/xml"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+const testComponentDef = `
+worker: {
+	type: "component"
+	attributes: workload: definition: {
+		apiVersion: "apps/v1"
+		kind:       "Deployment"
+	}
+}
+template: {
+	output: {
+		apiVersion: "apps/v1"
+		kind:       "Deployment"
+		metadata: {
+			name:      context.name
+			namespace: context.namespace
+		}
+		spec: replicas: parameter.replicas
+	}
+	outputs: service: {
+		apiVersion: "v1"
+		kind:       "Service"
+		metadata: name: context.name
+	}
+	parameter: {
+		replicas: *1 | int
+		image:    string
+	}
+}
+`
+
+const testTraitDef = `
+labels: {
+	type: "trait"
+	attributes: appliesToWorkloads: ["deployments.apps"]
+}
+template: {
+	patch: metadata: labels: parameter
+	parameter: [string]: string
+}
+`
+
+func loadTestDefinition(t *testing.T, cueString string) *Definition {
+	def := &Definition{Unstructured: unstructured.Unstructured{}}
+	require.NoError(t, def.FromCUEString(cueString, nil))
+	return def
+}
+
+func TestRunTestSuite(t *testing.T) {
+	r := require.New(t)
+	dir := t.TempDir()
+	component := loadTestDefinition(t, testComponentDef)
+	suite := &TestSuite{Cases: []TestCase{{
+		Name:      "partial-match",
+		Parameter: map[string]interface{}{"image": "nginx", "replicas": 2},
+		Context:   TestContext{Name: "web", Namespace: "prod"},
+		Expect: TestExpectation{
+			Output:  map[string]interface{}{"metadata": map[string]interface{}{"name": "web", "namespace": "prod"}, "spec": map[string]interface{}{"replicas": float64(2)}},
+			Outputs: map[string]map[string]interface{}{"service": {"kind": "Service"}},
+		},
+	}, {
+		Name:      "mismatch",
+		Parameter: map[string]interface{}{"image": "nginx"},
+		Expect:    TestExpectation{Output: map[string]interface{}{"spec": map[string]interface{}{"replicas": float64(3)}}},
+	}, {
+		Name:      "expected-error",
+		Parameter: map[string]interface{}{"replicas": "x"},
+		Expect:    TestExpectation{Error: "replicas"},
+	}, {
+		Name:      "unexpected-success",
+		Parameter: map[string]interface{}{"image": "nginx"},
+		Expect:    TestExpectation{Error: "some error"},
+	}, {
+		Name:      "golden",
+		Parameter: map[string]interface{}{"image": "nginx"},
+		Expect:    TestExpectation{Golden: "worker.golden.yaml"},
+	}}}
+
+	// golden file not exists
+	results := component.RunTestSuite(suite, dir, false)
+	r.Len(results, 5)
+	r.True(results[0].Passed(), results[0].Failures)
+	r.Equal([]string{"output.spec.replicas: expect 3, got 1"}, results[1].Failures)
+	r.True(results[2].Passed(), results[2].Failures)
+	r.False(results[3].Passed())
+	r.False(results[4].Passed())
+
+	results = component.RunTestSuite(suite, dir, true)
+	r.True(results[4].Passed(), results[4].Failures)
+	_, err := os.Stat(filepath.Join(dir, "worker.golden.yaml"))
+	r.NoError(err)
+	results = component.RunTestSuite(suite, dir, false)
+	r.True(results[4].Passed(), results[4].Failures)
+
+	report := NewJUnitTestSuite("worker.cue", results)
+	r.Equal(5, report.Tests)
+	r.Equal(2, report.Failures)
+	_, err = xml.Marshal(JUnitTestSuites{Suites: []JUnitTestSuite{report}})
+	r.NoError(err)
+
+	trait := loadTestDefinition(t, testTraitDef)
+	results = trait.RunTestSuite(&TestSuite{Cases: []TestCase{{
+		Name:      "patch-workload",
+		Parameter: map[string]interface{}{"app": "web"},
+		Workload:  map[string]interface{}{"apiVersion": "apps/v1", "kind": "Deployment", "metadata": map[string]interface{}{"name": "web"}},
+		Expect:    TestExpectation{Output: map[string]interface{}{"metadata": map[string]interface{}{"name": "web", "labels": map[string]interface{}{"app": "web"}}}},
+	}}}, dir, false)
+	r.True(results[0].Passed(), results[0].Failures)
+}
+
+func TestPartialMatch(t *testing.T) {
+	actual := map[string]interface{}{
+		"a": "x",
+		"b": []interface{}{map[string]interface{}{"c": float64(1), "d": true}},
+	}
+	testCases := map[string]struct {
+		expected interface{}
+		failures []string
+	}{
+		"match-subset":    {expected: map[string]interface{}{"b": []interface{}{map[string]interface{}{"c": float64(1)}}}},
+		"mismatch-value":  {expected: map[string]interface{}{"a": "y"}, failures: []string{`$.a: expect "y", got "x"`}},
+		"missing-field":   {expected: map[string]interface{}{"e": "y"}, failures: []string{`$.e: expect "y", got <nil>`}},
+		"list-length":     {expected: map[string]interface{}{"b": []interface{}{}}, failures: []string{`$.b: expect [], got [{"c":1,"d":true}]`}},
+		"type-mismatched": {expected: map[string]interface{}{"a": map[string]interface{}{}}, failures: []string{`$.a: expect object, got "x"`}},
+	}
+	for name, tt := range testCases {
+		t.Run(name, func(t *testing.T) {
+			require.Equal(t, tt.failures, PartialMatch("$", tt.expected, actual))
+		})
+	}
+}
@@ -110,6 +110,13 @@ const (
 
 	// LabelControllerName indicates the controller name
 	LabelControllerName = "controller.oam.dev/name"
+
+	// LabelScheduledShardID records the id of the controller shard that the application (and its resourcetrackers)
+	// is scheduled to
+	LabelScheduledShardID = "controller.core.oam.dev/scheduled-shard-id"
+
+	// LabelControllerShard marks the lease is used for recording the heartbeat of the controller shard
+	LabelControllerShard = "controller.core.oam.dev/shard"
 )
 
 const (
@@ -235,6 +242,15 @@ const (
 
 	// AnnotationAddonDefinitionBondCompKey indicates the definition in addon bond component.
 	AnnotationAddonDefinitionBondCompKey = "addon.oam.dev/bind-component"
+
+	// AnnotationDeployWindowOverride indicates the application should skip the check of deploy-window policies
+	AnnotationDeployWindowOverride = "app.oam.dev/deploy-window-override"
+
+	// AnnotationRollbackRevision records the application revision that the application is rolled back to
+	AnnotationRollbackRevision = "app.oam.dev/rollback-revision"
+
+	// AnnotationRollbackReason records the reason why the application is rolled back
+	AnnotationRollbackReason = "app.oam.dev/rollback-reason"
 )
 
 const (
@@ -577,6 +577,10 @@ func GetGVKFromDefinition(dm discoverymapper.DiscoveryMapper, definitionRef comm
 		return metav1.GroupVersionKind{}, nil
 	}
 	var gvk metav1.GroupVersionKind
+	// the discovery mapper is absent in offline mode, definitions referring to a resource cannot be resolved then
+	if dm == nil {
+		return gvk, errors.Errorf("cannot resolve the definition reference %s without the discovery mapper", definitionRef.Name)
+	}
 	groupResource := schema.ParseGroupResource(definitionRef.Name)
 	gvr := schema.GroupVersionResource{Group: groupResource.Group, Resource: groupResource.Resource, Version: definitionRef.Version}
 	kinds, err := dm.KindsFor(gvr)
@@ -474,6 +474,12 @@ func TestGetGVKFromDef(t *testing.T) {
 		Version: "",
 		Kind:    "",
 	}, gvk)
+
+	_, err = util.GetGVKFromDefinition(nil, common.DefinitionReference{Name: "abcs.example.com"})
+	assert.Error(t, err)
+	gvk, err = util.GetGVKFromDefinition(nil, common.DefinitionReference{})
+	assert.NoError(t, err)
+	assert.Equal(t, metav1.GroupVersionKind{}, gvk)
 }
 
 func TestConvertWorkloadGVK2Def(t *testing.T) {
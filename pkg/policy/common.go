@@ -64,3 +64,12 @@ func ParseSharedResourcePolicy(app *v1beta1.Application) (*v1alpha1.SharedResour
 	}
 	return nil, nil
 }
+
+// ParseServerSideApplyPolicy parse server-side-apply policy
+func ParseServerSideApplyPolicy(app *v1beta1.Application) (*v1alpha1.ServerSideApplyPolicySpec, error) {
+	spec := &v1alpha1.ServerSideApplyPolicySpec{}
+	if exists, err := parsePolicy(app, v1alpha1.ServerSideApplyPolicyType, spec); exists {
+		return spec, err
+	}
+	return nil, nil
+}
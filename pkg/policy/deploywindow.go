@@ -0,0 +1,130 @@
+/*
+Copyright 2022 The KubeVela Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package policy
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/robfig/cron/v3"
+	"k8s.io/kubectl/pkg/util/slice"
+
+	"github.com/oam-dev/kubevela/apis/core.oam.dev/v1alpha1"
+	"github.com/oam-dev/kubevela/apis/core.oam.dev/v1beta1"
+	pkgutils "github.com/oam-dev/kubevela/pkg/utils"
+)
+
+// CheckDeployWindows check whether the deploy targeting the given topology policies is allowed at the given time
+// by the deploy-window policies. If not allowed, the reason will be returned.
+func CheckDeployWindows(policies []v1beta1.AppPolicy, topologies []string, now time.Time) (allowed bool, reason string, err error) {
+	for _, policy := range policies {
+		if policy.Type != v1alpha1.DeployWindowPolicyType {
+			continue
+		}
+		if policy.Properties == nil {
+			return false, "", fmt.Errorf("deploy-window policy %s must not have empty properties", policy.Name)
+		}
+		spec := &v1alpha1.DeployWindowPolicySpec{}
+		if err = pkgutils.StrictUnmarshal(policy.Properties.Raw, spec); err != nil {
+			return false, "", errors.Wrapf(err, "failed to parse deploy-window policy %s", policy.Name)
+		}
+		for _, rule := range spec.Rules {
+			if !matchDeployWindowRule(rule, topologies) {
+				continue
+			}
+			allowed, reason, err = checkDeployWindowRule(rule, now)
+			if err != nil {
+				return false, "", errors.Wrapf(err, "invalid deploy-window policy %s", policy.Name)
+			}
+			if !allowed {
+				return false, fmt.Sprintf("%s (policy %s)", reason, policy.Name), nil
+			}
+		}
+	}
+	return true, "", nil
+}
+
+func matchDeployWindowRule(rule v1alpha1.DeployWindowRule, topologies []string) bool {
+	if len(rule.Topologies) == 0 {
+		return true
+	}
+	for _, topology := range topologies {
+		if slice.ContainsString(rule.Topologies, topology, nil) {
+			return true
+		}
+	}
+	return false
+}
+
+// checkDeployWindowRule deny windows take precedence over allow windows. If allow windows exist in the rule,
+// the deploy is only allowed inside one of them.
+func checkDeployWindowRule(rule v1alpha1.DeployWindowRule, now time.Time) (bool, string, error) {
+	hasAllowWindow, inAllowWindow := false, false
+	var nextOpen time.Time
+	for _, window := range rule.Windows {
+		active, start, end, err := evalDeployWindow(window, now)
+		if err != nil {
+			return false, "", err
+		}
+		switch window.Kind {
+		case v1alpha1.DeployWindowKindDeny:
+			if active {
+				return false, fmt.Sprintf("deploy is denied by window %q until %s", window.Schedule, end.Format(time.RFC3339)), nil
+			}
+		case v1alpha1.DeployWindowKindAllow:
+			hasAllowWindow = true
+			if active {
+				inAllowWindow = true
+			} else if nextOpen.IsZero() || start.Before(nextOpen) {
+				nextOpen = start
+			}
+		default:
+			return false, "", errors.Errorf("unknown kind %q of deploy window %q", window.Kind, window.Schedule)
+		}
+	}
+	if hasAllowWindow && !inAllowWindow {
+		return false, fmt.Sprintf("deploy is outside the allowed windows, next window opens at %s", nextOpen.Format(time.RFC3339)), nil
+	}
+	return true, "", nil
+}
+
+// evalDeployWindow check if the window is active at the given time. If active, the start and end of the current
+// window will be returned, otherwise the start and end of the next window will be returned.
+func evalDeployWindow(window v1alpha1.DeployWindow, now time.Time) (active bool, start time.Time, end time.Time, err error) {
+	loc := time.UTC
+	if window.TimeZone != "" {
+		if loc, err = time.LoadLocation(window.TimeZone); err != nil {
+			return false, start, end, errors.Wrapf(err, "invalid time zone of deploy window %q", window.Schedule)
+		}
+	}
+	schedule, err := cron.ParseStandard(window.Schedule)
+	if err != nil {
+		return false, start, end, errors.Wrapf(err, "invalid schedule of deploy window %q", window.Schedule)
+	}
+	duration, err := time.ParseDuration(window.Duration)
+	if err != nil {
+		return false, start, end, errors.Wrapf(err, "invalid duration of deploy window %q", window.Schedule)
+	}
+	if duration <= 0 {
+		return false, start, end, errors.Errorf("duration of deploy window %q must be positive", window.Schedule)
+	}
+	t := now.In(loc)
+	// the first start after (t - duration) is either the start of the current window or the start of the next one
+	start = schedule.Next(t.Add(-duration))
+	return !start.After(t), start, start.Add(duration), nil
+}
@@ -0,0 +1,109 @@
+/*
+Copyright 2022 The KubeVela Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package policy
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"github.com/oam-dev/kubevela/apis/core.oam.dev/v1alpha1"
+	"github.com/oam-dev/kubevela/apis/core.oam.dev/v1beta1"
+)
+
+func TestCheckDeployWindows(t *testing.T) {
+	// Monday 2022-10-10 10:00 UTC
+	now := time.Date(2022, 10, 10, 10, 0, 0, 0, time.UTC)
+	testCases := map[string]struct {
+		Properties string
+		Topologies []string
+		Allowed    bool
+		Reason     string
+		HasError   bool
+	}{
+		"no-rules": {
+			Properties: `{"rules":[]}`,
+			Allowed:    true,
+		},
+		"inside-allow-window": {
+			Properties: `{"rules":[{"windows":[{"kind":"allow","schedule":"0 9 * * 1-5","duration":"8h"}]}]}`,
+			Allowed:    true,
+		},
+		"outside-allow-window": {
+			Properties: `{"rules":[{"windows":[{"kind":"allow","schedule":"0 22 * * *","duration":"2h"}]}]}`,
+			Allowed:    false,
+			Reason:     "deploy is outside the allowed windows, next window opens at 2022-10-10T22:00:00Z (policy dw)",
+		},
+		"inside-deny-window": {
+			Properties: `{"rules":[{"windows":[{"kind":"allow","schedule":"0 0 * * *","duration":"24h"},{"kind":"deny","schedule":"0 8 * * 1","duration":"4h"}]}]}`,
+			Allowed:    false,
+			Reason:     "deploy is denied by window \"0 8 * * 1\" until 2022-10-10T12:00:00Z (policy dw)",
+		},
+		"deny-window-with-time-zone": {
+			Properties: `{"rules":[{"windows":[{"kind":"deny","schedule":"0 17 * * *","duration":"2h","timeZone":"Asia/Shanghai"}]}]}`,
+			Allowed:    false,
+			Reason:     "deploy is denied by window \"0 17 * * *\" until 2022-10-10T19:00:00+08:00 (policy dw)",
+		},
+		"rule-for-other-topology": {
+			Properties: `{"rules":[{"topologies":["prod"],"windows":[{"kind":"deny","schedule":"0 8 * * 1","duration":"4h"}]}]}`,
+			Topologies: []string{"staging"},
+			Allowed:    true,
+		},
+		"rule-for-matched-topology": {
+			Properties: `{"rules":[{"topologies":["prod"],"windows":[{"kind":"deny","schedule":"0 8 * * 1","duration":"4h"}]}]}`,
+			Topologies: []string{"staging", "prod"},
+			Allowed:    false,
+			Reason:     "deploy is denied by window \"0 8 * * 1\" until 2022-10-10T12:00:00Z (policy dw)",
+		},
+		"invalid-schedule": {
+			Properties: `{"rules":[{"windows":[{"kind":"deny","schedule":"bad","duration":"4h"}]}]}`,
+			HasError:   true,
+		},
+		"invalid-duration": {
+			Properties: `{"rules":[{"windows":[{"kind":"deny","schedule":"0 8 * * *","duration":"-1h"}]}]}`,
+			HasError:   true,
+		},
+		"invalid-kind": {
+			Properties: `{"rules":[{"windows":[{"kind":"maybe","schedule":"0 8 * * *","duration":"1h"}]}]}`,
+			HasError:   true,
+		},
+		"invalid-time-zone": {
+			Properties: `{"rules":[{"windows":[{"kind":"deny","schedule":"0 8 * * *","duration":"1h","timeZone":"Mars/Base"}]}]}`,
+			HasError:   true,
+		},
+	}
+	for name, tt := range testCases {
+		t.Run(name, func(t *testing.T) {
+			r := require.New(t)
+			policies := []v1beta1.AppPolicy{{
+				Name:       "dw",
+				Type:       v1alpha1.DeployWindowPolicyType,
+				Properties: &runtime.RawExtension{Raw: []byte(tt.Properties)},
+			}}
+			allowed, reason, err := CheckDeployWindows(policies, tt.Topologies, now)
+			if tt.HasError {
+				r.Error(err)
+				return
+			}
+			r.NoError(err)
+			r.Equal(tt.Allowed, allowed)
+			r.Equal(tt.Reason, reason)
+		})
+	}
+}
@@ -24,6 +24,7 @@ import (
 	utilfeature "k8s.io/apiserver/pkg/util/feature"
 
 	"github.com/oam-dev/kubevela/apis/core.oam.dev/v1alpha1"
+	"github.com/oam-dev/kubevela/apis/core.oam.dev/v1beta1"
 	"github.com/oam-dev/kubevela/pkg/auth"
 	"github.com/oam-dev/kubevela/pkg/features"
 	"github.com/oam-dev/kubevela/pkg/multicluster"
@@ -63,12 +64,7 @@ func (h *resourceKeeper) Dispatch(ctx context.Context, manifests []*unstructured
 		options = append(options, MetaOnlyOption{})
 	}
 	h.ClearNamespaceForClusterScopedResources(manifests)
-	// 0. check admission
-	if err = h.AdmissionCheck(ctx, manifests); err != nil {
-		return err
-	}
-	// 1. record manifests in resourcetracker
-	if err = h.record(ctx, manifests, options...); err != nil {
+	if err = h.admitAndRecord(ctx, manifests, options...); err != nil {
 		return err
 	}
 	// 2. apply manifests
@@ -82,6 +78,27 @@ func (h *resourceKeeper) Dispatch(ctx context.Context, manifests []*unstructured
 	return nil
 }
 
+// admitAndRecord check admission and record manifests in resourcetracker. The whole procedure is serialized
+// so that the application quota can be computed with the resources recorded by concurrent dispatches. The
+// quota is only checked if some of the manifests are not recorded yet, which happens when the application
+// revision changes, so that dispatching the same manifests again in the workflow does not re-compute it.
+func (h *resourceKeeper) admitAndRecord(ctx context.Context, manifests []*unstructured.Unstructured, options ...DispatchOption) error {
+	h.admissionMu.Lock()
+	defer h.admissionMu.Unlock()
+	// 0. check admission
+	if err := h.AdmissionCheck(ctx, manifests); err != nil {
+		return err
+	}
+	if !h.ContainsResources(manifests) {
+		quotaHandler := &QuotaAdmissionHandler{Client: h.Client, app: h.app, rts: []*v1beta1.ResourceTracker{h._rootRT, h._currentRT}}
+		if err := quotaHandler.Validate(ctx, manifests); err != nil {
+			return err
+		}
+	}
+	// 1. record manifests in resourcetracker
+	return h.record(ctx, manifests, options...)
+}
+
 func (h *resourceKeeper) record(ctx context.Context, manifests []*unstructured.Unstructured, options ...DispatchOption) error {
 	h.mu.Lock()
 	defer h.mu.Unlock()
@@ -142,6 +159,7 @@ func (h *resourceKeeper) dispatch(ctx context.Context, manifests []*unstructured
 		if h.isShared(manifest) {
 			ao = append([]apply.ApplyOption{apply.SharedByApp(h.app)}, ao...)
 		}
+		ao = append(ao, h.serverSideApplyOptions(manifest)...)
 		manifest, err := ApplyStrategies(applyCtx, h, manifest, v1alpha1.ApplyOnceStrategyOnAppUpdate)
 		if err != nil {
 			return errors.Wrapf(err, "failed to apply once policy for application %s,%s", h.app.Name, err.Error())
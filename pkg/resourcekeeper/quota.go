@@ -0,0 +1,336 @@
+/*
+Copyright 2022 The KubeVela Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resourcekeeper
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/yaml"
+
+	"github.com/oam-dev/kubevela/apis/core.oam.dev/common"
+	"github.com/oam-dev/kubevela/apis/core.oam.dev/condition"
+	"github.com/oam-dev/kubevela/apis/core.oam.dev/v1beta1"
+	"github.com/oam-dev/kubevela/apis/types"
+	"github.com/oam-dev/kubevela/pkg/multicluster"
+	"github.com/oam-dev/kubevela/pkg/oam"
+)
+
+var (
+	// ApplicationQuotaCPU if not empty, the total cpu requests of resources dispatched by one application cannot exceed it
+	ApplicationQuotaCPU = ""
+	// ApplicationQuotaMemory if not empty, the total memory requests of resources dispatched by one application cannot exceed it
+	ApplicationQuotaMemory = ""
+	// ApplicationQuotaReplicas if larger than 0, the total replicas of workloads dispatched by one application cannot exceed it
+	ApplicationQuotaReplicas int64 = 0
+	// ApplicationQuotaManagedResources if larger than 0, the number of resources managed by one application cannot exceed it
+	ApplicationQuotaManagedResources int64 = 0
+	// ApplicationQuotaConfigMapName is the name of the ConfigMap in the system namespace which stores the quota for
+	// applications in each namespace. The key of the data is the namespace and the value is the quota in YAML format.
+	ApplicationQuotaConfigMapName = "application-quota"
+)
+
+// ReasonQuotaExceeded is the condition reason when the resources of application exceed the quota
+const ReasonQuotaExceeded condition.ConditionReason = "QuotaExceeded"
+
+// ApplicationQuota describes the limits of the resources that one application can dispatch
+type ApplicationQuota struct {
+	// CPU is the max total cpu requests of the workloads
+	CPU *resource.Quantity `json:"cpu,omitempty"`
+	// Memory is the max total memory requests of the workloads
+	Memory *resource.Quantity `json:"memory,omitempty"`
+	// Replicas is the max total replicas of the workloads
+	Replicas *int64 `json:"replicas,omitempty"`
+	// ManagedResources is the max number of the managed resources
+	ManagedResources *int64 `json:"managedResources,omitempty"`
+}
+
+// IsEmpty check if no limit is set in the quota
+func (in *ApplicationQuota) IsEmpty() bool {
+	return in.CPU == nil && in.Memory == nil && in.Replicas == nil && in.ManagedResources == nil
+}
+
+// overrideBy override the limits of the quota by the limits set in the other one
+func (in *ApplicationQuota) overrideBy(other *ApplicationQuota) {
+	if other.CPU != nil {
+		in.CPU = other.CPU
+	}
+	if other.Memory != nil {
+		in.Memory = other.Memory
+	}
+	if other.Replicas != nil {
+		in.Replicas = other.Replicas
+	}
+	if other.ManagedResources != nil {
+		in.ManagedResources = other.ManagedResources
+	}
+}
+
+// GetGlobalApplicationQuota get the application quota set by the controller flags
+func GetGlobalApplicationQuota() (*ApplicationQuota, error) {
+	quota := &ApplicationQuota{}
+	if ApplicationQuotaCPU != "" {
+		cpu, err := resource.ParseQuantity(ApplicationQuotaCPU)
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid cpu quota %s", ApplicationQuotaCPU)
+		}
+		quota.CPU = &cpu
+	}
+	if ApplicationQuotaMemory != "" {
+		memory, err := resource.ParseQuantity(ApplicationQuotaMemory)
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid memory quota %s", ApplicationQuotaMemory)
+		}
+		quota.Memory = &memory
+	}
+	if ApplicationQuotaReplicas > 0 {
+		replicas := ApplicationQuotaReplicas
+		quota.Replicas = &replicas
+	}
+	if ApplicationQuotaManagedResources > 0 {
+		managedResources := ApplicationQuotaManagedResources
+		quota.ManagedResources = &managedResources
+	}
+	return quota, nil
+}
+
+// GetApplicationQuota get the quota for applications in the given namespace, the quota in the ConfigMap
+// overrides the global one
+func GetApplicationQuota(ctx context.Context, cli client.Client, namespace string) (*ApplicationQuota, error) {
+	quota, err := GetGlobalApplicationQuota()
+	if err != nil {
+		return nil, err
+	}
+	if ApplicationQuotaConfigMapName == "" {
+		return quota, nil
+	}
+	cm := &corev1.ConfigMap{}
+	if err = cli.Get(multicluster.ContextInLocalCluster(ctx), client.ObjectKey{Namespace: oam.SystemDefinitionNamespace, Name: ApplicationQuotaConfigMapName}, cm); err != nil {
+		if kerrors.IsNotFound(err) {
+			return quota, nil
+		}
+		return nil, errors.Wrapf(err, "failed to get application quota configmap")
+	}
+	if data, found := cm.Data[namespace]; found {
+		nsQuota := &ApplicationQuota{}
+		if err = yaml.Unmarshal([]byte(data), nsQuota); err != nil {
+			return nil, errors.Wrapf(err, "invalid application quota for namespace %s", namespace)
+		}
+		quota.overrideBy(nsQuota)
+	}
+	return quota, nil
+}
+
+// ApplicationResourceUsage is the usage of resources dispatched by one application
+type ApplicationResourceUsage struct {
+	CPU              resource.Quantity
+	Memory           resource.Quantity
+	Replicas         int64
+	ManagedResources int64
+}
+
+// Exceeds return the reasons of exceeding the quota, empty if the usage is within the quota
+func (in *ApplicationResourceUsage) Exceeds(quota *ApplicationQuota) []string {
+	var reasons []string
+	if quota.CPU != nil && in.CPU.Cmp(*quota.CPU) > 0 {
+		reasons = append(reasons, fmt.Sprintf("cpu requests %s exceeds quota %s", in.CPU.String(), quota.CPU.String()))
+	}
+	if quota.Memory != nil && in.Memory.Cmp(*quota.Memory) > 0 {
+		reasons = append(reasons, fmt.Sprintf("memory requests %s exceeds quota %s", in.Memory.String(), quota.Memory.String()))
+	}
+	if quota.Replicas != nil && in.Replicas > *quota.Replicas {
+		reasons = append(reasons, fmt.Sprintf("replicas %d exceeds quota %d", in.Replicas, *quota.Replicas))
+	}
+	if quota.ManagedResources != nil && in.ManagedResources > *quota.ManagedResources {
+		reasons = append(reasons, fmt.Sprintf("managed resources %d exceeds quota %d", in.ManagedResources, *quota.ManagedResources))
+	}
+	return reasons
+}
+
+// add the resource usage of the manifest
+func (in *ApplicationResourceUsage) add(manifest *unstructured.Unstructured) {
+	in.ManagedResources++
+	podSpecPath, replicasPath := getPodSpecPath(manifest)
+	if podSpecPath == nil {
+		return
+	}
+	replicas := int64(1)
+	if replicasPath != nil {
+		if r, found, err := unstructured.NestedInt64(manifest.Object, replicasPath...); err == nil && found {
+			replicas = r
+		}
+	}
+	in.Replicas += replicas
+	podSpecObj, found, err := unstructured.NestedMap(manifest.Object, podSpecPath...)
+	if err != nil || !found {
+		return
+	}
+	podSpec := &corev1.PodSpec{}
+	if err = runtime.DefaultUnstructuredConverter.FromUnstructured(podSpecObj, podSpec); err != nil {
+		return
+	}
+	for _, container := range podSpec.Containers {
+		cpu, memory := getContainerRequests(container)
+		in.CPU.Add(*resource.NewMilliQuantity(cpu.MilliValue()*replicas, resource.DecimalSI))
+		in.Memory.Add(*resource.NewQuantity(memory.Value()*replicas, resource.BinarySI))
+	}
+}
+
+// getContainerRequests get the requests of container, if requests is not set, limits will be used as the same as
+// the defaulting logic of Kubernetes
+func getContainerRequests(container corev1.Container) (cpu resource.Quantity, memory resource.Quantity) {
+	getRequest := func(name corev1.ResourceName) resource.Quantity {
+		if q, ok := container.Resources.Requests[name]; ok {
+			return q
+		}
+		if q, ok := container.Resources.Limits[name]; ok {
+			return q
+		}
+		return resource.Quantity{}
+	}
+	return getRequest(corev1.ResourceCPU), getRequest(corev1.ResourceMemory)
+}
+
+// getPodSpecPath get the path of pod spec and replicas in the workload, nil if the manifest does not contain pods
+func getPodSpecPath(manifest *unstructured.Unstructured) (podSpecPath []string, replicasPath []string) {
+	switch manifest.GetKind() {
+	case "Pod":
+		return []string{"spec"}, nil
+	case "CronJob":
+		return []string{"spec", "jobTemplate", "spec", "template", "spec"}, nil
+	case "Job", "DaemonSet":
+		return []string{"spec", "template", "spec"}, nil
+	}
+	if _, found, _ := unstructured.NestedMap(manifest.Object, "spec", "template", "spec"); found {
+		return []string{"spec", "template", "spec"}, []string{"spec", "replicas"}
+	}
+	return nil, nil
+}
+
+func quotaResourceKey(manifest *unstructured.Unstructured) string {
+	cluster := oam.GetCluster(manifest)
+	if cluster == "" {
+		cluster = types.ClusterLocalName
+	}
+	gvk := manifest.GroupVersionKind()
+	return strings.Join([]string{gvk.Group, gvk.Kind, cluster, manifest.GetNamespace(), manifest.GetName()}, "/")
+}
+
+// QuotaExceededError is the error returned when the resources of application exceed the quota
+type QuotaExceededError struct {
+	Reasons []string
+}
+
+// Error implements the error interface
+func (e QuotaExceededError) Error() string {
+	return fmt.Sprintf("forbidden resources: application quota exceeded, %s", strings.Join(e.Reasons, ", "))
+}
+
+// IsQuotaExceededError check if the error is caused by exceeding the application quota
+func IsQuotaExceededError(err error) bool {
+	var quotaErr QuotaExceededError
+	return errors.As(err, &quotaErr)
+}
+
+// QuotaAdmissionHandler defines the handler to validate if the resources dispatched by the application exceed the quota.
+// The usage is computed from the resources to dispatch and the resources already recorded in the resourcetrackers,
+// the live resources are not fetched.
+type QuotaAdmissionHandler struct {
+	client.Client
+	app *v1beta1.Application
+	rts []*v1beta1.ResourceTracker
+}
+
+// Validate check if the resources exceed the application quota
+func (h *QuotaAdmissionHandler) Validate(ctx context.Context, manifests []*unstructured.Unstructured) error {
+	quota, err := GetApplicationQuota(ctx, h.Client, h.app.GetNamespace())
+	if err != nil {
+		return err
+	}
+	if quota.IsEmpty() {
+		return nil
+	}
+	resources := map[string]*unstructured.Unstructured{}
+	var keys []string
+	for _, manifest := range manifests {
+		if manifest == nil {
+			continue
+		}
+		key := quotaResourceKey(manifest)
+		if _, found := resources[key]; !found {
+			keys = append(keys, key)
+		}
+		resources[key] = manifest
+	}
+	// the recorded resources are counted unless they are going to be updated by the manifests
+	for _, rt := range h.rts {
+		if rt == nil {
+			continue
+		}
+		for _, mr := range rt.Spec.ManagedResources {
+			if mr.Deleted {
+				continue
+			}
+			key := quotaResourceKey(mr.ToUnstructured())
+			if _, found := resources[key]; found {
+				continue
+			}
+			keys = append(keys, key)
+			resources[key] = recordedResource(mr)
+		}
+	}
+	usage := &ApplicationResourceUsage{}
+	for _, key := range keys {
+		usage.add(resources[key])
+	}
+	if reasons := usage.Exceeds(quota); len(reasons) > 0 {
+		err := QuotaExceededError{Reasons: reasons}
+		h.app.Status.SetConditions(condition.Condition{
+			Type:               condition.ConditionType(common.ResourceQuotaCondition.String()),
+			Status:             corev1.ConditionFalse,
+			LastTransitionTime: metav1.Now(),
+			Reason:             ReasonQuotaExceeded,
+			Message:            err.Error(),
+		})
+		return err
+	}
+	h.app.Status.SetConditions(condition.ReadyCondition(common.ResourceQuotaCondition.String()))
+	return nil
+}
+
+// recordedResource get the resource recorded in the resourcetracker. The resource recorded with metadata only,
+// which happens if the apply-once policy is enabled, is only counted as a managed resource.
+func recordedResource(mr v1beta1.ManagedResource) *unstructured.Unstructured {
+	if mr.Data != nil {
+		if u, ok := mr.Data.Object.(*unstructured.Unstructured); ok {
+			return u
+		}
+		if obj, err := mr.ToUnstructuredWithData(); err == nil {
+			return obj
+		}
+	}
+	return mr.ToUnstructured()
+}
@@ -0,0 +1,174 @@
+/*
+Copyright 2022 The KubeVela Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resourcekeeper
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/oam-dev/kubevela/apis/core.oam.dev/common"
+	"github.com/oam-dev/kubevela/apis/core.oam.dev/condition"
+	"github.com/oam-dev/kubevela/apis/core.oam.dev/v1beta1"
+	"github.com/oam-dev/kubevela/pkg/oam"
+	velacommon "github.com/oam-dev/kubevela/pkg/utils/common"
+)
+
+func newQuotaTestDeployment(name string, replicas int64, cpu string, memory string) *unstructured.Unstructured {
+	return &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "apps/v1",
+		"kind":       "Deployment",
+		"metadata":   map[string]interface{}{"name": name, "namespace": "test"},
+		"spec": map[string]interface{}{
+			"replicas": replicas,
+			"template": map[string]interface{}{
+				"spec": map[string]interface{}{
+					"containers": []interface{}{map[string]interface{}{
+						"name":      "main",
+						"image":     "nginx",
+						"resources": map[string]interface{}{"requests": map[string]interface{}{"cpu": cpu}, "limits": map[string]interface{}{"memory": memory}},
+					}},
+				},
+			},
+		},
+	}}
+}
+
+func TestApplicationResourceUsage(t *testing.T) {
+	r := require.New(t)
+	usage := &ApplicationResourceUsage{}
+	usage.add(newQuotaTestDeployment("a", 3, "500m", "1Gi"))
+	usage.add(&unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "batch/v1",
+		"kind":       "CronJob",
+		"metadata":   map[string]interface{}{"name": "b"},
+		"spec": map[string]interface{}{"jobTemplate": map[string]interface{}{"spec": map[string]interface{}{"template": map[string]interface{}{"spec": map[string]interface{}{
+			"containers": []interface{}{map[string]interface{}{"name": "main", "resources": map[string]interface{}{"requests": map[string]interface{}{"cpu": "1", "memory": "512Mi"}}}},
+		}}}}},
+	}})
+	usage.add(&unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "v1",
+		"kind":       "ConfigMap",
+		"metadata":   map[string]interface{}{"name": "c"},
+	}})
+	r.Equal("2500m", usage.CPU.String())
+	r.Equal(int64(3*1024*1024*1024+512*1024*1024), usage.Memory.Value())
+	r.Equal(int64(4), usage.Replicas)
+	r.Equal(int64(3), usage.ManagedResources)
+}
+
+func TestQuotaAdmissionHandler_Validate(t *testing.T) {
+	defer func() {
+		ApplicationQuotaCPU = ""
+		ApplicationQuotaReplicas = 0
+		ApplicationQuotaManagedResources = 0
+	}()
+	r := require.New(t)
+	cli := fake.NewClientBuilder().WithScheme(velacommon.Scheme).WithObjects(&corev1.ConfigMap{
+		ObjectMeta: v1.ObjectMeta{Name: ApplicationQuotaConfigMapName, Namespace: oam.SystemDefinitionNamespace},
+		Data:       map[string]string{"test": "replicas: 5\nmemory: 4Gi"},
+	}).Build()
+	app := &v1beta1.Application{ObjectMeta: v1.ObjectMeta{Name: "app", Namespace: "test"}}
+	rt := &v1beta1.ResourceTracker{}
+	rt.AddManagedResource(newQuotaTestDeployment("existing", 2, "1", "1Gi"), false, false, "")
+	handler := &QuotaAdmissionHandler{Client: cli, app: app, rts: []*v1beta1.ResourceTracker{nil, rt}}
+	ctx := context.Background()
+	quotaCond := condition.ConditionType(common.ResourceQuotaCondition.String())
+
+	// no global quota, namespace quota: replicas 5, memory 4Gi
+	r.NoError(handler.Validate(ctx, []*unstructured.Unstructured{newQuotaTestDeployment("new", 2, "1", "1Gi")}))
+	r.Equal(corev1.ConditionTrue, app.Status.GetCondition(quotaCond).Status)
+	err := handler.Validate(ctx, []*unstructured.Unstructured{newQuotaTestDeployment("new", 4, "1", "1Gi")})
+	r.Error(err)
+	r.Contains(err.Error(), "replicas 6 exceeds quota 5")
+	r.Contains(err.Error(), "memory requests 6Gi exceeds quota 4Gi")
+	cond := app.Status.GetCondition(quotaCond)
+	r.Equal(corev1.ConditionFalse, cond.Status)
+	r.Equal(ReasonQuotaExceeded, cond.Reason)
+
+	// updating the existing resource should not be counted twice
+	r.NoError(handler.Validate(ctx, []*unstructured.Unstructured{newQuotaTestDeployment("existing", 4, "1", "1Gi")}))
+
+	// global quota
+	ApplicationQuotaCPU = "2"
+	err = handler.Validate(ctx, []*unstructured.Unstructured{newQuotaTestDeployment("new", 1, "500m", "1Gi")})
+	r.Error(err)
+	r.Contains(err.Error(), "cpu requests 2500m exceeds quota 2")
+	ApplicationQuotaCPU = ""
+	ApplicationQuotaManagedResources = 1
+	err = handler.Validate(ctx, []*unstructured.Unstructured{{Object: map[string]interface{}{
+		"apiVersion": "v1", "kind": "ConfigMap", "metadata": map[string]interface{}{"name": "cm", "namespace": "test"},
+	}}})
+	r.Error(err)
+	r.Contains(err.Error(), "managed resources 2 exceeds quota 1")
+
+	r.True(IsQuotaExceededError(err))
+
+	// namespace without quota
+	ApplicationQuotaManagedResources = 0
+	app.Namespace = "other"
+	r.NoError(handler.Validate(ctx, []*unstructured.Unstructured{newQuotaTestDeployment("new", 100, "100", "100Gi")}))
+}
+
+func TestQuotaAdmissionHandler_MetaOnlyResources(t *testing.T) {
+	defer func() {
+		ApplicationQuotaReplicas = 0
+		ApplicationQuotaManagedResources = 0
+	}()
+	r := require.New(t)
+	cli := fake.NewClientBuilder().WithScheme(velacommon.Scheme).Build()
+	app := &v1beta1.Application{ObjectMeta: v1.ObjectMeta{Name: "app", Namespace: "test"}}
+	rt := &v1beta1.ResourceTracker{}
+	rt.AddManagedResource(newQuotaTestDeployment("meta", 3, "1", "1Gi"), true, false, "")
+	handler := &QuotaAdmissionHandler{Client: cli, app: app, rts: []*v1beta1.ResourceTracker{rt}}
+	ctx := context.Background()
+
+	// the resource recorded with metadata only is counted as a managed resource without replicas
+	ApplicationQuotaReplicas = 2
+	r.NoError(handler.Validate(ctx, []*unstructured.Unstructured{newQuotaTestDeployment("new", 2, "1", "1Gi")}))
+	ApplicationQuotaManagedResources = 1
+	err := handler.Validate(ctx, []*unstructured.Unstructured{newQuotaTestDeployment("new", 2, "1", "1Gi")})
+	r.Error(err)
+	r.Contains(err.Error(), "managed resources 2 exceeds quota 1")
+}
+
+func TestResourceKeeper_AdmitAndRecord(t *testing.T) {
+	defer func() { ApplicationQuotaReplicas = 0 }()
+	r := require.New(t)
+	app := &v1beta1.Application{ObjectMeta: v1.ObjectMeta{Name: "app", Namespace: "test"}}
+	rt := &v1beta1.ResourceTracker{ObjectMeta: v1.ObjectMeta{Name: "app-v1-test"}}
+	cli := fake.NewClientBuilder().WithScheme(velacommon.Scheme).WithObjects(rt).Build()
+	h := &resourceKeeper{Client: cli, app: app, _currentRT: rt}
+	ctx := context.Background()
+
+	ApplicationQuotaReplicas = 3
+	r.NoError(h.admitAndRecord(ctx, []*unstructured.Unstructured{newQuotaTestDeployment("a", 2, "1", "1Gi")}))
+	r.True(h.ContainsResources([]*unstructured.Unstructured{newQuotaTestDeployment("a", 2, "1", "1Gi")}))
+	err := h.admitAndRecord(ctx, []*unstructured.Unstructured{newQuotaTestDeployment("b", 2, "1", "1Gi")})
+	r.Error(err)
+	r.True(IsQuotaExceededError(err))
+	r.False(h.ContainsResources([]*unstructured.Unstructured{newQuotaTestDeployment("b", 2, "1", "1Gi")}))
+
+	// the quota is not checked again for the manifests recorded in the current revision
+	ApplicationQuotaReplicas = 1
+	r.NoError(h.admitAndRecord(ctx, []*unstructured.Unstructured{newQuotaTestDeployment("a", 2, "1", "1Gi")}))
+}
@@ -52,15 +52,18 @@ type resourceKeeper struct {
 	app *v1beta1.Application
 	mu  sync.Mutex
 
+	admissionMu sync.Mutex
+
 	applicator  apply.Applicator
 	_rootRT     *v1beta1.ResourceTracker
 	_currentRT  *v1beta1.ResourceTracker
 	_historyRTs []*v1beta1.ResourceTracker
 	_crRT       *v1beta1.ResourceTracker
 
-	applyOncePolicy      *v1alpha1.ApplyOncePolicySpec
-	garbageCollectPolicy *v1alpha1.GarbageCollectPolicySpec
-	sharedResourcePolicy *v1alpha1.SharedResourcePolicySpec
+	applyOncePolicy       *v1alpha1.ApplyOncePolicySpec
+	garbageCollectPolicy  *v1alpha1.GarbageCollectPolicySpec
+	sharedResourcePolicy  *v1alpha1.SharedResourcePolicySpec
+	serverSideApplyPolicy *v1alpha1.ServerSideApplyPolicySpec
 
 	cache *resourceCache
 }
@@ -105,6 +108,9 @@ func (h *resourceKeeper) parseApplicationResourcePolicy() (err error) {
 	if h.sharedResourcePolicy, err = policy.ParseSharedResourcePolicy(h.app); err != nil {
 		return errors.Wrapf(err, "failed to parse shared-resource policy")
 	}
+	if h.serverSideApplyPolicy, err = policy.ParseServerSideApplyPolicy(h.app); err != nil {
+		return errors.Wrapf(err, "failed to parse server-side-apply policy")
+	}
 	return nil
 }
 
@@ -17,9 +17,13 @@ limitations under the License.
 package resourcekeeper
 
 import (
+	"fmt"
+
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 
+	"github.com/oam-dev/kubevela/apis/core.oam.dev/v1alpha1"
 	"github.com/oam-dev/kubevela/pkg/utils"
+	"github.com/oam-dev/kubevela/pkg/utils/apply"
 )
 
 // ClearNamespaceForClusterScopedResources clear namespace for cluster scoped resources
@@ -37,3 +41,18 @@ func (h *resourceKeeper) isShared(manifest *unstructured.Unstructured) bool {
 	}
 	return h.sharedResourcePolicy.FindStrategy(manifest)
 }
+
+func (h *resourceKeeper) serverSideApplyOptions(manifest *unstructured.Unstructured) []apply.ApplyOption {
+	if h.serverSideApplyPolicy == nil {
+		return nil
+	}
+	matched, conflict := h.serverSideApplyPolicy.FindStrategy(manifest)
+	if !matched {
+		return nil
+	}
+	fieldManager := h.serverSideApplyPolicy.FieldManager
+	if fieldManager == "" {
+		fieldManager = fmt.Sprintf("kubevela/%s/%s", h.app.Namespace, h.app.Name)
+	}
+	return []apply.ApplyOption{apply.ServerSideApply(fieldManager, conflict == v1alpha1.ServerSideApplyConflictForce)}
+}
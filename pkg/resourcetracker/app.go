@@ -31,6 +31,7 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	"github.com/oam-dev/kubevela/apis/core.oam.dev/v1beta1"
+	"github.com/oam-dev/kubevela/pkg/controller/sharding"
 	"github.com/oam-dev/kubevela/pkg/features"
 	"github.com/oam-dev/kubevela/pkg/monitor/metrics"
 	"github.com/oam-dev/kubevela/pkg/oam"
@@ -82,6 +83,7 @@ func createResourceTracker(ctx context.Context, cli client.Client, app *v1beta1.
 	if app.Status.LatestRevision != nil {
 		meta.AddLabels(rt, map[string]string{oam.LabelAppRevision: app.Status.LatestRevision.Name})
 	}
+	sharding.PropagateScheduledShardID(app, rt)
 	rt.Spec.Type = rtType
 	if rtType == v1beta1.ResourceTrackerTypeVersioned {
 		rt.Spec.ApplicationGeneration = app.GetGeneration()
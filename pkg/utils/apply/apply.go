@@ -61,6 +61,10 @@ type applyAction struct {
 	updateAnnotation bool
 	dryRun           bool
 	quiet            bool
+
+	serverSideApply bool
+	fieldManager    string
+	forceConflicts  bool
 }
 
 // ApplyOption is called before applying state to the object.
@@ -177,6 +181,9 @@ func (a *APIApplicator) Apply(ctx context.Context, desired client.Object, ao ...
 	}
 
 	switch {
+	case applyAct.serverSideApply:
+		loggingApply("applying object by server-side apply", desired, applyAct.quiet)
+		return errors.Wrapf(serverSideApply(ctx, a.c, desired, applyAct), "cannot apply object")
 	case utilfeature.DefaultMutableFeatureGate.Enabled(features.ApplyResourceByUpdate) && isUpdatableResource(desired):
 		loggingApply("updating object", desired, applyAct.quiet)
 		desired.SetResourceVersion(existing.GetResourceVersion())
@@ -228,6 +235,10 @@ func createOrGetExisting(ctx context.Context, act *applyAction, c client.Client,
 		if err := executeApplyOptions(act, nil, desired, ao); err != nil {
 			return nil, err
 		}
+		if act.serverSideApply {
+			loggingApply("creating object by server-side apply", desired, act.quiet)
+			return nil, errors.Wrap(serverSideApply(ctx, c, desired, act), "cannot create object")
+		}
 		if act.updateAnnotation {
 			if err := addLastAppliedConfigAnnotation(desired); err != nil {
 				return nil, err
@@ -264,6 +275,21 @@ func createOrGetExisting(ctx context.Context, act *applyAction, c client.Client,
 	return existing, nil
 }
 
+// serverSideApply send the desired object to the server by server-side apply, the fields of the object
+// will be owned by the field manager of the apply action
+func serverSideApply(ctx context.Context, c client.Client, desired client.Object, act *applyAction) error {
+	desired.SetManagedFields(nil)
+	desired.SetResourceVersion("")
+	options := []client.PatchOption{client.FieldOwner(act.fieldManager)}
+	if act.forceConflicts {
+		options = append(options, client.ForceOwnership)
+	}
+	if act.dryRun {
+		options = append(options, client.DryRunAll)
+	}
+	return c.Patch(ctx, desired, client.Apply, options...)
+}
+
 func executeApplyOptions(act *applyAction, existing, desired client.Object, aos []ApplyOption) error {
 	// if existing is nil, it means the object is going to be created.
 	// ApplyOption function should handle this situation carefully by itself.
@@ -374,6 +400,19 @@ func DisableUpdateAnnotation() ApplyOption {
 	}
 }
 
+// ServerSideApply let the resource be applied by server-side apply with the given field manager instead of
+// the three-way merge patch. The last-applied-configuration annotation will not be recorded.
+// If force is true, the conflicts with other field managers will be overridden, otherwise the apply will abort.
+func ServerSideApply(fieldManager string, force bool) ApplyOption {
+	return func(act *applyAction, _, _ client.Object) error {
+		act.serverSideApply = true
+		act.fieldManager = fieldManager
+		act.forceConflicts = force
+		act.updateAnnotation = false
+		return nil
+	}
+}
+
 // SharedByApp let the resource be sharable
 func SharedByApp(app *v1beta1.Application) ApplyOption {
 	return func(act *applyAction, existing, desired client.Object) error {
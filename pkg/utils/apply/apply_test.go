@@ -310,6 +310,54 @@ func TestCreator(t *testing.T) {
 
 }
 
+func TestServerSideApply(t *testing.T) {
+	r := require.New(t)
+	newDesired := func() *unstructured.Unstructured {
+		desired := &unstructured.Unstructured{}
+		desired.SetAPIVersion("v1")
+		desired.SetKind("ConfigMap")
+		desired.SetName("desired")
+		desired.SetNamespace("default")
+		desired.SetResourceVersion("1")
+		return desired
+	}
+	var patched *unstructured.Unstructured
+	var patch client.Patch
+	patchOpts := &client.PatchOptions{}
+	cli := &test.MockClient{
+		MockGet: test.NewMockGetFn(nil, func(obj client.Object) error {
+			newDesired().DeepCopyInto(obj.(*unstructured.Unstructured))
+			return nil
+		}),
+		MockPatch: func(_ context.Context, obj client.Object, p client.Patch, opts ...client.PatchOption) error {
+			patched, patch = obj.(*unstructured.Unstructured), p
+			patchOpts = &client.PatchOptions{}
+			patchOpts.ApplyOptions(opts)
+			return nil
+		},
+	}
+	a := NewAPIApplicator(cli)
+
+	r.NoError(a.Apply(ctx, newDesired(), ServerSideApply("kubevela", true)))
+	r.Equal(client.Apply, patch)
+	r.Equal("kubevela", patchOpts.FieldManager)
+	r.NotNil(patchOpts.Force)
+	r.True(*patchOpts.Force)
+	r.Equal("", patched.GetResourceVersion())
+	r.NotContains(patched.GetAnnotations(), oam.AnnotationLastAppliedConfig)
+
+	r.NoError(a.Apply(ctx, newDesired(), ServerSideApply("kubevela", false), DryRunAll()))
+	r.Nil(patchOpts.Force)
+	r.Equal([]string{metav1.DryRunAll}, patchOpts.DryRun)
+
+	// create the object by server-side apply if not exists
+	cli.MockGet = test.NewMockGetFn(kerrors.NewNotFound(schema.GroupResource{}, ""))
+	cli.MockCreate = test.NewMockCreateFn(errFake)
+	patch = nil
+	r.NoError(a.Apply(ctx, newDesired(), ServerSideApply("kubevela", true)))
+	r.Equal(client.Apply, patch)
+}
+
 func TestMustBeControllableBy(t *testing.T) {
 	uid := types.UID("very-unique-string")
 	controller := true
@@ -22,7 +22,6 @@ import (
 	pkgmulticluster "github.com/kubevela/pkg/multicluster"
 	"k8s.io/client-go/discovery"
 
-	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/client-go/rest"
 	"k8s.io/client-go/util/flowcontrol"
@@ -97,21 +96,14 @@ func (a *Args) GetClient() (client.Client, error) {
 	return a.client, nil
 }
 
-// GetFakeClient returns a fake client with the definition objects preloaded
+// GetFakeClient returns a fake client with the definition objects preloaded, it requires no kubeconfig
 func (a *Args) GetFakeClient(defs []oam.Object) (client.Client, error) {
 	if a.client != nil {
 		return a.client, nil
 	}
-	if a.config == nil {
-		if err := a.SetConfig(nil); err != nil {
-			return nil, err
-		}
-	}
 	objs := make([]client.Object, 0, len(defs))
 	for _, def := range defs {
-		if unstructDef, ok := def.(*unstructured.Unstructured); ok {
-			objs = append(objs, unstructDef)
-		}
+		objs = append(objs, def)
 	}
 	return fake.NewClientBuilder().WithObjects(objs...).WithScheme(a.Schema).Build(), nil
 }
@@ -0,0 +1,159 @@
+/*
+ Copyright 2022 The KubeVela Authors.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package velaql
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"sort"
+	"sync"
+	"time"
+
+	pkgtypes "k8s.io/apimachinery/pkg/types"
+	"k8s.io/apiserver/pkg/endpoints/request"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/kubevela/workflow/pkg/cue/model/value"
+
+	"github.com/oam-dev/kubevela/apis/core.oam.dev/v1beta1"
+)
+
+// DefaultViewCacheTTL is the default time to live of the cached query results
+const DefaultViewCacheTTL = 10 * time.Second
+
+const (
+	// appNameParameter and appNamespaceParameter are the conventional parameters of the views to query an application
+	appNameParameter      = "appName"
+	appNamespaceParameter = "appNs"
+)
+
+// ViewCache caches the query results by the view, the export, the parameter and the user in the context, as the
+// user impersonated may see different results. The cached result expires after the TTL, or once the generation of
+// the application queried is changed.
+type ViewCache struct {
+	ttl     time.Duration
+	mu      sync.Mutex
+	entries map[string]viewCacheEntry
+}
+
+type viewCacheEntry struct {
+	result   string
+	expireAt time.Time
+	// app is the application queried, the entry is invalidated once its generation is changed
+	app        *pkgtypes.NamespacedName
+	generation int64
+}
+
+// NewViewCache create a cache of the query results with the TTL
+func NewViewCache(ttl time.Duration) *ViewCache {
+	return &ViewCache{
+		ttl:     ttl,
+		entries: map[string]viewCacheEntry{},
+	}
+}
+
+// Get return the cached result of the query if it's not expired
+func (c *ViewCache) Get(ctx context.Context, cli client.Client, qv QueryView) (*value.Value, bool) {
+	key, err := viewCacheKey(ctx, qv)
+	if err != nil {
+		return nil, false
+	}
+	c.mu.Lock()
+	entry, ok := c.entries[key]
+	c.mu.Unlock()
+	if !ok {
+		return nil, false
+	}
+	if time.Now().After(entry.expireAt) || (entry.app != nil && applicationGeneration(ctx, cli, *entry.app) != entry.generation) {
+		c.mu.Lock()
+		delete(c.entries, key)
+		c.mu.Unlock()
+		return nil, false
+	}
+	v, err := value.NewValue(entry.result, nil, "")
+	if err != nil {
+		return nil, false
+	}
+	return v, true
+}
+
+// Set cache the result of the query
+func (c *ViewCache) Set(ctx context.Context, cli client.Client, qv QueryView, result *value.Value) {
+	key, err := viewCacheKey(ctx, qv)
+	if err != nil {
+		return
+	}
+	str, err := result.String()
+	if err != nil {
+		return
+	}
+	entry := viewCacheEntry{result: str, expireAt: time.Now().Add(c.ttl)}
+	if app := queriedApplication(qv); app != nil {
+		entry.app = app
+		entry.generation = applicationGeneration(ctx, cli, *app)
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	now := time.Now()
+	for k, e := range c.entries {
+		if now.After(e.expireAt) {
+			delete(c.entries, k)
+		}
+	}
+	c.entries[key] = entry
+}
+
+func viewCacheKey(ctx context.Context, qv QueryView) (string, error) {
+	parameter, err := json.Marshal(qv.Parameter)
+	if err != nil {
+		return "", err
+	}
+	var userName string
+	var groups []string
+	if userInfo, ok := request.UserFrom(ctx); ok && userInfo != nil {
+		userName = userInfo.GetName()
+		groups = append(groups, userInfo.GetGroups()...)
+		sort.Strings(groups)
+	}
+	h := sha256.New()
+	for _, s := range append([]string{qv.View, qv.Export, string(parameter), userName}, groups...) {
+		h.Write([]byte(s))
+		h.Write([]byte{0})
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// queriedApplication return the application queried by the conventional parameters, or nil if not found
+func queriedApplication(qv QueryView) *pkgtypes.NamespacedName {
+	name, _ := qv.Parameter[appNameParameter].(string)
+	namespace, _ := qv.Parameter[appNamespaceParameter].(string)
+	if name == "" || namespace == "" {
+		return nil
+	}
+	return &pkgtypes.NamespacedName{Namespace: namespace, Name: name}
+}
+
+// applicationGeneration return the generation of the application, or 0 if it's not found
+func applicationGeneration(ctx context.Context, cli client.Client, key pkgtypes.NamespacedName) int64 {
+	app := &v1beta1.Application{}
+	if err := cli.Get(ctx, key, app); err != nil {
+		return 0
+	}
+	return app.Generation
+}
@@ -0,0 +1,82 @@
+/*
+ Copyright 2022 The KubeVela Authors.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package velaql
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apiserver/pkg/authentication/user"
+	"k8s.io/apiserver/pkg/endpoints/request"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/kubevela/workflow/pkg/cue/model/value"
+
+	"github.com/oam-dev/kubevela/apis/core.oam.dev/v1beta1"
+	"github.com/oam-dev/kubevela/pkg/utils/common"
+)
+
+func TestViewCache(t *testing.T) {
+	ctx := context.Background()
+	app := &v1beta1.Application{ObjectMeta: metav1.ObjectMeta{Name: "app", Namespace: "default", Generation: 1}}
+	cli := fake.NewClientBuilder().WithScheme(common.Scheme).WithObjects(app).Build()
+	result, err := value.NewValue(`resources: [{name: "deploy"}]`, nil, "")
+	assert.NoError(t, err)
+
+	cache := NewViewCache(time.Minute)
+	qv := QueryView{View: "tree-view", Export: "status", Parameter: map[string]interface{}{"appName": "app", "appNs": "default"}}
+	_, ok := cache.Get(ctx, cli, qv)
+	assert.False(t, ok)
+	cache.Set(ctx, cli, qv, result)
+	cached, ok := cache.Get(ctx, cli, qv)
+	assert.True(t, ok)
+	status := map[string]interface{}{}
+	assert.NoError(t, cached.UnmarshalTo(&status))
+	assert.Equal(t, map[string]interface{}{"resources": []interface{}{map[string]interface{}{"name": "deploy"}}}, status)
+
+	// the result of the other parameter is not cached
+	_, ok = cache.Get(ctx, cli, QueryView{View: "tree-view", Export: "status", Parameter: map[string]interface{}{"appName": "app", "appNs": "vela-system"}})
+	assert.False(t, ok)
+
+	// the result is cached for each user
+	userCtx := request.WithUser(ctx, &user.DefaultInfo{Name: "alice", Groups: []string{"dev", "ops"}})
+	_, ok = cache.Get(userCtx, cli, qv)
+	assert.False(t, ok)
+	cache.Set(userCtx, cli, qv, result)
+	_, ok = cache.Get(request.WithUser(ctx, &user.DefaultInfo{Name: "alice", Groups: []string{"ops", "dev"}}), cli, qv)
+	assert.True(t, ok)
+	_, ok = cache.Get(request.WithUser(ctx, &user.DefaultInfo{Name: "alice", Groups: []string{"dev"}}), cli, qv)
+	assert.False(t, ok)
+	_, ok = cache.Get(request.WithUser(ctx, &user.DefaultInfo{Name: "bob", Groups: []string{"dev", "ops"}}), cli, qv)
+	assert.False(t, ok)
+
+	// the result is invalidated once the application is updated
+	app.Generation = 2
+	assert.NoError(t, cli.Update(ctx, app))
+	_, ok = cache.Get(ctx, cli, qv)
+	assert.False(t, ok)
+
+	// the result is expired after the TTL
+	cache = NewViewCache(time.Millisecond)
+	cache.Set(ctx, cli, qv, result)
+	time.Sleep(2 * time.Millisecond)
+	_, ok = cache.Get(ctx, cli, qv)
+	assert.False(t, ok)
+}
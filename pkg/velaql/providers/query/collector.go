@@ -18,6 +18,7 @@ package query
 
 import (
 	"context"
+	"time"
 
 	"github.com/hashicorp/go-version"
 	"github.com/pkg/errors"
@@ -140,6 +141,7 @@ func (c *AppCollector) ListApplicationResources(ctx context.Context, app *v1beta
 	if err != nil {
 		return managedResources, err
 	}
+	refreshTopologyRules(ctx, c.k8sClient)
 
 	filter := func(node types.ResourceTreeNode) bool {
 		return isResourceMatchKindAndVersion(c.opt.Filter, node.Kind, node.APIVersion)
@@ -293,12 +295,102 @@ func getObjectCreatedByComponent(ctx context.Context, cli client.Client, objRef
 }
 
 func getEventFieldSelector(obj *unstructured.Unstructured) fields.Selector {
+	field := getEventFieldSet(obj)
+	field["involvedObject.uid"] = string(obj.GetUID())
+	return field.AsSelector()
+}
+
+func getEventFieldSet(obj *unstructured.Unstructured) fields.Set {
 	field := fields.Set{}
 	field["involvedObject.name"] = obj.GetName()
 	field["involvedObject.namespace"] = obj.GetNamespace()
 	field["involvedObject.kind"] = obj.GetObjectKind().GroupVersionKind().Kind
-	field["involvedObject.uid"] = string(obj.GetUID())
-	return field.AsSelector()
+	return field
+}
+
+// ListObjectEvents lists the events of the object in the given cluster, the events
+// are matched by the uid as well if the uid of the object is set
+func ListObjectEvents(ctx context.Context, cli client.Client, cluster string, obj *unstructured.Unstructured) ([]corev1.Event, error) {
+	field := getEventFieldSet(obj)
+	if uid := obj.GetUID(); uid != "" {
+		field["involvedObject.uid"] = string(uid)
+	}
+	return listEvents(ctx, cli, cluster, obj.GetNamespace(), field.AsSelector())
+}
+
+// ResourceNodeEvents is a node in the resource tree of the application and the events of the resource
+type ResourceNodeEvents struct {
+	Node   *types.ResourceTreeNode
+	Events []corev1.Event
+	// Err is the error listing the events of the resource, e.g. the cluster is unreachable
+	Err error
+}
+
+// ListApplicationResourceEvents walks the resource trees of the application and lists the events of each
+// resource in the cluster of the resource, the parent resource is listed before its children
+func (c *AppCollector) ListApplicationResourceEvents(ctx context.Context, app *v1beta1.Application) ([]ResourceNodeEvents, error) {
+	resources, err := c.ListApplicationResources(ctx, app)
+	if err != nil {
+		return nil, err
+	}
+	var nodeEvents []ResourceNodeEvents
+	for _, node := range walkResourceTrees(resources) {
+		obj := &unstructured.Unstructured{}
+		obj.SetAPIVersion(node.APIVersion)
+		obj.SetKind(node.Kind)
+		obj.SetNamespace(node.Namespace)
+		obj.SetName(node.Name)
+		obj.SetUID(node.UID)
+		events, err := ListObjectEvents(ctx, c.k8sClient, node.Cluster, obj)
+		nodeEvents = append(nodeEvents, ResourceNodeEvents{Node: node, Events: events, Err: err})
+	}
+	return nodeEvents, nil
+}
+
+// walkResourceTrees return the nodes in the resource trees of the resources, parents before children
+func walkResourceTrees(resources []*types.AppliedResource) []*types.ResourceTreeNode {
+	var nodes []*types.ResourceTreeNode
+	var walk func(node *types.ResourceTreeNode)
+	walk = func(node *types.ResourceTreeNode) {
+		nodes = append(nodes, node)
+		for _, leaf := range node.LeafNodes {
+			walk(leaf)
+		}
+	}
+	for _, resource := range resources {
+		if resource.ResourceTree != nil {
+			walk(resource.ResourceTree)
+		}
+	}
+	return nodes
+}
+
+// EventTime return the time the event happened at last
+func EventTime(event corev1.Event) time.Time {
+	switch {
+	case !event.LastTimestamp.IsZero():
+		return event.LastTimestamp.Time
+	case !event.EventTime.IsZero():
+		return event.EventTime.Time
+	case !event.FirstTimestamp.IsZero():
+		return event.FirstTimestamp.Time
+	default:
+		return event.CreationTimestamp.Time
+	}
+}
+
+func listEvents(ctx context.Context, cli client.Client, cluster string, namespace string, selector fields.Selector) ([]corev1.Event, error) {
+	eventList := corev1.EventList{}
+	listOpts := []client.ListOption{
+		client.InNamespace(namespace),
+		client.MatchingFieldsSelector{
+			Selector: selector,
+		},
+	}
+	if err := cli.List(multicluster.ContextWithClusterName(ctx, cluster), &eventList, listOpts...); err != nil {
+		return nil, err
+	}
+	return eventList.Items, nil
 }
 
 func isResourceInTargetCluster(opt FilterOption, resource common.ClusterObjectReference) bool {
@@ -0,0 +1,55 @@
+/*
+ Copyright 2022. The KubeVela Authors.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package query
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/oam-dev/kubevela/pkg/velaql/providers/query/types"
+)
+
+func TestWalkResourceTrees(t *testing.T) {
+	resources := []*types.AppliedResource{
+		{ResourceTree: &types.ResourceTreeNode{Name: "deploy", LeafNodes: []*types.ResourceTreeNode{
+			{Name: "rs", LeafNodes: []*types.ResourceTreeNode{{Name: "pod-1"}, {Name: "pod-2"}}},
+		}}},
+		{},
+		{ResourceTree: &types.ResourceTreeNode{Name: "service"}},
+	}
+	var names []string
+	for _, node := range walkResourceTrees(resources) {
+		names = append(names, node.Name)
+	}
+	assert.Equal(t, []string{"deploy", "rs", "pod-1", "pod-2", "service"}, names)
+}
+
+func TestEventTime(t *testing.T) {
+	created := metav1.NewTime(time.Now().Add(-time.Hour))
+	first := metav1.NewTime(time.Now().Add(-time.Minute))
+	last := metav1.NewTime(time.Now())
+	event := corev1.Event{ObjectMeta: metav1.ObjectMeta{CreationTimestamp: created}}
+	assert.Equal(t, created.Time, EventTime(event))
+	event.FirstTimestamp = first
+	assert.Equal(t, first.Time, EventTime(event))
+	event.LastTimestamp = last
+	assert.Equal(t, last.Time, EventTime(event))
+}
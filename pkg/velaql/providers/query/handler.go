@@ -193,19 +193,11 @@ func (h *provider) SearchEvents(ctx monitorContext.Context, wfCtx wfContext.Cont
 		return err
 	}
 
-	listCtx := multicluster.ContextWithClusterName(ctx, cluster)
-	fieldSelector := getEventFieldSelector(obj)
-	eventList := corev1.EventList{}
-	listOpts := []client.ListOption{
-		client.InNamespace(obj.GetNamespace()),
-		client.MatchingFieldsSelector{
-			Selector: fieldSelector,
-		},
-	}
-	if err := h.cli.List(listCtx, &eventList, listOpts...); err != nil {
+	events, err := listEvents(ctx, h.cli, cluster, obj.GetNamespace(), getEventFieldSelector(obj))
+	if err != nil {
 		return v.FillObject(err.Error(), "err")
 	}
-	return fillQueryResult(v, eventList.Items, "list")
+	return fillQueryResult(v, events, "list")
 }
 
 func (h *provider) CollectLogsInPod(ctx monitorContext.Context, wfCtx wfContext.Context, v *value.Value, act types.Action) error {
@@ -0,0 +1,409 @@
+/*
+Copyright 2022 The KubeVela Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package query
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"sync"
+	"text/template"
+	"time"
+
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/dynamic/dynamicinformer"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/klog"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/oam-dev/kubevela/apis/core.oam.dev/v1alpha1"
+	"github.com/oam-dev/kubevela/pkg/auth"
+	"github.com/oam-dev/kubevela/pkg/velaql/providers/query/types"
+)
+
+// topologyRuleStore holds the ResourceTopologyRules. The rules are kept in sync by the watcher once it's
+// started, otherwise they are listed again before building the resource tree once they are out of date.
+type topologyRuleStore struct {
+	mu          sync.RWMutex
+	rules       map[string]*v1alpha1.ResourceTopologyRule
+	watching    bool
+	refreshedAt time.Time
+}
+
+// topologyRuleRefreshInterval is the interval to list the ResourceTopologyRules again if they are not watched,
+// like in the CLI or the controller querying the resource tree without the watcher
+var topologyRuleRefreshInterval = 30 * time.Second
+
+var topologyRules = &topologyRuleStore{rules: map[string]*v1alpha1.ResourceTopologyRule{}}
+
+func (s *topologyRuleStore) set(rule *v1alpha1.ResourceTopologyRule) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if !validTopologyRule(rule) {
+		delete(s.rules, rule.Name)
+		return
+	}
+	s.rules[rule.Name] = rule
+}
+
+func (s *topologyRuleStore) remove(name string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.rules, name)
+}
+
+// replace swap in all the rules at once, so the readers never see the partially replaced rules
+func (s *topologyRuleStore) replace(rules []v1alpha1.ResourceTopologyRule) {
+	replaced := make(map[string]*v1alpha1.ResourceTopologyRule, len(rules))
+	for i := range rules {
+		if validTopologyRule(&rules[i]) {
+			replaced[rules[i].Name] = &rules[i]
+		}
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.rules = replaced
+}
+
+func validTopologyRule(rule *v1alpha1.ResourceTopologyRule) bool {
+	if err := ValidateResourceTopologyRule(rule); err != nil {
+		// don't let one invalid rule break the whole process
+		klog.Errorf("ignore the invalid resource topology rule %s: %v", rule.Name, err)
+		return false
+	}
+	return true
+}
+
+func (s *topologyRuleStore) setWatching(watching bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.watching = watching
+}
+
+func (s *topologyRuleStore) isWatching() bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.watching
+}
+
+// needRefresh return if the rules should be listed, the rules are listed at most once in the refresh interval
+// if they are not watched
+func (s *topologyRuleStore) needRefresh() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.watching || time.Since(s.refreshedAt) < topologyRuleRefreshInterval {
+		return false
+	}
+	s.refreshedAt = time.Now()
+	return true
+}
+
+// sortedRules return the rules of the parent resource type by the precedence
+func (s *topologyRuleStore) sortedRules(grt GroupResourceType) []*v1alpha1.ResourceTopologyRule {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	var rules []*v1alpha1.ResourceTopologyRule
+	for _, rule := range s.rules {
+		if rule.Spec.ParentResourceType.Group == grt.Group && rule.Spec.ParentResourceType.Kind == grt.Kind {
+			rules = append(rules, rule)
+		}
+	}
+	sort.Slice(rules, func(i, j int) bool {
+		if rules[i].Spec.Priority != rules[j].Spec.Priority {
+			return rules[i].Spec.Priority > rules[j].Spec.Priority
+		}
+		return rules[i].Name < rules[j].Name
+	})
+	return rules
+}
+
+// getRule get the rule of the parent resource type. The ResourceTopologyRules are applied by the precedence,
+// the children resource types of a rule are merged with the rules of the lower priority and the built-in rule,
+// unless the rule overrides them.
+func getRule(grt GroupResourceType) (*ChildrenResourcesRule, bool) {
+	builtin, hasBuiltin := globalRule.GetRule(grt)
+	rules := topologyRules.sortedRules(grt)
+	if len(rules) == 0 {
+		return builtin, hasBuiltin
+	}
+	rule := &ChildrenResourcesRule{GroupResourceType: grt, SubResources: buildSubResources(nil)}
+	for _, r := range rules {
+		for _, child := range r.Spec.ChildrenResourceTypes {
+			if rule.SubResources.Get(ResourceType{APIVersion: child.APIVersion, Kind: child.Kind}) == nil {
+				rule.SubResources.Put(buildTopologyRuleSelector(child))
+			}
+		}
+		if r.Spec.Mode == v1alpha1.TopologyRuleModeOverride {
+			return rule, true
+		}
+	}
+	if hasBuiltin {
+		rule.DefaultGenListOptionFunc = builtin.DefaultGenListOptionFunc
+		rule.DisableFilterByOwnerReference = builtin.DisableFilterByOwnerReference
+		for _, sub := range *builtin.SubResources {
+			if rule.SubResources.Get(sub.ResourceType) == nil {
+				rule.SubResources.Put(sub)
+			}
+		}
+	}
+	return rule, true
+}
+
+func buildTopologyRuleSelector(child v1alpha1.TopologyChildResourceType) *SubResourceSelector {
+	selector := &SubResourceSelector{ResourceType: ResourceType{APIVersion: child.APIVersion, Kind: child.Kind}}
+	if child.Matcher == v1alpha1.TopologyChildMatcherLabel {
+		selector.listOptions = topologyRuleLabelListOption(child)
+	} else {
+		selector.matchOwnerReference = true
+	}
+	return selector
+}
+
+// topologyRuleLabelListOption list the children resources in the namespace of the parent object by the label
+// selector at the selector path of the parent object and the labels rendered by the parent object
+func topologyRuleLabelListOption(child v1alpha1.TopologyChildResourceType) genListOptionFunc {
+	return func(obj unstructured.Unstructured) (client.ListOptions, error) {
+		selector := labels.Everything()
+		if child.SelectorPath != "" {
+			fields := strings.Split(child.SelectorPath, ".")
+			value, exist, err := unstructured.NestedFieldNoCopy(obj.Object, fields...)
+			if err != nil {
+				return client.ListOptions{}, err
+			}
+			if !exist {
+				return client.ListOptions{}, fmt.Errorf("the selector %s is not found in %s %s", child.SelectorPath, obj.GetKind(), obj.GetName())
+			}
+			workload := WorkloadUnstructured{obj}
+			m, _ := value.(map[string]interface{})
+			_, hasMatchLabels := m["matchLabels"]
+			_, hasMatchExpressions := m["matchExpressions"]
+			if hasMatchLabels || hasMatchExpressions {
+				selector, err = workload.GetSelector(fields...)
+			} else {
+				selector, err = workload.convertLabel2Selector(fields...)
+			}
+			if err != nil {
+				return client.ListOptions{}, err
+			}
+		}
+		if len(child.MatchLabels) > 0 {
+			matchLabels := map[string]string{}
+			for k, v := range child.MatchLabels {
+				rendered, err := renderTopologyRuleLabel(v, obj)
+				if err != nil {
+					return client.ListOptions{}, err
+				}
+				matchLabels[k] = rendered
+			}
+			requirements, _ := labels.SelectorFromSet(matchLabels).Requirements()
+			selector = selector.Add(requirements...)
+		}
+		return client.ListOptions{Namespace: obj.GetNamespace(), LabelSelector: selector}, nil
+	}
+}
+
+func renderTopologyRuleLabel(value string, obj unstructured.Unstructured) (string, error) {
+	t, err := template.New("label").Option("missingkey=error").Parse(value)
+	if err != nil {
+		return "", err
+	}
+	var buf bytes.Buffer
+	if err = t.Execute(&buf, obj.Object); err != nil {
+		return "", errors.Wrapf(err, "fail to render the label %s", value)
+	}
+	return buf.String(), nil
+}
+
+// ValidateResourceTopologyRule validate the ResourceTopologyRule beyond the schema of the CRD
+func ValidateResourceTopologyRule(rule *v1alpha1.ResourceTopologyRule) error {
+	var errs field.ErrorList
+	specPath := field.NewPath("spec")
+	if rule.Spec.ParentResourceType.Kind == "" {
+		errs = append(errs, field.Required(specPath.Child("parentResourceType", "kind"), "the kind of the parent resource is required"))
+	}
+	switch rule.Spec.Mode {
+	case "", v1alpha1.TopologyRuleModeMerge, v1alpha1.TopologyRuleModeOverride:
+	default:
+		errs = append(errs, field.NotSupported(specPath.Child("mode"), rule.Spec.Mode, []string{string(v1alpha1.TopologyRuleModeMerge), string(v1alpha1.TopologyRuleModeOverride)}))
+	}
+	if len(rule.Spec.ChildrenResourceTypes) == 0 {
+		errs = append(errs, field.Required(specPath.Child("childrenResourceTypes"), "at least one children resource type is required"))
+	}
+	seen := map[ResourceType]bool{}
+	for i, child := range rule.Spec.ChildrenResourceTypes {
+		childPath := specPath.Child("childrenResourceTypes").Index(i)
+		if _, err := schema.ParseGroupVersion(child.APIVersion); err != nil || child.APIVersion == "" {
+			errs = append(errs, field.Invalid(childPath.Child("apiVersion"), child.APIVersion, "invalid api version"))
+		}
+		if child.Kind == "" {
+			errs = append(errs, field.Required(childPath.Child("kind"), "the kind of the children resources is required"))
+		}
+		rt := ResourceType{APIVersion: child.APIVersion, Kind: child.Kind}
+		if seen[rt] {
+			errs = append(errs, field.Duplicate(childPath, fmt.Sprintf("%s %s", child.APIVersion, child.Kind)))
+		}
+		seen[rt] = true
+		switch child.Matcher {
+		case "", v1alpha1.TopologyChildMatcherOwnerReference:
+			if child.SelectorPath != "" || len(child.MatchLabels) > 0 {
+				errs = append(errs, field.Forbidden(childPath, "selectorPath and matchLabels are only used by the Label matcher"))
+			}
+		case v1alpha1.TopologyChildMatcherLabel:
+			if child.SelectorPath == "" && len(child.MatchLabels) == 0 {
+				errs = append(errs, field.Required(childPath, "either selectorPath or matchLabels is required by the Label matcher"))
+			}
+			for k, v := range child.MatchLabels {
+				if _, err := template.New("label").Parse(v); err != nil {
+					errs = append(errs, field.Invalid(childPath.Child("matchLabels").Key(k), v, err.Error()))
+				}
+			}
+		default:
+			errs = append(errs, field.NotSupported(childPath.Child("matcher"), child.Matcher, []string{string(v1alpha1.TopologyChildMatcherOwnerReference), string(v1alpha1.TopologyChildMatcherLabel)}))
+		}
+	}
+	return errs.ToAggregate()
+}
+
+// refreshTopologyRules list the ResourceTopologyRules if they are not watched and out of date. The rules are
+// shared by all the requests, so they are always listed by the identity of the process instead of the user
+// impersonated.
+func refreshTopologyRules(ctx context.Context, k8sClient client.Client) {
+	if !topologyRules.needRefresh() {
+		return
+	}
+	rules := v1alpha1.ResourceTopologyRuleList{}
+	if err := k8sClient.List(auth.ContextClearUserInfo(ctx), &rules); err != nil {
+		// the CRD may not be installed in the cluster
+		klog.V(4).Infof("fail to list the resource topology rules: %v", err)
+		return
+	}
+	topologyRules.replace(rules.Items)
+}
+
+// topologyRuleWatchBackoff is the backoff to retry watching the ResourceTopologyRules, e.g. the CRD is not installed yet
+var topologyRuleWatchBackoff = wait.Backoff{Duration: 5 * time.Second, Factor: 2, Jitter: 0.1, Steps: math.MaxInt32, Cap: 5 * time.Minute}
+
+// WatchResourceTopologyRules watch the ResourceTopologyRules, so the changes of the rules take effect in the
+// resource tree immediately without listing them for every request. The watch is retried with the backoff
+// until the rules could be listed, the rules are listed in the refresh interval meanwhile. It blocks until the
+// context is done.
+func WatchResourceTopologyRules(ctx context.Context, cfg *rest.Config) {
+	dynamicClient, err := dynamic.NewForConfig(cfg)
+	if err != nil {
+		klog.Errorf("fail to watch the resource topology rules: %v", err)
+		return
+	}
+	gvr := v1alpha1.SchemeGroupVersion.WithResource("resourcetopologyrules")
+	backoff := topologyRuleWatchBackoff
+	for {
+		if _, err = dynamicClient.Resource(gvr).List(ctx, metav1.ListOptions{Limit: 1}); err == nil {
+			break
+		}
+		delay := backoff.Step()
+		klog.Warningf("the resource topology rules are not watched, retry in %s: %v", delay, err)
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(delay):
+		}
+	}
+	toRule := func(obj interface{}) *v1alpha1.ResourceTopologyRule {
+		if tombstone, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+			obj = tombstone.Obj
+		}
+		u, ok := obj.(*unstructured.Unstructured)
+		if !ok {
+			return nil
+		}
+		rule := &v1alpha1.ResourceTopologyRule{}
+		if err := runtime.DefaultUnstructuredConverter.FromUnstructured(u.Object, rule); err != nil {
+			klog.Errorf("fail to decode the resource topology rule %s: %v", u.GetName(), err)
+			return nil
+		}
+		return rule
+	}
+	factory := dynamicinformer.NewDynamicSharedInformerFactory(dynamicClient, 0)
+	informer := factory.ForResource(gvr).Informer()
+	informer.AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc: func(obj interface{}) {
+			if rule := toRule(obj); rule != nil {
+				topologyRules.set(rule)
+			}
+		},
+		UpdateFunc: func(_, obj interface{}) {
+			if rule := toRule(obj); rule != nil {
+				topologyRules.set(rule)
+			}
+		},
+		DeleteFunc: func(obj interface{}) {
+			if rule := toRule(obj); rule != nil {
+				topologyRules.remove(rule.Name)
+			}
+		},
+	})
+	go informer.Run(ctx.Done())
+	if !cache.WaitForCacheSync(ctx.Done(), informer.HasSynced) {
+		return
+	}
+	topologyRules.setWatching(true)
+	klog.Info("the resource topology rules are watched")
+	<-ctx.Done()
+	topologyRules.setWatching(false)
+}
+
+// ListChildrenByTopologyRule list the children resources of the parent resource by the rule only, so the rule
+// could be tested against a live resource before it's applied. The children are expanded by the rules in effect.
+func ListChildrenByTopologyRule(ctx context.Context, k8sClient client.Client, parent types.ResourceTreeNode, rule *v1alpha1.ResourceTopologyRule) ([]*types.ResourceTreeNode, error) {
+	if err := ValidateResourceTopologyRule(rule); err != nil {
+		return nil, err
+	}
+	parentObject, err := fetchObjectWithResourceTreeNode(ctx, parent.Cluster, k8sClient, parent)
+	if err != nil {
+		return nil, err
+	}
+	gvk := parentObject.GroupVersionKind()
+	if gvk.Group != rule.Spec.ParentResourceType.Group || gvk.Kind != rule.Spec.ParentResourceType.Kind {
+		return nil, fmt.Errorf("the rule %s is for the parent resource %s, but the resource is %s", rule.Name,
+			schema.GroupKind{Group: rule.Spec.ParentResourceType.Group, Kind: rule.Spec.ParentResourceType.Kind}, gvk.GroupKind())
+	}
+	if err = mergeCustomRules(ctx, k8sClient); err != nil {
+		return nil, err
+	}
+	refreshTopologyRules(ctx, k8sClient)
+	children := &ChildrenResourcesRule{
+		GroupResourceType: GroupResourceType{Group: gvk.Group, Kind: gvk.Kind},
+		SubResources:      buildSubResources(nil),
+	}
+	for _, child := range rule.Spec.ChildrenResourceTypes {
+		children.SubResources.Put(buildTopologyRuleSelector(child))
+	}
+	return listSubResourcesByRule(ctx, parent.Cluster, k8sClient, children, *parentObject, 1, func(node types.ResourceTreeNode) bool {
+		return true
+	})
+}
@@ -0,0 +1,261 @@
+/*
+Copyright 2022 The KubeVela Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package query
+
+import (
+	"context"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/apiserver/pkg/authentication/user"
+	"k8s.io/apiserver/pkg/endpoints/request"
+	"k8s.io/client-go/rest"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/oam-dev/kubevela/apis/core.oam.dev/v1alpha1"
+	"github.com/oam-dev/kubevela/pkg/utils/common"
+	"github.com/oam-dev/kubevela/pkg/velaql/providers/query/types"
+)
+
+func newTopologyRule(name string, priority int32, mode v1alpha1.TopologyRuleMode, parent v1alpha1.TopologyParentResourceType, children ...v1alpha1.TopologyChildResourceType) *v1alpha1.ResourceTopologyRule {
+	return &v1alpha1.ResourceTopologyRule{
+		ObjectMeta: metav1.ObjectMeta{Name: name},
+		Spec: v1alpha1.ResourceTopologyRuleSpec{
+			ParentResourceType:    parent,
+			ChildrenResourceTypes: children,
+			Priority:              priority,
+			Mode:                  mode,
+		},
+	}
+}
+
+func TestValidateResourceTopologyRule(t *testing.T) {
+	parent := v1alpha1.TopologyParentResourceType{Group: "apps", Kind: "Deployment"}
+	assert.NoError(t, ValidateResourceTopologyRule(newTopologyRule("valid", 0, "", parent,
+		v1alpha1.TopologyChildResourceType{APIVersion: "v1", Kind: "Pod"},
+		v1alpha1.TopologyChildResourceType{APIVersion: "v1", Kind: "ConfigMap", Matcher: v1alpha1.TopologyChildMatcherLabel, MatchLabels: map[string]string{"app": "{{ .metadata.name }}"}},
+	)))
+
+	err := ValidateResourceTopologyRule(newTopologyRule("invalid", 0, "Replace", v1alpha1.TopologyParentResourceType{},
+		v1alpha1.TopologyChildResourceType{APIVersion: "v1", Kind: "Pod", SelectorPath: "spec.selector"},
+		v1alpha1.TopologyChildResourceType{APIVersion: "v1", Kind: "Pod", Matcher: v1alpha1.TopologyChildMatcherLabel},
+		v1alpha1.TopologyChildResourceType{APIVersion: "a/b/c", Kind: "Secret", Matcher: v1alpha1.TopologyChildMatcherLabel, MatchLabels: map[string]string{"app": "{{ .metadata.name"}},
+	))
+	assert.Error(t, err)
+	for _, msg := range []string{
+		"spec.parentResourceType.kind: Required value",
+		`spec.mode: Unsupported value: "Replace"`,
+		"spec.childrenResourceTypes[0]: Forbidden: selectorPath and matchLabels are only used by the Label matcher",
+		`spec.childrenResourceTypes[1]: Duplicate value: "v1 Pod"`,
+		"spec.childrenResourceTypes[1]: Required value: either selectorPath or matchLabels is required by the Label matcher",
+		`spec.childrenResourceTypes[2].apiVersion: Invalid value: "a/b/c"`,
+		"spec.childrenResourceTypes[2].matchLabels[app]: Invalid value",
+	} {
+		assert.Contains(t, err.Error(), msg)
+	}
+}
+
+func TestGetRuleWithTopologyRules(t *testing.T) {
+	defer topologyRules.replace(nil)
+	deployment := GroupResourceType{Group: "apps", Kind: "Deployment"}
+	builtin, ok := globalRule.GetRule(deployment)
+	assert.True(t, ok)
+
+	topologyRules.replace(nil)
+	rule, ok := getRule(deployment)
+	assert.True(t, ok)
+	assert.Equal(t, builtin, rule)
+
+	parent := v1alpha1.TopologyParentResourceType{Group: "apps", Kind: "Deployment"}
+	topologyRules.replace([]v1alpha1.ResourceTopologyRule{
+		*newTopologyRule("low", 1, v1alpha1.TopologyRuleModeMerge, parent,
+			v1alpha1.TopologyChildResourceType{APIVersion: "v1", Kind: "ConfigMap", Matcher: v1alpha1.TopologyChildMatcherLabel, SelectorPath: "spec.selector"},
+			v1alpha1.TopologyChildResourceType{APIVersion: "v1", Kind: "Secret"}),
+		*newTopologyRule("high", 10, "", parent,
+			v1alpha1.TopologyChildResourceType{APIVersion: "v1", Kind: "ConfigMap"}),
+		// the invalid rule is ignored
+		*newTopologyRule("invalid", 100, v1alpha1.TopologyRuleModeOverride, parent),
+	})
+	rule, ok = getRule(deployment)
+	assert.True(t, ok)
+	var children []ResourceType
+	for _, sub := range *rule.SubResources {
+		children = append(children, sub.ResourceType)
+	}
+	assert.Equal(t, []ResourceType{{APIVersion: "v1", Kind: "ConfigMap"}, {APIVersion: "v1", Kind: "Secret"}, {APIVersion: "apps/v1", Kind: "ReplicaSet"}}, children)
+	// the ConfigMap is matched by the rule with the higher priority
+	assert.True(t, (*rule.SubResources)[0].matchOwnerReference)
+	// the built-in children keep their own list options
+	assert.NotNil(t, (*rule.SubResources)[2].listOptions)
+
+	topologyRules.set(newTopologyRule("override", 5, v1alpha1.TopologyRuleModeOverride, parent,
+		v1alpha1.TopologyChildResourceType{APIVersion: "v1", Kind: "Pod"}))
+	rule, _ = getRule(deployment)
+	children = nil
+	for _, sub := range *rule.SubResources {
+		children = append(children, sub.ResourceType)
+	}
+	assert.Equal(t, []ResourceType{{APIVersion: "v1", Kind: "ConfigMap"}, {APIVersion: "v1", Kind: "Pod"}}, children)
+
+	// the merged rule inherits how the built-in rule lists the children
+	helmRelease := v1alpha1.TopologyParentResourceType{Group: "helm.toolkit.fluxcd.io", Kind: "HelmRelease"}
+	topologyRules.set(newTopologyRule("helm", 0, "", helmRelease, v1alpha1.TopologyChildResourceType{APIVersion: "batch/v1", Kind: "Job"}))
+	rule, _ = getRule(GroupResourceType{Group: helmRelease.Group, Kind: helmRelease.Kind})
+	assert.NotNil(t, rule.DefaultGenListOptionFunc)
+	assert.True(t, rule.DisableFilterByOwnerReference)
+	assert.NotNil(t, rule.SubResources.Get(ResourceType{APIVersion: "batch/v1", Kind: "Job"}))
+	topologyRules.set(newTopologyRule("helm", 0, v1alpha1.TopologyRuleModeOverride, helmRelease, v1alpha1.TopologyChildResourceType{APIVersion: "batch/v1", Kind: "Job"}))
+	rule, _ = getRule(GroupResourceType{Group: helmRelease.Group, Kind: helmRelease.Kind})
+	assert.Nil(t, rule.DefaultGenListOptionFunc)
+	assert.Len(t, *rule.SubResources, 1)
+
+	// the rule of the resource type without the built-in rule
+	_, ok = getRule(GroupResourceType{Group: "example.com", Kind: "Database"})
+	assert.False(t, ok)
+	topologyRules.set(newTopologyRule("database", 0, "", v1alpha1.TopologyParentResourceType{Group: "example.com", Kind: "Database"},
+		v1alpha1.TopologyChildResourceType{APIVersion: "apps/v1", Kind: "StatefulSet"}))
+	_, ok = getRule(GroupResourceType{Group: "example.com", Kind: "Database"})
+	assert.True(t, ok)
+	topologyRules.remove("database")
+	_, ok = getRule(GroupResourceType{Group: "example.com", Kind: "Database"})
+	assert.False(t, ok)
+}
+
+func TestTopologyRuleLabelListOption(t *testing.T) {
+	obj := unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "example.com/v1",
+		"kind":       "Database",
+		"metadata":   map[string]interface{}{"name": "db", "namespace": "default"},
+		"spec": map[string]interface{}{
+			"selector": map[string]interface{}{"matchLabels": map[string]interface{}{"app": "db"}},
+			"labels":   map[string]interface{}{"tier": "data"},
+		},
+	}}
+	opts, err := topologyRuleLabelListOption(v1alpha1.TopologyChildResourceType{SelectorPath: "spec.selector", MatchLabels: map[string]string{"owner": "{{ .metadata.name }}-{{ .kind }}"}})(obj)
+	assert.NoError(t, err)
+	assert.Equal(t, "default", opts.Namespace)
+	assert.Equal(t, "app=db,owner=db-Database", opts.LabelSelector.String())
+
+	opts, err = topologyRuleLabelListOption(v1alpha1.TopologyChildResourceType{SelectorPath: "spec.labels"})(obj)
+	assert.NoError(t, err)
+	assert.Equal(t, "tier=data", opts.LabelSelector.String())
+
+	_, err = topologyRuleLabelListOption(v1alpha1.TopologyChildResourceType{SelectorPath: "spec.template.selector"})(obj)
+	assert.Contains(t, err.Error(), "the selector spec.template.selector is not found in Database db")
+	_, err = topologyRuleLabelListOption(v1alpha1.TopologyChildResourceType{MatchLabels: map[string]string{"owner": "{{ .metadata.uid }}"}})(obj)
+	assert.Contains(t, err.Error(), "fail to render the label")
+}
+
+func TestListChildrenByTopologyRule(t *testing.T) {
+	defer topologyRules.replace(nil)
+	ctx := context.Background()
+	deploy := &appsv1.Deployment{
+		TypeMeta:   metav1.TypeMeta{APIVersion: "apps/v1", Kind: "Deployment"},
+		ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "default", UID: "web-uid", Labels: map[string]string{"app": "web"}},
+	}
+	owned := metav1.OwnerReference{APIVersion: "apps/v1", Kind: "Deployment", Name: "web", UID: "web-uid"}
+	cli := fake.NewClientBuilder().WithScheme(common.Scheme).WithObjects(
+		deploy,
+		&corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "web-config", Namespace: "default", Labels: map[string]string{"app.oam.dev/owner": "web"}}},
+		&corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "other-config", Namespace: "default", Labels: map[string]string{"app.oam.dev/owner": "other"}}},
+		&corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "web-secret", Namespace: "default", OwnerReferences: []metav1.OwnerReference{owned}}},
+		&corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "orphan-secret", Namespace: "default"}},
+	).Build()
+
+	rule := newTopologyRule("web-children", 0, "", v1alpha1.TopologyParentResourceType{Group: "apps", Kind: "Deployment"},
+		v1alpha1.TopologyChildResourceType{APIVersion: "v1", Kind: "ConfigMap", Matcher: v1alpha1.TopologyChildMatcherLabel, MatchLabels: map[string]string{"app.oam.dev/owner": "{{ .metadata.name }}"}},
+		v1alpha1.TopologyChildResourceType{APIVersion: "v1", Kind: "Secret"},
+	)
+	parent := types.ResourceTreeNode{APIVersion: "apps/v1", Kind: "Deployment", Namespace: "default", Name: "web"}
+	children, err := ListChildrenByTopologyRule(ctx, cli, parent, rule)
+	assert.NoError(t, err)
+	var names []string
+	for _, child := range children {
+		names = append(names, child.Kind+"/"+child.Name)
+	}
+	assert.Equal(t, []string{"ConfigMap/web-config", "Secret/web-secret"}, names)
+
+	rule.Spec.ParentResourceType.Kind = "StatefulSet"
+	_, err = ListChildrenByTopologyRule(ctx, cli, parent, rule)
+	assert.Contains(t, err.Error(), "the rule web-children is for the parent resource StatefulSet.apps, but the resource is Deployment.apps")
+}
+
+func TestRefreshTopologyRules(t *testing.T) {
+	defer topologyRules.replace(nil)
+	parent := v1alpha1.TopologyParentResourceType{Group: "apps", Kind: "Deployment"}
+	cli := &userRecordingClient{Client: fake.NewClientBuilder().WithScheme(common.Scheme).WithObjects(
+		newTopologyRule("configs", 1, "", parent, v1alpha1.TopologyChildResourceType{APIVersion: "v1", Kind: "ConfigMap"}),
+	).Build()}
+	ctx := request.WithUser(context.Background(), &user.DefaultInfo{Name: "alice"})
+	topologyRules.refreshedAt = time.Time{}
+	refreshTopologyRules(ctx, cli)
+	// the rules are listed by the identity of the process
+	assert.True(t, cli.listed)
+	assert.Nil(t, cli.user)
+	assert.Len(t, topologyRules.sortedRules(GroupResourceType{Group: "apps", Kind: "Deployment"}), 1)
+
+	// the rules are not listed again until they are out of date
+	cli.listed = false
+	refreshTopologyRules(ctx, cli)
+	assert.False(t, cli.listed)
+	topologyRules.refreshedAt = time.Now().Add(-topologyRuleRefreshInterval)
+	refreshTopologyRules(ctx, cli)
+	assert.True(t, cli.listed)
+}
+
+func TestWatchResourceTopologyRulesRetry(t *testing.T) {
+	var requests int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		http.NotFound(w, r)
+	}))
+	defer server.Close()
+	backoff := topologyRuleWatchBackoff
+	defer func() { topologyRuleWatchBackoff = backoff }()
+	topologyRuleWatchBackoff = wait.Backoff{Duration: time.Millisecond, Factor: 1, Steps: math.MaxInt32}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+	// the CRD is not found, the watch is retried until the context is done
+	WatchResourceTopologyRules(ctx, &rest.Config{Host: server.URL})
+	assert.Greater(t, atomic.LoadInt32(&requests), int32(1))
+	assert.False(t, topologyRules.isWatching())
+}
+
+// userRecordingClient records the user in the context of listing
+type userRecordingClient struct {
+	client.Client
+	listed bool
+	user   user.Info
+}
+
+func (c *userRecordingClient) List(ctx context.Context, list client.ObjectList, opts ...client.ListOption) error {
+	c.listed = true
+	c.user, _ = request.UserFrom(ctx)
+	return c.Client.List(ctx, list, opts...)
+}
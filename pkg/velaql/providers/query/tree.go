@@ -292,6 +292,8 @@ func (c *SubResources) Put(cr *SubResourceSelector) {
 type SubResourceSelector struct {
 	ResourceType
 	listOptions genListOptionFunc
+	// matchOwnerReference means only match the resources owned by the parent object, ignoring the default genListOptionFunc of the rule
+	matchOwnerReference bool
 }
 
 type genListOptionFunc func(unstructured.Unstructured) (client.ListOptions, error)
@@ -900,64 +902,76 @@ func iterateListSubResources(ctx context.Context, cluster string, k8sClient clie
 	group := parentObject.GetObjectKind().GroupVersionKind().Group
 	kind := parentObject.GetObjectKind().GroupVersionKind().Kind
 
-	if rule, ok := globalRule.GetRule(GroupResourceType{Group: group, Kind: kind}); ok {
-		var resList []*types.ResourceTreeNode
-		for i := range *rule.SubResources {
-			resource := (*rule.SubResources)[i].ResourceType
-			specifiedFunc := (*rule.SubResources)[i].listOptions
+	if rule, ok := getRule(GroupResourceType{Group: group, Kind: kind}); ok {
+		return listSubResourcesByRule(ctx, cluster, k8sClient, rule, *parentObject, depth, filter)
+	}
+	return nil, nil
+}
 
-			clusterCTX := multicluster.ContextWithClusterName(ctx, cluster)
-			items, err := listItemByRule(clusterCTX, k8sClient, resource, *parentObject, specifiedFunc, rule.DefaultGenListOptionFunc, rule.DisableFilterByOwnerReference)
-			if err != nil {
-				if meta.IsNoMatchError(err) || runtime.IsNotRegisteredError(err) {
-					klog.Warningf("ignore list resources: %s as %v", resource.Kind, err)
-					continue
-				}
-				return nil, err
+// listSubResourcesByRule list the sub resources of the parent object by the rule, the sub resources are iterated
+// by the rules of their own types
+func listSubResourcesByRule(ctx context.Context, cluster string, k8sClient client.Client, rule *ChildrenResourcesRule, parentObject unstructured.Unstructured, depth int, filter func(node types.ResourceTreeNode) bool) ([]*types.ResourceTreeNode, error) {
+	var resList []*types.ResourceTreeNode
+	for i := range *rule.SubResources {
+		resource := (*rule.SubResources)[i].ResourceType
+		specifiedFunc := (*rule.SubResources)[i].listOptions
+		defaultFunc := rule.DefaultGenListOptionFunc
+		if (*rule.SubResources)[i].matchOwnerReference {
+			// only the resources owned by the parent object are matched
+			defaultFunc = nil
+		}
+
+		clusterCTX := multicluster.ContextWithClusterName(ctx, cluster)
+		items, err := listItemByRule(clusterCTX, k8sClient, resource, parentObject, specifiedFunc, defaultFunc, rule.DisableFilterByOwnerReference)
+		if err != nil {
+			if meta.IsNoMatchError(err) || runtime.IsNotRegisteredError(err) {
+				klog.Warningf("ignore list resources: %s as %v", resource.Kind, err)
+				continue
 			}
-			for i, item := range items {
-				rtn := types.ResourceTreeNode{
-					APIVersion: item.GetAPIVersion(),
-					Kind:       item.GroupVersionKind().Kind,
-					Namespace:  item.GetNamespace(),
-					Name:       item.GetName(),
-					UID:        item.GetUID(),
-					Cluster:    cluster,
-					Object:     items[i],
-				}
-				if _, ok := globalRule.GetRule(GroupResourceType{Group: item.GetObjectKind().GroupVersionKind().Group, Kind: item.GetObjectKind().GroupVersionKind().Kind}); ok {
-					childrenRes, err := iterateListSubResources(ctx, cluster, k8sClient, rtn, depth+1, filter)
-					if err != nil {
-						return nil, err
-					}
-					rtn.LeafNodes = childrenRes
-				}
-				if !filter(rtn) && len(rtn.LeafNodes) == 0 {
-					continue
-				}
-				healthStatus, err := CheckResourceStatus(item)
-				if err != nil {
-					return nil, err
-				}
-				rtn.HealthStatus = *healthStatus
-				addInfo, err := additionalInfo(item)
+			return nil, err
+		}
+		for i, item := range items {
+			rtn := types.ResourceTreeNode{
+				APIVersion: item.GetAPIVersion(),
+				Kind:       item.GroupVersionKind().Kind,
+				Namespace:  item.GetNamespace(),
+				Name:       item.GetName(),
+				UID:        item.GetUID(),
+				Cluster:    cluster,
+				Object:     items[i],
+			}
+			if _, ok := getRule(GroupResourceType{Group: item.GetObjectKind().GroupVersionKind().Group, Kind: item.GetObjectKind().GroupVersionKind().Kind}); ok {
+				childrenRes, err := iterateListSubResources(ctx, cluster, k8sClient, rtn, depth+1, filter)
 				if err != nil {
 					return nil, err
 				}
-				rtn.CreationTimestamp = item.GetCreationTimestamp().Time
-				if !item.GetDeletionTimestamp().IsZero() {
-					rtn.DeletionTimestamp = item.GetDeletionTimestamp().Time
-				}
-				rtn.AdditionalInfo = addInfo
-				resList = append(resList, &rtn)
+				rtn.LeafNodes = childrenRes
+			}
+			if !filter(rtn) && len(rtn.LeafNodes) == 0 {
+				continue
+			}
+			healthStatus, err := CheckResourceStatus(item)
+			if err != nil {
+				return nil, err
 			}
+			rtn.HealthStatus = *healthStatus
+			addInfo, err := additionalInfo(item)
+			if err != nil {
+				return nil, err
+			}
+			rtn.CreationTimestamp = item.GetCreationTimestamp().Time
+			if !item.GetDeletionTimestamp().IsZero() {
+				rtn.DeletionTimestamp = item.GetDeletionTimestamp().Time
+			}
+			rtn.AdditionalInfo = addInfo
+			resList = append(resList, &rtn)
 		}
-		return resList, nil
 	}
-	return nil, nil
+	return resList, nil
 }
 
 // mergeCustomRules merge user defined resource topology rules with the system ones
+// The rules in the ConfigMaps are kept for compatibility, the ResourceTopologyRules take precedence over them.
 func mergeCustomRules(ctx context.Context, k8sClient client.Client) error {
 	rulesList := v12.ConfigMapList{}
 	if err := k8sClient.List(ctx, &rulesList, client.InNamespace(velatypes.DefaultKubeVelaNS), client.HasLabels{oam.LabelResourceRules}); err != nil {
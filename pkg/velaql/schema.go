@@ -0,0 +1,90 @@
+/*
+ Copyright 2022 The KubeVela Authors.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package velaql
+
+import (
+	"fmt"
+
+	"cuelang.org/go/cue"
+
+	"github.com/kubevela/workflow/pkg/cue/model/value"
+
+	"github.com/oam-dev/kubevela/pkg/cue/script"
+)
+
+// ParameterError is the error of the parameter not matching the parameter schema declared by the view
+type ParameterError struct {
+	View    string
+	Message string
+}
+
+// Error return the error message
+func (e *ParameterError) Error() string {
+	return fmt.Sprintf("invalid parameter of the view %s: %s", e.View, e.Message)
+}
+
+// ValidateViewParameter validates the parameter of the query by the `parameter` field declared in the view,
+// and returns the parameter converted to the types of the schema. The parameters in velaQL are parsed as
+// literals, so `name=1` is converted to the string "1" if the parameter `name` is declared as a string.
+// The parameter is returned as it is if the view declares no parameter schema.
+func ValidateViewParameter(viewName, view string, parameter map[string]interface{}) (map[string]interface{}, error) {
+	val, err := value.NewValue(view, nil, "")
+	if err != nil {
+		// the invalid view is reported when running it
+		return parameter, nil //nolint:nilerr
+	}
+	schema, err := val.LookupValue(KeyWordParameter)
+	if err != nil {
+		return parameter, nil //nolint:nilerr
+	}
+	kinds := map[string]cue.Kind{}
+	iter, err := schema.CueValue().Fields(cue.Optional(true))
+	if err != nil {
+		return nil, &ParameterError{View: viewName, Message: err.Error()}
+	}
+	for iter.Next() {
+		kinds[iter.Selector().Unquoted()] = iter.Value().IncompleteKind()
+	}
+
+	var result map[string]interface{}
+	if parameter != nil {
+		result = make(map[string]interface{}, len(parameter))
+	}
+	for key, v := range parameter {
+		kind, declared := kinds[key]
+		if !declared && !schema.CueValue().Allows(cue.Str(key)) {
+			return nil, &ParameterError{View: viewName, Message: fmt.Sprintf("unknown parameter %s", key)}
+		}
+		if _, isString := v.(string); declared && !isString && kind == cue.StringKind {
+			v = fmt.Sprint(v)
+		}
+		result[key] = v
+	}
+	if len(result) > 0 {
+		if err = schema.FillObject(result); err != nil {
+			return nil, &ParameterError{View: viewName, Message: err.Error()}
+		}
+	}
+	if err = schema.CueValue().Validate(); err != nil {
+		return nil, &ParameterError{View: viewName, Message: script.ConvertFieldError(err).Error()}
+	}
+	// the required parameters are not set if the parameter is not concrete
+	if _, err = schema.CueValue().MarshalJSON(); err != nil {
+		return nil, &ParameterError{View: viewName, Message: script.ConvertFieldError(err).Error()}
+	}
+	return result, nil
+}
@@ -0,0 +1,91 @@
+/*
+ Copyright 2022 The KubeVela Authors.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package velaql
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateViewParameter(t *testing.T) {
+	view := `
+import (
+	"vela/ql"
+)
+parameter: {
+	appName: string
+	appNs:   string
+	name?:   string
+	limit:   *10 | int
+}
+response: ql.#ListResourcesInApp & {
+	app: {
+		name:      parameter.appName
+		namespace: parameter.appNs
+	}
+}
+status: response.list
+`
+	testcases := map[string]struct {
+		parameter map[string]interface{}
+		expected  map[string]interface{}
+		err       string
+	}{
+		"valid": {
+			parameter: map[string]interface{}{"appName": "app", "appNs": "default", "limit": int64(5)},
+			expected:  map[string]interface{}{"appName": "app", "appNs": "default", "limit": int64(5)},
+		},
+		"convert to string": {
+			parameter: map[string]interface{}{"appName": int64(123), "appNs": "default", "name": true},
+			expected:  map[string]interface{}{"appName": "123", "appNs": "default", "name": "true"},
+		},
+		"missing required": {
+			parameter: map[string]interface{}{"appName": "app"},
+			err:       "invalid parameter of the view test-view: Field: appNs Message: This parameter is required",
+		},
+		"wrong type": {
+			parameter: map[string]interface{}{"appName": "app", "appNs": "default", "limit": "many"},
+			err:       "invalid parameter of the view test-view",
+		},
+	}
+	for name, tc := range testcases {
+		t.Run(name, func(t *testing.T) {
+			result, err := ValidateViewParameter("test-view", view, tc.parameter)
+			if tc.err != "" {
+				assert.Error(t, err)
+				assert.Contains(t, err.Error(), tc.err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expected, result)
+		})
+	}
+
+	closedView := "parameter: close({name: string})\nstatus: parameter.name\n"
+	_, err := ValidateViewParameter("closed-view", closedView, map[string]interface{}{"name": "a", "other": "b"})
+	assert.EqualError(t, err, "invalid parameter of the view closed-view: unknown parameter other")
+
+	_, err = ValidateViewParameter("test-view", view, nil)
+	assert.Error(t, err)
+
+	// the view without parameter schema is not validated
+	parameter := map[string]interface{}{"any": "value"}
+	result, err := ValidateViewParameter("view", "status: \"ok\"", parameter)
+	assert.NoError(t, err)
+	assert.Equal(t, parameter, result)
+}
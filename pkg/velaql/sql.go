@@ -0,0 +1,478 @@
+/*
+ Copyright 2022 The KubeVela Authors.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package velaql
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/pkg/errors"
+)
+
+// SelectStatement is the ad-hoc query in the SQL-like syntax:
+//
+//	SELECT <* | field, ...> FROM <source> [alias] [JOIN <source> [alias] ON <field> = <field>]
+//	[WHERE <condition>] [ORDER BY <field> [ASC | DESC]] [LIMIT <n>]
+//
+// The condition is composed of `field <op> value` by AND, OR and parentheses, the op could be
+// =, !=, <, <=, >, >=, LIKE and IN.
+type SelectStatement struct {
+	Fields  []string
+	From    SourceRef
+	Join    *JoinClause
+	Where   *Condition
+	OrderBy string
+	Desc    bool
+	Limit   int
+}
+
+// SourceRef is the source to query with the alias
+type SourceRef struct {
+	Source string
+	Alias  string
+}
+
+// JoinClause joins the rows of the source on the equality of the fields
+type JoinClause struct {
+	SourceRef
+	Left  string
+	Right string
+}
+
+// Condition is the condition in the WHERE clause, it's either a comparison or the combination of the
+// sub conditions by AND or OR
+type Condition struct {
+	// Op is one of the comparison operators, or AND, OR for the combination of the sub conditions
+	Op     string
+	Field  string
+	Values []interface{}
+	Sub    []*Condition
+}
+
+const (
+	opAnd  = "AND"
+	opOr   = "OR"
+	opLike = "LIKE"
+	opIn   = "IN"
+)
+
+var sqlKeywords = map[string]bool{
+	"SELECT": true, "FROM": true, "JOIN": true, "ON": true, "WHERE": true, opAnd: true, opOr: true,
+	opLike: true, opIn: true, "ORDER": true, "BY": true, "ASC": true, "DESC": true, "LIMIT": true,
+}
+
+type sqlToken struct {
+	text string
+	// quoted marks the string literal
+	quoted bool
+}
+
+type sqlParser struct {
+	tokens []sqlToken
+	pos    int
+}
+
+// ParseSelect parse the ad-hoc query in the SQL-like syntax
+func ParseSelect(sql string) (*SelectStatement, error) {
+	tokens, err := tokenizeSQL(sql)
+	if err != nil {
+		return nil, err
+	}
+	p := &sqlParser{tokens: tokens}
+	stmt, err := p.parseSelect()
+	if err != nil {
+		return nil, errors.WithMessage(err, "fail to parse the query")
+	}
+	return stmt, nil
+}
+
+func tokenizeSQL(sql string) ([]sqlToken, error) {
+	var tokens []sqlToken
+	runes := []rune(sql)
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		switch {
+		case unicode.IsSpace(r):
+			i++
+		case r == '\'' || r == '"':
+			end := i + 1
+			for end < len(runes) && runes[end] != r {
+				end++
+			}
+			if end >= len(runes) {
+				return nil, errors.Errorf("unterminated string at %d", i)
+			}
+			tokens = append(tokens, sqlToken{text: string(runes[i+1 : end]), quoted: true})
+			i = end + 1
+		case strings.ContainsRune(",()*", r):
+			tokens = append(tokens, sqlToken{text: string(r)})
+			i++
+		case strings.ContainsRune("=!<>", r):
+			op := string(r)
+			if i+1 < len(runes) && (runes[i+1] == '=' || (r == '<' && runes[i+1] == '>')) {
+				op += string(runes[i+1])
+			}
+			i += len(op)
+			switch op {
+			case "!":
+				return nil, errors.Errorf("unexpected ! at %d", i-1)
+			case "<>":
+				op = "!="
+			}
+			tokens = append(tokens, sqlToken{text: op})
+		default:
+			end := i
+			for end < len(runes) && (unicode.IsLetter(runes[end]) || unicode.IsDigit(runes[end]) || strings.ContainsRune("_.-/", runes[end])) {
+				end++
+			}
+			if end == i {
+				return nil, errors.Errorf("unexpected %c at %d", r, i)
+			}
+			tokens = append(tokens, sqlToken{text: string(runes[i:end])})
+			i = end
+		}
+	}
+	return tokens, nil
+}
+
+func (p *sqlParser) peek() (sqlToken, bool) {
+	if p.pos >= len(p.tokens) {
+		return sqlToken{}, false
+	}
+	return p.tokens[p.pos], true
+}
+
+func (p *sqlParser) next() (sqlToken, error) {
+	t, ok := p.peek()
+	if !ok {
+		return t, errors.New("unexpected end of the query")
+	}
+	p.pos++
+	return t, nil
+}
+
+// acceptKeyword consumes the next token if it's the keyword
+func (p *sqlParser) acceptKeyword(keyword string) bool {
+	t, ok := p.peek()
+	if ok && !t.quoted && strings.EqualFold(t.text, keyword) {
+		p.pos++
+		return true
+	}
+	return false
+}
+
+func (p *sqlParser) expectKeyword(keyword string) error {
+	if !p.acceptKeyword(keyword) {
+		t, _ := p.peek()
+		return errors.Errorf("expect %s but got %q", keyword, t.text)
+	}
+	return nil
+}
+
+func (p *sqlParser) identifier() (string, error) {
+	t, err := p.next()
+	if err != nil {
+		return "", err
+	}
+	if t.quoted || sqlKeywords[strings.ToUpper(t.text)] || strings.ContainsAny(t.text, ",()*=!<>") {
+		return "", errors.Errorf("expect a name but got %q", t.text)
+	}
+	return t.text, nil
+}
+
+func (p *sqlParser) parseSelect() (*SelectStatement, error) {
+	stmt := &SelectStatement{}
+	if err := p.expectKeyword("SELECT"); err != nil {
+		return nil, err
+	}
+	if p.acceptKeyword("*") {
+		stmt.Fields = nil
+	} else {
+		for {
+			field, err := p.identifier()
+			if err != nil {
+				return nil, err
+			}
+			stmt.Fields = append(stmt.Fields, field)
+			if !p.acceptKeyword(",") {
+				break
+			}
+		}
+	}
+	if err := p.expectKeyword("FROM"); err != nil {
+		return nil, err
+	}
+	var err error
+	if stmt.From, err = p.sourceRef(); err != nil {
+		return nil, err
+	}
+	if p.acceptKeyword("JOIN") {
+		join := &JoinClause{}
+		if join.SourceRef, err = p.sourceRef(); err != nil {
+			return nil, err
+		}
+		if err = p.expectKeyword("ON"); err != nil {
+			return nil, err
+		}
+		if join.Left, err = p.identifier(); err != nil {
+			return nil, err
+		}
+		if err = p.expectKeyword("="); err != nil {
+			return nil, err
+		}
+		if join.Right, err = p.identifier(); err != nil {
+			return nil, err
+		}
+		stmt.Join = join
+	}
+	if p.acceptKeyword("WHERE") {
+		if stmt.Where, err = p.parseOr(); err != nil {
+			return nil, err
+		}
+	}
+	if p.acceptKeyword("ORDER") {
+		if err = p.expectKeyword("BY"); err != nil {
+			return nil, err
+		}
+		if stmt.OrderBy, err = p.identifier(); err != nil {
+			return nil, err
+		}
+		if p.acceptKeyword("DESC") {
+			stmt.Desc = true
+		} else {
+			p.acceptKeyword("ASC")
+		}
+	}
+	if p.acceptKeyword("LIMIT") {
+		t, err := p.next()
+		if err != nil {
+			return nil, err
+		}
+		if stmt.Limit, err = strconv.Atoi(t.text); err != nil || stmt.Limit < 0 {
+			return nil, errors.Errorf("invalid limit %q", t.text)
+		}
+	}
+	if t, ok := p.peek(); ok {
+		return nil, errors.Errorf("unexpected %q", t.text)
+	}
+	return stmt, nil
+}
+
+func (p *sqlParser) sourceRef() (SourceRef, error) {
+	source, err := p.identifier()
+	if err != nil {
+		return SourceRef{}, err
+	}
+	ref := SourceRef{Source: strings.ToLower(source), Alias: strings.ToLower(source)}
+	if t, ok := p.peek(); ok && !t.quoted && !sqlKeywords[strings.ToUpper(t.text)] {
+		if ref.Alias, err = p.identifier(); err != nil {
+			return SourceRef{}, err
+		}
+	}
+	return ref, nil
+}
+
+func (p *sqlParser) parseOr() (*Condition, error) {
+	return p.parseCombination(opOr, p.parseAnd)
+}
+
+func (p *sqlParser) parseAnd() (*Condition, error) {
+	return p.parseCombination(opAnd, p.parseCondition)
+}
+
+func (p *sqlParser) parseCombination(op string, parseSub func() (*Condition, error)) (*Condition, error) {
+	cond, err := parseSub()
+	if err != nil {
+		return nil, err
+	}
+	combined := &Condition{Op: op, Sub: []*Condition{cond}}
+	for p.acceptKeyword(op) {
+		if cond, err = parseSub(); err != nil {
+			return nil, err
+		}
+		combined.Sub = append(combined.Sub, cond)
+	}
+	if len(combined.Sub) == 1 {
+		return combined.Sub[0], nil
+	}
+	return combined, nil
+}
+
+func (p *sqlParser) parseCondition() (*Condition, error) {
+	if p.acceptKeyword("(") {
+		cond, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		return cond, p.expectKeyword(")")
+	}
+	field, err := p.identifier()
+	if err != nil {
+		return nil, err
+	}
+	t, err := p.next()
+	if err != nil {
+		return nil, err
+	}
+	cond := &Condition{Field: field, Op: strings.ToUpper(t.text)}
+	switch {
+	case t.quoted:
+		return nil, errors.Errorf("expect an operator after %s but got %q", field, t.text)
+	case cond.Op == opIn:
+		if err = p.expectKeyword("("); err != nil {
+			return nil, err
+		}
+		for {
+			v, err := p.literal()
+			if err != nil {
+				return nil, err
+			}
+			cond.Values = append(cond.Values, v)
+			if !p.acceptKeyword(",") {
+				break
+			}
+		}
+		return cond, p.expectKeyword(")")
+	case cond.Op == opLike, compareOps[cond.Op]:
+		v, err := p.literal()
+		if err != nil {
+			return nil, err
+		}
+		cond.Values = []interface{}{v}
+		return cond, nil
+	default:
+		return nil, errors.Errorf("unknown operator %q", t.text)
+	}
+}
+
+var compareOps = map[string]bool{"=": true, "!=": true, "<": true, "<=": true, ">": true, ">=": true}
+
+func (p *sqlParser) literal() (interface{}, error) {
+	t, err := p.next()
+	if err != nil {
+		return nil, err
+	}
+	if t.quoted {
+		return t.text, nil
+	}
+	if strings.ContainsAny(t.text, ",()*=!<>") {
+		return nil, errors.Errorf("expect a value but got %q", t.text)
+	}
+	return string2OtherType(t.text), nil
+}
+
+// match return whether the row matches the condition, the fields are resolved by the resolver
+func (c *Condition) match(resolve func(field string) (interface{}, error)) (bool, error) {
+	switch c.Op {
+	case opAnd, opOr:
+		for _, sub := range c.Sub {
+			matched, err := sub.match(resolve)
+			if err != nil {
+				return false, err
+			}
+			if matched == (c.Op == opOr) {
+				return matched, nil
+			}
+		}
+		return c.Op == opAnd, nil
+	}
+	v, err := resolve(c.Field)
+	if err != nil {
+		return false, err
+	}
+	switch c.Op {
+	case opIn:
+		for _, expected := range c.Values {
+			if compareValues(v, expected) == 0 {
+				return true, nil
+			}
+		}
+		return false, nil
+	case opLike:
+		pattern := "^" + strings.NewReplacer("%", ".*", "_", ".").Replace(regexp.QuoteMeta(fmt.Sprint(c.Values[0]))) + "$"
+		return regexp.MatchString(pattern, formatValue(v))
+	default:
+		cmp := compareValues(v, c.Values[0])
+		switch c.Op {
+		case "=":
+			return cmp == 0, nil
+		case "!=":
+			return cmp != 0, nil
+		case "<":
+			return cmp < 0, nil
+		case "<=":
+			return cmp <= 0, nil
+		case ">":
+			return cmp > 0, nil
+		default:
+			return cmp >= 0, nil
+		}
+	}
+}
+
+// compareValues compare the values as numbers if both are numbers, otherwise as strings
+func compareValues(a, b interface{}) int {
+	fa, aIsNumber := toFloat(a)
+	fb, bIsNumber := toFloat(b)
+	if aIsNumber && bIsNumber {
+		switch {
+		case fa < fb:
+			return -1
+		case fa > fb:
+			return 1
+		default:
+			return 0
+		}
+	}
+	return strings.Compare(formatValue(a), formatValue(b))
+}
+
+func toFloat(v interface{}) (float64, bool) {
+	switch n := v.(type) {
+	case int:
+		return float64(n), true
+	case int64:
+		return float64(n), true
+	case float64:
+		return n, true
+	default:
+		return 0, false
+	}
+}
+
+// formatValue format the value of the field to string
+func formatValue(v interface{}) string {
+	switch val := v.(type) {
+	case nil:
+		return ""
+	case []string:
+		return strings.Join(val, ",")
+	case map[string]string:
+		var kvs []string
+		for k, v := range val {
+			kvs = append(kvs, k+"="+v)
+		}
+		sort.Strings(kvs)
+		return strings.Join(kvs, ",")
+	default:
+		return fmt.Sprint(v)
+	}
+}
@@ -0,0 +1,494 @@
+/*
+ Copyright 2022 The KubeVela Authors.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package velaql
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/oam-dev/kubevela/apis/core.oam.dev/v1beta1"
+	"github.com/oam-dev/kubevela/pkg/multicluster"
+	"github.com/oam-dev/kubevela/pkg/velaql/providers/query"
+)
+
+// QueryResult is the result of the ad-hoc query
+type QueryResult struct {
+	Columns []string        `json:"columns"`
+	Rows    [][]interface{} `json:"rows"`
+	// Warnings are the applications failed to be queried, their rows are skipped
+	Warnings []string `json:"warnings,omitempty"`
+}
+
+// Records return the rows as the objects keyed by the columns
+func (r *QueryResult) Records() []map[string]interface{} {
+	records := make([]map[string]interface{}, 0, len(r.Rows))
+	for _, row := range r.Rows {
+		record := make(map[string]interface{}, len(r.Columns))
+		for i, column := range r.Columns {
+			record[column] = row[i]
+		}
+		records = append(records, record)
+	}
+	return records
+}
+
+// TableRows return the rows with the values formatted as strings
+func (r *QueryResult) TableRows() [][]string {
+	rows := make([][]string, 0, len(r.Rows))
+	for _, row := range r.Rows {
+		values := make([]string, 0, len(row))
+		for _, v := range row {
+			values = append(values, formatValue(v))
+		}
+		rows = append(rows, values)
+	}
+	return rows
+}
+
+// sqlRow is the row of the source, the columns are keyed by `alias.column` once the row is listed
+type sqlRow map[string]interface{}
+
+// querySource is the source could be queried by the ad-hoc query
+type querySource struct {
+	columns []string
+	// appColumn is the column of the application name, the sources with it are listed by the applications
+	appColumn string
+	rowsOfApp func(ctx context.Context, cli client.Client, app *v1beta1.Application) ([]sqlRow, error)
+	list      func(ctx context.Context, cli client.Client) ([]sqlRow, error)
+}
+
+var querySources = map[string]querySource{
+	"applications": {
+		columns:   []string{"name", "namespace", "phase", "revision", "components", "healthy", "createTime", "labels"},
+		appColumn: "name",
+		rowsOfApp: applicationRows,
+	},
+	"components": {
+		columns:   []string{"app", "namespace", "name", "type", "cluster", "env", "healthy", "message", "traits"},
+		appColumn: "app",
+		rowsOfApp: componentRows,
+	},
+	"resources": {
+		columns:   []string{"app", "namespace", "cluster", "component", "trait", "apiVersion", "kind", "resourceNamespace", "name", "revision", "latest"},
+		appColumn: "app",
+		rowsOfApp: resourceRows,
+	},
+	"workflow": {
+		columns:   []string{"app", "namespace", "name", "type", "parent", "phase", "message", "reason", "firstExecuteTime", "lastExecuteTime"},
+		appColumn: "app",
+		rowsOfApp: workflowRows,
+	},
+	"clusters": {
+		columns: []string{"name", "alias", "type", "endpoint", "accepted", "labels"},
+		list:    clusterRows,
+	},
+}
+
+// QuerySources return the sources and their columns could be queried by the ad-hoc query
+func QuerySources() map[string][]string {
+	sources := make(map[string][]string, len(querySources))
+	for name, source := range querySources {
+		sources[name] = source.columns
+	}
+	return sources
+}
+
+// ExecuteSelect run the ad-hoc query. The rows of the applications, components, managed resources and
+// workflow steps are collected in the same way as the query providers, the namespace and the application
+// name in the WHERE clause are used to narrow the applications listed.
+func ExecuteSelect(ctx context.Context, cli client.Client, stmt *SelectStatement) (*QueryResult, error) {
+	refs := []SourceRef{stmt.From}
+	if stmt.Join != nil {
+		if stmt.Join.Alias == stmt.From.Alias {
+			return nil, errors.Errorf("duplicated alias %s, specify different aliases for the joined sources", stmt.From.Alias)
+		}
+		refs = append(refs, stmt.Join.SourceRef)
+	}
+	var columns []string
+	for _, ref := range refs {
+		source, ok := querySources[ref.Source]
+		if !ok {
+			return nil, errors.Errorf("unknown source %s, the supported sources are %s", ref.Source, strings.Join(sourceNames(), ", "))
+		}
+		for _, column := range source.columns {
+			columns = append(columns, ref.Alias+"."+column)
+		}
+	}
+	resolve := func(field string) (string, error) {
+		return resolveColumn(columns, field)
+	}
+	if err := validateFields(stmt, resolve); err != nil {
+		return nil, err
+	}
+
+	rows, warnings, err := listSourceRows(ctx, cli, stmt.From, stmt.Where, resolve)
+	if err != nil {
+		return nil, err
+	}
+	if stmt.Join != nil {
+		joined, joinWarnings, err := listSourceRows(ctx, cli, stmt.Join.SourceRef, stmt.Where, resolve)
+		if err != nil {
+			return nil, err
+		}
+		warnings = append(warnings, joinWarnings...)
+		left, _ := resolve(stmt.Join.Left)
+		right, _ := resolve(stmt.Join.Right)
+		rows = joinRows(rows, joined, left, right)
+	}
+
+	var matched []sqlRow
+	for _, row := range rows {
+		if stmt.Where != nil {
+			ok, err := stmt.Where.match(func(field string) (interface{}, error) {
+				column, err := resolve(field)
+				if err != nil {
+					return nil, err
+				}
+				return row[column], nil
+			})
+			if err != nil {
+				return nil, err
+			}
+			if !ok {
+				continue
+			}
+		}
+		matched = append(matched, row)
+	}
+	if stmt.OrderBy != "" {
+		column, _ := resolve(stmt.OrderBy)
+		sort.SliceStable(matched, func(i, j int) bool {
+			cmp := compareValues(matched[i][column], matched[j][column])
+			if stmt.Desc {
+				return cmp > 0
+			}
+			return cmp < 0
+		})
+	}
+	if stmt.Limit > 0 && len(matched) > stmt.Limit {
+		matched = matched[:stmt.Limit]
+	}
+
+	result := &QueryResult{Columns: stmt.Fields, Rows: [][]interface{}{}, Warnings: warnings}
+	selected := make([]string, 0, len(stmt.Fields))
+	for _, field := range stmt.Fields {
+		column, _ := resolve(field)
+		selected = append(selected, column)
+	}
+	if len(stmt.Fields) == 0 {
+		selected = columns
+		for _, column := range columns {
+			if stmt.Join == nil {
+				column = column[len(stmt.From.Alias)+1:]
+			}
+			result.Columns = append(result.Columns, column)
+		}
+	}
+	for _, row := range matched {
+		values := make([]interface{}, 0, len(selected))
+		for _, column := range selected {
+			values = append(values, row[column])
+		}
+		result.Rows = append(result.Rows, values)
+	}
+	return result, nil
+}
+
+func sourceNames() []string {
+	var names []string
+	for name := range querySources {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// resolveColumn resolve the field to the column qualified by the alias. The field without the alias
+// is resolved to the only column with the name.
+func resolveColumn(columns []string, field string) (string, error) {
+	var found []string
+	for _, column := range columns {
+		if strings.EqualFold(column, field) || strings.HasSuffix(strings.ToLower(column), "."+strings.ToLower(field)) {
+			found = append(found, column)
+		}
+	}
+	switch len(found) {
+	case 0:
+		return "", errors.Errorf("unknown field %s", field)
+	case 1:
+		return found[0], nil
+	default:
+		return "", errors.Errorf("ambiguous field %s, it could be %s", field, strings.Join(found, " or "))
+	}
+}
+
+// validateFields check all the fields referred in the query, so the query fails before listing any rows
+func validateFields(stmt *SelectStatement, resolve func(field string) (string, error)) error {
+	fields := append([]string{}, stmt.Fields...)
+	if stmt.OrderBy != "" {
+		fields = append(fields, stmt.OrderBy)
+	}
+	if stmt.Join != nil {
+		fields = append(fields, stmt.Join.Left, stmt.Join.Right)
+	}
+	var walk func(cond *Condition)
+	walk = func(cond *Condition) {
+		if cond == nil {
+			return
+		}
+		if cond.Field != "" {
+			fields = append(fields, cond.Field)
+		}
+		for _, sub := range cond.Sub {
+			walk(sub)
+		}
+	}
+	walk(stmt.Where)
+	for _, field := range fields {
+		if _, err := resolve(field); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// listSourceRows list the rows of the source with the columns qualified by the alias. The application failed
+// to be queried is skipped and reported in the warnings, so one broken application doesn't fail the whole query.
+func listSourceRows(ctx context.Context, cli client.Client, ref SourceRef, where *Condition, resolve func(field string) (string, error)) ([]sqlRow, []string, error) {
+	source := querySources[ref.Source]
+	var rows []sqlRow
+	var warnings []string
+	if source.list != nil {
+		var err error
+		if rows, err = source.list(ctx, cli); err != nil {
+			return nil, nil, err
+		}
+	} else {
+		namespace := scopeOf(where, ref.Alias+".namespace", resolve)
+		name := scopeOf(where, ref.Alias+"."+source.appColumn, resolve)
+		apps := v1beta1.ApplicationList{}
+		if err := cli.List(ctx, &apps, client.InNamespace(namespace)); err != nil {
+			return nil, nil, errors.Wrapf(err, "fail to list the applications")
+		}
+		for i := range apps.Items {
+			app := &apps.Items[i]
+			if name != "" && app.Name != name {
+				continue
+			}
+			appRows, err := source.rowsOfApp(ctx, cli, app)
+			if err != nil {
+				warnings = append(warnings, fmt.Sprintf("fail to query %s of the application %s/%s: %s", ref.Source, app.Namespace, app.Name, err.Error()))
+				continue
+			}
+			rows = append(rows, appRows...)
+		}
+	}
+	for i, row := range rows {
+		qualified := make(sqlRow, len(row))
+		for column, v := range row {
+			qualified[ref.Alias+"."+column] = v
+		}
+		rows[i] = qualified
+	}
+	return rows, warnings, nil
+}
+
+// scopeOf return the value the column must equal to by the condition, or empty if the column is not restricted
+func scopeOf(where *Condition, column string, resolve func(field string) (string, error)) string {
+	if where == nil {
+		return ""
+	}
+	conds := []*Condition{where}
+	if where.Op == opAnd {
+		conds = where.Sub
+	}
+	for _, cond := range conds {
+		if cond.Op != "=" {
+			continue
+		}
+		if resolved, err := resolve(cond.Field); err != nil || resolved != column {
+			continue
+		}
+		if v, ok := cond.Values[0].(string); ok {
+			return v
+		}
+	}
+	return ""
+}
+
+// joinRows join the rows on the equality of the columns, the columns could be of either side
+func joinRows(left, right []sqlRow, leftColumn, rightColumn string) []sqlRow {
+	valueOf := func(l, r sqlRow, column string) interface{} {
+		if v, ok := l[column]; ok {
+			return v
+		}
+		return r[column]
+	}
+	var rows []sqlRow
+	for _, l := range left {
+		for _, r := range right {
+			if compareValues(valueOf(l, r, leftColumn), valueOf(l, r, rightColumn)) != 0 {
+				continue
+			}
+			row := make(sqlRow, len(l)+len(r))
+			for k, v := range l {
+				row[k] = v
+			}
+			for k, v := range r {
+				row[k] = v
+			}
+			rows = append(rows, row)
+		}
+	}
+	return rows
+}
+
+func formatTime(t metav1.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.Format(time.RFC3339)
+}
+
+func applicationRows(_ context.Context, _ client.Client, app *v1beta1.Application) ([]sqlRow, error) {
+	healthy := len(app.Status.Services) > 0
+	for _, service := range app.Status.Services {
+		healthy = healthy && service.Healthy
+	}
+	revision := ""
+	if app.Status.LatestRevision != nil {
+		revision = app.Status.LatestRevision.Name
+	}
+	return []sqlRow{{
+		"name":       app.Name,
+		"namespace":  app.Namespace,
+		"phase":      string(app.Status.Phase),
+		"revision":   revision,
+		"components": len(app.Spec.Components),
+		"healthy":    healthy,
+		"createTime": formatTime(app.CreationTimestamp),
+		"labels":     app.Labels,
+	}}, nil
+}
+
+func componentRows(_ context.Context, _ client.Client, app *v1beta1.Application) ([]sqlRow, error) {
+	var rows []sqlRow
+	for _, comp := range app.Spec.Components {
+		var traits []string
+		for _, trait := range comp.Traits {
+			traits = append(traits, trait.Type)
+		}
+		newRow := func() sqlRow {
+			return sqlRow{"app": app.Name, "namespace": app.Namespace, "name": comp.Name, "type": comp.Type, "traits": traits,
+				"cluster": "", "env": "", "healthy": false, "message": ""}
+		}
+		found := false
+		for _, service := range app.Status.Services {
+			if service.Name != comp.Name {
+				continue
+			}
+			found = true
+			row := newRow()
+			row["cluster"] = service.Cluster
+			if service.Cluster == "" {
+				row["cluster"] = multicluster.ClusterLocalName
+			}
+			row["env"] = service.Env
+			row["healthy"] = service.Healthy
+			row["message"] = service.Message
+			rows = append(rows, row)
+		}
+		// the component not dispatched yet has no status
+		if !found {
+			rows = append(rows, newRow())
+		}
+	}
+	return rows, nil
+}
+
+func resourceRows(ctx context.Context, cli client.Client, app *v1beta1.Application) ([]sqlRow, error) {
+	collector := query.NewAppCollector(cli, query.Option{Name: app.Name, Namespace: app.Namespace})
+	resources, err := collector.ListApplicationResources(ctx, app)
+	if err != nil {
+		return nil, err
+	}
+	var rows []sqlRow
+	for _, res := range resources {
+		rows = append(rows, sqlRow{
+			"app":               app.Name,
+			"namespace":         app.Namespace,
+			"cluster":           res.Cluster,
+			"component":         res.Component,
+			"trait":             res.Trait,
+			"apiVersion":        res.APIVersion,
+			"kind":              res.Kind,
+			"resourceNamespace": res.Namespace,
+			"name":              res.Name,
+			"revision":          res.Revision,
+			"latest":            res.Latest,
+		})
+	}
+	return rows, nil
+}
+
+func workflowRows(_ context.Context, _ client.Client, app *v1beta1.Application) ([]sqlRow, error) {
+	if app.Status.Workflow == nil {
+		return nil, nil
+	}
+	var rows []sqlRow
+	for _, step := range app.Status.Workflow.Steps {
+		rows = append(rows, sqlRow{
+			"app": app.Name, "namespace": app.Namespace, "name": step.Name, "type": step.Type, "parent": "",
+			"phase": string(step.Phase), "message": step.Message, "reason": step.Reason,
+			"firstExecuteTime": formatTime(step.FirstExecuteTime), "lastExecuteTime": formatTime(step.LastExecuteTime),
+		})
+		for _, sub := range step.SubStepsStatus {
+			rows = append(rows, sqlRow{
+				"app": app.Name, "namespace": app.Namespace, "name": sub.Name, "type": sub.Type, "parent": step.Name,
+				"phase": string(sub.Phase), "message": sub.Message, "reason": sub.Reason,
+				"firstExecuteTime": formatTime(sub.FirstExecuteTime), "lastExecuteTime": formatTime(sub.LastExecuteTime),
+			})
+		}
+	}
+	return rows, nil
+}
+
+func clusterRows(ctx context.Context, cli client.Client) ([]sqlRow, error) {
+	clusters, err := multicluster.ListVirtualClusters(ctx, cli)
+	if err != nil {
+		return nil, errors.Wrapf(err, "fail to list the clusters")
+	}
+	var rows []sqlRow
+	for _, cluster := range clusters {
+		rows = append(rows, sqlRow{
+			"name":     cluster.Name,
+			"alias":    cluster.Alias,
+			"type":     string(cluster.Type),
+			"endpoint": cluster.EndPoint,
+			"accepted": cluster.Accepted,
+			"labels":   cluster.Labels,
+		})
+	}
+	return rows, nil
+}
@@ -0,0 +1,223 @@
+/*
+ Copyright 2022 The KubeVela Authors.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package velaql
+
+import (
+	"context"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	workflowv1alpha1 "github.com/kubevela/workflow/api/v1alpha1"
+
+	"github.com/oam-dev/kubevela/apis/core.oam.dev/common"
+	"github.com/oam-dev/kubevela/apis/core.oam.dev/v1beta1"
+	"github.com/oam-dev/kubevela/pkg/oam"
+	utilcommon "github.com/oam-dev/kubevela/pkg/utils/common"
+)
+
+func TestParseSelect(t *testing.T) {
+	stmt, err := ParseSelect(`select a.name, r.kind from applications a join resources r on a.name = r.app
+		where (a.namespace = 'prod' or a.namespace = "staging") and r.kind in (Deployment, StatefulSet) and a.components >= 2
+		order by a.name desc limit 10`)
+	assert.NoError(t, err)
+	assert.Equal(t, &SelectStatement{
+		Fields: []string{"a.name", "r.kind"},
+		From:   SourceRef{Source: "applications", Alias: "a"},
+		Join:   &JoinClause{SourceRef: SourceRef{Source: "resources", Alias: "r"}, Left: "a.name", Right: "r.app"},
+		Where: &Condition{Op: opAnd, Sub: []*Condition{
+			{Op: opOr, Sub: []*Condition{
+				{Op: "=", Field: "a.namespace", Values: []interface{}{"prod"}},
+				{Op: "=", Field: "a.namespace", Values: []interface{}{"staging"}},
+			}},
+			{Op: opIn, Field: "r.kind", Values: []interface{}{"Deployment", "StatefulSet"}},
+			{Op: ">=", Field: "a.components", Values: []interface{}{int64(2)}},
+		}},
+		OrderBy: "a.name",
+		Desc:    true,
+		Limit:   10,
+	}, stmt)
+
+	stmt, err = ParseSelect("SELECT * FROM Workflow WHERE phase <> succeeded")
+	assert.NoError(t, err)
+	assert.Nil(t, stmt.Fields)
+	assert.Equal(t, SourceRef{Source: "workflow", Alias: "workflow"}, stmt.From)
+	assert.Equal(t, &Condition{Op: "!=", Field: "phase", Values: []interface{}{"succeeded"}}, stmt.Where)
+
+	for _, sql := range []string{
+		"",
+		"SELECT FROM applications",
+		"SELECT name applications",
+		"SELECT name FROM applications WHERE",
+		"SELECT name FROM applications WHERE name ~ 'a'",
+		"SELECT name FROM applications WHERE name = 'a",
+		"SELECT name FROM applications WHERE (name = a",
+		"SELECT name FROM applications LIMIT -1",
+		"SELECT name FROM applications LIMIT 1 2",
+	} {
+		_, err = ParseSelect(sql)
+		assert.Error(t, err, sql)
+	}
+}
+
+func TestConditionMatch(t *testing.T) {
+	row := map[string]interface{}{"name": "web-app", "components": 3, "healthy": false, "labels": map[string]string{"team": "a", "env": "prod"}}
+	resolve := func(field string) (interface{}, error) {
+		return row[field], nil
+	}
+	testCases := map[string]bool{
+		"name = web-app":                             true,
+		"name LIKE 'web%'":                           true,
+		"name LIKE 'w_b'":                            false,
+		"components > 2 AND components <= 3":         true,
+		"components IN (1, 2)":                       false,
+		"healthy = false OR name = other":            true,
+		"labels LIKE '%env=prod%'":                   true,
+		"(name = a OR name = b) AND healthy = false": false,
+	}
+	for where, expected := range testCases {
+		stmt, err := ParseSelect("SELECT * FROM applications WHERE " + where)
+		assert.NoError(t, err, where)
+		matched, err := stmt.Where.match(resolve)
+		assert.NoError(t, err, where)
+		assert.Equal(t, expected, matched, where)
+	}
+}
+
+func TestExecuteSelect(t *testing.T) {
+	ctx := context.Background()
+	newApp := func(name, namespace string, healthy bool) *v1beta1.Application {
+		return &v1beta1.Application{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace},
+			Spec: v1beta1.ApplicationSpec{Components: []common.ApplicationComponent{
+				{Name: "web", Type: "webservice", Traits: []common.ApplicationTrait{{Type: "scaler"}}},
+				{Name: "db", Type: "worker"},
+			}},
+			Status: common.AppStatus{
+				Phase: common.ApplicationRunning,
+				Services: []common.ApplicationComponentStatus{
+					{Name: "web", Cluster: "", Healthy: healthy, Message: "ready"},
+					{Name: "web", Cluster: "cluster-1", Healthy: true},
+				},
+				Workflow: &common.WorkflowStatus{Steps: []workflowv1alpha1.WorkflowStepStatus{{
+					StepStatus: workflowv1alpha1.StepStatus{Name: "deploy", Type: "deploy", Phase: workflowv1alpha1.WorkflowStepPhaseSucceeded},
+					SubStepsStatus: []workflowv1alpha1.StepStatus{
+						{Name: "deploy-web", Type: "apply-component", Phase: workflowv1alpha1.WorkflowStepPhaseFailed, Message: "timeout"},
+					},
+				}}},
+			},
+		}
+	}
+	rt := &v1beta1.ResourceTracker{
+		ObjectMeta: metav1.ObjectMeta{Name: "app-1-v1", Labels: map[string]string{oam.LabelAppName: "app-1", oam.LabelAppNamespace: "default"}},
+		Spec: v1beta1.ResourceTrackerSpec{
+			Type: v1beta1.ResourceTrackerTypeVersioned,
+			ManagedResources: []v1beta1.ManagedResource{
+				{
+					ClusterObjectReference: common.ClusterObjectReference{ObjectReference: corev1.ObjectReference{APIVersion: "apps/v1", Kind: "Deployment", Namespace: "default", Name: "web"}},
+					OAMObjectReference:     common.OAMObjectReference{Component: "web"},
+				},
+				{
+					ClusterObjectReference: common.ClusterObjectReference{Cluster: "cluster-1", ObjectReference: corev1.ObjectReference{APIVersion: "v1", Kind: "Service", Namespace: "default", Name: "web"}},
+					OAMObjectReference:     common.OAMObjectReference{Component: "web", Trait: "gateway"},
+				},
+			},
+		},
+	}
+	cli := fake.NewClientBuilder().WithScheme(utilcommon.Scheme).
+		WithObjects(newApp("app-1", "default", false), newApp("app-2", "default", true), newApp("app-3", "prod", true), rt).Build()
+
+	query := func(sql string) (*QueryResult, error) {
+		stmt, err := ParseSelect(sql)
+		assert.NoError(t, err, sql)
+		return ExecuteSelect(ctx, cli, stmt)
+	}
+
+	result, err := query("SELECT name, namespace, healthy FROM applications WHERE namespace = default ORDER BY name DESC")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"name", "namespace", "healthy"}, result.Columns)
+	assert.Equal(t, [][]interface{}{{"app-2", "default", true}, {"app-1", "default", false}}, result.Rows)
+
+	result, err = query("SELECT app, name, cluster, traits FROM components WHERE healthy = false")
+	assert.NoError(t, err)
+	assert.Equal(t, [][]string{
+		{"app-1", "web", "local", "scaler"},
+		{"app-1", "db", "", ""},
+		{"app-2", "db", "", ""},
+		{"app-3", "db", "", ""},
+	}, result.TableRows())
+
+	result, err = query("SELECT app, name, parent FROM workflow WHERE phase = failed AND namespace = prod")
+	assert.NoError(t, err)
+	assert.Equal(t, [][]interface{}{{"app-3", "deploy-web", "deploy"}}, result.Rows)
+
+	result, err = query("SELECT c.app, c.cluster, r.kind FROM components c JOIN resources r ON c.name = r.component WHERE c.app = app-1 AND r.kind = Service")
+	assert.NoError(t, err)
+	assert.Equal(t, [][]interface{}{{"app-1", "local", "Service"}, {"app-1", "cluster-1", "Service"}}, result.Rows)
+	assert.Equal(t, []map[string]interface{}{
+		{"c.app": "app-1", "c.cluster": "local", "r.kind": "Service"},
+		{"c.app": "app-1", "c.cluster": "cluster-1", "r.kind": "Service"},
+	}, result.Records())
+
+	result, err = query("SELECT r.name, r.kind, c.name FROM resources r JOIN clusters c ON r.cluster = c.name")
+	assert.NoError(t, err)
+	assert.Equal(t, [][]interface{}{{"web", "Deployment", "local"}}, result.Rows)
+
+	result, err = query("SELECT * FROM applications LIMIT 1")
+	assert.NoError(t, err)
+	assert.Equal(t, querySources["applications"].columns, result.Columns)
+	assert.Equal(t, 1, len(result.Rows))
+
+	result, err = query("SELECT c.name, r.kind FROM resources r JOIN components c ON c.name = r.component WHERE r.app = app-1 AND c.app = app-1 AND c.cluster = local")
+	assert.NoError(t, err)
+	assert.Equal(t, [][]interface{}{{"web", "Deployment"}, {"web", "Service"}}, result.Rows)
+
+	// the application failed to be queried is skipped with a warning
+	stmt, err := ParseSelect("SELECT app, kind FROM resources WHERE namespace = default")
+	assert.NoError(t, err)
+	result, err = ExecuteSelect(ctx, &failingListClient{Client: cli}, stmt)
+	assert.NoError(t, err)
+	assert.Empty(t, result.Rows)
+	assert.Equal(t, 2, len(result.Warnings))
+	assert.Contains(t, result.Warnings[0], "fail to query resources of the application default/app-1")
+
+	_, err = query("SELECT name FROM pods")
+	assert.Contains(t, err.Error(), "unknown source pods")
+	_, err = query("SELECT foo FROM applications")
+	assert.Contains(t, err.Error(), "unknown field foo")
+	_, err = query("SELECT name FROM applications a JOIN components c ON a.name = c.app")
+	assert.Contains(t, err.Error(), "ambiguous field name")
+	_, err = query("SELECT a.name FROM applications a JOIN components a ON a.name = a.app")
+	assert.Contains(t, err.Error(), "duplicated alias a")
+}
+
+// failingListClient fails to list the resource trackers
+type failingListClient struct {
+	client.Client
+}
+
+func (c *failingListClient) List(ctx context.Context, list client.ObjectList, opts ...client.ListOption) error {
+	if _, ok := list.(*v1beta1.ResourceTrackerList); ok {
+		return errors.New("the resource trackers are not available")
+	}
+	return c.Client.List(ctx, list, opts...)
+}
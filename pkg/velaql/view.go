@@ -41,6 +41,7 @@ import (
 	"github.com/kubevela/workflow/pkg/generator"
 	"github.com/kubevela/workflow/pkg/providers"
 	"github.com/kubevela/workflow/pkg/providers/kube"
+	wfTemplate "github.com/kubevela/workflow/pkg/tasks/template"
 	wfTypes "github.com/kubevela/workflow/pkg/types"
 
 	"github.com/oam-dev/kubevela/apis/types"
@@ -77,6 +78,7 @@ type ViewHandler struct {
 	dm        discoverymapper.DiscoveryMapper
 	pd        *packages.PackageDiscover
 	namespace string
+	cache     *ViewCache
 }
 
 // NewViewHandler new view handler
@@ -90,8 +92,45 @@ func NewViewHandler(cli client.Client, cfg *rest.Config, dm discoverymapper.Disc
 	}
 }
 
-// QueryView generate view step
+// WithCache caches the query results of the handler in the cache
+func (handler *ViewHandler) WithCache(cache *ViewCache) *ViewHandler {
+	handler.cache = cache
+	return handler
+}
+
+// QueryView validates the parameter by the parameter schema of the view, and returns the cached result if
+// the handler has a cache, otherwise generates and runs the view step
 func (handler *ViewHandler) QueryView(ctx context.Context, qv QueryView) (*value.Value, error) {
+	loader := template.NewViewTemplateLoader(handler.cli, handler.namespace)
+	viewName := qv.View
+	if len(strings.Split(qv.View, "\n")) > 2 {
+		loader = &template.EchoLoader{}
+		viewName = "from file"
+	}
+	view, err := loader.LoadTemplate(ctx, qv.View)
+	if err != nil {
+		return nil, err
+	}
+	if qv.Parameter, err = ValidateViewParameter(viewName, view, qv.Parameter); err != nil {
+		return nil, err
+	}
+	if handler.cache != nil {
+		if result, ok := handler.cache.Get(ctx, handler.cli, qv); ok {
+			return result, nil
+		}
+	}
+	result, err := handler.runView(ctx, qv, loader)
+	if err != nil {
+		return nil, err
+	}
+	if handler.cache != nil {
+		handler.cache.Set(ctx, handler.cli, qv, result)
+	}
+	return result, nil
+}
+
+// runView generates the view step and runs it
+func (handler *ViewHandler) runView(ctx context.Context, qv QueryView, loader wfTemplate.Loader) (*value.Value, error) {
 	outputsTemplate := fmt.Sprintf(OutputsTemplate, qv.Export, qv.Export)
 	queryKey := QueryParameterKey{}
 	if err := json.Unmarshal([]byte(outputsTemplate), &queryKey); err != nil {
@@ -129,10 +168,6 @@ func (handler *ViewHandler) QueryView(ctx context.Context, qv QueryView) (*value
 		Delete: handler.delete,
 	})
 	query.Install(handlerProviders, handler.cli, handler.cfg)
-	loader := template.NewViewTemplateLoader(handler.cli, handler.namespace)
-	if len(strings.Split(qv.View, "\n")) > 2 {
-		loader = &template.EchoLoader{}
-	}
 	logCtx := monitorContext.NewTraceContext(ctx, "").AddTag("velaql")
 	runners, err := generator.GenerateRunners(logCtx, instance, wfTypes.StepGeneratorOptions{
 		Providers:       handlerProviders,
@@ -22,6 +22,7 @@ import (
 	"net/http"
 
 	"github.com/pkg/errors"
+	admissionv1 "k8s.io/api/admission/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	utilfeature "k8s.io/apiserver/pkg/util/feature"
 	"k8s.io/klog/v2"
@@ -32,26 +33,26 @@ import (
 
 	"github.com/oam-dev/kubevela/apis/core.oam.dev/v1beta1"
 	"github.com/oam-dev/kubevela/pkg/auth"
+	"github.com/oam-dev/kubevela/pkg/controller/sharding"
 	"github.com/oam-dev/kubevela/pkg/features"
 	"github.com/oam-dev/kubevela/pkg/oam"
 	"github.com/oam-dev/kubevela/pkg/utils"
 )
 
-// MutatingHandler adding user info to application annotations
+// MutatingHandler adding user info to application annotations and scheduling application to controller shards
 type MutatingHandler struct {
-	skipUsers []string
-	Decoder   *admission.Decoder
+	skipUsers   []string
+	coordinator *sharding.Coordinator
+	Decoder     *admission.Decoder
 }
 
 var _ admission.Handler = &MutatingHandler{}
 
 // Handle mutate application
 func (h *MutatingHandler) Handle(ctx context.Context, req admission.Request) admission.Response {
-	if !utilfeature.DefaultMutableFeatureGate.Enabled(features.AuthenticateApplication) {
-		return admission.Patched("")
-	}
-
-	if slices.Contains(h.skipUsers, req.UserInfo.Username) {
+	authenticate := utilfeature.DefaultMutableFeatureGate.Enabled(features.AuthenticateApplication) &&
+		!slices.Contains(h.skipUsers, req.UserInfo.Username)
+	if !authenticate && h.coordinator == nil {
 		return admission.Patched("")
 	}
 
@@ -60,11 +61,19 @@ func (h *MutatingHandler) Handle(ctx context.Context, req admission.Request) adm
 		return admission.Errored(http.StatusBadRequest, err)
 	}
 
-	if metav1.HasAnnotation(app.ObjectMeta, oam.AnnotationApplicationServiceAccountName) {
-		return admission.Errored(http.StatusBadRequest, errors.New("service-account annotation is not permitted when authentication enabled"))
+	if authenticate {
+		if metav1.HasAnnotation(app.ObjectMeta, oam.AnnotationApplicationServiceAccountName) {
+			return admission.Errored(http.StatusBadRequest, errors.New("service-account annotation is not permitted when authentication enabled"))
+		}
+		klog.Infof("[ApplicationMutatingHandler] Setting UserInfo into Application, UserInfo: %v, Application: %s/%s", req.UserInfo, app.GetNamespace(), app.GetName())
+		auth.SetUserInfoInAnnotation(&app.ObjectMeta, req.UserInfo)
+	}
+
+	if h.coordinator != nil {
+		if err := h.schedule(req, app); err != nil {
+			return admission.Errored(http.StatusBadRequest, err)
+		}
 	}
-	klog.Infof("[ApplicationMutatingHandler] Setting UserInfo into Application, UserInfo: %v, Application: %s/%s", req.UserInfo, app.GetNamespace(), app.GetName())
-	auth.SetUserInfoInAnnotation(&app.ObjectMeta, req.UserInfo)
 
 	bs, err := json.Marshal(app)
 	if err != nil {
@@ -73,6 +82,30 @@ func (h *MutatingHandler) Handle(ctx context.Context, req admission.Request) adm
 	return admission.PatchResponseFromRaw(req.AdmissionRequest.Object.Raw, bs)
 }
 
+// schedule set the controller shard for the created application. For the updated application, the scheduled shard
+// is kept since it can only be changed by the controller, which moves the resourcetrackers together.
+func (h *MutatingHandler) schedule(req admission.Request, app *v1beta1.Application) error {
+	switch req.Operation {
+	case admissionv1.Create:
+		if shardID := h.coordinator.Schedule(app); shardID != "" {
+			sharding.SetScheduledShardID(app, shardID)
+		}
+	case admissionv1.Update:
+		if slices.Contains(h.skipUsers, req.UserInfo.Username) {
+			return nil
+		}
+		old := &v1beta1.Application{}
+		if err := h.Decoder.DecodeRaw(req.OldObject, old); err != nil {
+			return err
+		}
+		if shardID := sharding.GetScheduledShardID(old); shardID != "" {
+			sharding.SetScheduledShardID(app, shardID)
+		}
+	default:
+	}
+	return nil
+}
+
 var _ admission.DecoderInjector = &MutatingHandler{}
 
 // InjectDecoder .
@@ -84,7 +117,7 @@ func (h *MutatingHandler) InjectDecoder(d *admission.Decoder) error {
 // RegisterMutatingHandler will register component mutation handler to the webhook
 func RegisterMutatingHandler(mgr manager.Manager) {
 	server := mgr.GetWebhookServer()
-	handler := &MutatingHandler{}
+	handler := &MutatingHandler{coordinator: sharding.DefaultCoordinator}
 	if userInfo := utils.GetUserInfoFromConfig(mgr.GetConfig()); userInfo != nil {
 		klog.Infof("[ApplicationMutatingHandler] add skip user %s", userInfo.Username)
 		handler.skipUsers = []string{userInfo.Username}
@@ -44,6 +44,8 @@ type ValidatingHandler struct {
 	dm     discoverymapper.DiscoveryMapper
 	pd     *packages.PackageDiscover
 	Client client.Client
+	// APIReader reads the objects without the cache
+	APIReader client.Reader
 	// Decoder decodes objects
 	Decoder *admission.Decoder
 }
@@ -120,5 +122,5 @@ func (h *ValidatingHandler) Handle(ctx context.Context, req admission.Request) a
 // RegisterValidatingHandler will register application validate handler to the webhook
 func RegisterValidatingHandler(mgr manager.Manager, args controller.Args) {
 	server := mgr.GetWebhookServer()
-	server.Register("/validating-core-oam-dev-v1beta1-applications", &webhook.Admission{Handler: &ValidatingHandler{dm: args.DiscoveryMapper, pd: args.PackageDiscover}})
+	server.Register("/validating-core-oam-dev-v1beta1-applications", &webhook.Admission{Handler: &ValidatingHandler{dm: args.DiscoveryMapper, pd: args.PackageDiscover, APIReader: mgr.GetAPIReader()}})
 }
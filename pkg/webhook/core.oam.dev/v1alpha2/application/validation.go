@@ -27,7 +27,9 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	"github.com/oam-dev/kubevela/apis/core.oam.dev/v1beta1"
+	"github.com/oam-dev/kubevela/pkg/appdependency"
 	"github.com/oam-dev/kubevela/pkg/appfile"
+	"github.com/oam-dev/kubevela/pkg/controller/sharding"
 	"github.com/oam-dev/kubevela/pkg/oam"
 	"github.com/oam-dev/kubevela/pkg/oam/util"
 )
@@ -59,6 +61,52 @@ func (h *ValidatingHandler) ValidateWorkflow(ctx context.Context, app *v1beta1.A
 	return errs
 }
 
+// dependencyReader return the reader for reading the depended applications. In sharding mode, the cache only contains
+// the applications scheduled to the current shard, so the uncached reader is used.
+func (h *ValidatingHandler) dependencyReader() client.Reader {
+	if sharding.EnableSharding && h.APIReader != nil {
+		return h.APIReader
+	}
+	return h.Client
+}
+
+// ValidateDependencies validates the depended applications, and rejects the cyclic dependencies across applications
+func (h *ValidatingHandler) ValidateDependencies(ctx context.Context, app *v1beta1.Application) field.ErrorList {
+	var errs field.ErrorList
+	path := field.NewPath("spec", "dependsOn")
+	keys := make(map[string]interface{})
+	for i, dep := range app.Spec.DependsOn {
+		key := appdependency.Key(app, dep).String()
+		switch {
+		case dep.Name == "":
+			errs = append(errs, field.Required(path.Index(i).Child("name"), "name of the depended application is required"))
+		case key == client.ObjectKeyFromObject(app).String():
+			errs = append(errs, field.Invalid(path.Index(i), key, "application cannot depend on itself"))
+		}
+		if _, ok := keys[key]; ok {
+			errs = append(errs, field.Duplicate(path.Index(i), key))
+		}
+		keys[key] = nil
+		switch dep.Condition {
+		case "", v1beta1.AppDependencyConditionHealthy, v1beta1.AppDependencyConditionWorkflowSucceeded:
+		default:
+			errs = append(errs, field.NotSupported(path.Index(i).Child("condition"), dep.Condition,
+				[]string{string(v1beta1.AppDependencyConditionHealthy), string(v1beta1.AppDependencyConditionWorkflowSucceeded)}))
+		}
+	}
+	if len(errs) > 0 || len(app.Spec.DependsOn) == 0 {
+		return errs
+	}
+	cycle, err := appdependency.DetectCycle(ctx, h.dependencyReader(), app)
+	if err != nil {
+		return append(errs, field.InternalError(path, err))
+	}
+	if cycle != nil {
+		errs = append(errs, field.Invalid(path, appdependency.FormatCycle(cycle), "cyclic dependency between applications"))
+	}
+	return errs
+}
+
 // ValidateTimeout validates the timeout of steps
 func (h *ValidatingHandler) ValidateTimeout(name, timeout string) field.ErrorList {
 	var errs field.ErrorList
@@ -100,6 +148,7 @@ func (h *ValidatingHandler) ValidateCreate(ctx context.Context, app *v1beta1.App
 
 	errs = append(errs, h.ValidateWorkflow(ctx, app)...)
 	errs = append(errs, h.ValidateComponents(ctx, app)...)
+	errs = append(errs, h.ValidateDependencies(ctx, app)...)
 	return errs
 }
 
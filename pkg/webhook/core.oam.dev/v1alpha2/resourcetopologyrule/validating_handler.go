@@ -0,0 +1,75 @@
+/*
+Copyright 2022 The KubeVela Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resourcetopologyrule
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	admissionv1 "k8s.io/api/admission/v1"
+	"k8s.io/klog"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	"github.com/oam-dev/kubevela/apis/core.oam.dev/v1alpha1"
+	"github.com/oam-dev/kubevela/pkg/velaql/providers/query"
+)
+
+var resourceTopologyRuleGVR = v1alpha1.SchemeGroupVersion.WithResource("resourcetopologyrules")
+
+// ValidatingHandler handles validation of resource topology rule, so that the invalid rules are rejected
+// rather than ignored silently when building the resource tree
+type ValidatingHandler struct {
+	// Decoder decodes object
+	Decoder *admission.Decoder
+}
+
+var _ admission.Handler = &ValidatingHandler{}
+
+// Handle validate resource topology rule
+func (h *ValidatingHandler) Handle(ctx context.Context, req admission.Request) admission.Response {
+	if req.Resource.String() != resourceTopologyRuleGVR.String() {
+		return admission.Errored(http.StatusBadRequest, fmt.Errorf("expect resource to be %s", resourceTopologyRuleGVR))
+	}
+	if req.Operation == admissionv1.Create || req.Operation == admissionv1.Update {
+		obj := &v1alpha1.ResourceTopologyRule{}
+		if err := h.Decoder.Decode(req, obj); err != nil {
+			return admission.Errored(http.StatusBadRequest, err)
+		}
+		if err := query.ValidateResourceTopologyRule(obj); err != nil {
+			klog.Info("validation failed ", " name: ", obj.Name, " errMsg: ", err.Error())
+			return admission.Denied(err.Error())
+		}
+	}
+	return admission.ValidationResponse(true, "")
+}
+
+var _ admission.DecoderInjector = &ValidatingHandler{}
+
+// InjectDecoder injects the decoder into the ValidatingHandler
+func (h *ValidatingHandler) InjectDecoder(d *admission.Decoder) error {
+	h.Decoder = d
+	return nil
+}
+
+// RegisterValidatingHandler will register ResourceTopologyRule validation to webhook
+func RegisterValidatingHandler(mgr manager.Manager) {
+	server := mgr.GetWebhookServer()
+	server.Register("/validating-core-oam-dev-v1alpha1-resourcetopologyrules", &webhook.Admission{Handler: &ValidatingHandler{}})
+}
@@ -0,0 +1,68 @@
+/*
+Copyright 2022 The KubeVela Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resourcetopologyrule
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	admissionv1 "k8s.io/api/admission/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	"github.com/oam-dev/kubevela/apis/core.oam.dev/v1alpha1"
+)
+
+func TestValidatingHandler(t *testing.T) {
+	r := require.New(t)
+	decoder, err := admission.NewDecoder(runtime.NewScheme())
+	r.NoError(err)
+	handler := &ValidatingHandler{}
+	r.NoError(handler.InjectDecoder(decoder))
+	newRequest := func(resource string, rule *v1alpha1.ResourceTopologyRule) admission.Request {
+		raw, err := json.Marshal(rule)
+		r.NoError(err)
+		return admission.Request{AdmissionRequest: admissionv1.AdmissionRequest{
+			Operation: admissionv1.Create,
+			Resource:  metav1.GroupVersionResource{Group: v1alpha1.Group, Version: v1alpha1.Version, Resource: resource},
+			Object:    runtime.RawExtension{Raw: raw},
+		}}
+	}
+	rule := &v1alpha1.ResourceTopologyRule{
+		ObjectMeta: metav1.ObjectMeta{Name: "deployment-configs"},
+		Spec: v1alpha1.ResourceTopologyRuleSpec{
+			ParentResourceType:    v1alpha1.TopologyParentResourceType{Group: "apps", Kind: "Deployment"},
+			ChildrenResourceTypes: []v1alpha1.TopologyChildResourceType{{APIVersion: "v1", Kind: "ConfigMap"}},
+		},
+	}
+	rule.SetGroupVersionKind(v1alpha1.ResourceTopologyRuleGroupVersionKind)
+
+	resp := handler.Handle(context.Background(), newRequest("foos", rule))
+	r.False(resp.Allowed)
+	resp = handler.Handle(context.Background(), newRequest("resourcetopologyrules", rule))
+	r.True(resp.Allowed)
+
+	rule.Spec.Mode = "Replace"
+	rule.Spec.ChildrenResourceTypes[0].Matcher = v1alpha1.TopologyChildMatcherLabel
+	resp = handler.Handle(context.Background(), newRequest("resourcetopologyrules", rule))
+	r.False(resp.Allowed)
+	r.Contains(string(resp.Result.Reason), "spec.mode")
+	r.Contains(string(resp.Result.Reason), "either selectorPath or matchLabels is required")
+}
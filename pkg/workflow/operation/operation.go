@@ -26,6 +26,7 @@ import (
 	"k8s.io/client-go/util/retry"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
+	workflowv1alpha1 "github.com/kubevela/workflow/api/v1alpha1"
 	wfUtils "github.com/kubevela/workflow/pkg/utils"
 
 	"github.com/oam-dev/kubevela/apis/core.oam.dev/common"
@@ -293,6 +294,45 @@ func (wo appWorkflowOperator) Terminate(ctx context.Context) error {
 	return wo.writeOutputF("Successfully terminate workflow: %s\n", app.Name)
 }
 
+// RetryWorkflowStep retry the failed step or sub-step of the workflow in the application. The status of the
+// step is reset so that it will be executed again while the succeeded steps are kept.
+func RetryWorkflowStep(ctx context.Context, cli client.Client, app *v1beta1.Application, stepName string) error {
+	status := app.Status.Workflow
+	if status == nil {
+		return fmt.Errorf("the workflow in application is not running")
+	}
+	found := false
+	for i := 0; i < len(status.Steps) && !found; i++ {
+		step := &status.Steps[i]
+		if step.Name == stepName {
+			if step.Phase != workflowv1alpha1.WorkflowStepPhaseFailed {
+				return errors.Errorf("step %s is %s, only the failed step can be retried", stepName, step.Phase)
+			}
+			status.Steps = append(status.Steps[:i], status.Steps[i+1:]...)
+			found = true
+			break
+		}
+		for j, sub := range step.SubStepsStatus {
+			if sub.Name != stepName {
+				continue
+			}
+			if sub.Phase != workflowv1alpha1.WorkflowStepPhaseFailed {
+				return errors.Errorf("step %s is %s, only the failed step can be retried", stepName, sub.Phase)
+			}
+			step.SubStepsStatus = append(step.SubStepsStatus[:j], step.SubStepsStatus[j+1:]...)
+			step.Phase, step.Reason, step.Message = workflowv1alpha1.WorkflowStepPhaseRunning, "", ""
+			found = true
+			break
+		}
+	}
+	if !found {
+		return errors.Errorf("step %s is not found in the workflow of application %s", stepName, app.Name)
+	}
+	status.Terminated, status.Suspend, status.Finished = false, false, false
+	status.Message = ""
+	return cli.Status().Update(ctx, app)
+}
+
 func (wo appWorkflowOperator) writeOutput(str string) error {
 	if wo.outputWriter == nil {
 		return nil
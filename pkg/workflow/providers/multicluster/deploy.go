@@ -66,7 +66,7 @@ type deployWorkflowStepExecutor struct {
 
 // Deploy execute deploy workflow step
 func (executor *deployWorkflowStepExecutor) Deploy(ctx context.Context, policyNames []string, parallelism int) (bool, string, error) {
-	policies, err := selectPolicies(executor.af.Policies, policyNames)
+	policies, err := SelectPolicies(executor.af.Policies, policyNames)
 	if err != nil {
 		return false, "", err
 	}
@@ -80,7 +80,7 @@ func (executor *deployWorkflowStepExecutor) Deploy(ctx context.Context, policyNa
 	if err != nil {
 		return false, "", err
 	}
-	components, err = overrideConfiguration(policies, components)
+	components, err = OverrideConfiguration(policies, components)
 	if err != nil {
 		return false, "", err
 	}
@@ -91,7 +91,8 @@ func (executor *deployWorkflowStepExecutor) Deploy(ctx context.Context, policyNa
 	return applyComponents(ctx, executor.apply, executor.healthCheck, components, placements, parallelism)
 }
 
-func selectPolicies(policies []v1beta1.AppPolicy, policyNames []string) ([]v1beta1.AppPolicy, error) {
+// SelectPolicies select the policies by names, error if any of the policies is not found
+func SelectPolicies(policies []v1beta1.AppPolicy, policyNames []string) ([]v1beta1.AppPolicy, error) {
 	policyMap := make(map[string]v1beta1.AppPolicy)
 	for _, policy := range policies {
 		policyMap[policy.Name] = policy
@@ -128,7 +129,8 @@ func loadComponents(ctx context.Context, renderer oamProvider.WorkloadRenderer,
 	return loadedComponents, nil
 }
 
-func overrideConfiguration(policies []v1beta1.AppPolicy, components []common.ApplicationComponent) ([]common.ApplicationComponent, error) {
+// OverrideConfiguration apply the override policies to the components in order
+func OverrideConfiguration(policies []v1beta1.AppPolicy, components []common.ApplicationComponent) ([]common.ApplicationComponent, error) {
 	var err error
 	for _, policy := range policies {
 		if policy.Type == v1alpha1.OverridePolicyType {
@@ -79,7 +79,7 @@ func TestOverrideConfiguration(t *testing.T) {
 	for name, tt := range testCases {
 		t.Run(name, func(t *testing.T) {
 			r := require.New(t)
-			comps, err := overrideConfiguration(tt.Policies, tt.Components)
+			comps, err := OverrideConfiguration(tt.Policies, tt.Components)
 			if tt.Error != "" {
 				r.NotNil(err)
 				r.Contains(err.Error(), tt.Error)
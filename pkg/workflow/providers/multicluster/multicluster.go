@@ -17,7 +17,11 @@ limitations under the License.
 package multicluster
 
 import (
+	"time"
+
 	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	monitorContext "github.com/kubevela/pkg/monitor/context"
@@ -25,10 +29,13 @@ import (
 	"github.com/kubevela/workflow/pkg/cue/model/value"
 	wfTypes "github.com/kubevela/workflow/pkg/types"
 
+	"github.com/oam-dev/kubevela/apis/core.oam.dev/common"
+	"github.com/oam-dev/kubevela/apis/core.oam.dev/condition"
 	"github.com/oam-dev/kubevela/apis/core.oam.dev/v1alpha1"
 	"github.com/oam-dev/kubevela/apis/core.oam.dev/v1beta1"
 	"github.com/oam-dev/kubevela/pkg/appfile"
 	"github.com/oam-dev/kubevela/pkg/multicluster"
+	"github.com/oam-dev/kubevela/pkg/oam"
 	pkgpolicy "github.com/oam-dev/kubevela/pkg/policy"
 	"github.com/oam-dev/kubevela/pkg/policy/envbinding"
 	oamProvider "github.com/oam-dev/kubevela/pkg/workflow/providers/oam"
@@ -37,6 +44,8 @@ import (
 const (
 	// ProviderName is provider name for install.
 	ProviderName = "multicluster"
+	// ReasonOutsideDeployWindow is the condition reason when the deploy is paused by deploy-window policies.
+	ReasonOutsideDeployWindow condition.ConditionReason = "OutsideDeployWindow"
 )
 
 type provider struct {
@@ -187,6 +196,14 @@ func (p *provider) Deploy(ctx monitorContext.Context, wfCtx wfContext.Context, v
 	if err != nil {
 		return err
 	}
+	allowed, reason, err := p.checkDeployWindows(policyNames)
+	if err != nil {
+		return err
+	}
+	if !allowed {
+		act.Wait(reason)
+		return nil
+	}
 	executor := NewDeployWorkflowStepExecutor(p.Client, p.af, p.apply, p.healthCheck, p.renderer, ignoreTerraformComponent)
 	healthy, reason, err := executor.Deploy(ctx, policyNames, int(parallelism))
 	if err != nil {
@@ -198,12 +215,56 @@ func (p *provider) Deploy(ctx monitorContext.Context, wfCtx wfContext.Context, v
 	return nil
 }
 
+// checkDeployWindows check if the deploy step is allowed by the deploy-window policies at present and record
+// the result in the application conditions. The check is skipped if the application has the override annotation.
+func (p *provider) checkDeployWindows(policyNames []string) (bool, string, error) {
+	if p.app == nil || p.app.GetAnnotations()[oam.AnnotationDeployWindowOverride] == "true" {
+		return true, "", nil
+	}
+	hasDeployWindow := false
+	for _, policy := range p.af.Policies {
+		if policy.Type == v1alpha1.DeployWindowPolicyType {
+			hasDeployWindow = true
+			break
+		}
+	}
+	if !hasDeployWindow {
+		return true, "", nil
+	}
+	policies, err := SelectPolicies(p.af.Policies, policyNames)
+	if err != nil {
+		return false, "", err
+	}
+	var topologies []string
+	for _, policy := range policies {
+		if policy.Type == v1alpha1.TopologyPolicyType {
+			topologies = append(topologies, policy.Name)
+		}
+	}
+	allowed, reason, err := pkgpolicy.CheckDeployWindows(p.af.Policies, topologies, time.Now())
+	if err != nil {
+		return false, "", err
+	}
+	if allowed {
+		p.app.Status.SetConditions(condition.ReadyCondition(common.DeployWindowCondition.String()))
+		return true, "", nil
+	}
+	p.app.Status.SetConditions(condition.Condition{
+		Type:               condition.ConditionType(common.DeployWindowCondition.String()),
+		Status:             corev1.ConditionFalse,
+		LastTransitionTime: metav1.Now(),
+		Reason:             ReasonOutsideDeployWindow,
+		Message:            reason,
+	})
+	return false, reason, nil
+}
+
 func (p *provider) GetPlacementsFromTopologyPolicies(ctx monitorContext.Context, wfCtx wfContext.Context, v *value.Value, act wfTypes.Action) error {
 	policyNames, err := v.GetStringSlice("policies")
 	if err != nil {
 		return err
 	}
-	policies, err := selectPolicies(p.af.Policies, policyNames)
+	policies, err := SelectPolicies(p.af.Policies, policyNames)
 	if err != nil {
 		return err
 	}
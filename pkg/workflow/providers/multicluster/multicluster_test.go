@@ -33,10 +33,13 @@ import (
 	clustercommon "github.com/oam-dev/cluster-gateway/pkg/common"
 
 	apicommon "github.com/oam-dev/kubevela/apis/core.oam.dev/common"
+	"github.com/oam-dev/kubevela/apis/core.oam.dev/condition"
 	"github.com/oam-dev/kubevela/apis/core.oam.dev/v1alpha1"
 	"github.com/oam-dev/kubevela/apis/core.oam.dev/v1beta1"
 	"github.com/oam-dev/kubevela/apis/types"
+	"github.com/oam-dev/kubevela/pkg/appfile"
 	"github.com/oam-dev/kubevela/pkg/multicluster"
+	"github.com/oam-dev/kubevela/pkg/oam"
 	"github.com/oam-dev/kubevela/pkg/utils/common"
 )
 
@@ -511,3 +514,48 @@ func TestListClusters(t *testing.T) {
 	r.NoError(outputs.UnmarshalTo(&obj))
 	r.Equal(clusterNames, obj.Clusters)
 }
+
+func TestCheckDeployWindows(t *testing.T) {
+	r := require.New(t)
+	app := &v1beta1.Application{}
+	af := &appfile.Appfile{Policies: []v1beta1.AppPolicy{{
+		Name:       "freeze",
+		Type:       v1alpha1.DeployWindowPolicyType,
+		Properties: &runtime.RawExtension{Raw: []byte(`{"rules":[{"topologies":["prod"],"windows":[{"kind":"deny","schedule":"* * * * *","duration":"2m"}]}]}`)},
+	}, {
+		Name:       "prod",
+		Type:       v1alpha1.TopologyPolicyType,
+		Properties: &runtime.RawExtension{Raw: []byte(`{"clusters":["local"]}`)},
+	}, {
+		Name:       "staging",
+		Type:       v1alpha1.TopologyPolicyType,
+		Properties: &runtime.RawExtension{Raw: []byte(`{"clusters":["local"]}`)},
+	}}}
+	p := &provider{app: app, af: af}
+
+	allowed, _, err := p.checkDeployWindows([]string{"staging"})
+	r.NoError(err)
+	r.True(allowed)
+	cond := app.Status.GetCondition(condition.ConditionType(apicommon.DeployWindowCondition.String()))
+	r.Equal(corev1.ConditionTrue, cond.Status)
+
+	allowed, reason, err := p.checkDeployWindows([]string{"prod"})
+	r.NoError(err)
+	r.False(allowed)
+	r.Contains(reason, "deploy is denied by window")
+	cond = app.Status.GetCondition(condition.ConditionType(apicommon.DeployWindowCondition.String()))
+	r.Equal(corev1.ConditionFalse, cond.Status)
+	r.Equal(ReasonOutsideDeployWindow, cond.Reason)
+	r.Equal(reason, cond.Message)
+
+	app.SetAnnotations(map[string]string{oam.AnnotationDeployWindowOverride: "true"})
+	allowed, _, err = p.checkDeployWindows([]string{"prod"})
+	r.NoError(err)
+	r.True(allowed)
+
+	_, _, err = p.checkDeployWindows([]string{"not-exist"})
+	r.NoError(err)
+	app.SetAnnotations(nil)
+	_, _, err = p.checkDeployWindows([]string{"not-exist"})
+	r.Error(err)
+}
@@ -0,0 +1,442 @@
+/*
+Copyright 2022 The KubeVela Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cli
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/oam-dev/kubevela/apis/core.oam.dev/common"
+	"github.com/oam-dev/kubevela/apis/core.oam.dev/v1alpha1"
+	"github.com/oam-dev/kubevela/apis/core.oam.dev/v1beta1"
+	"github.com/oam-dev/kubevela/apis/types"
+	"github.com/oam-dev/kubevela/pkg/oam"
+	oamutil "github.com/oam-dev/kubevela/pkg/oam/util"
+	"github.com/oam-dev/kubevela/pkg/resourcetracker"
+	"github.com/oam-dev/kubevela/pkg/utils/apply"
+	common2 "github.com/oam-dev/kubevela/pkg/utils/common"
+	velaerrors "github.com/oam-dev/kubevela/pkg/utils/errors"
+	cmdutil "github.com/oam-dev/kubevela/pkg/utils/util"
+)
+
+const (
+	// AdoptModeRefObjects adopts all the resources with a ref-objects component
+	AdoptModeRefObjects = "ref-objects"
+	// AdoptModeInfer adopts the workloads with the best-matching component definitions and
+	// the other resources with a ref-objects component
+	AdoptModeInfer = "infer"
+
+	helmReleaseNameAnnotation      = "meta.helm.sh/release-name"
+	helmReleaseNamespaceAnnotation = "meta.helm.sh/release-namespace"
+)
+
+var defaultAdoptResourceTypes = []string{
+	"deployments.apps", "statefulsets.apps", "daemonsets.apps", "services", "configmaps",
+	"persistentvolumeclaims", "ingresses.networking.k8s.io",
+}
+
+// AdoptOptions contains the options of the adopt command
+type AdoptOptions struct {
+	cmdutil.IOStreams
+	AppName        string
+	Namespace      string
+	LabelSelector  string
+	HelmRelease    string
+	ResourceTypes  []string
+	Mode           string
+	DefinitionFile string
+	Apply          bool
+
+	// Resources are the explicitly specified resources in the format of type/name
+	Resources []string
+}
+
+// NewAdoptCommand creates `adopt` command
+func NewAdoptCommand(c common2.Args, order string, ioStreams cmdutil.IOStreams) *cobra.Command {
+	o := &AdoptOptions{IOStreams: ioStreams}
+	cmd := &cobra.Command{
+		Use:                   "adopt [TYPE/NAME...]",
+		DisableFlagsInUseLine: true,
+		Short:                 "Adopt existing resources into an application",
+		Long: "Adopt existing resources into an application.\n\n" +
+			"The resources can be specified by type/name, by label selector or by Helm release. An application is generated " +
+			"to manage them, with a ref-objects component by default. In infer mode, the workloads are matched against the " +
+			"component definitions and adopted as the best-matching component type with the properties inferred from the " +
+			"live resources, a definition only matches if it reproduces the live workload so that adopting it causes no restart.\n\n" +
+			"The generated application is printed by default. With --apply, the resources are labeled as managed by the " +
+			"application, the application is created and the resources are recorded in its ResourceTracker.",
+		Example: "# adopt a deployment and a service with a ref-objects component\n" +
+			"> vela adopt deployment/nginx service/nginx -n default --app-name nginx\n" +
+			"# adopt the resources of a Helm release and infer the component types\n" +
+			"> vela adopt --helm-release my-release -n default --mode infer\n" +
+			"# adopt the resources by label selector and create the application\n" +
+			"> vela adopt -l app=nginx --apply",
+		Annotations: map[string]string{
+			types.TagCommandOrder: order,
+			types.TagCommandType:  types.TypeApp,
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			namespace, err := GetFlagNamespaceOrEnv(cmd, c)
+			if err != nil {
+				return err
+			}
+			o.Namespace = namespace
+			o.Resources = args
+			return o.Run(cmd, c)
+		},
+	}
+	cmd.Flags().StringVar(&o.AppName, "app-name", "", "specify the name of the generated application, defaults to the Helm release name or the name of the first resource")
+	cmd.Flags().StringVarP(&o.LabelSelector, "selector", "l", "", "select the resources to adopt by label selector")
+	cmd.Flags().StringVar(&o.HelmRelease, "helm-release", "", "select the resources of the Helm release to adopt")
+	cmd.Flags().StringSliceVar(&o.ResourceTypes, "resource-types", defaultAdoptResourceTypes, "the resource types to search when selecting by label selector or Helm release")
+	cmd.Flags().StringVar(&o.Mode, "mode", AdoptModeRefObjects, "the mode to generate components, one of: (ref-objects, infer)")
+	cmd.Flags().StringVarP(&o.DefinitionFile, "definition", "d", "", "specify a definition file or directory, it will be used as the candidate component definitions in infer mode")
+	cmd.Flags().BoolVar(&o.Apply, "apply", false, "take over the resources and create the application instead of printing it")
+	addNamespaceAndEnvArg(cmd)
+	cmd.SetOut(ioStreams.Out)
+	return cmd
+}
+
+// Run adopts the resources
+func (o *AdoptOptions) Run(cmd *cobra.Command, c common2.Args) error {
+	if o.Mode != AdoptModeRefObjects && o.Mode != AdoptModeInfer {
+		return errors.Errorf("adopt mode %s is not supported, only ref-objects and infer are supported", o.Mode)
+	}
+	cli, err := c.GetClient()
+	if err != nil {
+		return err
+	}
+	ctx := context.Background()
+	objs, err := o.selectResources(ctx, cli)
+	if err != nil {
+		return err
+	}
+	app, err := o.generateApplication(ctx, cli, c, objs)
+	if err != nil {
+		return err
+	}
+	if !o.Apply {
+		str, err := printObj("yaml", app)
+		if err != nil {
+			return err
+		}
+		o.Info(str)
+		return nil
+	}
+	if !assumeYes {
+		confirmed := NewUserInput().AskBool(fmt.Sprintf("Do you want application %s to take over %d resources in namespace %s", app.Name, len(objs), app.Namespace), &UserInputOptions{assumeYes})
+		if !confirmed {
+			return errors.New("adoption is cancelled")
+		}
+	}
+	if err = takeOverResources(ctx, cli, app, objs); err != nil {
+		return err
+	}
+	if err = cli.Create(ctx, app); err != nil {
+		// the resources must not be left owned by an application that does not exist
+		if releaseErr := releaseResources(ctx, cli, objs); releaseErr != nil {
+			return errors.Wrapf(err, "failed to create application %s, and failed to release the resources taken over: %s", app.Name, releaseErr.Error())
+		}
+		return errors.Wrapf(err, "failed to create application %s", app.Name)
+	}
+	if err = recordResources(ctx, cli, app, objs); err != nil {
+		return err
+	}
+	cmd.Printf("Application %s/%s has been created to manage %d resources.\n", app.Namespace, app.Name, len(objs))
+	if o.HelmRelease != "" {
+		cmd.Printf("The resources are still recorded in Helm release %s, remove the release records with "+
+			"`kubectl delete secret -n %s -l owner=helm,name=%s` rather than `helm uninstall` to keep the resources.\n", o.HelmRelease, o.Namespace, o.HelmRelease)
+	}
+	return nil
+}
+
+// selectResources gets the resources specified by type/name, or lists the resources of
+// the candidate types matching the label selector and Helm release
+func (o *AdoptOptions) selectResources(ctx context.Context, cli client.Client) ([]*unstructured.Unstructured, error) {
+	var objs []*unstructured.Unstructured
+	if len(o.Resources) > 0 {
+		if o.LabelSelector != "" || o.HelmRelease != "" {
+			return nil, errors.New("resources cannot be specified by type/name together with label selector or Helm release")
+		}
+		for _, res := range o.Resources {
+			parts := strings.SplitN(res, "/", 2)
+			if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+				return nil, errors.Errorf("invalid resource %s, it should be in the format of type/name", res)
+			}
+			gvk, err := resourceTypeToGVK(cli, parts[0])
+			if err != nil {
+				return nil, err
+			}
+			obj := &unstructured.Unstructured{}
+			obj.SetGroupVersionKind(gvk)
+			if err = cli.Get(ctx, client.ObjectKey{Namespace: o.Namespace, Name: parts[1]}, obj); err != nil {
+				return nil, errors.Wrapf(err, "failed to get %s", res)
+			}
+			objs = append(objs, obj)
+		}
+		return objs, nil
+	}
+	if o.LabelSelector == "" && o.HelmRelease == "" {
+		return nil, errors.New("resources must be specified by type/name, label selector or Helm release")
+	}
+	selector := labels.Everything()
+	if o.LabelSelector != "" {
+		var err error
+		if selector, err = labels.Parse(o.LabelSelector); err != nil {
+			return nil, errors.Wrapf(err, "invalid label selector %s", o.LabelSelector)
+		}
+	}
+	for _, resourceType := range o.ResourceTypes {
+		gvk, err := resourceTypeToGVK(cli, resourceType)
+		if err != nil {
+			return nil, err
+		}
+		list := &unstructured.UnstructuredList{}
+		list.SetGroupVersionKind(gvk.GroupVersion().WithKind(gvk.Kind + "List"))
+		if err = cli.List(ctx, list, client.InNamespace(o.Namespace), client.MatchingLabelsSelector{Selector: selector}); err != nil {
+			return nil, errors.Wrapf(err, "failed to list %s", resourceType)
+		}
+		for i := range list.Items {
+			obj := list.Items[i].DeepCopy()
+			// the resources controlled by others, such as the endpoints of services, are
+			// adopted together with their controllers
+			if metav1.GetControllerOf(obj) != nil {
+				continue
+			}
+			if o.HelmRelease != "" {
+				annotations := obj.GetAnnotations()
+				if annotations[helmReleaseNameAnnotation] != o.HelmRelease || annotations[helmReleaseNamespaceAnnotation] != o.Namespace {
+					continue
+				}
+			}
+			objs = append(objs, obj)
+		}
+	}
+	if len(objs) == 0 {
+		return nil, errors.New("no resources found to adopt")
+	}
+	return objs, nil
+}
+
+func resourceTypeToGVK(cli client.Client, resourceType string) (schema.GroupVersionKind, error) {
+	gvk, err := cli.RESTMapper().KindFor(schema.ParseGroupResource(resourceType).WithVersion(""))
+	if err != nil {
+		return gvk, errors.Wrapf(err, "unknown resource type %s", resourceType)
+	}
+	return gvk, nil
+}
+
+// generateApplication generates the application to manage the resources. In infer mode,
+// the workloads are adopted as the best-matching component types, the rest of resources
+// are referred by a ref-objects component.
+func (o *AdoptOptions) generateApplication(ctx context.Context, cli client.Client, c common2.Args, objs []*unstructured.Unstructured) (*v1beta1.Application, error) {
+	app := &v1beta1.Application{
+		TypeMeta:   metav1.TypeMeta{APIVersion: v1beta1.SchemeGroupVersion.String(), Kind: v1beta1.ApplicationKind},
+		ObjectMeta: metav1.ObjectMeta{Name: o.AppName, Namespace: o.Namespace},
+	}
+	if app.Name == "" {
+		app.Name = o.HelmRelease
+	}
+	if app.Name == "" {
+		app.Name = objs[0].GetName()
+	}
+	appKey := apply.GetAppKey(app)
+	for _, obj := range objs {
+		if controlledBy := apply.GetControlledBy(obj); controlledBy != "" && controlledBy != appKey {
+			return nil, errors.Errorf("%s %s/%s is managed by application %s", obj.GetKind(), obj.GetNamespace(), obj.GetName(), controlledBy)
+		}
+	}
+
+	refObjs := objs
+	if o.Mode == AdoptModeInfer {
+		inferred, rest, err := o.inferComponents(ctx, cli, c, app, objs)
+		if err != nil {
+			return nil, err
+		}
+		app.Spec.Components = inferred
+		refObjs = rest
+	}
+	if len(refObjs) > 0 {
+		comp, err := refObjectsComponent(cli, uniqueComponentName(app.Spec.Components, app.Name), refObjs)
+		if err != nil {
+			return nil, err
+		}
+		app.Spec.Components = append(app.Spec.Components, *comp)
+	}
+	return app, nil
+}
+
+func (o *AdoptOptions) inferComponents(ctx context.Context, cli client.Client, c common2.Args, app *v1beta1.Application, objs []*unstructured.Unstructured) ([]common.ApplicationComponent, []*unstructured.Unstructured, error) {
+	dryRunOpt, err := newDryRunOption(&DryRunCmdOptions{DefinitionFile: o.DefinitionFile}, c, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+	candidates, err := candidateDefinitions(ctx, cli, dryRunOpt.Auxiliaries, app.Namespace)
+	if err != nil {
+		return nil, nil, err
+	}
+	var comps []common.ApplicationComponent
+	var rest []*unstructured.Unstructured
+	for _, obj := range objs {
+		defs := candidates[obj.GroupVersionKind().GroupKind()]
+		if len(defs) == 0 || uniqueComponentName(comps, obj.GetName()) != obj.GetName() {
+			rest = append(rest, obj)
+			continue
+		}
+		comp, err := dryRunOpt.InferComponent(ctx, app, obj, defs)
+		if err != nil {
+			return nil, nil, err
+		}
+		if comp == nil {
+			rest = append(rest, obj)
+			continue
+		}
+		comps = append(comps, *comp)
+	}
+	return comps, rest, nil
+}
+
+// candidateDefinitions returns the names of the component definitions grouped by the kind
+// of their workloads, the local definitions take precedence over the ones in cluster
+func candidateDefinitions(ctx context.Context, cli client.Client, localDefs []oam.Object, namespace string) (map[schema.GroupKind][]string, error) {
+	kinds := map[string]schema.GroupKind{}
+	for _, ns := range []string{types.DefaultKubeVelaNS, namespace} {
+		defs := &v1beta1.ComponentDefinitionList{}
+		if err := cli.List(ctx, defs, client.InNamespace(ns)); err != nil {
+			return nil, errors.Wrapf(err, "failed to list component definitions in namespace %s", ns)
+		}
+		for _, def := range defs.Items {
+			gv, err := schema.ParseGroupVersion(def.Spec.Workload.Definition.APIVersion)
+			if err != nil {
+				continue
+			}
+			kinds[def.Name] = schema.GroupKind{Group: gv.Group, Kind: def.Spec.Workload.Definition.Kind}
+		}
+	}
+	for _, obj := range localDefs {
+		un, err := oamutil.Object2Unstructured(obj)
+		if err != nil || un.GetKind() != v1beta1.ComponentDefinitionKind {
+			continue
+		}
+		apiVersion, _, _ := unstructured.NestedString(un.Object, "spec", "workload", "definition", "apiVersion")
+		kind, _, _ := unstructured.NestedString(un.Object, "spec", "workload", "definition", "kind")
+		gv, err := schema.ParseGroupVersion(apiVersion)
+		if err != nil {
+			continue
+		}
+		kinds[un.GetName()] = schema.GroupKind{Group: gv.Group, Kind: kind}
+	}
+	candidates := map[schema.GroupKind][]string{}
+	for name, gk := range kinds {
+		candidates[gk] = append(candidates[gk], name)
+	}
+	for gk := range candidates {
+		sort.Strings(candidates[gk])
+	}
+	return candidates, nil
+}
+
+func refObjectsComponent(cli client.Client, name string, objs []*unstructured.Unstructured) (*common.ApplicationComponent, error) {
+	spec := v1alpha1.RefObjectsComponentSpec{}
+	for _, obj := range objs {
+		mapping, err := cli.RESTMapper().RESTMapping(obj.GroupVersionKind().GroupKind(), obj.GroupVersionKind().Version)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to get resource type of %s", obj.GetKind())
+		}
+		spec.Objects = append(spec.Objects, v1alpha1.ObjectReferrer{
+			ObjectTypeIdentifier: v1alpha1.ObjectTypeIdentifier{Resource: mapping.Resource.Resource, Group: mapping.Resource.Group},
+			ObjectSelector:       v1alpha1.ObjectSelector{Name: obj.GetName()},
+		})
+	}
+	return &common.ApplicationComponent{
+		Name:       name,
+		Type:       v1alpha1.RefObjectsComponentType,
+		Properties: oamutil.Object2RawExtension(spec),
+	}, nil
+}
+
+func uniqueComponentName(comps []common.ApplicationComponent, name string) string {
+	names := map[string]bool{}
+	for _, comp := range comps {
+		names[comp.Name] = true
+	}
+	candidate := name
+	for i := 1; names[candidate]; i++ {
+		candidate = fmt.Sprintf("%s-%d", name, i)
+	}
+	return candidate
+}
+
+// takeOverResources labels the resources as managed by the application, so that the
+// application can dispatch them without conflict
+func takeOverResources(ctx context.Context, cli client.Client, app *v1beta1.Application, objs []*unstructured.Unstructured) error {
+	for i, obj := range objs {
+		patch := client.MergeFrom(obj.DeepCopy())
+		oamutil.AddLabels(obj, map[string]string{
+			oam.LabelAppName:      app.Name,
+			oam.LabelAppNamespace: app.Namespace,
+		})
+		if err := cli.Patch(ctx, obj, patch); err != nil {
+			err = errors.Wrapf(err, "failed to take over %s %s/%s", obj.GetKind(), obj.GetNamespace(), obj.GetName())
+			if releaseErr := releaseResources(ctx, cli, objs[:i]); releaseErr != nil {
+				return errors.Wrapf(err, "failed to release the resources taken over: %s", releaseErr.Error())
+			}
+			return err
+		}
+	}
+	return nil
+}
+
+// recordResources records the adopted resources in the current ResourceTracker of the created
+// application, so that the application owns them before its first dispatch
+func recordResources(ctx context.Context, cli client.Client, app *v1beta1.Application, objs []*unstructured.Unstructured) error {
+	rt, err := resourcetracker.CreateCurrentResourceTracker(ctx, cli, app)
+	if err != nil {
+		return errors.Wrapf(err, "failed to create the ResourceTracker of application %s", app.Name)
+	}
+	if err = resourcetracker.RecordManifestsInResourceTracker(ctx, cli, rt, objs, true, false, ""); err != nil {
+		return errors.Wrapf(err, "failed to record the adopted resources in ResourceTracker %s", rt.Name)
+	}
+	return nil
+}
+
+// releaseResources removes the application labels added by takeOverResources, the adopted
+// resources are never managed by any application before
+func releaseResources(ctx context.Context, cli client.Client, objs []*unstructured.Unstructured) error {
+	var errs []error
+	for _, obj := range objs {
+		patch := client.MergeFrom(obj.DeepCopy())
+		labels := obj.GetLabels()
+		delete(labels, oam.LabelAppName)
+		delete(labels, oam.LabelAppNamespace)
+		obj.SetLabels(labels)
+		if err := cli.Patch(ctx, obj, patch); err != nil {
+			errs = append(errs, errors.Wrapf(err, "failed to release %s %s/%s", obj.GetKind(), obj.GetNamespace(), obj.GetName()))
+		}
+	}
+	return velaerrors.AggregateErrors(errs)
+}
@@ -0,0 +1,137 @@
+/*
+Copyright 2022 The KubeVela Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cli
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/oam-dev/kubevela/apis/core.oam.dev/v1alpha1"
+	"github.com/oam-dev/kubevela/apis/core.oam.dev/v1beta1"
+	"github.com/oam-dev/kubevela/pkg/oam"
+	"github.com/oam-dev/kubevela/pkg/resourcetracker"
+	common2 "github.com/oam-dev/kubevela/pkg/utils/common"
+	cmdutil "github.com/oam-dev/kubevela/pkg/utils/util"
+)
+
+func TestAdoptResources(t *testing.T) {
+	r := require.New(t)
+	mapper := meta.NewDefaultRESTMapper(nil)
+	mapper.Add(appsv1.SchemeGroupVersion.WithKind("Deployment"), meta.RESTScopeNamespace)
+	mapper.Add(corev1.SchemeGroupVersion.WithKind("Service"), meta.RESTScopeNamespace)
+	mapper.Add(corev1.SchemeGroupVersion.WithKind("ConfigMap"), meta.RESTScopeNamespace)
+	helmAnnotations := map[string]string{helmReleaseNameAnnotation: "nginx", helmReleaseNamespaceAnnotation: "default"}
+	cli := fake.NewClientBuilder().WithScheme(common2.Scheme).WithRESTMapper(mapper).WithObjects(
+		&appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Name: "nginx", Namespace: "default", Annotations: helmAnnotations}},
+		&corev1.Service{ObjectMeta: metav1.ObjectMeta{Name: "nginx", Namespace: "default", Annotations: helmAnnotations}},
+		&corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "config", Namespace: "default"}},
+		&corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "managed", Namespace: "default", Labels: map[string]string{
+			oam.LabelAppName: "other", oam.LabelAppNamespace: "default"}}},
+	).Build()
+	c := common2.Args{}
+	c.SetClient(cli)
+	ctx := context.Background()
+	newOptions := func() *AdoptOptions {
+		return &AdoptOptions{
+			IOStreams:     cmdutil.IOStreams{Out: &bytes.Buffer{}},
+			Namespace:     "default",
+			ResourceTypes: []string{"deployments.apps", "services", "configmaps"},
+			Mode:          AdoptModeRefObjects,
+		}
+	}
+
+	o := newOptions()
+	_, err := o.selectResources(ctx, cli)
+	r.Error(err)
+
+	o.HelmRelease = "nginx"
+	objs, err := o.selectResources(ctx, cli)
+	r.NoError(err)
+	r.Len(objs, 2)
+	app, err := o.generateApplication(ctx, cli, c, objs)
+	r.NoError(err)
+	r.Equal("nginx", app.Name)
+	r.Len(app.Spec.Components, 1)
+	r.Equal(v1alpha1.RefObjectsComponentType, app.Spec.Components[0].Type)
+	spec := v1alpha1.RefObjectsComponentSpec{}
+	r.NoError(json.Unmarshal(app.Spec.Components[0].Properties.Raw, &spec))
+	r.Equal([]v1alpha1.ObjectReferrer{
+		{ObjectTypeIdentifier: v1alpha1.ObjectTypeIdentifier{Resource: "deployments", Group: "apps"}, ObjectSelector: v1alpha1.ObjectSelector{Name: "nginx"}},
+		{ObjectTypeIdentifier: v1alpha1.ObjectTypeIdentifier{Resource: "services"}, ObjectSelector: v1alpha1.ObjectSelector{Name: "nginx"}},
+	}, spec.Objects)
+
+	o = newOptions()
+	o.Resources = []string{"configmap/managed"}
+	objs, err = o.selectResources(ctx, cli)
+	r.NoError(err)
+	_, err = o.generateApplication(ctx, cli, c, objs)
+	r.Error(err)
+
+	o = newOptions()
+	o.Resources = []string{"configmap/config", "service/nginx"}
+	o.AppName = "adopted"
+	o.Apply = true
+	assumeYes = true
+	defer func() { assumeYes = false }()
+	r.NoError(o.Run(&cobra.Command{}, c))
+	app = &v1beta1.Application{}
+	r.NoError(cli.Get(ctx, client.ObjectKey{Namespace: "default", Name: "adopted"}, app))
+	r.Equal("adopted", app.Spec.Components[0].Name)
+	cm := &corev1.ConfigMap{}
+	r.NoError(cli.Get(ctx, client.ObjectKey{Namespace: "default", Name: "config"}, cm))
+	r.Equal("adopted", cm.Labels[oam.LabelAppName])
+	r.Equal("default", cm.Labels[oam.LabelAppNamespace])
+	_, currentRT, _, _, err := resourcetracker.ListApplicationResourceTrackers(ctx, cli, app)
+	r.NoError(err)
+	r.NotNil(currentRT)
+	var recorded []string
+	for _, mr := range currentRT.Spec.ManagedResources {
+		recorded = append(recorded, mr.Kind+"/"+mr.Namespace+"/"+mr.Name)
+	}
+	r.ElementsMatch([]string{"ConfigMap/default/config", "Service/default/nginx"}, recorded)
+
+	// the labels are removed if the application cannot be created
+	o = newOptions()
+	o.Resources = []string{"deployment/nginx"}
+	o.AppName = "adopted"
+	o.Apply = true
+	err = o.Run(&cobra.Command{}, c)
+	r.Error(err)
+	r.Contains(err.Error(), "failed to create application adopted")
+	deploy := &appsv1.Deployment{}
+	r.NoError(cli.Get(ctx, client.ObjectKey{Namespace: "default", Name: "nginx"}, deploy))
+	r.NotContains(deploy.Labels, oam.LabelAppName)
+	r.NotContains(deploy.Labels, oam.LabelAppNamespace)
+
+	_, err = resourceTypeToGVK(cli, "unknown")
+	r.Error(err)
+	gvk, err := resourceTypeToGVK(cli, "deployment")
+	r.NoError(err)
+	r.Equal(schema.GroupVersionKind{Group: "apps", Version: "v1", Kind: "Deployment"}, gvk)
+}
@@ -41,6 +41,30 @@ import (
 
 var assumeYes bool
 
+// ExitCodeError is returned by the commands whose exit code carries the result
+// rather than a failure, e.g. `vela live-diff --exit-code` when changes are found
+type ExitCodeError struct {
+	Code   int
+	Reason string
+}
+
+// Error implements the error interface
+func (e *ExitCodeError) Error() string {
+	return e.Reason
+}
+
+// GetExitCode returns the exit code of the command from the error returned by it
+func GetExitCode(err error) int {
+	if err == nil {
+		return 0
+	}
+	var exitErr *ExitCodeError
+	if errors.As(err, &exitErr) {
+		return exitErr.Code
+	}
+	return 1
+}
+
 // NewCommand will contain all commands
 func NewCommand() *cobra.Command {
 	return NewCommandWithIOStreams(util.NewDefaultIOStreams())
@@ -96,6 +120,7 @@ func NewCommandWithIOStreams(ioStream util.IOStreams) *cobra.Command {
 		NewTopCommand(commandArgs, "11", ioStream),
 		NewListCommand(commandArgs, "10", ioStream),
 		NewAppStatusCommand(commandArgs, "9", ioStream),
+		NewAdoptCommand(commandArgs, "8", ioStream),
 		NewDeleteCommand(commandArgs, "7", ioStream),
 		NewExecCommand(commandArgs, "6", ioStream),
 		NewPortForwardCommand(commandArgs, "5", ioStream),
@@ -104,6 +129,7 @@ func NewCommandWithIOStreams(ioStream util.IOStreams) *cobra.Command {
 		NewLiveDiffCommand(commandArgs, "2", ioStream),
 		NewDryRunCommand(commandArgs, ioStream),
 		RevisionCommandGroup(commandArgs),
+		NewRollbackCommand(commandArgs, ioStream),
 
 		// Workflows
 		NewWorkflowCommand(commandArgs, ioStream),
@@ -120,6 +146,7 @@ func NewCommandWithIOStreams(ioStream util.IOStreams) *cobra.Command {
 		NewTraitCommand(commandArgs, ioStream),
 		NewComponentsCommand(commandArgs, ioStream),
 		NewProviderCommand(commandArgs, "10", ioStream),
+		TopologyRuleCommandGroup(commandArgs, "5", ioStream),
 		AuthCommandGroup(f, ioStream),
 		KubeCommandGroup(f, ioStream),
 
@@ -46,13 +46,15 @@ import (
 	"github.com/oam-dev/kubevela/pkg/oam/discoverymapper"
 	"github.com/oam-dev/kubevela/pkg/utils/common"
 	cmdutil "github.com/oam-dev/kubevela/pkg/utils/util"
+	wftemplate "github.com/oam-dev/kubevela/pkg/workflow/template"
 	"github.com/oam-dev/kubevela/references/appfile"
 )
 
 type debugOpts struct {
-	step   string
-	focus  string
-	errMsg string
+	step        string
+	focus       string
+	errMsg      string
+	interactive bool
 	// TODO: (fog) add watch flag
 	// watch bool
 }
@@ -66,7 +68,10 @@ func NewDebugCommand(c common.Args, ioStreams cmdutil.IOStreams) *cobra.Command
 		Aliases: []string{"debug"},
 		Short:   "Debug running application",
 		Long:    "Debug running application with debug policy.",
-		Example: `vela debug <application-name>`,
+		Example: "# debug the recorded values of the application\n" +
+			"> vela debug <application-name>\n" +
+			"# step through the workflow interactively, pause before each step to inspect and edit it\n" +
+			"> vela debug <application-name> -i",
 		RunE: func(cmd *cobra.Command, args []string) error {
 			if len(args) < 1 {
 				return fmt.Errorf("must specify application name")
@@ -79,15 +84,40 @@ func NewDebugCommand(c common.Args, ioStreams cmdutil.IOStreams) *cobra.Command
 			if err != nil {
 				return err
 			}
+			if dOpts.interactive {
+				return dOpts.debugInteractively(ctx, c, app, ioStreams)
+			}
 			return dOpts.debugApplication(ctx, c, app, ioStreams)
 		},
 	}
 	addNamespaceAndEnvArg(cmd)
 	cmd.Flags().StringVarP(&dOpts.step, "step", "s", "", "specify the step or component to debug")
 	cmd.Flags().StringVarP(&dOpts.focus, "focus", "f", "", "specify the focus value to debug, only valid for application with workflow")
+	cmd.Flags().BoolVarP(&dOpts.interactive, "interactive", "i", false, "step through the workflow interactively, the workflow pauses before each step to inspect, evaluate and edit the step")
 	return cmd
 }
 
+func (d *debugOpts) debugInteractively(ctx context.Context, c common.Args, app *v1beta1.Application, ioStreams cmdutil.IOStreams) error {
+	cli, err := c.GetClient()
+	if err != nil {
+		return err
+	}
+	config, err := c.GetConfig()
+	if err != nil {
+		return err
+	}
+	pd, err := c.GetPackageDiscover()
+	if err != nil {
+		return err
+	}
+	dm, err := discoverymapper.New(config)
+	if err != nil {
+		return err
+	}
+	loader := wftemplate.NewWorkflowStepTemplateLoader(cli, dm)
+	return newDebugSession(cli, pd, loader, app, ioStreams).Run(ctx)
+}
+
 func (d *debugOpts) debugApplication(ctx context.Context, c common.Args, app *v1beta1.Application, ioStreams cmdutil.IOStreams) error {
 	cli, err := c.GetClient()
 	if err != nil {
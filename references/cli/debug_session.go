@@ -0,0 +1,689 @@
+/*
+Copyright 2022 The KubeVela Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/AlecAivazis/survey/v2"
+	"github.com/fatih/color"
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/util/retry"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/yaml"
+
+	workflowv1alpha1 "github.com/kubevela/workflow/api/v1alpha1"
+	"github.com/kubevela/workflow/pkg/cue/model/value"
+	"github.com/kubevela/workflow/pkg/cue/packages"
+	"github.com/kubevela/workflow/pkg/tasks/template"
+	wfTypes "github.com/kubevela/workflow/pkg/types"
+
+	"github.com/oam-dev/kubevela/apis/core.oam.dev/v1alpha1"
+	"github.com/oam-dev/kubevela/apis/core.oam.dev/v1beta1"
+	velaprocess "github.com/oam-dev/kubevela/pkg/cue/process"
+	"github.com/oam-dev/kubevela/pkg/oam/util"
+	cmdutil "github.com/oam-dev/kubevela/pkg/utils/util"
+	"github.com/oam-dev/kubevela/pkg/workflow/operation"
+)
+
+// debugBreakpointPrefix is the name prefix of the suspend steps inserted before the workflow
+// steps to pause the workflow in the interactive debug session
+const debugBreakpointPrefix = "debug-breakpoint-"
+
+// debugSessionPolicy is the name of the debug policy added by the interactive debug session,
+// it is removed together with the breakpoints when the session quits
+const debugSessionPolicy = "debug-session"
+
+const debugSessionHelp = `Commands:
+  show                  show the inputs and the rendered template of the step
+  context               show the context variables of the step
+  eval <expr>           evaluate a CUE expression against the rendered template of the step
+  recorded [expr]       show the recorded value of the last executed step, or evaluate a CUE expression against it
+  edit                  edit the properties, inputs and outputs of the step, the workflow restarts and runs to the step again
+  continue              run the step and pause before the next one
+  retry                 restart the workflow and run to the step again
+  quit                  quit the debug session
+`
+
+// debugSession steps through the workflow of an application. Suspend steps are inserted
+// before each workflow step as breakpoints and the debug policy records the value of each
+// executed step, the session waits for the workflow to pause at a breakpoint or on a
+// failed step and lets the user inspect the step before resuming it.
+type debugSession struct {
+	cli       client.Client
+	pd        *packages.PackageDiscover
+	loader    template.Loader
+	ioStreams cmdutil.IOStreams
+	app       *v1beta1.Application
+	interval  time.Duration
+	timeout   time.Duration
+
+	// passed are the breakpoints to be resumed without asking the user, they are set when
+	// the workflow is restarted to run to a step again
+	passed map[string]bool
+	// acknowledged is the failure that the user chooses to continue with
+	acknowledged string
+
+	ask  func(message string) (string, error)
+	edit func(message string, content string) (string, error)
+}
+
+// debugPause describes where the workflow pauses
+type debugPause struct {
+	// step is the step to run next for breakpoint, or the failed step
+	step     string
+	failed   bool
+	message  string
+	finished bool
+}
+
+func (p *debugPause) key() string {
+	return p.step + "/" + p.message
+}
+
+func newDebugSession(cli client.Client, pd *packages.PackageDiscover, loader template.Loader, app *v1beta1.Application, ioStreams cmdutil.IOStreams) *debugSession {
+	return &debugSession{
+		cli:       cli,
+		pd:        pd,
+		loader:    loader,
+		ioStreams: ioStreams,
+		app:       app,
+		interval:  time.Second,
+		timeout:   10 * time.Minute,
+		passed:    map[string]bool{},
+		ask: func(message string) (string, error) {
+			var line string
+			err := survey.AskOne(&survey.Input{Message: message}, &line)
+			return line, err
+		},
+		edit: func(message string, content string) (string, error) {
+			var edited string
+			err := survey.AskOne(&survey.Editor{Message: message, Default: content, HideDefault: true, AppendDefault: true}, &edited)
+			return edited, err
+		},
+	}
+}
+
+// Run inserts the breakpoints into the workflow and steps through it until it finishes or
+// the user quits
+func (s *debugSession) Run(ctx context.Context) error {
+	if s.app.Spec.Workflow == nil || len(s.app.Spec.Workflow.Steps) == 0 {
+		return errors.New("interactive debug requires the application to have workflow steps")
+	}
+	if mode := s.app.Spec.Workflow.Mode; mode != nil && mode.Steps == workflowv1alpha1.WorkflowModeDAG {
+		return errors.New("interactive debug does not support workflow in DAG mode")
+	}
+	if !hasDebugBreakpoints(s.app) {
+		if err := s.updateSpec(ctx, func(app *v1beta1.Application) {
+			addDebugSessionPolicy(app)
+			addDebugBreakpoints(app)
+		}); err != nil {
+			return errors.WithMessage(err, "failed to insert breakpoints into workflow")
+		}
+		s.ioStreams.Info(color.CyanString("Breakpoints are inserted before the workflow steps, the workflow will restart and pause before each step."))
+	}
+	for {
+		pause, err := s.waitForPause(ctx)
+		if err != nil {
+			return err
+		}
+		if pause.finished {
+			s.ioStreams.Infof("Workflow of application %s ends in phase %s.\n", s.app.Name, s.app.Status.Workflow.Phase)
+			return s.quit(ctx)
+		}
+		if !pause.failed && s.passed[pause.step] {
+			if err = s.resume(ctx); err != nil {
+				return err
+			}
+			continue
+		}
+		s.passed = map[string]bool{}
+		s.showPause(ctx, pause)
+		quit, err := s.interact(ctx, pause)
+		if err != nil {
+			return err
+		}
+		if quit {
+			return s.quit(ctx)
+		}
+	}
+}
+
+func (s *debugSession) waitForPause(ctx context.Context) (*debugPause, error) {
+	var pause *debugPause
+	err := wait.PollImmediate(s.interval, s.timeout, func() (bool, error) {
+		if err := s.cli.Get(ctx, client.ObjectKeyFromObject(s.app), s.app); err != nil {
+			return false, err
+		}
+		if s.app.Status.ObservedGeneration != s.app.Generation || s.app.Status.Workflow == nil {
+			return false, nil
+		}
+		pause = pauseOfWorkflow(s.app)
+		if pause != nil && pause.failed && pause.key() == s.acknowledged {
+			pause = nil
+		}
+		return pause != nil, nil
+	})
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to wait for the workflow of application %s to pause", s.app.Name)
+	}
+	return pause, nil
+}
+
+// pauseOfWorkflow finds the breakpoint or the failed step where the workflow pauses, nil
+// if the workflow is still running
+func pauseOfWorkflow(app *v1beta1.Application) *debugPause {
+	status := app.Status.Workflow
+	if status == nil {
+		return nil
+	}
+	if status.Finished || status.Terminated {
+		return &debugPause{finished: true}
+	}
+	for _, step := range status.Steps {
+		switch {
+		case step.Type == wfTypes.WorkflowStepTypeSuspend && strings.HasPrefix(step.Name, debugBreakpointPrefix) && step.Phase == workflowv1alpha1.WorkflowStepPhaseRunning:
+			return &debugPause{step: strings.TrimPrefix(step.Name, debugBreakpointPrefix)}
+		case step.Phase == workflowv1alpha1.WorkflowStepPhaseFailed:
+			return &debugPause{step: step.Name, failed: true, message: step.Message}
+		}
+		for _, sub := range step.SubStepsStatus {
+			if sub.Phase == workflowv1alpha1.WorkflowStepPhaseFailed {
+				return &debugPause{step: sub.Name, failed: true, message: sub.Message}
+			}
+		}
+	}
+	return nil
+}
+
+func (s *debugSession) interact(ctx context.Context, pause *debugPause) (bool, error) {
+	for {
+		line, err := s.ask(fmt.Sprintf("(debug %s)", pause.step))
+		if err != nil {
+			return false, err
+		}
+		command, arg := strings.TrimSpace(line), ""
+		if i := strings.IndexAny(command, " \t"); i >= 0 {
+			command, arg = command[:i], strings.TrimSpace(command[i:])
+		}
+		switch command {
+		case "show", "s":
+			s.showPause(ctx, pause)
+		case "context":
+			ctxFile, err := s.contextFile(s.findStep(pause.step))
+			if err != nil {
+				s.printError(err)
+				continue
+			}
+			s.ioStreams.Info(ctxFile)
+		case "eval", "e":
+			v, err := s.renderStep(ctx, s.findStep(pause.step))
+			if err == nil {
+				err = s.printExpression(v, arg)
+			}
+			s.printError(err)
+		case "recorded":
+			v, err := s.recordedValue(ctx, s.lastExecutedStep(pause))
+			if err == nil {
+				err = s.printExpression(v, arg)
+			}
+			s.printError(err)
+		case "edit":
+			edited, err := s.editStep(ctx, pause.step)
+			if err != nil {
+				s.printError(err)
+				continue
+			}
+			if edited {
+				s.runTo(pause.step)
+				return false, nil
+			}
+		case "continue", "c":
+			if pause.failed {
+				s.acknowledged = pause.key()
+				return false, nil
+			}
+			return false, s.resume(ctx)
+		case "retry", "r":
+			s.runTo(pause.step)
+			if pause.failed {
+				s.passed[pause.step] = true
+			}
+			return false, s.restart(ctx)
+		case "quit", "q":
+			return true, nil
+		default:
+			s.ioStreams.Info(debugSessionHelp)
+		}
+	}
+}
+
+func (s *debugSession) printError(err error) {
+	if err != nil {
+		s.ioStreams.Info(color.RedString("%s%s", emojiFail, err.Error()))
+	}
+}
+
+func (s *debugSession) showPause(ctx context.Context, pause *debugPause) {
+	step := s.findStep(pause.step)
+	if pause.failed {
+		s.ioStreams.Info(color.RedString("\n%sStep %s failed: %s", emojiFail, pause.step, pause.message))
+	} else {
+		s.ioStreams.Info(color.CyanString("\n▫️ Paused before step %s", pause.step))
+	}
+	if step == nil {
+		return
+	}
+	s.ioStreams.Infof("%s %s\n", color.GreenString("Type:"), step.Type)
+	if step.Properties != nil && len(step.Properties.Raw) > 0 {
+		props, err := yaml.JSONToYAML(step.Properties.Raw)
+		if err == nil {
+			s.ioStreams.Infof("%s\n%s", color.GreenString("Properties:"), props)
+		}
+	}
+	if len(step.Inputs) > 0 {
+		s.ioStreams.Info(color.GreenString("Inputs:"))
+		inputs, err := s.resolveInputs(ctx, step)
+		for _, input := range step.Inputs {
+			val, ok := inputs[input.From]
+			if !ok {
+				s.ioStreams.Infof("  %s -> %s: <unresolved>\n", input.From, input.ParameterKey)
+				continue
+			}
+			bs, _ := json.Marshal(val)
+			s.ioStreams.Infof("  %s -> %s: %s\n", input.From, input.ParameterKey, bs)
+		}
+		s.printError(err)
+	}
+	v, err := s.renderStep(ctx, step)
+	if err != nil {
+		s.printError(errors.WithMessage(err, "render step template"))
+		return
+	}
+	rendered, err := v.String()
+	if err != nil {
+		s.printError(err)
+		return
+	}
+	s.ioStreams.Infof("%s\n%s\n", color.GreenString("Rendered:"), rendered)
+}
+
+func (s *debugSession) printExpression(v *value.Value, expr string) error {
+	if expr != "" {
+		var err error
+		if v, err = v.LookupByScript(expr); err != nil {
+			return err
+		}
+	}
+	str, err := v.String()
+	if err != nil {
+		return err
+	}
+	s.ioStreams.Info(strings.TrimSpace(str))
+	return nil
+}
+
+// findStep finds the workflow step or sub step by name
+func (s *debugSession) findStep(name string) *workflowv1alpha1.WorkflowStepBase {
+	if s.app.Spec.Workflow == nil {
+		return nil
+	}
+	for i, step := range s.app.Spec.Workflow.Steps {
+		if step.Name == name {
+			return &s.app.Spec.Workflow.Steps[i].WorkflowStepBase
+		}
+		for j, sub := range step.SubSteps {
+			if sub.Name == name {
+				return &s.app.Spec.Workflow.Steps[i].SubSteps[j]
+			}
+		}
+	}
+	return nil
+}
+
+// lastExecutedStep returns the failed step, or the step executed before the breakpoint
+func (s *debugSession) lastExecutedStep(pause *debugPause) string {
+	if pause.failed {
+		return pause.step
+	}
+	last := ""
+	for _, step := range s.app.Status.Workflow.Steps {
+		if step.Name == debugBreakpointPrefix+pause.step {
+			break
+		}
+		if !strings.HasPrefix(step.Name, debugBreakpointPrefix) {
+			last = step.Name
+		}
+	}
+	return last
+}
+
+// recordedValue loads the value of the executed step recorded by the debug policy
+func (s *debugSession) recordedValue(ctx context.Context, step string) (*value.Value, error) {
+	if step == "" {
+		return nil, errors.New("no step is executed yet")
+	}
+	d := &debugOpts{step: step}
+	v, _, err := d.getDebugRawValue(ctx, s.cli, s.pd, s.app)
+	return v, err
+}
+
+// resolveInputs evaluates the outputs of the previous steps referred by the inputs of the step
+func (s *debugSession) resolveInputs(ctx context.Context, step *workflowv1alpha1.WorkflowStepBase) (map[string]interface{}, error) {
+	inputs := map[string]interface{}{}
+	for _, input := range step.Inputs {
+		producer, valueFrom := "", ""
+		for _, stepName := range s.stepNames() {
+			for _, output := range s.findStep(stepName).Outputs {
+				if output.Name == input.From {
+					producer, valueFrom = stepName, output.ValueFrom
+				}
+			}
+		}
+		if producer == "" {
+			return inputs, errors.Errorf("no step outputs %s", input.From)
+		}
+		recorded, err := s.recordedValue(ctx, producer)
+		if err != nil {
+			return inputs, errors.WithMessagef(err, "load the recorded value of step %s", producer)
+		}
+		v, err := recorded.LookupByScript(valueFrom)
+		if err != nil {
+			return inputs, errors.WithMessagef(err, "evaluate output %s of step %s", input.From, producer)
+		}
+		var val interface{}
+		bs, err := v.CueValue().MarshalJSON()
+		if err == nil {
+			err = json.Unmarshal(bs, &val)
+		}
+		if err != nil {
+			return inputs, errors.Wrapf(err, "decode output %s of step %s", input.From, producer)
+		}
+		inputs[input.From] = val
+	}
+	return inputs, nil
+}
+
+func (s *debugSession) stepNames() []string {
+	var names []string
+	for _, step := range s.app.Spec.Workflow.Steps {
+		names = append(names, step.Name)
+		for _, sub := range step.SubSteps {
+			names = append(names, sub.Name)
+		}
+	}
+	return names
+}
+
+// contextFile renders the context variables of the step, the component name is the one
+// the step refers to, or the only component of the application
+func (s *debugSession) contextFile(step *workflowv1alpha1.WorkflowStepBase) (string, error) {
+	data := velaprocess.ContextData{
+		Namespace: s.app.Namespace,
+		AppName:   s.app.Name,
+		CompName:  s.stepComponent(step),
+	}
+	if s.app.Status.LatestRevision != nil {
+		data.AppRevisionName = s.app.Status.LatestRevision.Name
+	}
+	return velaprocess.NewContext(data).BaseContextFile()
+}
+
+// stepComponent returns the component set in the properties of the step, or the only
+// component of the application if the step does not set one
+func (s *debugSession) stepComponent(step *workflowv1alpha1.WorkflowStepBase) string {
+	if step != nil && step.Properties != nil && len(step.Properties.Raw) > 0 {
+		props := struct {
+			Component string `json:"component"`
+		}{}
+		if err := json.Unmarshal(step.Properties.Raw, &props); err == nil && props.Component != "" {
+			return props.Component
+		}
+	}
+	if len(s.app.Spec.Components) == 1 {
+		return s.app.Spec.Components[0].Name
+	}
+	return ""
+}
+
+// renderStep renders the template of the step with its properties, resolved inputs and
+// context variables, the actions in the template are not executed
+func (s *debugSession) renderStep(ctx context.Context, step *workflowv1alpha1.WorkflowStepBase) (*value.Value, error) {
+	if step == nil {
+		return nil, errors.New("step not found in workflow")
+	}
+	tmpl, err := s.loader.LoadTemplate(ctx, step.Type)
+	if err != nil {
+		return nil, errors.WithMessagef(err, "load template of step type %s", step.Type)
+	}
+	params := map[string]interface{}{}
+	if step.Properties != nil && len(step.Properties.Raw) > 0 {
+		if err = json.Unmarshal(step.Properties.Raw, &params); err != nil {
+			return nil, errors.Wrapf(err, "invalid properties of step %s", step.Name)
+		}
+	}
+	// inputs that cannot be resolved are left unset in parameter
+	inputs, _ := s.resolveInputs(ctx, step)
+	for _, input := range step.Inputs {
+		if val, ok := inputs[input.From]; ok && input.ParameterKey != "" {
+			if err = unstructured.SetNestedField(params, val, strings.Split(input.ParameterKey, ".")...); err != nil {
+				return nil, err
+			}
+		}
+	}
+	paramsJSON, err := json.Marshal(params)
+	if err != nil {
+		return nil, err
+	}
+	ctxFile, err := s.contextFile(step)
+	if err != nil {
+		return nil, err
+	}
+	return value.NewValue(strings.Join([]string{tmpl, "parameter: " + string(paramsJSON), ctxFile}, "\n"), s.pd, "")
+}
+
+// debugStepEdit is the part of the step could be edited in the debug session
+type debugStepEdit struct {
+	Properties map[string]interface{}       `json:"properties,omitempty"`
+	Inputs     workflowv1alpha1.StepInputs  `json:"inputs,omitempty"`
+	Outputs    workflowv1alpha1.StepOutputs `json:"outputs,omitempty"`
+}
+
+// editStep lets the user edit the properties, the inputs and the outputs of the step in editor and updates the application
+func (s *debugSession) editStep(ctx context.Context, name string) (bool, error) {
+	step := s.findStep(name)
+	if step == nil {
+		return false, errors.Errorf("step %s not found in workflow", name)
+	}
+	current := debugStepEdit{Inputs: step.Inputs, Outputs: step.Outputs}
+	if step.Properties != nil && len(step.Properties.Raw) > 0 {
+		if err := json.Unmarshal(step.Properties.Raw, &current.Properties); err != nil {
+			return false, err
+		}
+	}
+	content, err := yaml.Marshal(current)
+	if err != nil {
+		return false, err
+	}
+	edited, err := s.edit(fmt.Sprintf("Edit the properties, inputs and outputs of step %s", name), string(content))
+	if err != nil {
+		return false, err
+	}
+	if strings.TrimSpace(edited) == strings.TrimSpace(string(content)) {
+		return false, nil
+	}
+	updated := debugStepEdit{}
+	if err = yaml.UnmarshalStrict([]byte(edited), &updated); err != nil {
+		return false, errors.Wrapf(err, "invalid step")
+	}
+	if err = s.updateSpec(ctx, func(app *v1beta1.Application) {
+		if step := s.findStep(name); step != nil {
+			step.Properties = nil
+			if updated.Properties != nil {
+				step.Properties = util.Object2RawExtension(updated.Properties)
+			}
+			step.Inputs = updated.Inputs
+			step.Outputs = updated.Outputs
+		}
+	}); err != nil {
+		return false, err
+	}
+	s.ioStreams.Info(color.CyanString("Step %s is updated, the workflow will restart and run to the step.", name))
+	return true, nil
+}
+
+// runTo marks the breakpoints before the step to be resumed automatically
+func (s *debugSession) runTo(name string) {
+	s.passed = map[string]bool{}
+	for _, step := range s.app.Spec.Workflow.Steps {
+		if strings.TrimPrefix(step.Name, debugBreakpointPrefix) == name || step.Name == name {
+			return
+		}
+		if step.Type == wfTypes.WorkflowStepTypeSuspend && strings.HasPrefix(step.Name, debugBreakpointPrefix) {
+			s.passed[strings.TrimPrefix(step.Name, debugBreakpointPrefix)] = true
+		}
+		for _, sub := range step.SubSteps {
+			if sub.Name == name {
+				return
+			}
+		}
+	}
+}
+
+func (s *debugSession) resume(ctx context.Context) error {
+	return retry.RetryOnConflict(retry.DefaultBackoff, func() error {
+		if err := s.cli.Get(ctx, client.ObjectKeyFromObject(s.app), s.app); err != nil {
+			return err
+		}
+		if s.app.Status.Workflow == nil {
+			return nil
+		}
+		return operation.NewApplicationWorkflowOperator(s.cli, nil, s.app).Resume(ctx)
+	})
+}
+
+func (s *debugSession) restart(ctx context.Context) error {
+	return retry.RetryOnConflict(retry.DefaultBackoff, func() error {
+		if err := s.cli.Get(ctx, client.ObjectKeyFromObject(s.app), s.app); err != nil {
+			return err
+		}
+		if s.app.Status.Workflow == nil {
+			return nil
+		}
+		return operation.NewApplicationWorkflowOperator(s.cli, nil, s.app).Restart(ctx)
+	})
+}
+
+func (s *debugSession) updateSpec(ctx context.Context, mutate func(app *v1beta1.Application)) error {
+	return retry.RetryOnConflict(retry.DefaultBackoff, func() error {
+		if err := s.cli.Get(ctx, client.ObjectKeyFromObject(s.app), s.app); err != nil {
+			return err
+		}
+		mutate(s.app)
+		return s.cli.Update(ctx, s.app)
+	})
+}
+
+// quit asks the user whether to remove the breakpoints and the debug policy added by the session
+func (s *debugSession) quit(ctx context.Context) error {
+	answer, err := s.ask("Remove the breakpoints from the workflow? The workflow will restart without pause. (y/N)")
+	if err != nil {
+		return err
+	}
+	if answer = strings.ToLower(strings.TrimSpace(answer)); answer != "y" && answer != "yes" {
+		s.ioStreams.Info(color.CyanString("Breakpoints are kept, rerun `vela debug %s -i` to continue debugging.", s.app.Name))
+		return nil
+	}
+	if err = s.updateSpec(ctx, func(app *v1beta1.Application) {
+		removeDebugBreakpoints(app)
+		removeDebugSessionPolicy(app)
+	}); err != nil {
+		return errors.WithMessage(err, "failed to remove breakpoints from workflow")
+	}
+	s.ioStreams.Info(color.CyanString("Breakpoints and the debug policy are removed from the application."))
+	return nil
+}
+
+// addDebugSessionPolicy adds the debug policy to record the values of the steps unless the
+// application has one already
+func addDebugSessionPolicy(app *v1beta1.Application) {
+	for _, policy := range app.Spec.Policies {
+		if policy.Type == v1alpha1.DebugPolicyType {
+			return
+		}
+	}
+	app.Spec.Policies = append(app.Spec.Policies, v1beta1.AppPolicy{Name: debugSessionPolicy, Type: v1alpha1.DebugPolicyType})
+}
+
+func removeDebugSessionPolicy(app *v1beta1.Application) {
+	var policies []v1beta1.AppPolicy
+	for _, policy := range app.Spec.Policies {
+		if policy.Type == v1alpha1.DebugPolicyType && policy.Name == debugSessionPolicy {
+			continue
+		}
+		policies = append(policies, policy)
+	}
+	app.Spec.Policies = policies
+}
+
+func hasDebugBreakpoints(app *v1beta1.Application) bool {
+	if app.Spec.Workflow == nil {
+		return false
+	}
+	for _, step := range app.Spec.Workflow.Steps {
+		if step.Type == wfTypes.WorkflowStepTypeSuspend && strings.HasPrefix(step.Name, debugBreakpointPrefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// addDebugBreakpoints inserts a suspend step before each step of the workflow, the sub
+// steps of step groups run without pause
+func addDebugBreakpoints(app *v1beta1.Application) {
+	var steps []workflowv1alpha1.WorkflowStep
+	for _, step := range app.Spec.Workflow.Steps {
+		if step.Type != wfTypes.WorkflowStepTypeSuspend {
+			steps = append(steps, workflowv1alpha1.WorkflowStep{WorkflowStepBase: workflowv1alpha1.WorkflowStepBase{
+				Name: debugBreakpointPrefix + step.Name,
+				Type: wfTypes.WorkflowStepTypeSuspend,
+			}})
+		}
+		steps = append(steps, step)
+	}
+	app.Spec.Workflow.Steps = steps
+}
+
+func removeDebugBreakpoints(app *v1beta1.Application) {
+	if app.Spec.Workflow == nil {
+		return
+	}
+	var steps []workflowv1alpha1.WorkflowStep
+	for _, step := range app.Spec.Workflow.Steps {
+		if step.Type == wfTypes.WorkflowStepTypeSuspend && strings.HasPrefix(step.Name, debugBreakpointPrefix) {
+			continue
+		}
+		steps = append(steps, step)
+	}
+	app.Spec.Workflow.Steps = steps
+}
@@ -0,0 +1,196 @@
+/*
+Copyright 2022 The KubeVela Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cli
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	workflowv1alpha1 "github.com/kubevela/workflow/api/v1alpha1"
+	"github.com/kubevela/workflow/pkg/cue/packages"
+	"github.com/kubevela/workflow/pkg/debug"
+	wfTypes "github.com/kubevela/workflow/pkg/types"
+
+	"github.com/oam-dev/kubevela/apis/core.oam.dev/common"
+	"github.com/oam-dev/kubevela/apis/core.oam.dev/v1beta1"
+	"github.com/oam-dev/kubevela/pkg/oam/util"
+	common2 "github.com/oam-dev/kubevela/pkg/utils/common"
+	cmdutil "github.com/oam-dev/kubevela/pkg/utils/util"
+)
+
+type mapLoader map[string]string
+
+func (l mapLoader) LoadTemplate(_ context.Context, name string) (string, error) {
+	if tmpl, ok := l[name]; ok {
+		return tmpl, nil
+	}
+	return "", errors.Errorf("template %s not found", name)
+}
+
+func TestDebugBreakpoints(t *testing.T) {
+	r := require.New(t)
+	app := &v1beta1.Application{Spec: v1beta1.ApplicationSpec{Workflow: &v1beta1.Workflow{Steps: []workflowv1alpha1.WorkflowStep{
+		{WorkflowStepBase: workflowv1alpha1.WorkflowStepBase{Name: "step1", Type: "apply"}},
+		{WorkflowStepBase: workflowv1alpha1.WorkflowStepBase{Name: "approve", Type: wfTypes.WorkflowStepTypeSuspend}},
+		{WorkflowStepBase: workflowv1alpha1.WorkflowStepBase{Name: "group", Type: "step-group"}, SubSteps: []workflowv1alpha1.WorkflowStepBase{{Name: "sub", Type: "apply"}}},
+	}}}}
+	r.False(hasDebugBreakpoints(app))
+	addDebugBreakpoints(app)
+	r.True(hasDebugBreakpoints(app))
+	var names []string
+	for _, step := range app.Spec.Workflow.Steps {
+		names = append(names, step.Name)
+	}
+	r.Equal([]string{"debug-breakpoint-step1", "step1", "approve", "debug-breakpoint-group", "group"}, names)
+	removeDebugBreakpoints(app)
+	r.False(hasDebugBreakpoints(app))
+	r.Len(app.Spec.Workflow.Steps, 3)
+}
+
+func TestPauseOfWorkflow(t *testing.T) {
+	testCases := map[string]struct {
+		status   *common.WorkflowStatus
+		expected *debugPause
+	}{
+		"not-started": {},
+		"running": {
+			status: &common.WorkflowStatus{Steps: []workflowv1alpha1.WorkflowStepStatus{
+				{StepStatus: workflowv1alpha1.StepStatus{Name: "step1", Phase: workflowv1alpha1.WorkflowStepPhaseRunning}},
+			}},
+		},
+		"breakpoint": {
+			status: &common.WorkflowStatus{Steps: []workflowv1alpha1.WorkflowStepStatus{
+				{StepStatus: workflowv1alpha1.StepStatus{Name: "debug-breakpoint-step1", Type: wfTypes.WorkflowStepTypeSuspend, Phase: workflowv1alpha1.WorkflowStepPhaseSucceeded}},
+				{StepStatus: workflowv1alpha1.StepStatus{Name: "step1", Phase: workflowv1alpha1.WorkflowStepPhaseSucceeded}},
+				{StepStatus: workflowv1alpha1.StepStatus{Name: "debug-breakpoint-step2", Type: wfTypes.WorkflowStepTypeSuspend, Phase: workflowv1alpha1.WorkflowStepPhaseRunning}},
+			}},
+			expected: &debugPause{step: "step2"},
+		},
+		"failed-sub-step": {
+			status: &common.WorkflowStatus{Steps: []workflowv1alpha1.WorkflowStepStatus{
+				{StepStatus: workflowv1alpha1.StepStatus{Name: "group", Phase: workflowv1alpha1.WorkflowStepPhaseRunning},
+					SubStepsStatus: []workflowv1alpha1.StepStatus{{Name: "sub", Phase: workflowv1alpha1.WorkflowStepPhaseFailed, Message: "boom"}}},
+			}},
+			expected: &debugPause{step: "sub", failed: true, message: "boom"},
+		},
+		"finished": {
+			status:   &common.WorkflowStatus{Finished: true},
+			expected: &debugPause{finished: true},
+		},
+	}
+	for name, tt := range testCases {
+		t.Run(name, func(t *testing.T) {
+			app := &v1beta1.Application{Status: common.AppStatus{Workflow: tt.status}}
+			require.Equal(t, tt.expected, pauseOfWorkflow(app))
+		})
+	}
+}
+
+func TestDebugSessionInteract(t *testing.T) {
+	r := require.New(t)
+	app := &v1beta1.Application{
+		ObjectMeta: metav1.ObjectMeta{Name: "app", Namespace: "default"},
+		Spec: v1beta1.ApplicationSpec{Components: []common.ApplicationComponent{{Name: "worker", Type: "webservice"}}, Workflow: &v1beta1.Workflow{Steps: []workflowv1alpha1.WorkflowStep{
+			{WorkflowStepBase: workflowv1alpha1.WorkflowStepBase{Name: "step1", Type: "compute", Outputs: workflowv1alpha1.StepOutputs{{Name: "replicas", ValueFrom: "output.value"}}}},
+			{WorkflowStepBase: workflowv1alpha1.WorkflowStepBase{Name: "step2", Type: "scale", Inputs: workflowv1alpha1.StepInputs{{From: "replicas", ParameterKey: "replicas"}},
+				Properties: util.Object2RawExtension(map[string]interface{}{"name": "web"})}},
+		}}},
+	}
+	addDebugSessionPolicy(app)
+	addDebugBreakpoints(app)
+	app.Status.Workflow = &common.WorkflowStatus{Suspend: true, Steps: []workflowv1alpha1.WorkflowStepStatus{
+		{StepStatus: workflowv1alpha1.StepStatus{Name: "debug-breakpoint-step1", Type: wfTypes.WorkflowStepTypeSuspend, Phase: workflowv1alpha1.WorkflowStepPhaseSucceeded}},
+		{StepStatus: workflowv1alpha1.StepStatus{Name: "step1", Type: "compute", Phase: workflowv1alpha1.WorkflowStepPhaseSucceeded}},
+		{StepStatus: workflowv1alpha1.StepStatus{Name: "debug-breakpoint-step2", Type: wfTypes.WorkflowStepTypeSuspend, Phase: workflowv1alpha1.WorkflowStepPhaseRunning}},
+	}}
+	cm := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: debug.GenerateContextName("app", "step1"), Namespace: "default"},
+		Data:       map[string]string{"debug": "output: value: 3\n"},
+	}
+	cli := fake.NewClientBuilder().WithScheme(common2.Scheme).WithObjects(app, cm).Build()
+	loader := mapLoader{"scale": "parameter: {\n\tname: string\n\treplicas: int\n}\nresult: \"\\(context.name)-\\(parameter.name)-\\(parameter.replicas)\"\n"}
+	out := &bytes.Buffer{}
+	s := newDebugSession(cli, &packages.PackageDiscover{}, loader, app, cmdutil.IOStreams{Out: out})
+	var commands []string
+	s.ask = func(message string) (string, error) {
+		if len(commands) == 0 {
+			return "", errors.New("no more commands")
+		}
+		command := commands[0]
+		commands = commands[1:]
+		return command, nil
+	}
+	pause := pauseOfWorkflow(app)
+	r.Equal("step2", pause.step)
+
+	s.showPause(context.Background(), pause)
+	r.Contains(out.String(), "Paused before step step2")
+	r.Contains(out.String(), "replicas -> replicas: 3")
+
+	out.Reset()
+	commands = []string{"eval result", "recorded output.value", "continue"}
+	quit, err := s.interact(context.Background(), pause)
+	r.NoError(err)
+	r.False(quit)
+	r.Contains(out.String(), `"worker-web-3"`)
+	r.Contains(out.String(), "3")
+	updated := &v1beta1.Application{}
+	r.NoError(cli.Get(context.Background(), client.ObjectKeyFromObject(app), updated))
+	r.Equal(workflowv1alpha1.WorkflowStepPhaseSucceeded, updated.Status.Workflow.Steps[2].Phase)
+	r.False(updated.Status.Workflow.Suspend)
+
+	s.edit = func(message string, content string) (string, error) {
+		r.Equal("inputs:\n- from: replicas\n  parameterKey: replicas\nproperties:\n  name: web\n", content)
+		return "properties:\n  name: api\ninputs:\n- from: image\n  parameterKey: image\noutputs:\n- name: replicas\n  valueFrom: output.value\n", nil
+	}
+	commands = []string{"edit"}
+	quit, err = s.interact(context.Background(), pause)
+	r.NoError(err)
+	r.False(quit)
+	r.True(s.passed["step1"])
+	r.False(s.passed["step2"])
+	r.NoError(cli.Get(context.Background(), client.ObjectKeyFromObject(app), updated))
+	r.Equal(`{"name":"api"}`, string(updated.Spec.Workflow.Steps[3].Properties.Raw))
+	r.Len(updated.Spec.Workflow.Steps[3].Inputs, 1)
+	r.Equal("image", updated.Spec.Workflow.Steps[3].Inputs[0].From)
+	r.Len(updated.Spec.Workflow.Steps[3].Outputs, 1)
+	r.Equal("output.value", updated.Spec.Workflow.Steps[3].Outputs[0].ValueFrom)
+
+	// the unknown fields are rejected
+	s.edit = func(message string, content string) (string, error) {
+		return "property:\n  name: web\n", nil
+	}
+	_, err = s.editStep(context.Background(), "step2")
+	r.Contains(err.Error(), "invalid step")
+
+	commands = []string{"quit", "y"}
+	quit, err = s.interact(context.Background(), pause)
+	r.NoError(err)
+	r.True(quit)
+	r.NoError(s.quit(context.Background()))
+	r.NoError(cli.Get(context.Background(), client.ObjectKeyFromObject(app), updated))
+	r.False(hasDebugBreakpoints(updated))
+	r.Empty(updated.Spec.Policies)
+}
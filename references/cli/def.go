@@ -20,6 +20,7 @@ import (
 	"bufio"
 	"bytes"
 	"context"
+	"encoding/xml"
 	"fmt"
 	"os"
 	"os/exec"
@@ -27,6 +28,7 @@ import (
 	"path/filepath"
 	"reflect"
 	"regexp"
+	"sort"
 	"strconv"
 	"strings"
 	"time"
@@ -34,6 +36,7 @@ import (
 	"cuelang.org/go/cue"
 	"cuelang.org/go/cue/cuecontext"
 	"cuelang.org/go/encoding/gocode/gocodec"
+	"github.com/fatih/color"
 	crossplane "github.com/oam-dev/terraform-controller/api/types/crossplane-runtime"
 	"github.com/pkg/errors"
 	"github.com/spf13/cobra"
@@ -87,9 +90,12 @@ func DefinitionCommandGroup(c common.Args, order string, ioStreams util.IOStream
 		NewDefinitionDelCommand(c),
 		NewDefinitionInitCommand(c),
 		NewDefinitionValidateCommand(c),
+		NewDefinitionDiffCommand(c),
+		NewDefinitionTestCommand(),
 		NewDefinitionGenDocCommand(c, ioStreams),
 		NewCapabilityShowCommand(c, ioStreams),
 		NewDefinitionGenAPICommand(c),
+		NewDefinitionGenSDKCommand(c),
 	)
 	return cmd
 }
@@ -521,7 +527,8 @@ func NewDefinitionGetCommand(c common.Args) *cobra.Command {
 
 // NewDefinitionGenDocCommand create the `vela def doc-gen` command to generate documentation of definitions
 func NewDefinitionGenDocCommand(c common.Args, ioStreams util.IOStreams) *cobra.Command {
-	var docPath, location, i18nPath string
+	var docPath, location, i18nPath, siteDir string
+	var fromDirs, fromAddons []string
 	cmd := &cobra.Command{
 		Use:   "doc-gen NAME",
 		Short: "Generate documentation for definitions",
@@ -531,16 +538,30 @@ func NewDefinitionGenDocCommand(c common.Args, ioStreams util.IOStreams) *cobra.
 			"2. Generate documentation for local CUE Definition file webservice.cue:\n" +
 			"> vela def doc-gen webservice.cue\n" +
 			"3. Generate documentation for local Cloud Resource Definition YAML alibaba-vpc.yaml:\n" +
-			"> vela def doc-gen alibaba-vpc.yaml\n",
+			"> vela def doc-gen alibaba-vpc.yaml\n" +
+			"4. Generate the documentation site of all definitions in the cluster into directory site:\n" +
+			"> vela def doc-gen --site site\n" +
+			"5. Generate the documentation site of the definitions in local directory and addon:\n" +
+			"> vela def doc-gen --site site --from-dir ./defs --from-addon ./addons/fluxcd\n",
 		Deprecated: "This command has been replaced by 'vela show' or 'vela def show'.",
 		RunE: func(cmd *cobra.Command, args []string) error {
-			if len(args) == 0 {
-				return fmt.Errorf("please specify definition name, cue file or a cloud resource definition yaml")
-			}
 			namespace, err := cmd.Flags().GetString(FlagNamespace)
 			if err != nil {
 				return errors.Wrapf(err, "failed to get `%s`", Namespace)
 			}
+			if siteDir != "" {
+				return generateDefinitionSite(context.Background(), c, ioStreams, definitionSiteOptions{
+					dir:        siteDir,
+					fromDirs:   fromDirs,
+					fromAddons: fromAddons,
+					namespace:  namespace,
+					location:   location,
+					i18nPath:   i18nPath,
+				})
+			}
+			if len(args) == 0 {
+				return fmt.Errorf("please specify definition name, cue file or a cloud resource definition yaml")
+			}
 			return ShowReferenceMarkdown(context.Background(), c, ioStreams, args[0], docPath, location, i18nPath, namespace, 0)
 
 		},
@@ -549,6 +570,9 @@ func NewDefinitionGenDocCommand(c common.Args, ioStreams util.IOStreams) *cobra.
 	cmd.Flags().StringVarP(&location, "location", "l", "", "specify the location for of the doc generated from definition, now supported options 'zh', 'en'. ")
 	cmd.Flags().StringP(Namespace, "n", types.DefaultKubeVelaNS, "Specify which namespace the definition locates.")
 	cmd.Flags().StringVarP(&i18nPath, "i18n", "", "https://kubevela.io/reference-i18n.json", "specify the location for of the doc generated from definition, now supported options 'zh', 'en'. ")
+	cmd.Flags().StringVarP(&siteDir, "site", "", "", "Generate the documentation site of all definitions into the directory, with index, search index, version history and validated examples.")
+	cmd.Flags().StringSliceVarP(&fromDirs, "from-dir", "", nil, "Load the definitions of the site from the local directories instead of the cluster, only works with --site.")
+	cmd.Flags().StringSliceVarP(&fromAddons, "from-addon", "", nil, "Load the definitions of the site from the local addon packages instead of the cluster, only works with --site.")
 	return cmd
 }
 
@@ -1062,6 +1086,121 @@ func NewDefinitionValidateCommand(c common.Args) *cobra.Command {
 	return cmd
 }
 
+// NewDefinitionTestCommand create the `vela def test` command to help user test the rendering of the definitions
+func NewDefinitionTestCommand() *cobra.Command {
+	var (
+		casesFile string
+		update    bool
+		junitFile string
+	)
+	cmd := &cobra.Command{
+		Use:   "test DEFINITION.cue",
+		Short: "Test X-Definition.",
+		Long: "Test the rendering of X-Definition with the test cases offline.\n" +
+			"The test cases are read from the file with the same name of the definition and the suffix " + pkgdef.TestSuiteFileSuffix + ", " +
+			"which contains the parameter, the context and the expected output of each case. If a directory is used as input, " +
+			"all cue definitions with the test cases in the directory will be tested.\n" +
+			"* Currently, only ComponentDefinition and TraitDefinition are supported.",
+		Example: "# Command below will test my-def.cue with the test cases in my-def" + pkgdef.TestSuiteFileSuffix + ".\n" +
+			"> vela def test my-def.cue\n" +
+			"# Command below will test all definitions in the ./defs/ directory and write the JUnit report.\n" +
+			"> vela def test ./defs/ --junit report.xml\n" +
+			"# Command below will update the golden files with the rendered result.\n" +
+			"> vela def test my-def.cue --update",
+		Args: cobra.ExactValidArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			defFiles := map[string]string{args[0]: casesFile}
+			fi, err := os.Stat(args[0])
+			if err != nil {
+				return errors.Wrapf(err, "failed to get input %s", args[0])
+			}
+			if fi.IsDir() {
+				if casesFile != "" {
+					return errors.New("cannot specify the test cases file for directory")
+				}
+				defFiles = map[string]string{}
+				err = filepath.Walk(args[0], func(path string, info os.FileInfo, err error) error {
+					if err != nil || filepath.Ext(path) != ".cue" {
+						return err
+					}
+					if _, err := os.Stat(strings.TrimSuffix(path, ".cue") + pkgdef.TestSuiteFileSuffix); err == nil {
+						defFiles[path] = ""
+					}
+					return nil
+				})
+				if err != nil {
+					return errors.Wrapf(err, "failed to read directory %s", args[0])
+				}
+			}
+			var paths []string
+			for path := range defFiles {
+				paths = append(paths, path)
+			}
+			sort.Strings(paths)
+
+			report := pkgdef.JUnitTestSuites{}
+			total, failed := 0, 0
+			for _, path := range paths {
+				results, err := testDefinition(path, defFiles[path], update)
+				if err != nil {
+					return err
+				}
+				cmd.Printf("--- %s ---\n", filepath.Base(path))
+				for _, result := range results {
+					total++
+					if result.Passed() {
+						cmd.Printf("%s %s (%s)\n", color.GreenString("PASS"), result.Name, result.Duration.Round(time.Millisecond))
+						continue
+					}
+					failed++
+					cmd.Printf("%s %s (%s)\n", color.RedString("FAIL"), result.Name, result.Duration.Round(time.Millisecond))
+					for _, failure := range result.Failures {
+						cmd.Printf("    %s\n", failure)
+					}
+				}
+				report.Suites = append(report.Suites, pkgdef.NewJUnitTestSuite(filepath.Base(path), results))
+			}
+			if junitFile != "" {
+				bs, err := xml.MarshalIndent(report, "", "  ")
+				if err != nil {
+					return err
+				}
+				if err = os.WriteFile(junitFile, append([]byte(xml.Header), bs...), 0600); err != nil {
+					return errors.Wrapf(err, "failed to write JUnit report to %s", junitFile)
+				}
+			}
+			cmd.Printf("\n%d passed, %d failed\n", total-failed, failed)
+			if failed > 0 {
+				return errors.Errorf("%d test case(s) failed", failed)
+			}
+			return nil
+		},
+	}
+	cmd.Flags().StringVar(&casesFile, "cases", "", "Specify the file of the test cases. If empty, the file with the same name of the definition and the suffix "+pkgdef.TestSuiteFileSuffix+" will be used.")
+	cmd.Flags().BoolVar(&update, "update", false, "Update the golden files with the rendered result instead of comparing them.")
+	cmd.Flags().StringVar(&junitFile, "junit", "", "Specify the path to write the test report in JUnit format.")
+	return cmd
+}
+
+func testDefinition(defFile string, casesFile string, update bool) ([]pkgdef.TestResult, error) {
+	cueBytes, err := os.ReadFile(filepath.Clean(defFile))
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to read %s", defFile)
+	}
+	def := pkgdef.Definition{Unstructured: unstructured.Unstructured{}}
+	if err = def.FromCUEString(string(cueBytes), nil); err != nil {
+		return nil, errors.Wrapf(err, "failed to parse CUE %s", defFile)
+	}
+	if casesFile == "" {
+		casesFile = strings.TrimSuffix(defFile, ".cue") + pkgdef.TestSuiteFileSuffix
+	}
+	suite, err := pkgdef.LoadTestSuite(casesFile)
+	if err != nil {
+		return nil, err
+	}
+	return def.RunTestSuite(suite, filepath.Dir(casesFile), update), nil
+}
+
 // NewDefinitionGenAPICommand create the `vela def gen-api` command to help user generate Go code from the definition
 func NewDefinitionGenAPICommand(c common.Args) *cobra.Command {
 	var (
@@ -1122,3 +1261,115 @@ func NewDefinitionGenAPICommand(c common.Args) *cobra.Command {
 	cmd.Flags().StringVar(&prefix, "prefix", "", "Specify the prefix of the generated Go struct.")
 	return cmd
 }
+
+// NewDefinitionGenSDKCommand create the `vela def gen-sdk` command to help user generate the SDK from definitions
+func NewDefinitionGenSDKCommand(c common.Args) *cobra.Command {
+	var (
+		lang        string
+		packageName string
+		output      string
+		fromCluster bool
+		namespace   string
+	)
+	cmd := &cobra.Command{
+		Use:   "gen-sdk [DEFINITION.cue|DIRECTORY]",
+		Short: "Generate SDK from X-Definitions.",
+		Long: "Generate the SDK of Application from the definitions in the local files or in the cluster.\n" +
+			"The parameter types of the components, traits, policies and workflow steps are generated with the name prefixed by the definition name, " +
+			"along with the builder helpers for assembling the whole Application.\n" +
+			"Supported languages: " + strings.Join(pkgdef.SDKLanguages(), ", ") + ".",
+		Example: "# Command below will generate the TypeScript SDK for all definitions in the ./defs/ directory.\n" +
+			"> vela def gen-sdk ./defs/ --lang typescript -o ./sdk/\n" +
+			"# Command below will generate the Java SDK for the definitions installed in the cluster.\n" +
+			"> vela def gen-sdk --from-cluster --lang java --package com.example.vela -o ./src/main/java/com/example/vela/",
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if fromCluster == (len(args) > 0) {
+				return errors.New("either the path of definitions or --from-cluster must be specified")
+			}
+			generator, err := pkgdef.NewSDKGenerator(pkgdef.SDKLanguage(lang), packageName)
+			if err != nil {
+				return err
+			}
+			defs, pd, err := loadSDKDefinitions(c, args, fromCluster, namespace)
+			if err != nil {
+				return err
+			}
+			for _, def := range defs {
+				if err := generator.AddDefinition(def, pd); err != nil {
+					cmd.PrintErrf("skip %s %s: %v\n", def.GetType(), def.GetName(), err)
+				}
+			}
+			filename, code, err := generator.Generate()
+			if err != nil {
+				return errors.Wrapf(err, "failed to generate SDK")
+			}
+			if output == "" {
+				cmd.Print(string(code))
+				return nil
+			}
+			if err := os.MkdirAll(output, 0750); err != nil {
+				return errors.Wrapf(err, "failed to create output directory %s", output)
+			}
+			if err := os.WriteFile(filepath.Join(output, filename), code, 0600); err != nil {
+				return errors.Wrapf(err, "failed to write %s", filename)
+			}
+			cmd.Printf("SDK generated in %s\n", filepath.Join(output, filename))
+			return nil
+		},
+	}
+	cmd.Flags().StringVarP(&lang, "lang", "l", string(pkgdef.SDKLanguageTypeScript), "Specify the language of the generated SDK. Valid languages: "+strings.Join(pkgdef.SDKLanguages(), ", "))
+	cmd.Flags().StringVar(&packageName, "package", "", "Specify the package name of the generated code, only used for go and java.")
+	cmd.Flags().StringVarP(&output, "output", "o", "", "Specify the output directory of the generated code. If empty, the code will be printed in the console.")
+	cmd.Flags().BoolVar(&fromCluster, "from-cluster", false, "Generate SDK from the definitions installed in the cluster.")
+	cmd.Flags().StringVarP(&namespace, Namespace, "n", types.DefaultKubeVelaNS, "Specify the namespace of the definitions in the cluster, only used with --from-cluster.")
+	return cmd
+}
+
+func loadSDKDefinitions(c common.Args, args []string, fromCluster bool, namespace string) ([]*pkgdef.Definition, *packages.PackageDiscover, error) {
+	var defs []*pkgdef.Definition
+	if fromCluster {
+		config, err := c.GetConfig()
+		if err != nil {
+			return nil, nil, err
+		}
+		k8sClient, err := c.GetClient()
+		if err != nil {
+			return nil, nil, err
+		}
+		pd, err := packages.NewPackageDiscover(config)
+		if err != nil {
+			return nil, nil, err
+		}
+		objs, err := pkgdef.SearchDefinition(k8sClient, "", namespace)
+		if err != nil {
+			return nil, nil, err
+		}
+		for i := range objs {
+			defs = append(defs, &pkgdef.Definition{Unstructured: objs[i]})
+		}
+		return defs, pd, nil
+	}
+	var files []string
+	err := filepath.Walk(args[0], func(path string, info os.FileInfo, err error) error {
+		if err == nil && !info.IsDir() && filepath.Ext(path) == ".cue" {
+			files = append(files, path)
+		}
+		return err
+	})
+	if err != nil {
+		return nil, nil, errors.Wrapf(err, "failed to read %s", args[0])
+	}
+	for _, file := range files {
+		cueBytes, err := os.ReadFile(filepath.Clean(file))
+		if err != nil {
+			return nil, nil, errors.Wrapf(err, "failed to read %s", file)
+		}
+		def := &pkgdef.Definition{Unstructured: unstructured.Unstructured{}}
+		if err := def.FromLocalCUEString(string(cueBytes)); err != nil {
+			return nil, nil, errors.Wrapf(err, "failed to parse CUE %s", file)
+		}
+		defs = append(defs, def)
+	}
+	return defs, nil, nil
+}
@@ -0,0 +1,323 @@
+/*
+Copyright 2022 The KubeVela Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cli
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/oam-dev/kubevela/apis/core.oam.dev/v1beta1"
+	"github.com/oam-dev/kubevela/apis/types"
+	"github.com/oam-dev/kubevela/pkg/cue/script"
+	pkgdef "github.com/oam-dev/kubevela/pkg/definition"
+	"github.com/oam-dev/kubevela/pkg/utils/common"
+	"github.com/oam-dev/kubevela/references/docgen"
+)
+
+// definitionUsage is the usage of a definition in an application
+type definitionUsage struct {
+	App       string
+	Namespace string
+	// Name is the name of the component, trait (by the component it's attached to), policy or workflow step
+	Name       string
+	Properties *runtime.RawExtension
+}
+
+// NewDefinitionDiffCommand create the `vela def diff` command to detect the breaking changes between definitions
+func NewDefinitionDiffCommand(c common.Args) *cobra.Command {
+	var scanApps bool
+	cmd := &cobra.Command{
+		Use:   "diff OLD [NEW]",
+		Short: "Compare the parameters of definitions.",
+		Long: "Compare the parameters of two definitions and classify the changes as breaking or compatible.\n" +
+			"The definition could be a local CUE or YAML file, a definition in the cluster by NAME, or a revision of it by NAME@vREVISION.\n" +
+			"If only one local file is given, it will be compared with the definition of the same name in the cluster.",
+		Example: "# Command below will compare the local file my-webservice.cue with the webservice in the cluster\n" +
+			"> vela def diff my-webservice.cue\n" +
+			"# Command below will compare the revision 1 and revision 2 of webservice\n" +
+			"> vela def diff webservice@v1 webservice@v2\n" +
+			"# Command below will compare the local files and list the applications that would fail with the new definition\n" +
+			"> vela def diff webservice.cue my-webservice.cue --scan-apps",
+		Args: cobra.RangeArgs(1, 2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			definitionType, err := cmd.Flags().GetString(FlagType)
+			if err != nil {
+				return errors.Wrapf(err, "failed to get `%s`", FlagType)
+			}
+			namespace, err := cmd.Flags().GetString(FlagNamespace)
+			if err != nil {
+				return errors.Wrapf(err, "failed to get `%s`", Namespace)
+			}
+			ctx := context.Background()
+			var k8sClient client.Client
+			getClient := func() (client.Client, error) {
+				if k8sClient == nil {
+					if k8sClient, err = c.GetClient(); err != nil {
+						return nil, errors.Wrapf(err, "failed to get k8s client")
+					}
+				}
+				return k8sClient, nil
+			}
+
+			var oldDef, newDef *pkgdef.Definition
+			if len(args) == 1 {
+				if newDef, err = loadLocalDefinition(args[0]); err != nil {
+					return err
+				}
+				if definitionType == "" {
+					definitionType = newDef.GetType()
+				}
+				args = []string{newDef.GetName(), args[0]}
+			} else if newDef, err = loadDefinitionForDiff(ctx, cmd, getClient, args[1], definitionType, namespace); err != nil {
+				return err
+			}
+			if oldDef, err = loadDefinitionForDiff(ctx, cmd, getClient, args[0], definitionType, namespace); err != nil {
+				return err
+			}
+			if oldDef.GetKind() != newDef.GetKind() {
+				return fmt.Errorf("cannot compare %s %s with %s %s", oldDef.GetKind(), oldDef.GetName(), newDef.GetKind(), newDef.GetName())
+			}
+
+			changes, err := diffDefinitions(oldDef, newDef)
+			if err != nil {
+				return err
+			}
+			printDefinitionChanges(cmd, changes)
+
+			if !scanApps {
+				return nil
+			}
+			cli, err := getClient()
+			if err != nil {
+				return err
+			}
+			usages, err := listDefinitionUsages(ctx, cli, newDef.GetKind(), newDef.GetName())
+			if err != nil {
+				return err
+			}
+			return printInvalidUsages(cmd, newDef, usages)
+		},
+	}
+	cmd.Flags().StringP(FlagType, "t", "", "Specify which definition type to compare. If empty, all types will be searched. Valid types: "+strings.Join(pkgdef.ValidDefinitionTypes(), ", "))
+	cmd.Flags().StringP(Namespace, "n", types.DefaultKubeVelaNS, "Specify which namespace the definition locates.")
+	cmd.Flags().BoolVarP(&scanApps, "scan-apps", "", false, "Scan the applications in the cluster using the definition and list the ones failing to validate with the new definition.")
+	return cmd
+}
+
+// loadLocalDefinition load the definition from the local CUE or YAML file
+func loadLocalDefinition(path string) (*pkgdef.Definition, error) {
+	data, err := os.ReadFile(filepath.Clean(path))
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to read %s", path)
+	}
+	def := &pkgdef.Definition{Unstructured: unstructured.Unstructured{}}
+	switch filepath.Ext(path) {
+	case ".cue":
+		err = def.FromCUEString(string(data), nil)
+	case ".yaml", ".yml", ".json":
+		err = def.FromYAML(data)
+	default:
+		return nil, fmt.Errorf("unsupported definition file %s, only CUE and YAML are supported", path)
+	}
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to parse definition %s", path)
+	}
+	return def, nil
+}
+
+// loadDefinitionForDiff load the definition from the local file if exists, otherwise from the cluster by NAME or NAME@vREVISION
+func loadDefinitionForDiff(ctx context.Context, cmd *cobra.Command, getClient func() (client.Client, error), ref, definitionType, namespace string) (*pkgdef.Definition, error) {
+	if _, err := os.Stat(ref); err == nil {
+		return loadLocalDefinition(ref)
+	}
+	k8sClient, err := getClient()
+	if err != nil {
+		return nil, err
+	}
+	name, revision, found := strings.Cut(ref, "@v")
+	if !found {
+		return getSingleDefinition(cmd, name, k8sClient, definitionType, namespace)
+	}
+	rev, err := strconv.ParseInt(revision, 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid revision of %s: %w", ref, err)
+	}
+	revs, err := getDefRevs(ctx, k8sClient, namespace, definitionType, name, rev)
+	if err != nil {
+		return nil, err
+	}
+	if len(revs) == 0 {
+		return nil, fmt.Errorf("no %s with revision %d found in namespace %s", name, rev, namespace)
+	}
+	def, err := pkgdef.GetDefinitionFromDefinitionRevision(&revs[0])
+	if err != nil {
+		return nil, err
+	}
+	if def.GetKind() == "" {
+		// the type meta of the definition is not always stored in the revision
+		def.SetGVK(string(revs[0].Spec.DefinitionType) + "Definition")
+	}
+	return def, nil
+}
+
+// definitionTemplate return the CUE template of the definition
+func definitionTemplate(def *pkgdef.Definition) (string, error) {
+	template, found, err := unstructured.NestedString(def.Object, pkgdef.DefinitionTemplateKeys...)
+	if err != nil {
+		return "", err
+	}
+	if !found {
+		return "", fmt.Errorf("%s %s has no CUE template", def.GetKind(), def.GetName())
+	}
+	return template, nil
+}
+
+// diffDefinitions compare the parameters of the two definitions
+func diffDefinitions(oldDef, newDef *pkgdef.Definition) ([]docgen.ParameterChange, error) {
+	var params []map[string]docgen.ParameterSchema
+	for _, def := range []*pkgdef.Definition{oldDef, newDef} {
+		template, err := definitionTemplate(def)
+		if err != nil {
+			return nil, err
+		}
+		p, err := docgen.ParseParameterSchema(types.Capability{Name: def.GetName(), Category: types.CUECategory, CueTemplate: template})
+		if err != nil {
+			return nil, err
+		}
+		params = append(params, p)
+	}
+	return docgen.DiffParameters(params[0], params[1]), nil
+}
+
+func printDefinitionChanges(cmd *cobra.Command, changes []docgen.ParameterChange) {
+	if len(changes) == 0 {
+		cmd.Println("No parameter changes.")
+		return
+	}
+	breaking := 0
+	table := newUITable()
+	table.AddRow("PARAMETER", "CHANGE", "IMPACT", "DETAIL")
+	for _, change := range changes {
+		impact := "compatible"
+		if change.Breaking() {
+			impact = "breaking"
+			breaking++
+		}
+		table.AddRow(change.Path, change.Type, impact, change.String())
+	}
+	cmd.Println(table)
+	cmd.Printf("\n%d breaking changes, %d compatible changes.\n", breaking, len(changes)-breaking)
+}
+
+// listDefinitionUsages list the usages of the definition in the applications of all namespaces. The usages
+// referring a specific revision of the definition like NAME@vREVISION are not affected by the update, so
+// they are not included.
+func listDefinitionUsages(ctx context.Context, k8sClient client.Client, kind, name string) ([]definitionUsage, error) {
+	apps := v1beta1.ApplicationList{}
+	if err := k8sClient.List(ctx, &apps); err != nil {
+		return nil, errors.Wrapf(err, "failed to list applications")
+	}
+	var usages []definitionUsage
+	for _, app := range apps.Items {
+		add := func(usageName, usageType string, properties *runtime.RawExtension) {
+			if usageType == name {
+				usages = append(usages, definitionUsage{App: app.Name, Namespace: app.Namespace, Name: usageName, Properties: properties})
+			}
+		}
+		switch kind {
+		case v1beta1.ComponentDefinitionKind:
+			for _, comp := range app.Spec.Components {
+				add(comp.Name, comp.Type, comp.Properties)
+			}
+		case v1beta1.TraitDefinitionKind:
+			for _, comp := range app.Spec.Components {
+				for _, trait := range comp.Traits {
+					add(comp.Name, trait.Type, trait.Properties)
+				}
+			}
+		case v1beta1.PolicyDefinitionKind:
+			for _, policy := range app.Spec.Policies {
+				add(policy.Name, policy.Type, policy.Properties)
+			}
+		case v1beta1.WorkflowStepDefinitionKind:
+			if app.Spec.Workflow == nil {
+				continue
+			}
+			for _, step := range app.Spec.Workflow.Steps {
+				add(step.Name, step.Type, step.Properties)
+				for _, sub := range step.SubSteps {
+					add(sub.Name, sub.Type, sub.Properties)
+				}
+			}
+		}
+	}
+	return usages, nil
+}
+
+// validateDefinitionProperties validate the properties by the parameter of the CUE template
+func validateDefinitionProperties(template string, properties *runtime.RawExtension) error {
+	props := "{}"
+	if properties != nil && len(properties.Raw) > 0 {
+		props = string(properties.Raw)
+	}
+	val, err := script.CUE(template + "\nparameter: " + props).ParseToValue()
+	if err != nil {
+		return err
+	}
+	parameter, err := val.LookupValue("parameter")
+	if err != nil {
+		return err
+	}
+	if err = parameter.CueValue().Validate(); err != nil {
+		return script.ConvertFieldError(err)
+	}
+	if _, err = parameter.CueValue().MarshalJSON(); err != nil {
+		return script.ConvertFieldError(err)
+	}
+	return nil
+}
+
+func printInvalidUsages(cmd *cobra.Command, def *pkgdef.Definition, usages []definitionUsage) error {
+	template, err := definitionTemplate(def)
+	if err != nil {
+		return err
+	}
+	table := newUITable()
+	table.AddRow("APP", "NAMESPACE", "NAME", "ERROR")
+	invalid := 0
+	for _, usage := range usages {
+		if err := validateDefinitionProperties(template, usage.Properties); err != nil {
+			table.AddRow(usage.App, usage.Namespace, usage.Name, strings.ReplaceAll(err.Error(), "\n", " "))
+			invalid++
+		}
+	}
+	cmd.Printf("\n%d of %d usages of %s in applications would fail to validate with the new definition.\n", invalid, len(usages), def.GetName())
+	if invalid > 0 {
+		cmd.Println(table)
+	}
+	return nil
+}
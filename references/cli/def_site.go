@@ -0,0 +1,169 @@
+/*
+Copyright 2022 The KubeVela Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cli
+
+import (
+	"context"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/kubevela/workflow/pkg/cue/packages"
+
+	"github.com/oam-dev/kubevela/apis/core.oam.dev/v1beta1"
+	"github.com/oam-dev/kubevela/apis/types"
+	"github.com/oam-dev/kubevela/pkg/appfile/dryrun"
+	pkgdef "github.com/oam-dev/kubevela/pkg/definition"
+	"github.com/oam-dev/kubevela/pkg/oam"
+	"github.com/oam-dev/kubevela/pkg/utils/common"
+	"github.com/oam-dev/kubevela/pkg/utils/util"
+	"github.com/oam-dev/kubevela/references/docgen"
+)
+
+// definitionSiteOptions is the options of generating the definition site by `vela def doc-gen --site`
+type definitionSiteOptions struct {
+	dir        string
+	fromDirs   []string
+	fromAddons []string
+	namespace  string
+	location   string
+	i18nPath   string
+}
+
+// generateDefinitionSite renders the definitions into a static site. The definitions come from the local
+// directories and addons if specified, otherwise from the cluster.
+func generateDefinitionSite(ctx context.Context, c common.Args, ioStreams util.IOStreams, opts definitionSiteOptions) error {
+	i18n, err := loadDocI18n(opts.location, opts.i18nPath)
+	if err != nil {
+		return err
+	}
+	gen := &docgen.SiteGenerator{}
+	gen.I18N = i18n
+
+	var caps []types.Capability
+	var pd *packages.PackageDiscover
+	var dryRunOpt *dryrun.Option
+	if len(opts.fromDirs) > 0 || len(opts.fromAddons) > 0 {
+		paths := append([]string{}, opts.fromDirs...)
+		for _, addon := range opts.fromAddons {
+			paths = append(paths, filepath.Join(addon, "definitions"))
+		}
+		var objs []oam.Object
+		for _, p := range paths {
+			lcaps, err := docgen.ParseLocalFiles(p, c)
+			if err != nil {
+				return errors.WithMessagef(err, "load definitions from %s", p)
+			}
+			for _, lcap := range lcaps {
+				caps = append(caps, *lcap)
+			}
+			defs, err := readDefinitionObjects(p)
+			if err != nil {
+				return errors.WithMessagef(err, "load definitions from %s", p)
+			}
+			objs = append(objs, defs...)
+		}
+		// the examples of the local definitions are rendered offline with the definitions preloaded
+		fakeClient, err := c.GetFakeClient(objs)
+		if err != nil {
+			return err
+		}
+		dryRunOpt = dryrun.NewDryRunOption(fakeClient, nil, nil, &packages.PackageDiscover{}, objs, false)
+	} else {
+		if caps, err = docgen.LoadAllInstalledCapability(opts.namespace, c); err != nil {
+			return errors.WithMessage(err, "load definitions from cluster")
+		}
+		if pd, err = c.GetPackageDiscover(); err != nil {
+			return err
+		}
+		if gen.DiscoveryMapper, err = c.GetDiscoveryMapper(); err != nil {
+			return err
+		}
+		gen.Revisions = func(ctx context.Context, capability types.Capability) ([]docgen.CapabilityRevision, error) {
+			return docgen.ListCapabilityRevisions(ctx, c, pd, opts.namespace, capability.Name, capability.Type)
+		}
+		if dryRunOpt, err = newDryRunOption(&DryRunCmdOptions{}, c, nil); err != nil {
+			return err
+		}
+	}
+	gen.ValidateExample = func(ctx context.Context, app *v1beta1.Application) error {
+		if app.Namespace == "" {
+			app.Namespace = types.DefaultAppNamespace
+		}
+		_, _, err := dryRunOpt.ExecuteDryRun(ctx, app)
+		return err
+	}
+
+	report, err := gen.Generate(ctx, caps, pd, opts.dir)
+	if err != nil {
+		return err
+	}
+	ioStreams.Infof("Generated %d pages of definitions into %s, %d examples are validated.\n", report.Pages, opts.dir, report.Examples)
+	if len(report.ExampleErrors) > 0 {
+		var names []string
+		for name := range report.ExampleErrors {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		ioStreams.Infof("The examples of %d definitions failed to be rendered:\n", len(names))
+		for _, name := range names {
+			ioStreams.Infof("  %s: %v\n", name, report.ExampleErrors[name])
+		}
+	}
+	return nil
+}
+
+// readDefinitionObjects read the definitions in CUE or YAML format from the file or directory
+func readDefinitionObjects(path string) ([]oam.Object, error) {
+	var objs []oam.Object
+	err := filepath.WalkDir(path, func(file string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			return nil
+		}
+		switch filepath.Ext(file) {
+		case ".cue":
+			data, err := os.ReadFile(filepath.Clean(file))
+			if err != nil {
+				return err
+			}
+			def := pkgdef.Definition{Unstructured: unstructured.Unstructured{}}
+			if err = def.FromCUEString(string(data), nil); err != nil {
+				return errors.Wrapf(err, "failed to parse CUE definition %s", file)
+			}
+			objs = append(objs, &def.Unstructured)
+		case ".yaml", ".yml":
+			obj := &unstructured.Unstructured{}
+			if err := common.ReadYamlToObject(file, obj); err != nil {
+				return errors.Wrapf(err, "failed to read definition %s", file)
+			}
+			if !strings.HasSuffix(obj.GetKind(), "Definition") {
+				return nil
+			}
+			objs = append(objs, obj)
+		}
+		return nil
+	})
+	return objs, err
+}
@@ -32,6 +32,7 @@ import (
 	"github.com/stretchr/testify/assert"
 	"k8s.io/apimachinery/pkg/api/errors"
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 	"sigs.k8s.io/yaml"
@@ -630,3 +631,117 @@ func TestNewDefinitionVetCommand(t *testing.T) {
 		t.Fatalf("expect validation failed but error not found")
 	}
 }
+
+func TestReadDefinitionObjects(t *testing.T) {
+	objs, err := readDefinitionObjects("./test-data/defapply")
+	assert.NoError(t, err)
+	assert.Equal(t, 2, len(objs))
+	assert.Equal(t, "TraitDefinition", objs[0].GetObjectKind().GroupVersionKind().Kind)
+	assert.Equal(t, "ComponentDefinition", objs[1].GetObjectKind().GroupVersionKind().Kind)
+	_, err = readDefinitionObjects("./test-data/not-exist")
+	assert.Error(t, err)
+}
+
+func TestLoadSDKDefinitions(t *testing.T) {
+	dir := t.TempDir()
+	stepDef := `import "vela/op"
+
+"apply-and-wait": {
+	type: "workflow-step"
+	annotations: {}
+	labels: {}
+	description: "Apply the component and wait"
+}
+template: {
+	apply: op.#ApplyComponent & {
+		component: parameter.component
+	}
+	parameter: component: string
+}
+`
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "step.cue"), []byte(stepDef), 0600))
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "README.md"), []byte("not a definition"), 0600))
+	defs, pd, err := loadSDKDefinitions(common2.Args{}, []string{dir}, false, "")
+	assert.NoError(t, err)
+	assert.Nil(t, pd)
+	assert.Equal(t, 1, len(defs))
+	assert.Equal(t, "apply-and-wait", defs[0].GetName())
+	assert.Equal(t, "workflow-step", defs[0].GetType())
+
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "invalid.cue"), []byte("abc:]{xa}"), 0600))
+	_, _, err = loadSDKDefinitions(common2.Args{}, []string{dir}, false, "")
+	assert.Error(t, err)
+}
+
+func TestNewDefinitionDiffCommand(t *testing.T) {
+	c := initArgs()
+	dir := t.TempDir()
+	oldDef := `"my-scaler": {
+	type: "trait"
+	attributes: {}
+}
+template: {
+	patch: spec: replicas: parameter.replicas
+	parameter: {
+		replicas: *1 | int
+		label?: string
+	}
+}
+`
+	newDef := `"my-scaler": {
+	type: "trait"
+	attributes: {}
+}
+template: {
+	patch: spec: replicas: parameter.replicas
+	parameter: {
+		replicas: *2 | int
+		zone: string
+	}
+}
+`
+	oldFile, newFile := filepath.Join(dir, "old.cue"), filepath.Join(dir, "new.cue")
+	assert.NoError(t, os.WriteFile(oldFile, []byte(oldDef), 0600))
+	assert.NoError(t, os.WriteFile(newFile, []byte(newDef), 0600))
+	k8sClient, err := c.GetClient()
+	assert.NoError(t, err)
+	for i, props := range []string{`{"replicas":3,"zone":"a"}`, `{"replicas":3,"label":"x"}`, `{"replicas":3}`} {
+		app := &v1beta1.Application{
+			ObjectMeta: v1.ObjectMeta{Name: fmt.Sprintf("app-%d", i), Namespace: "default"},
+			Spec: v1beta1.ApplicationSpec{Components: []common3.ApplicationComponent{{
+				Name:   "comp",
+				Type:   "webservice",
+				Traits: []common3.ApplicationTrait{{Type: "my-scaler", Properties: &runtime.RawExtension{Raw: []byte(props)}}},
+			}}},
+		}
+		assert.NoError(t, k8sClient.Create(context.Background(), app))
+	}
+	pinned := &v1beta1.Application{
+		ObjectMeta: v1.ObjectMeta{Name: "app-pinned", Namespace: "default"},
+		Spec: v1beta1.ApplicationSpec{Components: []common3.ApplicationComponent{{
+			Name:   "comp",
+			Type:   "webservice",
+			Traits: []common3.ApplicationTrait{{Type: "my-scaler@v1"}},
+		}}},
+	}
+	assert.NoError(t, k8sClient.Create(context.Background(), pinned))
+
+	cmd := NewDefinitionDiffCommand(c)
+	initCommand(cmd)
+	buffer := bytes.NewBuffer(nil)
+	cmd.SetOut(buffer)
+	cmd.SetArgs([]string{oldFile, newFile, "--scan-apps"})
+	assert.NoError(t, cmd.Execute())
+	out := buffer.String()
+	assert.Contains(t, out, "removed parameter `label`")
+	assert.Contains(t, out, "changed the default value of `replicas` from 1 to 2")
+	assert.Contains(t, out, "added required parameter `zone` (string)")
+	assert.Contains(t, out, "2 breaking changes, 1 compatible changes.")
+	assert.Contains(t, out, "2 of 3 usages of my-scaler in applications would fail to validate with the new definition.")
+	assert.NotContains(t, out, "app-0")
+	assert.Contains(t, out, "app-1")
+	assert.Contains(t, out, "app-2")
+
+	cmd.SetArgs([]string{oldFile, filepath.Join(dir, "not-exist.cue")})
+	assert.Error(t, cmd.Execute())
+}
@@ -23,10 +23,10 @@ import (
 	"os"
 	"path/filepath"
 
+	"github.com/kubevela/workflow/pkg/cue/packages"
 	"github.com/pkg/errors"
 	"github.com/spf13/cobra"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
-	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/yaml"
 
 	corev1beta1 "github.com/oam-dev/kubevela/apis/core.oam.dev/v1beta1"
@@ -46,6 +46,8 @@ type DryRunCmdOptions struct {
 	ApplicationFile string
 	DefinitionFile  string
 	OfflineMode     bool
+	Clusters        []string
+	Output          string
 }
 
 // NewDryRunCommand creates `dry-run` command
@@ -60,6 +62,11 @@ func NewDryRunCommand(c common.Args, ioStreams cmdutil.IOStreams) *cobra.Command
 
 You can also specify a remote url for app:
 	vela dry-run -d /definition/directory/or/file/ -f https://remote-host/app.yaml
+
+In offline mode, the workflow and the topology, override and replication policies of the application
+are evaluated against the clusters given by --cluster, and the rendered resources are grouped by
+workflow step and cluster:
+	vela dry-run --offline -d /definition/directory/ -f app.yaml --cluster beijing:region=north --cluster hangzhou:region=east
 `,
 		Example: "vela dry-run",
 		Annotations: map[string]string{
@@ -89,6 +96,8 @@ You can also specify a remote url for app:
 	cmd.Flags().StringVarP(&o.ApplicationFile, "file", "f", "./app.yaml", "application file name")
 	cmd.Flags().StringVarP(&o.DefinitionFile, "definition", "d", "", "specify a definition file or directory, it will only be used in dry-run rather than applied to K8s cluster")
 	cmd.Flags().BoolVar(&o.OfflineMode, "offline", false, "Run `dry-run` in offline / local mode, all validation steps will be skipped")
+	cmd.Flags().StringVarP(&o.Output, "output", "o", "", "Output the rendered objects with their origins in machine-readable format. One of: (json, yaml)")
+	cmd.Flags().StringArrayVar(&o.Clusters, "cluster", []string{}, "Specify the fake clusters used by topology policies in offline mode, in the format of name or name:key1=value1,key2=value2")
 	addNamespaceAndEnvArg(cmd)
 	cmd.SetOut(ioStreams.Out)
 	return cmd
@@ -99,41 +108,21 @@ func DryRunApplication(cmdOption *DryRunCmdOptions, c common.Args, namespace str
 	var err error
 	var buff = bytes.Buffer{}
 
-	objs := []oam.Object{}
-	if cmdOption.DefinitionFile != "" {
-		objs, err = ReadObjectsFromFile(cmdOption.DefinitionFile)
+	var clusters []dryrun.FakeCluster
+	for _, cluster := range cmdOption.Clusters {
+		fakeCluster, err := dryrun.ParseFakeCluster(cluster)
 		if err != nil {
 			return buff, err
 		}
+		clusters = append(clusters, fakeCluster)
 	}
-
-	// Load a kubernetes client
-	var newClient client.Client
-	if cmdOption.OfflineMode {
-		// We will load a fake client with all the objects present in the definitions file preloaded
-		newClient, err = c.GetFakeClient(objs)
-	} else {
-		// Load an actual client here
-		newClient, err = c.GetClient()
-	}
-	if err != nil {
-		return buff, err
-	}
-
-	pd, err := c.GetPackageDiscover()
-	if err != nil {
-		return buff, err
-	}
-	config, err := c.GetConfig()
-	if err != nil {
-		return buff, err
+	if len(clusters) > 0 && !cmdOption.OfflineMode {
+		return buff, errors.New("fake clusters can only be used in offline mode")
 	}
-	dm, err := discoverymapper.New(config)
+	dryRunOpt, err := newDryRunOption(cmdOption, c, clusters)
 	if err != nil {
 		return buff, err
 	}
-
-	dryRunOpt := dryrun.NewDryRunOption(newClient, config, dm, pd, objs, false)
 	ctx := oamutil.SetNamespaceInCtx(context.Background(), namespace)
 
 	// Perform validation only if not in offline mode
@@ -149,10 +138,30 @@ func DryRunApplication(cmdOption *DryRunCmdOptions, c common.Args, namespace str
 		return buff, errors.WithMessagef(err, "read application file: %s", cmdOption.ApplicationFile)
 	}
 
+	if cmdOption.OfflineMode && (len(clusters) > 0 || dryrun.RequireWorkflowDryRun(app)) {
+		if app.Namespace == "" {
+			app.Namespace = namespace
+		}
+		steps, policies, err := dryRunOpt.ExecuteWorkflowDryRun(ctx, app)
+		if err != nil {
+			return buff, errors.WithMessage(err, "generate OAM objects")
+		}
+		if cmdOption.Output != "" {
+			return printDryRunReport(cmdOption.Output, dryrun.NewWorkflowDryRunReport(app.Name, steps, policies))
+		}
+		if err = dryRunOpt.PrintWorkflowDryRun(&buff, app, steps, policies); err != nil {
+			return buff, err
+		}
+		return buff, nil
+	}
+
 	comps, policies, err := dryRunOpt.ExecuteDryRun(ctx, app)
 	if err != nil {
 		return buff, errors.WithMessage(err, "generate OAM objects")
 	}
+	if cmdOption.Output != "" {
+		return printDryRunReport(cmdOption.Output, dryrun.NewDryRunReport(app.Name, comps, policies))
+	}
 
 	if err = dryRunOpt.PrintDryRun(&buff, app.Name, comps, policies); err != nil {
 		return buff, err
@@ -160,6 +169,60 @@ func DryRunApplication(cmdOption *DryRunCmdOptions, c common.Args, namespace str
 	return buff, nil
 }
 
+func printDryRunReport(format string, report *dryrun.DryRunReport) (bytes.Buffer, error) {
+	var buff = bytes.Buffer{}
+	if format != "json" && format != "yaml" {
+		return buff, errors.Errorf("output format %s is not supported, only json and yaml are supported", format)
+	}
+	str, err := printObj(format, report)
+	if err != nil {
+		return buff, err
+	}
+	buff.WriteString(str)
+	return buff, nil
+}
+
+// newDryRunOption loads the definitions from file and creates the dry-run option, in offline mode
+// the definitions and fake clusters are preloaded into a fake client and no kubeconfig is required
+func newDryRunOption(cmdOption *DryRunCmdOptions, c common.Args, clusters []dryrun.FakeCluster) (*dryrun.Option, error) {
+	var err error
+	objs := []oam.Object{}
+	if cmdOption.DefinitionFile != "" {
+		objs, err = ReadObjectsFromFile(cmdOption.DefinitionFile)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if cmdOption.OfflineMode {
+		fakeObjs := append([]oam.Object{}, objs...)
+		for _, cluster := range clusters {
+			fakeObjs = append(fakeObjs, cluster.Secret())
+		}
+		newClient, err := c.GetFakeClient(fakeObjs)
+		if err != nil {
+			return nil, err
+		}
+		return dryrun.NewDryRunOption(newClient, nil, nil, &packages.PackageDiscover{}, objs, false), nil
+	}
+	newClient, err := c.GetClient()
+	if err != nil {
+		return nil, err
+	}
+	pd, err := c.GetPackageDiscover()
+	if err != nil {
+		return nil, err
+	}
+	config, err := c.GetConfig()
+	if err != nil {
+		return nil, err
+	}
+	dm, err := discoverymapper.New(config)
+	if err != nil {
+		return nil, err
+	}
+	return dryrun.NewDryRunOption(newClient, config, dm, pd, objs, false), nil
+}
+
 // ReadObjectsFromFile will read objects from file or dir in the format of yaml
 func ReadObjectsFromFile(path string) ([]oam.Object, error) {
 	fi, err := os.Stat(path)
@@ -37,6 +37,7 @@ import (
 	"github.com/oam-dev/kubevela/pkg/utils/util"
 	querytypes "github.com/oam-dev/kubevela/pkg/velaql/providers/query/types"
 	"github.com/oam-dev/kubevela/references/appfile"
+	"github.com/oam-dev/kubevela/references/cli/top/config"
 )
 
 const (
@@ -191,16 +192,20 @@ func (o *VelaExecOptions) Init(ctx context.Context, c *cobra.Command, argsIn []s
 		return nil
 	}
 
+	return o.initPod(ctx, c, selectPod.Cluster, selectPod.Metadata.Namespace, selectPod.Metadata.Name)
+}
+
+// initPod prepares the factory and the client set to exec into the pod in the cluster
+func (o *VelaExecOptions) initPod(ctx context.Context, c *cobra.Command, cluster, namespace, pod string) error {
 	cf := genericclioptions.NewConfigFlags(true)
-	var namespace = selectPod.Metadata.Namespace
 	cf.Namespace = &namespace
 	cf.WrapConfigFn = func(cfg *rest.Config) *rest.Config {
-		cfg.Wrap(pkgmulticluster.NewTransportWrapper(pkgmulticluster.ForCluster(selectPod.Cluster)))
+		cfg.Wrap(pkgmulticluster.NewTransportWrapper(pkgmulticluster.ForCluster(cluster)))
 		return cfg
 	}
 	o.f = k8scmdutil.NewFactory(k8scmdutil.NewMatchVersionFlags(cf))
-	o.podName = selectPod.Metadata.Name
-	o.Ctx = multicluster.ContextWithClusterName(ctx, selectPod.Cluster)
+	o.podName = pod
+	o.Ctx = multicluster.ContextWithClusterName(ctx, cluster)
 	o.podNamespace = namespace
 	config, err := o.VelaC.GetConfig()
 	if err != nil {
@@ -219,6 +224,32 @@ func (o *VelaExecOptions) Init(ctx context.Context, c *cobra.Command, argsIn []s
 	return nil
 }
 
+// newContainerExecutor return the executor for vela top to launch the command in the container with the
+// terminal attached, it runs the same exec options as the exec command
+func newContainerExecutor(c common.Args) config.ContainerExecutor {
+	return func(ctx context.Context, cluster, namespace, pod, container string, command []string) error {
+		cmd := &cobra.Command{}
+		cmd.Flags().Duration(podRunningTimeoutFlag, defaultPodExecTimeout, "")
+		o := &VelaExecOptions{
+			kcExecOptions: &cmdexec.ExecOptions{Executor: &cmdexec.DefaultRemoteExecutor{}},
+			Args:          append([]string{pod}, command...),
+			Stdin:         defaultStdin,
+			TTY:           defaultTTY,
+			ClusterName:   cluster,
+			ContainerName: container,
+			VelaC:         c,
+			Cmd:           cmd,
+		}
+		if err := o.initPod(ctx, cmd, cluster, namespace, pod); err != nil {
+			return err
+		}
+		if err := o.Complete(); err != nil {
+			return err
+		}
+		return o.Run()
+	}
+}
+
 // Complete loads data from the command environment
 func (o *VelaExecOptions) Complete() error {
 	o.kcExecOptions.StreamOptions.Stdin = o.Stdin
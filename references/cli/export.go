@@ -17,9 +17,17 @@ limitations under the License.
 package cli
 
 import (
+	"context"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/pkg/errors"
 	"github.com/spf13/cobra"
 
 	"github.com/oam-dev/kubevela/apis/types"
+	"github.com/oam-dev/kubevela/pkg/appfile/dryrun"
+	oamutil "github.com/oam-dev/kubevela/pkg/oam/util"
 	common2 "github.com/oam-dev/kubevela/pkg/utils/common"
 	cmdutil "github.com/oam-dev/kubevela/pkg/utils/util"
 	"github.com/oam-dev/kubevela/references/common"
@@ -28,11 +36,20 @@ import (
 // NewExportCommand will create command for exporting deploy manifests from an AppFile
 func NewExportCommand(c common2.Args, ioStream cmdutil.IOStreams) *cobra.Command {
 	appFilePath := new(string)
+	o := &exportOptions{DryRunCmdOptions: DryRunCmdOptions{IOStreams: ioStream}}
 	cmd := &cobra.Command{
 		Use:                   "export",
 		DisableFlagsInUseLine: true,
 		Short:                 "Export deploy manifests from appfile",
-		Long:                  "Export deploy manifests from appfile or application.",
+		Long: "Export deploy manifests from appfile or application.\n\n" +
+			"With --target, the rendered resources of an application are packaged into a Helm chart or a Kustomize base, " +
+			"so that they can be deployed without KubeVela. For Helm chart, the properties of components are lifted into values.yaml " +
+			"and a values file is generated for each deploy step and each of its placements with the override policies applied. " +
+			"For Kustomize, an overlay is generated for each deploy step and each of its placements with the override policies applied.",
+		Example: "# export the application as a Helm chart\n" +
+			"> vela export -f app.yaml --target helm -o ./chart\n" +
+			"# export the application as a Kustomize base and overlays with local definitions\n" +
+			"> vela export -f app.yaml --target kustomize -o ./deploy -d ./definitions --offline",
 		Annotations: map[string]string{
 			types.TagCommandType: types.TypeSystem,
 		},
@@ -41,10 +58,14 @@ func NewExportCommand(c common2.Args, ioStream cmdutil.IOStreams) *cobra.Command
 			if err != nil {
 				return err
 			}
-			o := &common.AppfileOptions{
+			if o.Target != "" {
+				o.ApplicationFile = *appFilePath
+				return o.exportApplication(cmd, c, namespace)
+			}
+			ao := &common.AppfileOptions{
 				IO: ioStream,
 			}
-			_, data, err := o.Export(*appFilePath, namespace, true, c)
+			_, data, err := ao.Export(*appFilePath, namespace, true, c)
 			if err != nil {
 				return err
 			}
@@ -55,6 +76,60 @@ func NewExportCommand(c common2.Args, ioStream cmdutil.IOStreams) *cobra.Command
 	cmd.SetOut(ioStream.Out)
 
 	addNamespaceAndEnvArg(cmd)
-	cmd.Flags().StringVarP(appFilePath, "file", "f", "", "specify file path for appfile")
+	cmd.Flags().StringVarP(appFilePath, "file", "f", "", "specify file path for appfile, or application file if target is set")
+	cmd.Flags().StringVar(&o.Target, "target", "", "specify the target to export the rendered resources of the application, one of: (helm, kustomize)")
+	cmd.Flags().StringVarP(&o.OutputDir, "output", "o", "", "specify the directory to write the exported files, required if target is set")
+	cmd.Flags().StringVarP(&o.DefinitionFile, "definition", "d", "", "specify a definition file or directory, it will only be used in rendering rather than applied to K8s cluster")
+	cmd.Flags().BoolVar(&o.OfflineMode, "offline", false, "render the application with local definitions only, no K8s cluster is required")
 	return cmd
 }
+
+type exportOptions struct {
+	DryRunCmdOptions
+	Target    string
+	OutputDir string
+}
+
+func (o *exportOptions) exportApplication(cmd *cobra.Command, c common2.Args, namespace string) error {
+	if o.ApplicationFile == "" {
+		return errors.New("application file must be set by --file if target is set")
+	}
+	if o.OutputDir == "" {
+		return errors.New("output directory must be set by --output if target is set")
+	}
+	app, err := readApplicationFromFile(o.ApplicationFile)
+	if err != nil {
+		return errors.WithMessagef(err, "read application file: %s", o.ApplicationFile)
+	}
+	if app.Namespace == "" {
+		if namespace == "" {
+			namespace = types.DefaultAppNamespace
+		}
+		app.Namespace = namespace
+	}
+	dryRunOpt, err := newDryRunOption(&o.DryRunCmdOptions, c, nil)
+	if err != nil {
+		return err
+	}
+	ctx := oamutil.SetNamespaceInCtx(context.Background(), app.Namespace)
+	files, err := dryRunOpt.ExportApplication(ctx, app, dryrun.ExportTarget(o.Target))
+	if err != nil {
+		return err
+	}
+	names := make([]string, 0, len(files))
+	for name := range files {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		filename := filepath.Join(o.OutputDir, filepath.FromSlash(name))
+		if err = os.MkdirAll(filepath.Dir(filename), 0750); err != nil {
+			return err
+		}
+		if err = os.WriteFile(filename, files[name], 0600); err != nil {
+			return errors.Wrapf(err, "failed to write file %s", filename)
+		}
+	}
+	cmd.Printf("Application %s has been exported to %s as %s.\n", app.Name, o.OutputDir, o.Target)
+	return nil
+}
@@ -43,6 +43,10 @@ type LiveDiffCmdOptions struct {
 	Revision          string
 	SecondaryRevision string
 	Context           int
+	Output            string
+	ExitCode          bool
+
+	hasChanges bool
 }
 
 // NewLiveDiffCommand creates `live-diff` command
@@ -63,7 +67,9 @@ func NewLiveDiffCommand(c common.Args, order string, ioStreams cmdutil.IOStreams
 			"# compare two application revisions\n" +
 			"> vela live-diff --revision my-app-v1,my-app-v2\n" +
 			"# compare the application file and the specified revision\n" +
-			"> vela live-diff -f my-app.yaml -r my-app-v1 --context 10",
+			"> vela live-diff -f my-app.yaml -r my-app-v1 --context 10\n" +
+			"# output the diff in json and exit with code 2 if there are changes\n" +
+			"> vela live-diff -f my-app.yaml -o json --exit-code",
 		Annotations: map[string]string{
 			types.TagCommandOrder: order,
 			types.TagCommandType:  types.TypeApp,
@@ -82,6 +88,11 @@ func NewLiveDiffCommand(c common.Args, order string, ioStreams cmdutil.IOStreams
 				return err
 			}
 			cmd.Println(buff.String())
+			if o.ExitCode && o.hasChanges {
+				// the diff is printed already, only the exit code is left
+				cmd.SilenceErrors = true
+				return &ExitCodeError{Code: 2, Reason: "differences found"}
+			}
 			return nil
 		},
 	}
@@ -90,6 +101,8 @@ func NewLiveDiffCommand(c common.Args, order string, ioStreams cmdutil.IOStreams
 	cmd.Flags().StringVarP(&o.DefinitionFile, "definition", "d", "", "specify a file or directory containing capability definitions, they will only be used in dry-run rather than applied to K8s cluster")
 	cmd.Flags().StringVarP(&o.Revision, "revision", "r", "", "specify one or two application revision name(s), by default, it will compare with the latest revision")
 	cmd.Flags().IntVarP(&o.Context, "context", "c", -1, "output number lines of context around changes, by default show all unchanged lines")
+	cmd.Flags().StringVarP(&o.Output, "output", "o", "", "Output the diff in machine-readable format. One of: (json, yaml)")
+	cmd.Flags().BoolVar(&o.ExitCode, "exit-code", false, "Exit with code 2 if there are changes, 1 on errors and 0 otherwise")
 	addNamespaceAndEnvArg(cmd)
 	return cmd
 }
@@ -166,10 +179,21 @@ func LiveDiffApplication(cmdOption *LiveDiffCmdOptions, c common.Args) (bytes.Bu
 		return buff, errors.WithMessage(err, "cannot calculate diff")
 	}
 
-	reportDiffOpt := dryrun.NewReportDiffOption(cmdOption.Context, &buff)
-	reportDiffOpt.PrintDiffReport(diffResult)
+	return buff, cmdOption.printDiff(&buff, diffResult)
+}
 
-	return buff, nil
+func (o *LiveDiffCmdOptions) printDiff(buff *bytes.Buffer, diff *dryrun.DiffEntry) error {
+	o.hasChanges = diff.HasChanges()
+	if o.Output == "" {
+		dryrun.NewReportDiffOption(o.Context, buff).PrintDiffReport(diff)
+		return nil
+	}
+	str, err := printObj(o.Output, dryrun.NewDiffReport(diff, o.Context))
+	if err != nil {
+		return err
+	}
+	buff.WriteString(str)
+	return nil
 }
 
 func (o *LiveDiffCmdOptions) loadAndValidate(args []string) error {
@@ -196,6 +220,9 @@ func (o *LiveDiffCmdOptions) loadAndValidate(args []string) error {
 	if o.SecondaryRevision != "" && o.ApplicationFile != "" {
 		return errors.Errorf("cannot use application file and two revisions at the same time")
 	}
+	if o.Output != "" && o.Output != "json" && o.Output != "yaml" {
+		return errors.Errorf("output format %s is not supported, only json and yaml are supported", o.Output)
+	}
 	return nil
 }
 
@@ -233,7 +260,5 @@ func (o *LiveDiffCmdOptions) renderlessDiff(cli client.Client, option *dryrun.Li
 	if err != nil {
 		return buf, errors.WithMessage(err, "cannot calculate diff")
 	}
-	reportDiffOpt := dryrun.NewReportDiffOption(o.Context, &buf)
-	reportDiffOpt.PrintDiffReport(diffResult)
-	return buf, nil
+	return buf, o.printDiff(&buf, diffResult)
 }
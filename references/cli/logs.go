@@ -40,8 +40,19 @@ func NewLogsCommand(c common.Args, order string, ioStreams util.IOStreams) *cobr
 	cmd := &cobra.Command{
 		Use:   "logs APP_NAME",
 		Short: "Tail logs for application.",
-		Long:  "Tail logs for vela application.",
-		Args:  cobra.ExactArgs(1),
+		Long:  "Tail logs for vela application. Use --all to tail the logs of all the pods across the clusters concurrently.",
+		Example: `  # Select one pod of the application to tail its logs
+  vela logs first-vela-app
+
+  # Tail the logs of all the pods of the components express-server and worker in all clusters
+  vela logs first-vela-app --all -c express-server,worker
+
+  # Show the error logs in the last hour and extract the fields of the json logs
+  vela logs first-vela-app --all --include error --since 1h --fields level,msg
+
+  # Write the logs of each pod to files in the logs directory
+  vela logs first-vela-app --all --output-dir ./logs`,
+		Args: cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
 			var err error
 			largs.Namespace, err = GetFlagNamespaceOrEnv(cmd, c)
@@ -55,6 +66,12 @@ func NewLogsCommand(c common.Args, order string, ioStreams util.IOStreams) *cobr
 				return err
 			}
 			largs.App = app
+			if largs.Aggregate {
+				return largs.RunAggregated(ctx, ioStreams)
+			}
+			if len(largs.Include) > 0 || len(largs.Exclude) > 0 || largs.Since != "" || largs.Until != "" || len(largs.Fields) > 0 || largs.OutputDir != "" {
+				return fmt.Errorf("the filtering and output-dir flags must be used with --all")
+			}
 			if err := largs.Run(ctx, ioStreams); err != nil {
 				return err
 			}
@@ -67,10 +84,17 @@ func NewLogsCommand(c common.Args, order string, ioStreams util.IOStreams) *cobr
 	}
 
 	cmd.Flags().StringVarP(&largs.Output, "output", "o", "default", "output format for logs, support: [default, raw, json]")
-	cmd.Flags().StringVarP(&largs.ComponentName, "component", "c", "", "filter the pod by the component name")
+	cmd.Flags().StringVarP(&largs.ComponentName, "component", "c", "", "filter the pod by the component name, multiple components separated by comma are allowed with --all")
 	cmd.Flags().StringVarP(&largs.ClusterName, "cluster", "", "", "filter the pod by the cluster name")
 	cmd.Flags().StringVarP(&largs.PodName, "pod", "p", "", "specify the pod name")
 	cmd.Flags().StringVarP(&largs.ContainerName, "container", "", "", "specify the container name")
+	cmd.Flags().BoolVarP(&largs.Aggregate, "all", "a", false, "tail the logs of all the pods of the selected components across all the clusters concurrently")
+	cmd.Flags().StringSliceVarP(&largs.Include, "include", "", nil, "only show the log lines matching the regular expressions, must be used with --all")
+	cmd.Flags().StringSliceVarP(&largs.Exclude, "exclude", "", nil, "hide the log lines matching the regular expressions, must be used with --all")
+	cmd.Flags().StringVarP(&largs.Since, "since", "", "", "only show the logs after the time, can be a RFC3339 time or a duration like 1h, must be used with --all")
+	cmd.Flags().StringVarP(&largs.Until, "until", "", "", "only show the logs before the time, can be a RFC3339 time or a duration like 10m, must be used with --all")
+	cmd.Flags().StringSliceVarP(&largs.Fields, "fields", "", nil, "extract the fields from the json logs, nested fields are separated by dots, must be used with --all")
+	cmd.Flags().StringVarP(&largs.OutputDir, "output-dir", "", "", "write the logs of each pod to a file in the directory, must be used with --all")
 	addNamespaceAndEnvArg(cmd)
 	return cmd
 }
@@ -88,6 +112,15 @@ type Args struct {
 	ComponentName string
 	StepName      string
 	App           *v1beta1.Application
+
+	// the options of the aggregated mode
+	Aggregate bool
+	Include   []string
+	Exclude   []string
+	Since     string
+	Until     string
+	Fields    []string
+	OutputDir string
 }
 
 func (l *Args) printPodLogs(ctx context.Context, ioStreams util.IOStreams, selectPod *querytypes.PodBase, filters []string) error {
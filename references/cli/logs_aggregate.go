@@ -0,0 +1,390 @@
+/*
+Copyright 2022 The KubeVela Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cli
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"hash/fnv"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/fatih/color"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+
+	pkgmulticluster "github.com/kubevela/pkg/multicluster"
+
+	"github.com/oam-dev/kubevela/pkg/multicluster"
+	"github.com/oam-dev/kubevela/pkg/utils"
+	"github.com/oam-dev/kubevela/pkg/utils/util"
+	querytypes "github.com/oam-dev/kubevela/pkg/velaql/providers/query/types"
+)
+
+var logPrefixColors = []*color.Color{
+	color.New(color.FgHiCyan),
+	color.New(color.FgHiGreen),
+	color.New(color.FgHiMagenta),
+	color.New(color.FgHiYellow),
+	color.New(color.FgHiBlue),
+	color.New(color.FgHiRed),
+}
+
+// logTarget is a container whose logs are aggregated
+type logTarget struct {
+	Cluster   string `json:"cluster"`
+	Component string `json:"component"`
+	Namespace string `json:"namespace"`
+	Pod       string `json:"pod"`
+	Container string `json:"container"`
+}
+
+func (t logTarget) prefix() string {
+	return fmt.Sprintf("%s/%s/%s/%s", t.Cluster, t.Component, t.Pod, t.Container)
+}
+
+func (t logTarget) fileName() string {
+	return fmt.Sprintf("%s_%s_%s.log", t.Cluster, t.Namespace, t.Pod)
+}
+
+// logLine is one line of the aggregated logs
+type logLine struct {
+	logTarget
+	Timestamp time.Time `json:"timestamp,omitempty"`
+	Message   string    `json:"message"`
+}
+
+// logFilter filters the log lines and extracts the fields of the json logs
+type logFilter struct {
+	include []*regexp.Regexp
+	exclude []*regexp.Regexp
+	since   time.Time
+	until   time.Time
+	fields  []string
+}
+
+func newLogFilter(include, exclude []string, since, until string, fields []string, now time.Time) (*logFilter, error) {
+	f := &logFilter{fields: fields}
+	for _, expr := range include {
+		r, err := regexp.Compile(expr)
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid include expression %s", expr)
+		}
+		f.include = append(f.include, r)
+	}
+	for _, expr := range exclude {
+		r, err := regexp.Compile(expr)
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid exclude expression %s", expr)
+		}
+		f.exclude = append(f.exclude, r)
+	}
+	var err error
+	if f.since, err = parseLogTime(since, now); err != nil {
+		return nil, errors.WithMessage(err, "invalid since")
+	}
+	if f.until, err = parseLogTime(until, now); err != nil {
+		return nil, errors.WithMessage(err, "invalid until")
+	}
+	if !f.since.IsZero() && !f.until.IsZero() && !f.until.After(f.since) {
+		return nil, errors.New("until must be later than since")
+	}
+	return f, nil
+}
+
+// parseLogTime parses the time in RFC3339 format or the duration before now, like 10m
+func parseLogTime(value string, now time.Time) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+	if d, err := time.ParseDuration(value); err == nil {
+		return now.Add(-d), nil
+	}
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return time.Time{}, errors.Errorf("%s is neither a duration nor a RFC3339 time", value)
+	}
+	return t, nil
+}
+
+// follow reports whether the logs should be followed, the logs are not followed if until is in the past
+func (f *logFilter) follow(now time.Time) bool {
+	return f.until.IsZero() || f.until.After(now)
+}
+
+func (f *logFilter) match(message string) bool {
+	for _, r := range f.exclude {
+		if r.MatchString(message) {
+			return false
+		}
+	}
+	if len(f.include) == 0 {
+		return true
+	}
+	for _, r := range f.include {
+		if r.MatchString(message) {
+			return true
+		}
+	}
+	return false
+}
+
+// extract returns the selected fields of the json log as key=value pairs. The message is
+// returned as it is if it is not a json object or none of the fields exists.
+func (f *logFilter) extract(message string) string {
+	if len(f.fields) == 0 {
+		return message
+	}
+	obj := map[string]interface{}{}
+	if err := json.Unmarshal([]byte(message), &obj); err != nil {
+		return message
+	}
+	var pairs []string
+	for _, field := range f.fields {
+		var val interface{} = obj
+		for _, key := range strings.Split(field, ".") {
+			m, ok := val.(map[string]interface{})
+			if !ok {
+				val = nil
+				break
+			}
+			val = m[key]
+		}
+		if val == nil {
+			continue
+		}
+		if s, ok := val.(string); ok {
+			pairs = append(pairs, fmt.Sprintf("%s=%s", field, s))
+			continue
+		}
+		bs, _ := json.Marshal(val)
+		pairs = append(pairs, fmt.Sprintf("%s=%s", field, string(bs)))
+	}
+	if len(pairs) == 0 {
+		return message
+	}
+	return strings.Join(pairs, " ")
+}
+
+// scan reads the log stream with timestamps of the target, and sends the matched lines. It returns
+// when the stream ends or a line later than until is met.
+func (f *logFilter) scan(ctx context.Context, r io.Reader, target logTarget, lines chan<- logLine) error {
+	reader := bufio.NewReader(r)
+	for {
+		bs, err := reader.ReadBytes('\n')
+		if len(bs) > 0 {
+			line := logLine{logTarget: target, Message: strings.TrimRight(string(bs), "\r\n")}
+			if i := strings.IndexByte(line.Message, ' '); i > 0 {
+				if ts, err := time.Parse(time.RFC3339Nano, line.Message[:i]); err == nil {
+					line.Timestamp, line.Message = ts, line.Message[i+1:]
+				}
+			}
+			if !f.until.IsZero() && line.Timestamp.After(f.until) {
+				return nil
+			}
+			if f.match(line.Message) {
+				line.Message = f.extract(line.Message)
+				select {
+				case lines <- line:
+				case <-ctx.Done():
+					return nil
+				}
+			}
+		}
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+	}
+}
+
+// logWriter writes the aggregated logs to the output or the files of pods
+type logWriter struct {
+	ioStreams util.IOStreams
+	output    string
+	dir       string
+	files     map[string]*os.File
+}
+
+func (w *logWriter) write(line logLine) error {
+	if w.dir != "" {
+		name := line.fileName()
+		file, ok := w.files[name]
+		if !ok {
+			var err error
+			if file, err = os.Create(filepath.Clean(filepath.Join(w.dir, name))); err != nil {
+				return errors.Wrapf(err, "failed to create log file for pod %s", line.Pod)
+			}
+			w.files[name] = file
+			w.ioStreams.Infof("Writing logs of pod %s in cluster %s to %s\n", line.Pod, line.Cluster, file.Name())
+		}
+		_, err := fmt.Fprintf(file, "%s [%s] %s\n", line.Timestamp.Format(time.RFC3339Nano), line.Container, line.Message)
+		return err
+	}
+	switch w.output {
+	case "raw":
+		w.ioStreams.Info(line.Message)
+	case "json":
+		bs, err := json.Marshal(line)
+		if err != nil {
+			return err
+		}
+		w.ioStreams.Info(string(bs))
+	default:
+		prefix := line.prefix()
+		if !color.NoColor {
+			h := fnv.New32a()
+			_, _ = h.Write([]byte(line.Cluster + "/" + line.Pod))
+			prefix = logPrefixColors[h.Sum32()%uint32(len(logPrefixColors))].Sprint(prefix)
+		}
+		w.ioStreams.Infof("%s %s\n", prefix, line.Message)
+	}
+	return nil
+}
+
+func (w *logWriter) close() {
+	for _, file := range w.files {
+		_ = file.Close()
+	}
+}
+
+// RunAggregated tails the logs of all the pods of the selected components across all the clusters concurrently
+func (l *Args) RunAggregated(ctx context.Context, ioStreams util.IOStreams) error {
+	pods, err := GetApplicationPods(ctx, l.App.Name, l.App.Namespace, l.Args, Filter{})
+	if err != nil {
+		return err
+	}
+	config, err := l.Args.GetConfig()
+	if err != nil {
+		return err
+	}
+	config = rest.CopyConfig(config)
+	config.Wrap(pkgmulticluster.NewTransportWrapper())
+	clientSet, err := kubernetes.NewForConfig(config)
+	if err != nil {
+		return err
+	}
+	return l.aggregateLogs(ctx, ioStreams, clientSet, pods)
+}
+
+func (l *Args) aggregateLogs(ctx context.Context, ioStreams util.IOStreams, clientSet kubernetes.Interface, pods []querytypes.PodBase) error {
+	now := time.Now()
+	filter, err := newLogFilter(l.Include, l.Exclude, l.Since, l.Until, l.Fields, now)
+	if err != nil {
+		return err
+	}
+	if l.OutputDir != "" {
+		if err = os.MkdirAll(l.OutputDir, 0750); err != nil {
+			return errors.Wrapf(err, "failed to create directory %s", l.OutputDir)
+		}
+	}
+	targets, err := l.logTargets(ctx, clientSet, pods)
+	if err != nil {
+		return err
+	}
+	if len(targets) == 0 {
+		return errors.Errorf("no pod found in application %s", l.App.Name)
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	lines := make(chan logLine, 1024)
+	wg := sync.WaitGroup{}
+	for _, target := range targets {
+		wg.Add(1)
+		go func(target logTarget) {
+			defer wg.Done()
+			opts := &corev1.PodLogOptions{Container: target.Container, Follow: filter.follow(now), Timestamps: true}
+			if !filter.since.IsZero() {
+				opts.SinceTime = &metav1.Time{Time: filter.since}
+			}
+			clusterCtx := multicluster.ContextWithClusterName(ctx, target.Cluster)
+			stream, err := clientSet.CoreV1().Pods(target.Namespace).GetLogs(target.Pod, opts).Stream(clusterCtx)
+			if err != nil {
+				ioStreams.Errorf("failed to open the log stream of %s: %s\n", target.prefix(), err.Error())
+				return
+			}
+			defer func() { _ = stream.Close() }()
+			if err = filter.scan(ctx, stream, target, lines); err != nil && ctx.Err() == nil {
+				ioStreams.Errorf("failed to read the log stream of %s: %s\n", target.prefix(), err.Error())
+			}
+		}(target)
+	}
+	go func() {
+		wg.Wait()
+		close(lines)
+	}()
+
+	w := &logWriter{ioStreams: ioStreams, output: l.Output, dir: l.OutputDir, files: map[string]*os.File{}}
+	defer w.close()
+	for line := range lines {
+		if err = w.write(line); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// logTargets lists the containers of the pods in the selected components and cluster
+func (l *Args) logTargets(ctx context.Context, clientSet kubernetes.Interface, pods []querytypes.PodBase) ([]logTarget, error) {
+	var components []string
+	if l.ComponentName != "" {
+		components = strings.Split(l.ComponentName, ",")
+	}
+	var targets []logTarget
+	for _, pod := range pods {
+		cluster := pod.Cluster
+		if cluster == "" {
+			cluster = multicluster.ClusterLocalName
+		}
+		if l.ClusterName != "" && l.ClusterName != cluster {
+			continue
+		}
+		if len(components) > 0 && !utils.StringsContain(components, pod.Component) {
+			continue
+		}
+		obj, err := clientSet.CoreV1().Pods(pod.Metadata.Namespace).Get(multicluster.ContextWithClusterName(ctx, cluster), pod.Metadata.Name, metav1.GetOptions{})
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to get pod %s in cluster %s", pod.Metadata.Name, cluster)
+		}
+		for _, container := range obj.Spec.Containers {
+			if l.ContainerName != "" && l.ContainerName != container.Name {
+				continue
+			}
+			targets = append(targets, logTarget{
+				Cluster:   cluster,
+				Component: pod.Component,
+				Namespace: obj.Namespace,
+				Pod:       obj.Name,
+				Container: container.Name,
+			})
+		}
+	}
+	return targets, nil
+}
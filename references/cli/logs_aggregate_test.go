@@ -0,0 +1,123 @@
+/*
+Copyright 2022 The KubeVela Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cli
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/fatih/color"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"github.com/oam-dev/kubevela/apis/core.oam.dev/v1beta1"
+	"github.com/oam-dev/kubevela/pkg/utils/util"
+	querytypes "github.com/oam-dev/kubevela/pkg/velaql/providers/query/types"
+)
+
+func TestLogFilter(t *testing.T) {
+	r := require.New(t)
+	now := time.Date(2022, 10, 1, 12, 0, 0, 0, time.UTC)
+	_, err := newLogFilter([]string{"("}, nil, "", "", nil, now)
+	r.Error(err)
+	_, err = newLogFilter(nil, nil, "yesterday", "", nil, now)
+	r.Error(err)
+	_, err = newLogFilter(nil, nil, "1h", "2h", nil, now)
+	r.Error(err)
+
+	f, err := newLogFilter([]string{"error", "warn"}, []string{"health"}, "1h", "2022-10-01T11:30:00Z", []string{"level", "msg", "req.id"}, now)
+	r.NoError(err)
+	r.Equal(now.Add(-time.Hour), f.since)
+	r.False(f.follow(now))
+	r.True(f.match("an error occurs"))
+	r.False(f.match("error in health check"))
+	r.False(f.match("info"))
+	r.Equal(`level=error msg=failed req.id=3`, f.extract(`{"level":"error","msg":"failed","req":{"id":3}}`))
+	r.Equal("plain error", f.extract("plain error"))
+	r.Equal(`{"other":1}`, f.extract(`{"other":1}`))
+
+	logs := strings.Join([]string{
+		"2022-10-01T11:00:00.000000001Z warn: slow request",
+		"2022-10-01T11:10:00Z info: ok",
+		"2022-10-01T11:20:00Z error: failed",
+		"2022-10-01T11:40:00Z error: after until",
+		"",
+	}, "\n")
+	lines := make(chan logLine, 10)
+	r.NoError(f.scan(context.Background(), strings.NewReader(logs), logTarget{Pod: "web"}, lines))
+	close(lines)
+	var messages []string
+	for line := range lines {
+		r.Equal("web", line.Pod)
+		messages = append(messages, line.Timestamp.Format(time.RFC3339)+" "+line.Message)
+	}
+	r.Equal([]string{"2022-10-01T11:00:00Z warn: slow request", "2022-10-01T11:20:00Z error: failed"}, messages)
+}
+
+func TestAggregateLogs(t *testing.T) {
+	r := require.New(t)
+	noColor := color.NoColor
+	color.NoColor = true
+	defer func() { color.NoColor = noColor }()
+	newPod := func(name string, containers ...string) *corev1.Pod {
+		pod := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default"}}
+		for _, c := range containers {
+			pod.Spec.Containers = append(pod.Spec.Containers, corev1.Container{Name: c})
+		}
+		return pod
+	}
+	newPodBase := func(name, component string) querytypes.PodBase {
+		pod := querytypes.PodBase{Component: component}
+		pod.Metadata.Name = name
+		pod.Metadata.Namespace = "default"
+		return pod
+	}
+	clientSet := fake.NewSimpleClientset(newPod("web-1", "main", "sidecar"), newPod("worker-1", "main"))
+	pods := []querytypes.PodBase{newPodBase("web-1", "web"), newPodBase("worker-1", "worker")}
+	out := &bytes.Buffer{}
+	ioStreams := util.IOStreams{Out: out, ErrOut: out}
+	l := &Args{App: &v1beta1.Application{ObjectMeta: metav1.ObjectMeta{Name: "app"}}, Until: "1s"}
+
+	// the fake clientset always returns "fake logs" as the logs
+	r.NoError(l.aggregateLogs(context.Background(), ioStreams, clientSet, pods))
+	r.Contains(out.String(), "local/web/web-1/main fake logs")
+	r.Contains(out.String(), "local/web/web-1/sidecar fake logs")
+	r.Contains(out.String(), "local/worker/worker-1/main fake logs")
+
+	out.Reset()
+	l.ComponentName, l.ContainerName, l.Output = "web", "sidecar", "json"
+	r.NoError(l.aggregateLogs(context.Background(), ioStreams, clientSet, pods))
+	r.Equal(`{"cluster":"local","component":"web","namespace":"default","pod":"web-1","container":"sidecar","timestamp":"0001-01-01T00:00:00Z","message":"fake logs"}`+"\n", out.String())
+
+	out.Reset()
+	l.ContainerName, l.OutputDir = "", t.TempDir()
+	r.NoError(l.aggregateLogs(context.Background(), ioStreams, clientSet, pods))
+	bs, err := os.ReadFile(filepath.Join(l.OutputDir, "local_default_web-1.log"))
+	r.NoError(err)
+	r.Contains(string(bs), "[main] fake logs")
+	r.Contains(string(bs), "[sidecar] fake logs")
+
+	l.ClusterName = "remote"
+	r.Error(l.aggregateLogs(context.Background(), ioStreams, clientSet, pods))
+}
@@ -0,0 +1,276 @@
+/*
+Copyright 2022 The KubeVela Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cli
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	commontypes "github.com/oam-dev/kubevela/apis/core.oam.dev/common"
+	"github.com/oam-dev/kubevela/apis/core.oam.dev/v1beta1"
+	"github.com/oam-dev/kubevela/apis/types"
+	"github.com/oam-dev/kubevela/pkg/appfile/dryrun"
+	"github.com/oam-dev/kubevela/pkg/controller/core.oam.dev/v1alpha2/core"
+	"github.com/oam-dev/kubevela/pkg/definition"
+	"github.com/oam-dev/kubevela/pkg/oam"
+	"github.com/oam-dev/kubevela/pkg/oam/util"
+	"github.com/oam-dev/kubevela/pkg/utils/common"
+	cmdutil "github.com/oam-dev/kubevela/pkg/utils/util"
+)
+
+// RollbackOptions the options for rolling back an application to a previous revision
+type RollbackOptions struct {
+	cmdutil.IOStreams
+	AppName        string
+	Namespace      string
+	Revision       string
+	Reason         string
+	PublishVersion string
+	Context        int
+}
+
+// NewRollbackCommand creates the `rollback` command
+func NewRollbackCommand(c common.Args, ioStreams cmdutil.IOStreams) *cobra.Command {
+	o := &RollbackOptions{IOStreams: ioStreams}
+	cmd := &cobra.Command{
+		Use:   "rollback APP_NAME",
+		Short: "Rollback an application to a previous revision.",
+		Long: "Rollback an application to a previous revision. The application spec will be restored from the given " +
+			"ApplicationRevision, and the component and trait types will be pinned to the recorded definition revisions " +
+			"if the definitions have changed since then. The differences against the latest revision are shown before rollback.",
+		Example: `  # Rollback the application to the revision first-vela-app-v2
+  vela rollback first-vela-app --to first-vela-app-v2
+
+  # Rollback the application to the revision v2 and record the reason
+  vela rollback first-vela-app --to v2 --reason "bad image in v3"
+
+  # Rollback without confirmation, with a specified publish version
+  vela rollback first-vela-app --to 2 --publish-version rollback-v2 -y`,
+		Args: cobra.ExactArgs(1),
+		Annotations: map[string]string{
+			types.TagCommandType: types.TypeApp,
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			namespace, err := GetFlagNamespaceOrEnv(cmd, c)
+			if err != nil {
+				return err
+			}
+			o.AppName = args[0]
+			o.Namespace = namespace
+			if o.Revision == "" {
+				return errors.New("please specify the revision to rollback to with --to")
+			}
+			return o.Run(context.Background(), c)
+		},
+	}
+	cmd.Flags().StringVarP(&o.Revision, "to", "", "", "the application revision to rollback to, can be the revision name or the revision number like v2")
+	cmd.Flags().StringVarP(&o.Reason, "reason", "", "", "the reason of the rollback, recorded in the annotations of the application")
+	cmd.Flags().StringVarP(&o.PublishVersion, "publish-version", "", "", "the publish version of the rollback, generated from the revision by default")
+	cmd.Flags().IntVarP(&o.Context, "context", "c", 3, "output number lines of context around changes in the preview, -1 shows all unchanged lines")
+	addNamespaceAndEnvArg(cmd)
+	return cmd
+}
+
+// Run rolls back the application after showing the preview and getting the confirmation
+func (o *RollbackOptions) Run(ctx context.Context, c common.Args) error {
+	cli, err := c.GetClient()
+	if err != nil {
+		return err
+	}
+	app := &v1beta1.Application{}
+	if err = cli.Get(ctx, client.ObjectKey{Namespace: o.Namespace, Name: o.AppName}, app); err != nil {
+		return errors.Wrapf(err, "failed to get application %s/%s", o.Namespace, o.AppName)
+	}
+	rev := &v1beta1.ApplicationRevision{}
+	revName := applicationRevisionName(o.AppName, o.Revision)
+	if err = cli.Get(ctx, client.ObjectKey{Namespace: o.Namespace, Name: revName}, rev); err != nil {
+		return errors.Wrapf(err, "failed to get application revision %s/%s", o.Namespace, revName)
+	}
+	restored, err := o.restoreApplication(ctx, cli, app, rev)
+	if err != nil {
+		return err
+	}
+	if err = o.preview(ctx, c, restored); err != nil {
+		return err
+	}
+	if !assumeYes && !NewUserInput().AskBool(fmt.Sprintf("Do you want to rollback application %s to revision %s", app.Name, rev.Name), &UserInputOptions{assumeYes}) {
+		o.Info("Rollback canceled.")
+		return nil
+	}
+	if err = cli.Update(ctx, restored); err != nil {
+		return errors.Wrapf(err, "failed to rollback application %s to revision %s", app.Name, rev.Name)
+	}
+	o.Infof("%sApplication %s is rolled back to revision %s (PublishVersion: %s).\n", emojiSucceed, app.Name, rev.Name, oam.GetPublishVersion(restored))
+	o.Infof("Check the progress with `vela status %s -n %s --watch`.\n", app.Name, app.Namespace)
+	return nil
+}
+
+// restoreApplication returns a copy of the application with the spec restored from the revision and
+// the rollback recorded in the annotations
+func (o *RollbackOptions) restoreApplication(ctx context.Context, cli client.Client, app *v1beta1.Application, rev *v1beta1.ApplicationRevision) (*v1beta1.Application, error) {
+	if rev.Spec.Application.Name != app.Name {
+		return nil, errors.Errorf("revision %s does not belong to application %s", rev.Name, app.Name)
+	}
+	restored := app.DeepCopy()
+	restored.Spec = *rev.Spec.Application.Spec.DeepCopy()
+	pinned, err := pinDefinitionRevisions(ctx, cli, restored, rev)
+	if err != nil {
+		return nil, err
+	}
+	for _, msg := range pinned {
+		o.Infof("%s\n", msg)
+	}
+	publishVersion := o.PublishVersion
+	if publishVersion == "" {
+		publishVersion = fmt.Sprintf("rollback-%s-%s", rev.Name, time.Now().Format("20060102150405"))
+	}
+	oam.SetPublishVersion(restored, publishVersion)
+	annotations := restored.GetAnnotations()
+	annotations[oam.AnnotationRollbackRevision] = rev.Name
+	if o.Reason != "" {
+		annotations[oam.AnnotationRollbackReason] = o.Reason
+	} else {
+		delete(annotations, oam.AnnotationRollbackReason)
+	}
+	restored.SetAnnotations(annotations)
+	return restored, nil
+}
+
+// preview shows the differences between the latest revision and the application to rollback
+func (o *RollbackOptions) preview(ctx context.Context, c common.Args, app *v1beta1.Application) error {
+	if app.Status.LatestRevision == nil {
+		return nil
+	}
+	cli, err := c.GetClient()
+	if err != nil {
+		return err
+	}
+	latest := &v1beta1.ApplicationRevision{}
+	if err = cli.Get(ctx, client.ObjectKey{Namespace: app.Namespace, Name: app.Status.LatestRevision.Name}, latest); err != nil {
+		return errors.Wrapf(err, "failed to get the latest revision %s", app.Status.LatestRevision.Name)
+	}
+	config, err := c.GetConfig()
+	if err != nil {
+		return err
+	}
+	dm, err := c.GetDiscoveryMapper()
+	if err != nil {
+		return err
+	}
+	pd, err := c.GetPackageDiscover()
+	if err != nil {
+		return err
+	}
+	diff, err := dryrun.NewLiveDiffOption(cli, config, dm, pd, nil).Diff(ctx, app, latest)
+	if err != nil {
+		return errors.WithMessage(err, "cannot calculate the differences of the rollback")
+	}
+	o.Infof("Changes against the latest revision %s:\n", latest.Name)
+	dryrun.NewReportDiffOption(o.Context, o.Out).PrintDiffReport(diff)
+	return nil
+}
+
+// applicationRevisionName returns the name of the application revision, the revision
+// can be given by its name or number, like app-v2, v2 or 2
+func applicationRevisionName(appName string, revision string) string {
+	if strings.HasPrefix(revision, appName+"-v") {
+		return revision
+	}
+	return fmt.Sprintf("%s-v%s", appName, strings.TrimPrefix(revision, "v"))
+}
+
+// pinDefinitionRevisions pins the types of the components and traits in the application to the
+// definition revisions recorded in the application revision if the definitions have changed since then.
+// The messages of the pinned types are returned.
+func pinDefinitionRevisions(ctx context.Context, cli client.Client, app *v1beta1.Application, rev *v1beta1.ApplicationRevision) ([]string, error) {
+	ctx = util.SetNamespaceInCtx(ctx, app.Namespace)
+	var messages []string
+	pin := func(typ string, def runtime.Object, defType commontypes.DefinitionType) (string, error) {
+		pinned, err := pinnedDefinitionType(ctx, cli, typ, def, defType)
+		if err != nil {
+			return typ, err
+		}
+		if pinned != typ {
+			messages = append(messages, fmt.Sprintf("The %s definition %s has changed since revision %s, use %s instead.", strings.ToLower(string(defType)), typ, rev.Name, pinned))
+		}
+		return pinned, nil
+	}
+	for i, comp := range app.Spec.Components {
+		if def, ok := rev.Spec.ComponentDefinitions[comp.Type]; ok && !strings.Contains(comp.Type, "@") {
+			pinned, err := pin(comp.Type, def.DeepCopy(), commontypes.ComponentType)
+			if err != nil {
+				return nil, err
+			}
+			app.Spec.Components[i].Type = pinned
+		}
+		for j, trait := range comp.Traits {
+			if def, ok := rev.Spec.TraitDefinitions[trait.Type]; ok && !strings.Contains(trait.Type, "@") {
+				pinned, err := pin(trait.Type, def.DeepCopy(), commontypes.TraitType)
+				if err != nil {
+					return nil, err
+				}
+				app.Spec.Components[i].Traits[j].Type = pinned
+			}
+		}
+	}
+	return messages, nil
+}
+
+// pinnedDefinitionType returns the type referencing the definition revision that matches the recorded
+// definition. The type is unchanged if the current definition is the same as the recorded one.
+func pinnedDefinitionType(ctx context.Context, cli client.Client, name string, recorded runtime.Object, defType commontypes.DefinitionType) (string, error) {
+	recordedRev, _, err := core.GatherRevisionInfo(recorded)
+	if err != nil {
+		return name, err
+	}
+	current := recorded.DeepCopyObject().(client.Object)
+	if err = util.GetDefinition(ctx, cli, current, name); err == nil {
+		if currentRev, _, err := core.GatherRevisionInfo(current); err == nil && currentRev.Spec.RevisionHash == recordedRev.Spec.RevisionHash {
+			return name, nil
+		}
+	}
+	// the definition revisions are resolved in the namespace of the application first and then
+	// in the system namespace, a revision in the system namespace is shadowed by the one of the
+	// same name in the namespace of the application
+	namespaces := []string{util.GetDefinitionNamespaceWithCtx(ctx)}
+	if namespaces[0] != oam.SystemDefinitionNamespace {
+		namespaces = append(namespaces, oam.SystemDefinitionNamespace)
+	}
+	shadowed := map[string]bool{}
+	for _, namespace := range namespaces {
+		defRevs, err := definition.SearchDefinitionRevisions(ctx, cli, namespace, name, defType, 0)
+		if err != nil {
+			return name, err
+		}
+		for _, defRev := range defRevs {
+			if defRev.Spec.RevisionHash == recordedRev.Spec.RevisionHash && !shadowed[defRev.Name] {
+				return fmt.Sprintf("%s@v%s", name, strings.TrimPrefix(defRev.Name, name+"-v")), nil
+			}
+		}
+		for _, defRev := range defRevs {
+			shadowed[defRev.Name] = true
+		}
+	}
+	return name, errors.Errorf("the %s definition %s has changed and no definition revision matches the recorded one, cannot rollback", strings.ToLower(string(defType)), name)
+}
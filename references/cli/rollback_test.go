@@ -0,0 +1,113 @@
+/*
+Copyright 2022 The KubeVela Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cli
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/oam-dev/kubevela/apis/core.oam.dev/common"
+	"github.com/oam-dev/kubevela/apis/core.oam.dev/v1beta1"
+	"github.com/oam-dev/kubevela/pkg/controller/core.oam.dev/v1alpha2/core"
+	"github.com/oam-dev/kubevela/pkg/oam"
+	common2 "github.com/oam-dev/kubevela/pkg/utils/common"
+	cmdutil "github.com/oam-dev/kubevela/pkg/utils/util"
+)
+
+func TestApplicationRevisionName(t *testing.T) {
+	for revision, expected := range map[string]string{
+		"app-v2": "app-v2",
+		"v2":     "app-v2",
+		"2":      "app-v2",
+	} {
+		require.Equal(t, expected, applicationRevisionName("app", revision))
+	}
+}
+
+func TestRestoreApplication(t *testing.T) {
+	r := require.New(t)
+	ctx := context.Background()
+	newCompDef := func(template string) *v1beta1.ComponentDefinition {
+		return &v1beta1.ComponentDefinition{
+			ObjectMeta: metav1.ObjectMeta{Name: "worker", Namespace: "vela-system"},
+			Spec:       v1beta1.ComponentDefinitionSpec{Schematic: &common.Schematic{CUE: &common.CUE{Template: template}}},
+		}
+	}
+	newTraitDef := func() *v1beta1.TraitDefinition {
+		return &v1beta1.TraitDefinition{
+			ObjectMeta: metav1.ObjectMeta{Name: "scaler", Namespace: "vela-system"},
+			Spec:       v1beta1.TraitDefinitionSpec{Schematic: &common.Schematic{CUE: &common.CUE{Template: "patch: {}"}}},
+		}
+	}
+	oldDef, currentDef := newCompDef("output: {}"), newCompDef("output: {kind: \"Deployment\"}")
+	oldDefRev, _, err := core.GatherRevisionInfo(oldDef)
+	r.NoError(err)
+	oldDefRev.ObjectMeta = metav1.ObjectMeta{Name: "worker-v1", Namespace: "vela-system", Labels: map[string]string{oam.LabelComponentDefinitionName: "worker"}}
+	// the revision in the unrelated namespace is never used
+	jobDef := newCompDef("output: {kind: \"Job\"}")
+	otherDefRev, _, err := core.GatherRevisionInfo(jobDef)
+	r.NoError(err)
+	otherDefRev.ObjectMeta = metav1.ObjectMeta{Name: "worker-v2", Namespace: "other", Labels: map[string]string{oam.LabelComponentDefinitionName: "worker"}}
+
+	app := &v1beta1.Application{
+		ObjectMeta: metav1.ObjectMeta{Name: "app", Namespace: "default", Annotations: map[string]string{oam.AnnotationRollbackReason: "outdated"}},
+		Spec:       v1beta1.ApplicationSpec{Components: []common.ApplicationComponent{{Name: "web", Type: "worker"}}},
+	}
+	rev := &v1beta1.ApplicationRevision{
+		ObjectMeta: metav1.ObjectMeta{Name: "app-v1", Namespace: "default"},
+		Spec: v1beta1.ApplicationRevisionSpec{
+			Application: v1beta1.Application{
+				ObjectMeta: metav1.ObjectMeta{Name: "app", Namespace: "default"},
+				Spec:       v1beta1.ApplicationSpec{Components: []common.ApplicationComponent{{Name: "web", Type: "worker", Traits: []common.ApplicationTrait{{Type: "scaler"}}}}},
+			},
+			ComponentDefinitions: map[string]v1beta1.ComponentDefinition{"worker": *oldDef},
+			TraitDefinitions:     map[string]v1beta1.TraitDefinition{"scaler": *newTraitDef()},
+		},
+	}
+	cli := fake.NewClientBuilder().WithScheme(common2.Scheme).WithObjects(app, currentDef, newTraitDef(), oldDefRev, otherDefRev).Build()
+	out := &bytes.Buffer{}
+	o := &RollbackOptions{IOStreams: cmdutil.IOStreams{Out: out}}
+	restored, err := o.restoreApplication(ctx, cli, app, rev)
+	r.NoError(err)
+	r.Equal("worker@v1", restored.Spec.Components[0].Type)
+	r.Equal("scaler", restored.Spec.Components[0].Traits[0].Type)
+	r.Contains(out.String(), "use worker@v1 instead")
+	r.Equal("app-v1", restored.GetAnnotations()[oam.AnnotationRollbackRevision])
+	r.NotContains(restored.GetAnnotations(), oam.AnnotationRollbackReason)
+	r.Contains(oam.GetPublishVersion(restored), "rollback-app-v1-")
+	r.Equal("worker", app.Spec.Components[0].Type)
+
+	o.Reason, o.PublishVersion = "bad image", "v1-rollback"
+	rev.Spec.ComponentDefinitions["worker"] = *jobDef
+	_, err = o.restoreApplication(ctx, cli, app, rev)
+	r.Error(err)
+	rev.Spec.ComponentDefinitions["worker"] = *currentDef
+	restored, err = o.restoreApplication(ctx, cli, app, rev)
+	r.NoError(err)
+	r.Equal("worker", restored.Spec.Components[0].Type)
+	r.Equal("bad image", restored.GetAnnotations()[oam.AnnotationRollbackReason])
+	r.Equal("v1-rollback", oam.GetPublishVersion(restored))
+
+	rev.Spec.Application.Name = "other"
+	_, err = o.restoreApplication(ctx, cli, app, rev)
+	r.Error(err)
+}
@@ -490,9 +490,11 @@ func ShowReferenceMarkdown(ctx context.Context, c common.Args, ioStreams cmdutil
 
 func genRefParser(capabilityNameOrPath, ns, location, i18nPath string, rev int64) (docgen.ParseReference, error) {
 	ref := docgen.ParseReference{}
-	if location != "" {
-		docgen.LoadI18nData(i18nPath)
+	i18n, err := loadDocI18n(location, i18nPath)
+	if err != nil {
+		return ref, err
 	}
+	ref.I18N = i18n
 	if strings.HasSuffix(capabilityNameOrPath, ".yaml") || strings.HasSuffix(capabilityNameOrPath, ".cue") {
 		// read from local file
 		localFilePath := capabilityNameOrPath
@@ -503,15 +505,22 @@ func genRefParser(capabilityNameOrPath, ns, location, i18nPath string, rev int64
 		ref.DefinitionName = capabilityNameOrPath
 		ref.Remote = &docgen.FromCluster{Namespace: ns, Rev: rev}
 	}
+	return ref, nil
+}
+
+// loadDocI18n return the i18n of the location for the generated docs, the i18n data is loaded if the location is specified
+func loadDocI18n(location, i18nPath string) (*docgen.I18n, error) {
+	if location != "" {
+		docgen.LoadI18nData(i18nPath)
+	}
 	switch strings.ToLower(location) {
 	case "zh", "cn", "chinese":
-		ref.I18N = &docgen.Zh
+		return &docgen.Zh, nil
 	case "", "en", "english":
-		ref.I18N = &docgen.En
+		return &docgen.En, nil
 	default:
-		return ref, fmt.Errorf("unknown location %s for i18n translation", location)
+		return nil, fmt.Errorf("unknown location %s for i18n translation", location)
 	}
-	return ref, nil
 }
 
 // OpenBrowser will open browser by url in different OS system
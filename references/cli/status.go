@@ -29,6 +29,7 @@ import (
 	"github.com/pkg/errors"
 	"github.com/spf13/cobra"
 	"golang.org/x/term"
+	corev1 "k8s.io/api/core/v1"
 	pkgtypes "k8s.io/apimachinery/pkg/types"
 	"k8s.io/utils/pointer"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -40,10 +41,12 @@ import (
 	"github.com/kubevela/workflow/pkg/utils"
 
 	commontypes "github.com/oam-dev/kubevela/apis/core.oam.dev/common"
+	"github.com/oam-dev/kubevela/apis/core.oam.dev/condition"
 	"github.com/oam-dev/kubevela/apis/core.oam.dev/v1alpha1"
 	"github.com/oam-dev/kubevela/apis/core.oam.dev/v1alpha2"
 	"github.com/oam-dev/kubevela/apis/core.oam.dev/v1beta1"
 	"github.com/oam-dev/kubevela/apis/types"
+	"github.com/oam-dev/kubevela/pkg/appdependency"
 	pkgappfile "github.com/oam-dev/kubevela/pkg/appfile"
 	"github.com/oam-dev/kubevela/pkg/multicluster"
 	"github.com/oam-dev/kubevela/pkg/oam/discoverymapper"
@@ -105,6 +108,8 @@ func NewAppStatusCommand(c common.Args, order string, ioStreams cmdutil.IOStream
 	ctx := context.Background()
 	var outputFormat string
 	var detail bool
+	var watch bool
+	var timeout time.Duration
 	cmd := &cobra.Command{
 		Use:   "status APP_NAME",
 		Short: "Show status of an application.",
@@ -115,6 +120,10 @@ func NewAppStatusCommand(c common.Args, order string, ioStreams cmdutil.IOStream
   # Show detailed info in tree
   vela status first-vela-app --tree --detail --detail-format list
 
+  # Export the resource topology with the health status in Graphviz DOT, Mermaid or JSON
+  vela status first-vela-app --tree -o dot | dot -Tsvg > topology.svg
+  vela status first-vela-app --tree -o mermaid
+
   # Show pod list
   vela status first-vela-app --pod
   vela status first-vela-app --pod --component express-server --cluster local
@@ -126,7 +135,11 @@ func NewAppStatusCommand(c common.Args, order string, ioStreams cmdutil.IOStream
   vela status first-vela-app -o yaml
 
   # Get raw Application status using jsonpath
-  vela status first-vela-app -o jsonpath='{.status}'`,
+  vela status first-vela-app -o jsonpath='{.status}'
+
+  # Stream the progress until the application is running, exit with code 1 if it fails,
+  # 2 if the timeout is reached and 3 if the workflow is suspended
+  vela status first-vela-app --watch --timeout 10m`,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			// check args
 			argsLength := len(args)
@@ -140,6 +153,9 @@ func NewAppStatusCommand(c common.Args, order string, ioStreams cmdutil.IOStream
 				return err
 			}
 			if printTree, err := cmd.Flags().GetBool("tree"); err == nil && printTree {
+				if outputFormat != "" {
+					return exportApplicationTopology(ctx, c, cmd.OutOrStdout(), appName, namespace, outputFormat)
+				}
 				return printApplicationTree(c, cmd, appName, namespace)
 			}
 			if printPod, err := cmd.Flags().GetBool("pod"); err == nil && printPod {
@@ -165,6 +181,9 @@ func NewAppStatusCommand(c common.Args, order string, ioStreams cmdutil.IOStream
 			if err != nil {
 				return err
 			}
+			if watch {
+				return newAppStatusWatcher(newClient, ioStreams, appName, namespace, timeout).Run(ctx)
+			}
 			if outputFormat != "" {
 				return printRawApplication(context.Background(), c, outputFormat, cmd.OutOrStdout(), namespace, appName)
 			}
@@ -183,7 +202,9 @@ func NewAppStatusCommand(c common.Args, order string, ioStreams cmdutil.IOStream
 	cmd.Flags().BoolP("pod", "", false, "show pod list of the application")
 	cmd.Flags().BoolVarP(&detail, "detail", "d", false, "display more details in the application like input/output data in context. Note that if you want to show the realtime details of application resources, please use it with --tree")
 	cmd.Flags().StringP("detail-format", "", "inline", "the format for displaying details, must be used with --detail. Can be one of inline, wide, list, table, raw.")
-	cmd.Flags().StringVarP(&outputFormat, "output", "o", "", "raw Application output format. One of: (json, yaml, jsonpath)")
+	cmd.Flags().StringVarP(&outputFormat, "output", "o", "", "raw Application output format. One of: (json, yaml, jsonpath). If used with --tree, export the resource topology in one of: (dot, mermaid, json)")
+	cmd.Flags().BoolVarP(&watch, "watch", "w", false, "stream the workflow step transitions, resource health changes and events until the application is running, failed or suspended. The exit code reflects the final result.")
+	cmd.Flags().DurationVarP(&timeout, "timeout", "", 0, "the timeout for --watch, no timeout by default")
 	addNamespaceAndEnvArg(cmd)
 	return cmd
 }
@@ -201,12 +222,34 @@ func printAppStatus(_ context.Context, c client.Client, ioStreams cmdutil.IOStre
 	table.AddRow("  Created at:", app.CreationTimestamp.String())
 	table.AddRow("  Status:", getAppPhaseColor(app.Status.Phase).Sprint(app.Status.Phase))
 	cmd.Printf("%s\n\n", table.String())
+	printAppDependencies(cmd, app)
 	if err := printWorkflowStatus(c, ioStreams, appName, namespace, detail); err != nil {
 		return err
 	}
 	return loopCheckStatus(c, ioStreams, appName, namespace)
 }
 
+// printAppDependencies print the depended applications and the dependency blocking the application if exists
+func printAppDependencies(cmd *cobra.Command, app *v1beta1.Application) {
+	if len(app.Spec.DependsOn) == 0 {
+		return
+	}
+	cmd.Printf("Dependencies:\n\n")
+	table := newUITable()
+	for _, dep := range app.Spec.DependsOn {
+		cond := dep.Condition
+		if cond == "" {
+			cond = v1beta1.AppDependencyConditionHealthy
+		}
+		table.AddRow("  "+appdependency.Key(app, dep).String(), string(cond))
+	}
+	cond := app.Status.GetCondition(condition.ConditionType(commontypes.DependencyCondition.String()))
+	if cond.Status == corev1.ConditionFalse {
+		table.AddRow("  Blocked:", yellow.Sprint(cond.Message))
+	}
+	cmd.Printf("%s\n\n", table.String())
+}
+
 func formatEndpoints(endpoints []types2.ServiceEndpoint) [][]string {
 	var result [][]string
 	result = append(result, []string{"Cluster", "Component", "Ref(Kind/Namespace/Name)", "Endpoint", "Inner"})
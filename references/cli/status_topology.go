@@ -0,0 +1,254 @@
+/*
+Copyright 2022 The KubeVela Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	commontypes "github.com/oam-dev/kubevela/apis/core.oam.dev/common"
+	"github.com/oam-dev/kubevela/apis/core.oam.dev/v1beta1"
+	"github.com/oam-dev/kubevela/pkg/multicluster"
+	"github.com/oam-dev/kubevela/pkg/utils/common"
+	"github.com/oam-dev/kubevela/pkg/velaql/providers/query"
+	types2 "github.com/oam-dev/kubevela/pkg/velaql/providers/query/types"
+)
+
+const (
+	topologyFormatDOT     = "dot"
+	topologyFormatMermaid = "mermaid"
+	topologyFormatJSON    = "json"
+)
+
+// topologyNode is the node of the application topology: application -> clusters -> components -> resources,
+// the resources are expanded to the child resources like the pods by the resource tree rules
+type topologyNode struct {
+	Type       string                  `json:"type"`
+	Name       string                  `json:"name"`
+	Kind       string                  `json:"kind,omitempty"`
+	APIVersion string                  `json:"apiVersion,omitempty"`
+	Namespace  string                  `json:"namespace,omitempty"`
+	Cluster    string                  `json:"cluster,omitempty"`
+	Health     types2.HealthStatusCode `json:"health"`
+	Message    string                  `json:"message,omitempty"`
+	Children   []*topologyNode         `json:"children,omitempty"`
+}
+
+// exportApplicationTopology export the topology of the application in the format of dot, mermaid or json
+func exportApplicationTopology(ctx context.Context, c common.Args, out io.Writer, appName, appNs, format string) error {
+	if format != topologyFormatDOT && format != topologyFormatMermaid && format != topologyFormatJSON {
+		return fmt.Errorf("output format %s is not supported with --tree, only %s, %s and %s are supported", format, topologyFormatDOT, topologyFormatMermaid, topologyFormatJSON)
+	}
+	cli, err := c.GetClient()
+	if err != nil {
+		return err
+	}
+	app, err := loadRemoteApplication(cli, appNs, appName)
+	if err != nil {
+		return err
+	}
+	resources, err := query.NewAppCollector(cli, query.Option{Name: app.Name, Namespace: app.Namespace, WithTree: true}).ListApplicationResources(ctx, app)
+	if err != nil {
+		return errors.Wrapf(err, "failed to list the resources of the application")
+	}
+	root := buildApplicationTopology(app, resources)
+	switch format {
+	case topologyFormatDOT:
+		_, err = io.WriteString(out, renderTopologyDOT(root))
+	case topologyFormatMermaid:
+		_, err = io.WriteString(out, renderTopologyMermaid(root))
+	default:
+		var data []byte
+		if data, err = json.MarshalIndent(root, "", "  "); err == nil {
+			_, err = out.Write(append(data, '\n'))
+		}
+	}
+	return err
+}
+
+// buildApplicationTopology group the resources of the application by the clusters and the components
+func buildApplicationTopology(app *v1beta1.Application, resources []*types2.AppliedResource) *topologyNode {
+	root := &topologyNode{Type: "application", Name: app.Name, Namespace: app.Namespace, Health: applicationHealth(app), Message: string(app.Status.Phase)}
+	clusters := map[string]*topologyNode{}
+	components := map[string]*topologyNode{}
+	for _, res := range resources {
+		if res.ResourceTree == nil {
+			continue
+		}
+		cluster, ok := clusters[res.Cluster]
+		if !ok {
+			cluster = &topologyNode{Type: "cluster", Name: res.Cluster, Health: types2.HealthStatusHealthy}
+			clusters[res.Cluster] = cluster
+			root.Children = append(root.Children, cluster)
+		}
+		parent := cluster
+		if res.Component != "" {
+			key := res.Cluster + "/" + res.Component
+			if parent, ok = components[key]; !ok {
+				parent = &topologyNode{Type: "component", Name: res.Component, Cluster: res.Cluster}
+				parent.Health, parent.Message = componentHealth(app, res.Component, res.Cluster)
+				components[key] = parent
+				cluster.Children = append(cluster.Children, parent)
+			}
+		}
+		parent.Children = append(parent.Children, resourceTopologyNode(res.ResourceTree))
+	}
+	// the cluster is unhealthy if any component in it is unhealthy
+	for _, cluster := range clusters {
+		for _, child := range cluster.Children {
+			if child.Health == types2.HealthStatusUnHealthy {
+				cluster.Health = types2.HealthStatusUnHealthy
+			}
+		}
+	}
+	return root
+}
+
+func resourceTopologyNode(node *types2.ResourceTreeNode) *topologyNode {
+	n := &topologyNode{
+		Type:       "resource",
+		Name:       node.Name,
+		Kind:       node.Kind,
+		APIVersion: node.APIVersion,
+		Namespace:  node.Namespace,
+		Cluster:    node.Cluster,
+		Health:     node.HealthStatus.Status,
+		Message:    node.HealthStatus.Message,
+	}
+	if n.Health == "" {
+		n.Health = types2.HealthStatusUnKnown
+	}
+	for _, leaf := range node.LeafNodes {
+		n.Children = append(n.Children, resourceTopologyNode(leaf))
+	}
+	return n
+}
+
+func applicationHealth(app *v1beta1.Application) types2.HealthStatusCode {
+	for _, service := range app.Status.Services {
+		if !service.Healthy {
+			return types2.HealthStatusUnHealthy
+		}
+	}
+	switch app.Status.Phase {
+	case commontypes.ApplicationRunning:
+		return types2.HealthStatusHealthy
+	case commontypes.ApplicationWorkflowFailed, commontypes.ApplicationWorkflowTerminated, commontypes.ApplicationUnhealthy:
+		return types2.HealthStatusUnHealthy
+	default:
+		return types2.HealthStatusProgressing
+	}
+}
+
+func componentHealth(app *v1beta1.Application, component, cluster string) (types2.HealthStatusCode, string) {
+	for _, service := range app.Status.Services {
+		serviceCluster := service.Cluster
+		if serviceCluster == "" {
+			serviceCluster = multicluster.ClusterLocalName
+		}
+		if service.Name != component || serviceCluster != cluster {
+			continue
+		}
+		if service.Healthy {
+			return types2.HealthStatusHealthy, service.Message
+		}
+		return types2.HealthStatusUnHealthy, service.Message
+	}
+	return types2.HealthStatusUnKnown, ""
+}
+
+// topologyLabel return the label of the node shown in the graph
+func topologyLabel(node *topologyNode) string {
+	switch node.Type {
+	case "resource":
+		if node.Namespace != "" {
+			return fmt.Sprintf("%s\n%s/%s", node.Kind, node.Namespace, node.Name)
+		}
+		return fmt.Sprintf("%s\n%s", node.Kind, node.Name)
+	default:
+		return fmt.Sprintf("%s\n%s", strings.ToUpper(node.Type[:1])+node.Type[1:], node.Name)
+	}
+}
+
+var topologyHealthColors = map[types2.HealthStatusCode]string{
+	types2.HealthStatusHealthy:     "#d4edda",
+	types2.HealthStatusUnHealthy:   "#f8d7da",
+	types2.HealthStatusProgressing: "#fff3cd",
+	types2.HealthStatusUnKnown:     "#e2e3e5",
+}
+
+// walkTopology visit the nodes in the depth-first order with the sequential IDs
+func walkTopology(root *topologyNode, visit func(id string, node *topologyNode, parentID string)) {
+	count := 0
+	var walk func(node *topologyNode, parentID string)
+	walk = func(node *topologyNode, parentID string) {
+		id := fmt.Sprintf("n%d", count)
+		count++
+		visit(id, node, parentID)
+		for _, child := range node.Children {
+			walk(child, id)
+		}
+	}
+	walk(root, "")
+}
+
+// renderTopologyDOT render the topology in the Graphviz DOT language, the health is set as the node attribute
+func renderTopologyDOT(root *topologyNode) string {
+	quote := func(s string) string {
+		return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(s) + `"`
+	}
+	var nodes, edges strings.Builder
+	walkTopology(root, func(id string, node *topologyNode, parentID string) {
+		fmt.Fprintf(&nodes, "  %s [label=%s, type=%s, health=%s, tooltip=%s, fillcolor=%s];\n",
+			id, quote(topologyLabel(node)), quote(node.Type), quote(string(node.Health)), quote(node.Message), quote(topologyHealthColors[node.Health]))
+		if parentID != "" {
+			fmt.Fprintf(&edges, "  %s -> %s;\n", parentID, id)
+		}
+	})
+	return fmt.Sprintf("digraph %s {\n  rankdir=LR;\n  node [shape=box, style=\"rounded,filled\"];\n%s%s}\n", quote(root.Name), nodes.String(), edges.String())
+}
+
+// renderTopologyMermaid render the topology as the Mermaid flowchart, the nodes are styled by the health
+func renderTopologyMermaid(root *topologyNode) string {
+	quote := func(s string) string {
+		return `"` + strings.NewReplacer(`"`, "#quot;", "\n", "<br/>").Replace(s) + `"`
+	}
+	var sb strings.Builder
+	sb.WriteString("graph LR\n")
+	classes := map[types2.HealthStatusCode][]string{}
+	walkTopology(root, func(id string, node *topologyNode, parentID string) {
+		label := topologyLabel(node)
+		if parentID == "" {
+			fmt.Fprintf(&sb, "  %s[%s]\n", id, quote(label))
+		} else {
+			fmt.Fprintf(&sb, "  %s --> %s[%s]\n", parentID, id, quote(label))
+		}
+		classes[node.Health] = append(classes[node.Health], id)
+	})
+	for _, health := range []types2.HealthStatusCode{types2.HealthStatusHealthy, types2.HealthStatusUnHealthy, types2.HealthStatusProgressing, types2.HealthStatusUnKnown} {
+		if ids := classes[health]; len(ids) > 0 {
+			fmt.Fprintf(&sb, "  classDef %s fill:%s\n", health, topologyHealthColors[health])
+			fmt.Fprintf(&sb, "  class %s %s\n", strings.Join(ids, ","), health)
+		}
+	}
+	return sb.String()
+}
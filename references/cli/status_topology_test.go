@@ -0,0 +1,86 @@
+/*
+Copyright 2022 The KubeVela Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cli
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/oam-dev/kubevela/apis/core.oam.dev/common"
+	"github.com/oam-dev/kubevela/apis/core.oam.dev/v1beta1"
+	types2 "github.com/oam-dev/kubevela/pkg/velaql/providers/query/types"
+)
+
+func TestApplicationTopology(t *testing.T) {
+	r := require.New(t)
+	app := &v1beta1.Application{
+		ObjectMeta: metav1.ObjectMeta{Name: "app", Namespace: "default"},
+		Status: common.AppStatus{
+			Phase: common.ApplicationRunning,
+			Services: []common.ApplicationComponentStatus{
+				{Name: "web", Healthy: true},
+				{Name: "web", Cluster: "cluster-1", Healthy: false, Message: "0/1 replicas ready"},
+			},
+		},
+	}
+	deployment := func(cluster string, health types2.HealthStatusCode) *types2.AppliedResource {
+		return &types2.AppliedResource{Cluster: cluster, Component: "web", Kind: "Deployment", Namespace: "default", Name: "web", ResourceTree: &types2.ResourceTreeNode{
+			Cluster: cluster, APIVersion: "apps/v1", Kind: "Deployment", Namespace: "default", Name: "web", HealthStatus: types2.HealthStatus{Status: health},
+			LeafNodes: []*types2.ResourceTreeNode{{
+				Cluster: cluster, APIVersion: "apps/v1", Kind: "ReplicaSet", Namespace: "default", Name: "web-7d9c", HealthStatus: types2.HealthStatus{Status: health},
+				LeafNodes: []*types2.ResourceTreeNode{{Cluster: cluster, APIVersion: "v1", Kind: "Pod", Namespace: "default", Name: "web-7d9c-x2b4", HealthStatus: types2.HealthStatus{Status: health, Message: `Back-off "web"`}}},
+			}},
+		}}
+	}
+	resources := []*types2.AppliedResource{
+		deployment("local", types2.HealthStatusHealthy),
+		deployment("cluster-1", types2.HealthStatusProgressing),
+		// the resource deleted is not shown
+		{Cluster: "local", Component: "web", Kind: "Service", Name: "web"},
+	}
+
+	root := buildApplicationTopology(app, resources)
+	r.Equal(types2.HealthStatusUnHealthy, root.Health)
+	r.Len(root.Children, 2)
+	local, remote := root.Children[0], root.Children[1]
+	r.Equal("local", local.Name)
+	r.Equal(types2.HealthStatusHealthy, local.Health)
+	r.Equal(types2.HealthStatusUnHealthy, remote.Health)
+	r.Equal("0/1 replicas ready", remote.Children[0].Message)
+	pod := remote.Children[0].Children[0].Children[0].Children[0]
+	r.Equal("Pod", pod.Kind)
+	r.Equal(types2.HealthStatusProgressing, pod.Health)
+
+	dot := renderTopologyDOT(root)
+	r.Contains(dot, `digraph "app" {`)
+	r.Contains(dot, `n0 [label="Application\napp", type="application", health="UnHealthy", tooltip="running", fillcolor="#f8d7da"];`)
+	r.Contains(dot, `tooltip="Back-off \"web\""`)
+	r.Contains(dot, "n0 -> n1;")
+	r.Contains(dot, "n3 -> n4;")
+
+	mermaid := renderTopologyMermaid(root)
+	r.Contains(mermaid, "graph LR\n  n0[\"Application<br/>app\"]\n  n0 --> n1[\"Cluster<br/>local\"]\n")
+	r.Contains(mermaid, `n4 --> n5["Pod<br/>default/web-7d9c-x2b4"]`)
+	r.Contains(mermaid, "classDef UnHealthy fill:#f8d7da\n  class n0,n6,n7 UnHealthy\n")
+
+	data, err := json.Marshal(root)
+	r.NoError(err)
+	r.Contains(string(data), `{"type":"resource","name":"web-7d9c-x2b4","kind":"Pod","apiVersion":"v1","namespace":"default","cluster":"local","health":"Healthy","message":"Back-off \"web\""}`)
+}
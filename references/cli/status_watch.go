@@ -0,0 +1,278 @@
+/*
+Copyright 2022 The KubeVela Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cli
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/klog/v2"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	workflowv1alpha1 "github.com/kubevela/workflow/api/v1alpha1"
+
+	commontypes "github.com/oam-dev/kubevela/apis/core.oam.dev/common"
+	"github.com/oam-dev/kubevela/apis/core.oam.dev/v1beta1"
+	cmdutil "github.com/oam-dev/kubevela/pkg/utils/util"
+	"github.com/oam-dev/kubevela/pkg/velaql/providers/query"
+	types2 "github.com/oam-dev/kubevela/pkg/velaql/providers/query/types"
+)
+
+const watchInterval = 3 * time.Second
+
+const (
+	// watchExitCodeTimeout is the exit code of `vela status --watch` if the timeout is reached
+	watchExitCodeTimeout = 2
+	// watchExitCodeSuspended is the exit code of `vela status --watch` if the workflow is suspended
+	watchExitCodeSuspended = 3
+)
+
+// appStatusWatcher streams the progress of an application: the transitions of the
+// application phase and the workflow steps, the health changes of the resources and
+// the kubernetes events of the resources in all the clusters the application is dispatched to.
+type appStatusWatcher struct {
+	cli       client.Client
+	ioStreams cmdutil.IOStreams
+	name      string
+	namespace string
+	interval  time.Duration
+	timeout   time.Duration
+
+	since  time.Time
+	phase  commontypes.ApplicationPhase
+	steps  map[string]workflowv1alpha1.WorkflowStepPhase
+	health map[string]types2.HealthStatus
+	events map[string]int32
+
+	now func() time.Time
+}
+
+func newAppStatusWatcher(cli client.Client, ioStreams cmdutil.IOStreams, name, namespace string, timeout time.Duration) *appStatusWatcher {
+	return &appStatusWatcher{
+		cli:       cli,
+		ioStreams: ioStreams,
+		name:      name,
+		namespace: namespace,
+		interval:  watchInterval,
+		timeout:   timeout,
+		steps:     map[string]workflowv1alpha1.WorkflowStepPhase{},
+		health:    map[string]types2.HealthStatus{},
+		events:    map[string]int32{},
+		now:       time.Now,
+	}
+}
+
+// Run watches the application until it succeeds, fails, suspends or the timeout is reached.
+// An error is returned unless the application finishes successfully, so that the
+// exit code can be used to gate the following jobs: 1 for failure, 2 for timeout and 3
+// for the suspended workflow.
+func (w *appStatusWatcher) Run(ctx context.Context) error {
+	w.since = w.now()
+	if w.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, w.timeout)
+		defer cancel()
+	}
+	for {
+		done, err := w.poll(ctx)
+		if ctx.Err() != nil {
+			break
+		}
+		if done || err != nil {
+			return err
+		}
+		select {
+		case <-ctx.Done():
+		case <-time.After(w.interval):
+		}
+	}
+	w.ioStreams.Info(red.Sprintf("%sTimeout after %s waiting for application %s to be running", emojiFail, w.timeout, w.name))
+	return &ExitCodeError{Code: watchExitCodeTimeout, Reason: fmt.Sprintf("timeout waiting for application %s", w.name)}
+}
+
+// poll loads the application once, renders the changes since the last poll and
+// reports whether the application has reached a final state.
+func (w *appStatusWatcher) poll(ctx context.Context) (bool, error) {
+	app := &v1beta1.Application{}
+	if err := w.cli.Get(ctx, client.ObjectKey{Namespace: w.namespace, Name: w.name}, app); err != nil {
+		return false, errors.Wrapf(err, "failed to get application %s", w.name)
+	}
+	if wf := app.Status.Workflow; wf != nil && !wf.Finished && wf.StartTime.Time.Before(w.since) {
+		// include the events emitted since the beginning of the running workflow
+		w.since = wf.StartTime.Time
+	}
+	w.renderPhase(app)
+	w.renderSteps(app)
+	w.renderResources(ctx, app)
+	return w.finished(app)
+}
+
+func (w *appStatusWatcher) renderPhase(app *v1beta1.Application) {
+	if app.Status.Phase == w.phase {
+		return
+	}
+	w.phase = app.Status.Phase
+	w.printf(emojiExecuting, "app", "%s", getAppPhaseColor(w.phase).Sprint(w.phase))
+}
+
+func (w *appStatusWatcher) renderSteps(app *v1beta1.Application) {
+	if app.Status.Workflow == nil {
+		return
+	}
+	render := func(step workflowv1alpha1.StepStatus) {
+		if step.Phase == "" || w.steps[step.Name] == step.Phase {
+			return
+		}
+		w.steps[step.Name] = step.Phase
+		msg := fmt.Sprintf("%s (%s) %s", step.Name, step.Type, getWfStepColor(step.Phase).Sprint(step.Phase))
+		if step.Message != "" {
+			msg += ": " + step.Message
+		}
+		w.printf(stepPhaseEmoji(step.Phase), "step", "%s", msg)
+	}
+	for _, step := range app.Status.Workflow.Steps {
+		render(step.StepStatus)
+		for _, sub := range step.SubStepsStatus {
+			render(sub)
+		}
+	}
+}
+
+func (w *appStatusWatcher) renderResources(ctx context.Context, app *v1beta1.Application) {
+	nodeEvents, err := query.NewAppCollector(w.cli, query.Option{Name: app.Name, Namespace: app.Namespace, WithTree: true}).ListApplicationResourceEvents(ctx, app)
+	if err != nil {
+		klog.Warningf("failed to list the resources of application %s: %s", app.Name, err.Error())
+		return
+	}
+	for _, ne := range nodeEvents {
+		w.renderHealth(ne.Node)
+	}
+	for _, ne := range nodeEvents {
+		w.renderEvents(ne)
+	}
+}
+
+func (w *appStatusWatcher) renderHealth(node *types2.ResourceTreeNode) {
+	key := resourceNodeKey(node)
+	if last, ok := w.health[key]; ok && last.Status == node.HealthStatus.Status && last.Message == node.HealthStatus.Message {
+		return
+	}
+	w.health[key] = node.HealthStatus
+	icon, c := emojiExecuting, yellow
+	switch node.HealthStatus.Status {
+	case types2.HealthStatusHealthy:
+		icon, c = emojiSucceed, green
+	case types2.HealthStatusUnHealthy:
+		icon, c = emojiFail, red
+	}
+	msg := fmt.Sprintf("%s %s", key, c.Sprint(node.HealthStatus.Status))
+	if node.HealthStatus.Message != "" {
+		msg += ": " + node.HealthStatus.Message
+	}
+	w.printf(icon, "health", "%s", msg)
+}
+
+func (w *appStatusWatcher) renderEvents(ne query.ResourceNodeEvents) {
+	node := ne.Node
+	if ne.Err != nil {
+		klog.Warningf("failed to list the events of %s: %s", resourceNodeKey(node), ne.Err.Error())
+		return
+	}
+	for _, event := range ne.Events {
+		if query.EventTime(event).Before(w.since) {
+			continue
+		}
+		if count, seen := w.events[string(event.UID)]; seen && count == event.Count {
+			continue
+		}
+		w.events[string(event.UID)] = event.Count
+		icon := emojiExecuting
+		if event.Type == corev1.EventTypeWarning {
+			icon = emojiFail
+		}
+		msg := fmt.Sprintf("%s %s %s: %s", resourceNodeKey(node), event.Type, event.Reason, strings.TrimSpace(event.Message))
+		if event.Count > 1 {
+			msg += fmt.Sprintf(" (x%d)", event.Count)
+		}
+		w.printf(icon, "event", "%s", msg)
+	}
+}
+
+// finished checks whether the application reaches a final state. The status is only
+// trusted after the controller has observed the latest spec of the application.
+func (w *appStatusWatcher) finished(app *v1beta1.Application) (bool, error) {
+	if app.Status.ObservedGeneration < app.Generation {
+		return false, nil
+	}
+	wf := app.Status.Workflow
+	switch {
+	case app.Status.Phase == commontypes.ApplicationWorkflowFailed,
+		wf != nil && wf.Phase == workflowv1alpha1.WorkflowStateFailed:
+		msg := ""
+		if wf != nil {
+			msg = wf.Message
+		}
+		w.ioStreams.Info(red.Sprintf("%sApplication %s workflow failed: %s", emojiFail, app.Name, msg))
+		return true, errors.Errorf("the workflow of application %s failed", app.Name)
+	case app.Status.Phase == commontypes.ApplicationWorkflowTerminated,
+		wf != nil && (wf.Terminated || wf.Phase == workflowv1alpha1.WorkflowStateTerminated):
+		w.ioStreams.Info(red.Sprintf("%sApplication %s workflow terminated", emojiFail, app.Name))
+		return true, errors.Errorf("the workflow of application %s is terminated", app.Name)
+	case app.Status.Phase == commontypes.ApplicationWorkflowSuspending,
+		wf != nil && wf.Suspend:
+		// the workflow waits for a manual resume, it won't finish by itself
+		w.ioStreams.Info(yellow.Sprintf("%sApplication %s workflow is suspended", emojiExecuting, app.Name))
+		return true, &ExitCodeError{Code: watchExitCodeSuspended, Reason: fmt.Sprintf("the workflow of application %s is suspended", app.Name)}
+	case app.Status.Phase == commontypes.ApplicationRunning && (wf == nil || wf.Finished):
+		w.ioStreams.Info(green.Sprintf("%sApplication %s is running", emojiSucceed, app.Name))
+		return true, nil
+	}
+	return false, nil
+}
+
+func (w *appStatusWatcher) printf(icon string, category string, format string, a ...interface{}) {
+	w.ioStreams.Infof("%s %s%-6s %s\n", w.now().Format("15:04:05"), icon, category, fmt.Sprintf(format, a...))
+}
+
+func stepPhaseEmoji(phase workflowv1alpha1.WorkflowStepPhase) string {
+	switch phase {
+	case workflowv1alpha1.WorkflowStepPhaseSucceeded:
+		return emojiSucceed
+	case workflowv1alpha1.WorkflowStepPhaseFailed:
+		return emojiFail
+	case workflowv1alpha1.WorkflowStepPhaseSkipped:
+		return emojiSkip
+	default:
+		return emojiExecuting
+	}
+}
+
+func resourceNodeKey(node *types2.ResourceTreeNode) string {
+	name := node.Name
+	if node.Namespace != "" {
+		name = node.Namespace + "/" + name
+	}
+	cluster := node.Cluster
+	if cluster == "" {
+		cluster = "local"
+	}
+	return fmt.Sprintf("%s %s@%s", node.Kind, name, cluster)
+}
@@ -0,0 +1,152 @@
+/*
+Copyright 2022 The KubeVela Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cli
+
+import (
+	"bytes"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	workflowv1alpha1 "github.com/kubevela/workflow/api/v1alpha1"
+
+	"github.com/oam-dev/kubevela/apis/core.oam.dev/common"
+	"github.com/oam-dev/kubevela/apis/core.oam.dev/v1beta1"
+	"github.com/oam-dev/kubevela/pkg/oam"
+	common2 "github.com/oam-dev/kubevela/pkg/utils/common"
+	cmdutil "github.com/oam-dev/kubevela/pkg/utils/util"
+)
+
+func TestAppStatusWatcher(t *testing.T) {
+	r := require.New(t)
+	ctx := context.Background()
+	start := time.Now()
+	app := &v1beta1.Application{
+		ObjectMeta: metav1.ObjectMeta{Name: "app", Namespace: "default"},
+		Status: common.AppStatus{
+			Phase: common.ApplicationRunningWorkflow,
+			Workflow: &common.WorkflowStatus{StartTime: metav1.NewTime(start.Add(-time.Minute)), Steps: []workflowv1alpha1.WorkflowStepStatus{
+				{StepStatus: workflowv1alpha1.StepStatus{Name: "deploy", Type: "deploy", Phase: workflowv1alpha1.WorkflowStepPhaseRunning}},
+			}},
+		},
+	}
+	rt := &v1beta1.ResourceTracker{
+		ObjectMeta: metav1.ObjectMeta{Name: "app-default", Labels: map[string]string{oam.LabelAppName: "app", oam.LabelAppNamespace: "default"}},
+		Spec: v1beta1.ResourceTrackerSpec{Type: v1beta1.ResourceTrackerTypeRoot, ManagedResources: []v1beta1.ManagedResource{{
+			ClusterObjectReference: common.ClusterObjectReference{ObjectReference: corev1.ObjectReference{APIVersion: "v1", Kind: "ConfigMap", Namespace: "default", Name: "config"}},
+			OAMObjectReference:     common.OAMObjectReference{Component: "config"},
+		}}},
+	}
+	cm := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "config", Namespace: "default"}}
+	newEvent := func(name string, count int32, at time.Time) *corev1.Event {
+		return &corev1.Event{
+			ObjectMeta:     metav1.ObjectMeta{Name: name, Namespace: "default", UID: types.UID("uid-" + name)},
+			InvolvedObject: corev1.ObjectReference{APIVersion: "v1", Kind: "ConfigMap", Namespace: "default", Name: "config"},
+			Type:           corev1.EventTypeWarning,
+			Reason:         "Invalid",
+			Message:        name,
+			Count:          count,
+			LastTimestamp:  metav1.NewTime(at),
+		}
+	}
+	cli := fake.NewClientBuilder().WithScheme(common2.Scheme).WithObjects(app, rt, cm,
+		newEvent("outdated", 1, start.Add(-time.Hour)),
+		newEvent("recent", 1, start.Add(-time.Second)),
+	).Build()
+	out := &bytes.Buffer{}
+	w := newAppStatusWatcher(cli, cmdutil.IOStreams{Out: out}, "app", "default", 0)
+	w.since = start
+
+	done, err := w.poll(ctx)
+	r.NoError(err)
+	r.False(done)
+	r.Contains(out.String(), "runningWorkflow")
+	r.Contains(out.String(), "deploy (deploy)")
+	r.Contains(out.String(), "ConfigMap default/config@local")
+	r.Contains(out.String(), "Warning Invalid: recent")
+	r.NotContains(out.String(), "outdated")
+
+	// nothing changed
+	out.Reset()
+	done, err = w.poll(ctx)
+	r.NoError(err)
+	r.False(done)
+	r.Empty(out.String())
+
+	// the repeated event and the finished workflow are rendered
+	out.Reset()
+	r.NoError(cli.Update(ctx, newEvent("recent", 2, start)))
+	r.NoError(cli.Get(ctx, client.ObjectKeyFromObject(app), app))
+	app.Status.Phase = common.ApplicationRunning
+	app.Status.Workflow.Finished = true
+	app.Status.Workflow.Steps[0].Phase = workflowv1alpha1.WorkflowStepPhaseSucceeded
+	r.NoError(cli.Status().Update(ctx, app))
+	done, err = w.poll(ctx)
+	r.NoError(err)
+	r.True(done)
+	r.Contains(out.String(), "recent (x2)")
+	r.Contains(out.String(), "deploy (deploy) succeeded")
+	r.Contains(out.String(), "Application app is running")
+
+	testCases := map[string]struct {
+		status     common.AppStatus
+		generation int64
+		done       bool
+		exitCode   int
+	}{
+		"failed": {
+			status:   common.AppStatus{Phase: common.ApplicationWorkflowFailed, Workflow: &common.WorkflowStatus{Phase: workflowv1alpha1.WorkflowStateFailed}},
+			done:     true,
+			exitCode: 1,
+		},
+		"terminated": {
+			status:   common.AppStatus{Phase: common.ApplicationWorkflowTerminated, Workflow: &common.WorkflowStatus{Terminated: true}},
+			done:     true,
+			exitCode: 1,
+		},
+		"suspending": {
+			status:   common.AppStatus{Phase: common.ApplicationWorkflowSuspending, Workflow: &common.WorkflowStatus{Suspend: true}},
+			done:     true,
+			exitCode: watchExitCodeSuspended,
+		},
+		"outdated": {
+			status:     common.AppStatus{Phase: common.ApplicationRunning, Workflow: &common.WorkflowStatus{Finished: true}, ObservedGeneration: 1},
+			generation: 2,
+		},
+	}
+	for name, tt := range testCases {
+		t.Run(name, func(t *testing.T) {
+			app := &v1beta1.Application{ObjectMeta: metav1.ObjectMeta{Name: "app", Generation: tt.generation}, Status: tt.status}
+			done, err := w.finished(app)
+			require.Equal(t, tt.done, done)
+			require.Equal(t, tt.exitCode, GetExitCode(err))
+		})
+	}
+
+	pending := &v1beta1.Application{ObjectMeta: metav1.ObjectMeta{Name: "pending", Namespace: "default"}, Status: common.AppStatus{Phase: common.ApplicationRunningWorkflow}}
+	r.NoError(cli.Create(ctx, pending))
+	w = newAppStatusWatcher(cli, cmdutil.IOStreams{Out: out}, "pending", "default", 10*time.Millisecond)
+	w.interval = time.Millisecond
+	r.Equal(watchExitCodeTimeout, GetExitCode(w.Run(ctx)))
+}
@@ -72,6 +72,7 @@ func launchUI(c common.Args, namespace string) error {
 		return err
 	}
 	app := view.NewApp(k8sClient, restConfig, namespace)
+	app.SetContainerExecutor(newContainerExecutor(c))
 	app.Init()
 
 	return app.Run()
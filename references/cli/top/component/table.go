@@ -17,7 +17,13 @@ limitations under the License.
 package component
 
 import (
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
 	"github.com/gdamore/tcell/v2"
+	"github.com/pkg/errors"
 	"github.com/rivo/tview"
 
 	"github.com/oam-dev/kubevela/references/cli/top/model"
@@ -27,6 +33,11 @@ import (
 type Table struct {
 	*tview.Table
 	actions model.KeyActions
+	header  []string
+	// filter is the expression to filter the rows, it is parsed with the header when the rows are filtered
+	filter     string
+	sortColumn string
+	sortDesc   bool
 }
 
 // NewTable return a new table component
@@ -79,3 +90,127 @@ func (t *Table) keyboard(event *tcell.EventKey) *tcell.EventKey {
 	}
 	return event
 }
+
+// SetHeader set the column names of the table which are used to filter and sort the rows
+func (t *Table) SetHeader(header []string) {
+	t.header = header
+}
+
+// SetFilter set the filter of the rows, the expression is a regex matching any column, or in the form
+// of "column:regex" to match the specified column, e.g. "phase:running|starting". An empty expression
+// clears the filter.
+func (t *Table) SetFilter(expression string) error {
+	if _, _, err := t.parseFilter(expression); err != nil {
+		return err
+	}
+	t.filter = expression
+	return nil
+}
+
+// Filter return the filter expression of the rows
+func (t *Table) Filter() string {
+	return t.filter
+}
+
+// SetSort set the column to sort the rows by, the rows are not sorted if the column is empty
+func (t *Table) SetSort(column string, desc bool) {
+	t.sortColumn, t.sortDesc = column, desc
+}
+
+// Sort return the column to sort the rows by and whether the order is descending
+func (t *Table) Sort() (string, bool) {
+	return t.sortColumn, t.sortDesc
+}
+
+// NextSortColumn return the next column of the sort column in the header, it cycles through all
+// columns and then returns empty which means the rows are not sorted
+func (t *Table) NextSortColumn() string {
+	index := t.columnIndex(t.sortColumn)
+	if t.sortColumn == "" {
+		index = -1
+	}
+	if index+1 >= len(t.header) {
+		return ""
+	}
+	return t.header[index+1]
+}
+
+// FilterAndSort return the rows matching the filter in the order of the sort column, the numeric
+// columns are compared by the value
+func (t *Table) FilterAndSort(body [][]string) [][]string {
+	filter, filterIndex, err := t.parseFilter(t.filter)
+	rows := make([][]string, 0, len(body))
+	for _, row := range body {
+		if filter == nil || err != nil || matchRow(filter, row, filterIndex) {
+			rows = append(rows, row)
+		}
+	}
+	sortIndex := t.columnIndex(t.sortColumn)
+	if sortIndex < 0 {
+		return rows
+	}
+	sort.SliceStable(rows, func(i, j int) bool {
+		a, b := cellText(rows[i], sortIndex), cellText(rows[j], sortIndex)
+		if t.sortDesc {
+			a, b = b, a
+		}
+		return lessText(a, b)
+	})
+	return rows
+}
+
+// parseFilter return the regex and the index of the column of the filter expression, the index is -1 if
+// the filter applies to any column
+func (t *Table) parseFilter(expression string) (*regexp.Regexp, int, error) {
+	if expression == "" {
+		return nil, -1, nil
+	}
+	column, regex := -1, expression
+	if i := strings.Index(expression, ":"); i > 0 {
+		if index := t.columnIndex(expression[:i]); index >= 0 {
+			column, regex = index, expression[i+1:]
+		}
+	}
+	filter, err := regexp.Compile("(?i)" + regex)
+	if err != nil {
+		return nil, -1, errors.Wrapf(err, "invalid filter %q", expression)
+	}
+	return filter, column, nil
+}
+
+func (t *Table) columnIndex(name string) int {
+	for i, column := range t.header {
+		if name != "" && strings.EqualFold(column, name) {
+			return i
+		}
+	}
+	return -1
+}
+
+func matchRow(filter *regexp.Regexp, row []string, column int) bool {
+	if column >= 0 {
+		return filter.MatchString(cellText(row, column))
+	}
+	for _, cell := range row {
+		if filter.MatchString(cell) {
+			return true
+		}
+	}
+	return false
+}
+
+func cellText(row []string, column int) string {
+	if column < len(row) {
+		return row[column]
+	}
+	return ""
+}
+
+func lessText(a, b string) bool {
+	x, errA := strconv.ParseFloat(a, 64)
+	y, errB := strconv.ParseFloat(b, 64)
+	if errA == nil && errB == nil {
+		return x < y
+	}
+	return a < b
+}
@@ -55,3 +55,47 @@ func TestTable(t *testing.T) {
 		})
 	})
 }
+
+func TestTableFilterAndSort(t *testing.T) {
+	table := NewTable()
+	table.SetHeader([]string{"Name", "Phase", "Replicas"})
+	body := [][]string{
+		{"app-b", "running", "10"},
+		{"app-a", "workflowFailed", "2"},
+		{"app-c", "running", "1"},
+	}
+	t.Run("no filter", func(t *testing.T) {
+		assert.Equal(t, table.FilterAndSort(body), body)
+	})
+	t.Run("filter any column", func(t *testing.T) {
+		assert.NoError(t, table.SetFilter("FAILED"))
+		assert.Equal(t, table.FilterAndSort(body), [][]string{{"app-a", "workflowFailed", "2"}})
+	})
+	t.Run("filter column", func(t *testing.T) {
+		assert.NoError(t, table.SetFilter("name:b|c"))
+		assert.Equal(t, table.FilterAndSort(body), [][]string{{"app-b", "running", "10"}, {"app-c", "running", "1"}})
+		assert.Equal(t, table.Filter(), "name:b|c")
+	})
+	t.Run("invalid filter", func(t *testing.T) {
+		assert.Error(t, table.SetFilter("app-("))
+		assert.Equal(t, table.Filter(), "name:b|c")
+		assert.NoError(t, table.SetFilter(""))
+	})
+	t.Run("sort", func(t *testing.T) {
+		table.SetSort("replicas", false)
+		assert.Equal(t, table.FilterAndSort(body), [][]string{{"app-c", "running", "1"}, {"app-a", "workflowFailed", "2"}, {"app-b", "running", "10"}})
+		table.SetSort("Name", true)
+		assert.Equal(t, table.FilterAndSort(body), [][]string{{"app-c", "running", "1"}, {"app-b", "running", "10"}, {"app-a", "workflowFailed", "2"}})
+		column, desc := table.Sort()
+		assert.Equal(t, column, "Name")
+		assert.True(t, desc)
+	})
+	t.Run("next sort column", func(t *testing.T) {
+		table.SetSort("", false)
+		assert.Equal(t, table.NextSortColumn(), "Name")
+		table.SetSort("Phase", false)
+		assert.Equal(t, table.NextSortColumn(), "Replicas")
+		table.SetSort("Replicas", false)
+		assert.Equal(t, table.NextSortColumn(), "")
+	})
+}
@@ -75,4 +75,16 @@ const (
 	ContainerRunningPhaseColor = "[green::]"
 	// ContainerTerminatedPhaseColor is container terminated phase text color
 	ContainerTerminatedPhaseColor = "[red::]"
+	// EventWarningTypeColor is warning event type text color
+	EventWarningTypeColor = "[red::]"
+	// EventNormalTypeColor is normal event type text color
+	EventNormalTypeColor = "[green::]"
+	// WorkflowStepSucceededPhaseColor is workflow step succeeded phase text color
+	WorkflowStepSucceededPhaseColor = "[green::]"
+	// WorkflowStepRunningPhaseColor is workflow step running phase text color
+	WorkflowStepRunningPhaseColor = "[blue::]"
+	// WorkflowStepFailedPhaseColor is workflow step failed phase text color
+	WorkflowStepFailedPhaseColor = "[red::]"
+	// WorkflowStepSkippedPhaseColor is workflow step skipped and pending phase text color
+	WorkflowStepSkippedPhaseColor = "[gray::]"
 )
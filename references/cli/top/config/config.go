@@ -16,9 +16,20 @@ limitations under the License.
 
 package config
 
-import "k8s.io/client-go/rest"
+import (
+	"context"
+
+	"k8s.io/client-go/rest"
+)
+
+// ContainerExecutor execute the command in the container of the pod with the terminal attached
+type ContainerExecutor func(ctx context.Context, cluster, namespace, pod, container string, command []string) error
 
 // Config application configs
 type Config struct {
 	RestConfig *rest.Config
+	// Exec is the executor to launch the shell in the container
+	Exec ContainerExecutor
+	// Filters are the saved filters of the views
+	Filters Filters
 }
@@ -0,0 +1,87 @@
+/*
+Copyright 2022 The KubeVela Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config
+
+import (
+	"os"
+	"path/filepath"
+
+	"sigs.k8s.io/yaml"
+
+	"github.com/oam-dev/kubevela/pkg/utils/system"
+)
+
+// filterFileName is the name of the file in vela home dir to save the filters
+const filterFileName = "top-filters.yaml"
+
+// Filter is the filter and sort of the table in a view, which is saved and restored in the next launch
+type Filter struct {
+	// Expression is the regex to filter the rows, in the form of "regex" or "column:regex"
+	Expression string `json:"expression,omitempty"`
+	// SortColumn is the column to sort the rows by
+	SortColumn string `json:"sortColumn,omitempty"`
+	// SortDesc indicates the rows are sorted in descending order
+	SortDesc bool `json:"sortDesc,omitempty"`
+	// LabelSelector filters the applications by labels
+	LabelSelector string `json:"labelSelector,omitempty"`
+	// Cluster filters the applications by the cluster they are deployed to
+	Cluster string `json:"cluster,omitempty"`
+}
+
+// Filters is the map from the view name to the filter of the view
+type Filters map[string]Filter
+
+// LoadFilters load the saved filters, it returns empty filters if no filter is saved
+func LoadFilters() (Filters, error) {
+	filters := Filters{}
+	path, err := filterFilePath()
+	if err != nil {
+		return filters, err
+	}
+	data, err := os.ReadFile(filepath.Clean(path))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return filters, nil
+		}
+		return filters, err
+	}
+	if err = yaml.Unmarshal(data, &filters); err != nil {
+		return Filters{}, err
+	}
+	return filters, nil
+}
+
+// Save the filters to the file in vela home dir
+func (f Filters) Save() error {
+	path, err := filterFilePath()
+	if err != nil {
+		return err
+	}
+	data, err := yaml.Marshal(f)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0600)
+}
+
+func filterFilePath() (string, error) {
+	home, err := system.GetVelaHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, filterFileName), nil
+}
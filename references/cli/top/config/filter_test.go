@@ -0,0 +1,36 @@
+/*
+Copyright 2022 The KubeVela Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFilters(t *testing.T) {
+	t.Setenv("VELA_HOME", t.TempDir())
+	filters, err := LoadFilters()
+	assert.NoError(t, err)
+	assert.Empty(t, filters)
+
+	filters["app"] = Filter{Expression: "phase:running", SortColumn: "Name", SortDesc: true, LabelSelector: "team=a", Cluster: "prod"}
+	assert.NoError(t, filters.Save())
+	loaded, err := LoadFilters()
+	assert.NoError(t, err)
+	assert.Equal(t, filters, loaded)
+}
@@ -21,10 +21,12 @@ import (
 	"fmt"
 
 	workflowv1alpha1 "github.com/kubevela/workflow/api/v1alpha1"
+	"k8s.io/apimachinery/pkg/labels"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	"github.com/oam-dev/kubevela/apis/core.oam.dev/common"
 	"github.com/oam-dev/kubevela/apis/core.oam.dev/v1beta1"
+	"github.com/oam-dev/kubevela/pkg/multicluster"
 	"github.com/oam-dev/kubevela/pkg/velaql/providers/query"
 	"github.com/oam-dev/kubevela/pkg/velaql/providers/query/types"
 	"github.com/oam-dev/kubevela/references/cli/top/utils"
@@ -57,20 +59,47 @@ func (l ApplicationList) ToTableBody() [][]string {
 func ListApplications(ctx context.Context, c client.Client) (ApplicationList, error) {
 	apps := v1beta1.ApplicationList{}
 	namespace := ctx.Value(&CtxKeyNamespace).(string)
+	opts := []client.ListOption{client.InNamespace(namespace)}
+	if selector, ok := ctx.Value(&CtxKeyAppLabelSelector).(string); ok && selector != "" {
+		labelSelector, err := labels.Parse(selector)
+		if err != nil {
+			return ApplicationList{}, err
+		}
+		opts = append(opts, client.MatchingLabelsSelector{Selector: labelSelector})
+	}
 
-	if err := c.List(ctx, &apps, client.InNamespace(namespace)); err != nil {
+	if err := c.List(ctx, &apps, opts...); err != nil {
 		return ApplicationList{}, err
 	}
-	appList := make(ApplicationList, len(apps.Items))
-	for index, app := range apps.Items {
-		appList[index] = Application{name: app.Name, namespace: app.Namespace, phase: string(app.Status.Phase), createTime: app.CreationTimestamp.String()}
-		appList[index].service = serviceNum(app)
-		appList[index].workflow = workflowStepNum(app)
-		appList[index].workflowMode = workflowMode(app)
+	cluster, _ := ctx.Value(&CtxKeyCluster).(string)
+	appList := make(ApplicationList, 0, len(apps.Items))
+	for _, app := range apps.Items {
+		if cluster != "" && !deployedToCluster(app, cluster) {
+			continue
+		}
+		application := Application{name: app.Name, namespace: app.Namespace, phase: string(app.Status.Phase), createTime: app.CreationTimestamp.String()}
+		application.service = serviceNum(app)
+		application.workflow = workflowStepNum(app)
+		application.workflowMode = workflowMode(app)
+		appList = append(appList, application)
 	}
 	return appList, nil
 }
 
+// deployedToCluster return whether the application has resources deployed to the cluster
+func deployedToCluster(app v1beta1.Application, cluster string) bool {
+	for _, res := range app.Status.AppliedResources {
+		resourceCluster := res.Cluster
+		if resourceCluster == "" {
+			resourceCluster = multicluster.ClusterLocalName
+		}
+		if resourceCluster == cluster {
+			return true
+		}
+	}
+	return false
+}
+
 // LoadApplication load the corresponding application according to name and namespace
 func LoadApplication(c client.Client, name, ns string) (*v1beta1.Application, error) {
 	app := new(v1beta1.Application)
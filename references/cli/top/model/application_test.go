@@ -23,6 +23,12 @@ import (
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
 	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	common2 "github.com/oam-dev/kubevela/apis/core.oam.dev/common"
+	"github.com/oam-dev/kubevela/apis/core.oam.dev/v1beta1"
+	"github.com/oam-dev/kubevela/pkg/utils/common"
 )
 
 func TestApplicationList_ToTableBody(t *testing.T) {
@@ -30,6 +36,40 @@ func TestApplicationList_ToTableBody(t *testing.T) {
 	assert.Equal(t, appList.ToTableBody(), [][]string{{"Name", "Namespace", "Phase", "", "", "", "CreateTime"}})
 }
 
+func TestListApplicationsWithFilter(t *testing.T) {
+	local := &v1beta1.Application{
+		ObjectMeta: metav1.ObjectMeta{Name: "local", Namespace: "default", Labels: map[string]string{"team": "a"}},
+		Status:     common2.AppStatus{AppliedResources: []common2.ClusterObjectReference{{}}},
+	}
+	remote := &v1beta1.Application{
+		ObjectMeta: metav1.ObjectMeta{Name: "remote", Namespace: "default", Labels: map[string]string{"team": "b"}},
+		Status:     common2.AppStatus{AppliedResources: []common2.ClusterObjectReference{{Cluster: "prod"}}},
+	}
+	c := fake.NewClientBuilder().WithScheme(common.Scheme).WithObjects(local, remote).Build()
+	list := func(selector, cluster string) []string {
+		ctx := context.WithValue(context.Background(), &CtxKeyNamespace, "default")
+		ctx = context.WithValue(ctx, &CtxKeyAppLabelSelector, selector)
+		ctx = context.WithValue(ctx, &CtxKeyCluster, cluster)
+		apps, err := ListApplications(ctx, c)
+		assert.NoError(t, err)
+		var names []string
+		for _, app := range apps {
+			names = append(names, app.name)
+		}
+		return names
+	}
+	assert.Equal(t, []string{"local", "remote"}, list("", ""))
+	assert.Equal(t, []string{"remote"}, list("team=b", ""))
+	assert.Equal(t, []string{"local"}, list("", "local"))
+	assert.Equal(t, []string{"remote"}, list("team in (a,b)", "prod"))
+	assert.Empty(t, list("team=a", "prod"))
+
+	ctx := context.WithValue(context.Background(), &CtxKeyNamespace, "default")
+	ctx = context.WithValue(ctx, &CtxKeyAppLabelSelector, "team in (")
+	_, err := ListApplications(ctx, c)
+	assert.Error(t, err)
+}
+
 var _ = Describe("test Application", func() {
 	ctx := context.Background()
 	ctx = context.WithValue(ctx, &CtxKeyNamespace, "")
@@ -0,0 +1,119 @@
+/*
+Copyright 2022 The KubeVela Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package model
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/oam-dev/kubevela/apis/core.oam.dev/v1beta1"
+	"github.com/oam-dev/kubevela/pkg/multicluster"
+	"github.com/oam-dev/kubevela/pkg/velaql/providers/query"
+	"github.com/oam-dev/kubevela/references/cli/top/utils"
+)
+
+// Event is the kubernetes event of the application or the resource of the application
+type Event struct {
+	time      time.Time
+	eventType string
+	reason    string
+	object    string
+	cluster   string
+	count     int32
+	message   string
+}
+
+// EventList is event list
+type EventList []Event
+
+// ListApplicationEvents list the events of the application and the resources in its resource tree from all
+// clusters the application is dispatched to, the events are in the order of time from the latest one. The
+// resource whose events can't be listed, e.g. the cluster is unreachable, is skipped.
+func ListApplicationEvents(ctx context.Context, c client.Client) (EventList, error) {
+	name := ctx.Value(&CtxKeyAppName).(string)
+	namespace := ctx.Value(&CtxKeyNamespace).(string)
+	app, err := LoadApplication(c, name, namespace)
+	if err != nil {
+		return EventList{}, err
+	}
+	appObj := &unstructured.Unstructured{}
+	appObj.SetAPIVersion(v1beta1.SchemeGroupVersion.String())
+	appObj.SetKind(v1beta1.ApplicationKind)
+	appObj.SetNamespace(app.Namespace)
+	appObj.SetName(app.Name)
+	appObj.SetUID(app.UID)
+
+	nodeEvents, err := query.NewAppCollector(c, query.Option{Name: app.Name, Namespace: app.Namespace, WithTree: true}).ListApplicationResourceEvents(ctx, app)
+	if err != nil {
+		return EventList{}, err
+	}
+
+	list := EventList{}
+	seen := map[string]bool{}
+	add := func(cluster string, events []corev1.Event) {
+		for _, event := range events {
+			key := fmt.Sprintf("%s/%s", cluster, event.UID)
+			if seen[key] {
+				continue
+			}
+			seen[key] = true
+			list = append(list, loadEvent(cluster, event))
+		}
+	}
+	if events, err := query.ListObjectEvents(ctx, c, multicluster.ClusterLocalName, appObj); err == nil {
+		add(multicluster.ClusterLocalName, events)
+	}
+	for _, ne := range nodeEvents {
+		if ne.Err == nil {
+			add(ne.Node.Cluster, ne.Events)
+		}
+	}
+	sort.SliceStable(list, func(i, j int) bool { return list[i].time.After(list[j].time) })
+	return list, nil
+}
+
+func loadEvent(cluster string, event corev1.Event) Event {
+	if cluster == "" {
+		cluster = multicluster.ClusterLocalName
+	}
+	return Event{
+		time:      query.EventTime(event),
+		eventType: event.Type,
+		reason:    event.Reason,
+		object:    fmt.Sprintf("%s/%s", event.InvolvedObject.Kind, event.InvolvedObject.Name),
+		cluster:   cluster,
+		count:     event.Count,
+		message:   strings.TrimSpace(event.Message),
+	}
+}
+
+// ToTableBody generate body of table in event view
+func (l EventList) ToTableBody() [][]string {
+	data := make([][]string, len(l))
+	for index, event := range l {
+		data[index] = []string{utils.TimeFormat(time.Since(event.time)), event.eventType, event.reason, event.object, event.cluster, strconv.Itoa(int(event.count)), event.message}
+	}
+	return data
+}
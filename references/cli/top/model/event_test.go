@@ -0,0 +1,67 @@
+/*
+Copyright 2022 The KubeVela Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package model
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/oam-dev/kubevela/apis/core.oam.dev/v1beta1"
+	"github.com/oam-dev/kubevela/pkg/utils/common"
+)
+
+func TestListApplicationEvents(t *testing.T) {
+	app := &v1beta1.Application{ObjectMeta: metav1.ObjectMeta{Name: "app", Namespace: "default", UID: "app-uid"}}
+	newEvent := func(name, eventType, reason string, last time.Time) *corev1.Event {
+		return &corev1.Event{
+			ObjectMeta:     metav1.ObjectMeta{Name: name, Namespace: "default", UID: types.UID("uid-" + name)},
+			InvolvedObject: corev1.ObjectReference{Kind: "Application", Name: "app", Namespace: "default", UID: "app-uid"},
+			Type:           eventType,
+			Reason:         reason,
+			Message:        " " + reason + " message ",
+			Count:          2,
+			LastTimestamp:  metav1.NewTime(last),
+		}
+	}
+	now := time.Now()
+	c := fake.NewClientBuilder().WithScheme(common.Scheme).WithObjects(
+		app,
+		newEvent("rendered", corev1.EventTypeNormal, "Rendered", now.Add(-time.Hour)),
+		newEvent("failed", corev1.EventTypeWarning, "FailedWorkflow", now.Add(-time.Minute)),
+	).Build()
+	ctx := context.WithValue(context.Background(), &CtxKeyAppName, "app")
+	ctx = context.WithValue(ctx, &CtxKeyNamespace, "default")
+
+	events, err := ListApplicationEvents(ctx, c)
+	assert.NoError(t, err)
+	body := events.ToTableBody()
+	assert.Equal(t, len(body), 2)
+	// the latest event comes first
+	assert.Equal(t, body[0][1:], []string{"Warning", "FailedWorkflow", "Application/app", "local", "2", "FailedWorkflow message"})
+	assert.Equal(t, body[1][1:], []string{"Normal", "Rendered", "Application/app", "local", "2", "Rendered message"})
+
+	ctx = context.WithValue(ctx, &CtxKeyAppName, "not-exist")
+	_, err = ListApplicationEvents(ctx, c)
+	assert.Error(t, err)
+}
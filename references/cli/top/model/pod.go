@@ -20,6 +20,7 @@ import (
 	"context"
 	"fmt"
 	"strconv"
+	"sync"
 	"time"
 
 	v1 "k8s.io/api/core/v1"
@@ -45,6 +46,8 @@ type Pod struct {
 	CPUL      string
 	MemR      string
 	MemL      string
+	CPUTrend  string
+	MemTrend  string
 	IP        string
 	NodeName  string
 	Age       string
@@ -53,6 +56,57 @@ type Pod struct {
 // PodList is pod list
 type PodList []Pod
 
+var (
+	// podMetrics keeps the rolling window of the usage metrics of the pods
+	podMetrics = utils.NewMetricStore(utils.MetricHistorySize)
+	// componentMetrics keeps the rolling window of the usage metrics summed up from the pods of the components
+	componentMetrics = utils.NewMetricStore(utils.MetricHistorySize)
+	// componentPods are the metric keys of the pods in the latest pod list of the components
+	componentPods   = map[string][]string{}
+	componentPodsMu sync.Mutex
+	// sampledComponents are the contexts of the components whose pods have been listed, the usage metrics of
+	// their pods are sampled in the background since then
+	sampledComponents   = map[string]context.Context{}
+	sampledComponentsMu sync.Mutex
+)
+
+// SampleMetrics sample the usage metrics of the pods of the components in the interval until the context is done,
+// so the rolling window of the metrics keeps growing while the pods of the components are not shown
+func SampleMetrics(ctx context.Context, cfg *rest.Config, c client.Client, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			sampledComponentsMu.Lock()
+			components := make([]context.Context, 0, len(sampledComponents))
+			for _, compCtx := range sampledComponents {
+				components = append(components, compCtx)
+			}
+			sampledComponentsMu.Unlock()
+			for _, compCtx := range components {
+				if ctx.Err() != nil {
+					return
+				}
+				_, _ = ListPods(compCtx, cfg, c)
+			}
+		}
+	}
+}
+
+// sampleComponent add the component in the context to be sampled in the background
+func sampleComponent(ctx context.Context) {
+	compCtx := context.Background()
+	for _, key := range []*string{&CtxKeyAppName, &CtxKeyNamespace, &CtxKeyCluster, &CtxKeyClusterNamespace, &CtxKeyComponentName} {
+		compCtx = context.WithValue(compCtx, key, ctx.Value(key))
+	}
+	sampledComponentsMu.Lock()
+	defer sampledComponentsMu.Unlock()
+	sampledComponents[componentMetricKey(ctx)] = compCtx
+}
+
 // ListPods return pod list of component
 func ListPods(ctx context.Context, cfg *rest.Config, c client.Client) (PodList, error) {
 	appName := ctx.Value(&CtxKeyAppName).(string)
@@ -87,9 +141,74 @@ func ListPods(ctx context.Context, cfg *rest.Config, c client.Client) (PodList,
 		}
 		list[index] = LoadPodDetail(cfg, pod)
 	}
+	recordComponentMetric(ctx, list)
+	evictPodMetrics(ctx, list)
+	sampleComponent(ctx)
 	return list, nil
 }
 
+// ComponentMetricHistory return the metric history of the component in the context, the history is recorded
+// when the pods of the component are listed
+func ComponentMetricHistory(ctx context.Context) utils.MetricHistory {
+	return componentMetrics.History(componentMetricKey(ctx))
+}
+
+// recordComponentMetric record the sum of the latest metrics of the pods as the metric of the component
+func recordComponentMetric(ctx context.Context, pods PodList) {
+	var sample utils.MetricSample
+	for _, pod := range pods {
+		history := podMetrics.History(podMetricKey(pod.Cluster, pod.Namespace, pod.Name))
+		if len(history) == 0 {
+			continue
+		}
+		latest := history[len(history)-1]
+		sample.CPU += latest.CPU
+		sample.Mem += latest.Mem
+		if latest.Time.After(sample.Time) {
+			sample.Time = latest.Time
+		}
+	}
+	if !sample.Time.IsZero() {
+		componentMetrics.Record(componentMetricKey(ctx), sample)
+	}
+}
+
+// evictPodMetrics remove the metric histories of the pods of the component absent from the latest pod list
+func evictPodMetrics(ctx context.Context, pods PodList) {
+	componentKey := componentMetricKey(ctx)
+	var keys []string
+	latest := map[string]bool{}
+	for _, pod := range pods {
+		if pod.Name == "" {
+			continue
+		}
+		key := podMetricKey(pod.Cluster, pod.Namespace, pod.Name)
+		keys = append(keys, key)
+		latest[key] = true
+	}
+	componentPodsMu.Lock()
+	defer componentPodsMu.Unlock()
+	for _, key := range componentPods[componentKey] {
+		if !latest[key] {
+			podMetrics.Delete(key)
+		}
+	}
+	componentPods[componentKey] = keys
+}
+
+func componentMetricKey(ctx context.Context) string {
+	appName, _ := ctx.Value(&CtxKeyAppName).(string)
+	appNamespace, _ := ctx.Value(&CtxKeyNamespace).(string)
+	compCluster, _ := ctx.Value(&CtxKeyCluster).(string)
+	compNamespace, _ := ctx.Value(&CtxKeyClusterNamespace).(string)
+	compName, _ := ctx.Value(&CtxKeyComponentName).(string)
+	return fmt.Sprintf("%s/%s/%s/%s/%s", appNamespace, appName, compCluster, compNamespace, compName)
+}
+
+func podMetricKey(cluster, namespace, name string) string {
+	return fmt.Sprintf("%s/%s/%s", cluster, namespace, name)
+}
+
 // LoadPodDetail gather the pod detail info
 func LoadPodDetail(cfg *rest.Config, pod *v1.Pod) Pod {
 	podInfo := Pod{
@@ -106,6 +225,8 @@ func LoadPodDetail(cfg *rest.Config, pod *v1.Pod) Pod {
 	if len(podInfo.Cluster) == 0 {
 		podInfo.Cluster = types.ClusterLocalName
 	}
+	key := podMetricKey(podInfo.Cluster, pod.Namespace, pod.Name)
+	history := podMetrics.History(key)
 	metric, err := utils.PodMetric(cfg, pod.Name, pod.Namespace)
 	if err != nil {
 		podInfo.CPU, podInfo.Mem, podInfo.CPUL, podInfo.MemL, podInfo.CPUR, podInfo.MemR = utils.NA, utils.NA, utils.NA, utils.NA, utils.NA, utils.NA
@@ -116,7 +237,9 @@ func LoadPodDetail(cfg *rest.Config, pod *v1.Pod) Pod {
 		podInfo.MemR = utils.ToPercentageStr(c.Mem, r.Mem)
 		podInfo.CPUL = utils.ToPercentageStr(c.CPU, r.Lcpu)
 		podInfo.MemL = utils.ToPercentageStr(c.CPU, r.Lmem)
+		history = podMetrics.Record(key, utils.MetricSample{Time: metric.Timestamp.Time, CPU: c.CPU, Mem: c.Mem})
 	}
+	podInfo.CPUTrend, podInfo.MemTrend = utils.Sparkline(history.CPU()), utils.Sparkline(history.Mem())
 
 	return podInfo
 }
@@ -136,7 +259,7 @@ func readyContainerNum(pod *v1.Pod) string {
 func (l PodList) ToTableBody() [][]string {
 	data := make([][]string, len(l))
 	for index, pod := range l {
-		data[index] = []string{pod.Name, pod.Namespace, pod.Cluster, pod.Ready, pod.Status, pod.CPU, pod.Mem, pod.CPUR, pod.MemR, pod.CPUL, pod.MemL, pod.IP, pod.NodeName, pod.Age}
+		data[index] = []string{pod.Name, pod.Namespace, pod.Cluster, pod.Ready, pod.Status, pod.CPU, pod.Mem, pod.CPUR, pod.MemR, pod.CPUL, pod.MemL, pod.CPUTrend, pod.MemTrend, pod.IP, pod.NodeName, pod.Age}
 	}
 	return data
 }
@@ -26,6 +26,10 @@ import (
 	"github.com/stretchr/testify/assert"
 	v1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/oam-dev/kubevela/pkg/utils/common"
+	"github.com/oam-dev/kubevela/references/cli/top/utils"
 )
 
 func TestPodList_ToTableBody(t *testing.T) {
@@ -49,6 +53,70 @@ func TestPodList_ToTableBody(t *testing.T) {
 	assert.Equal(t, len(podList.ToTableBody()), 1)
 }
 
+func TestComponentMetricHistory(t *testing.T) {
+	ctx := context.WithValue(context.Background(), &CtxKeyAppName, "app")
+	ctx = context.WithValue(ctx, &CtxKeyNamespace, "default")
+	ctx = context.WithValue(ctx, &CtxKeyCluster, "local")
+	ctx = context.WithValue(ctx, &CtxKeyClusterNamespace, "default")
+	ctx = context.WithValue(ctx, &CtxKeyComponentName, "web")
+	pods := PodList{{Name: "web-1", Namespace: "default", Cluster: "local"}, {Name: "web-2", Namespace: "default", Cluster: "local"}}
+
+	// no metric is sampled
+	recordComponentMetric(ctx, pods)
+	assert.Empty(t, ComponentMetricHistory(ctx))
+
+	now := time.Now()
+	for i := 0; i < 2; i++ {
+		sampleTime := now.Add(time.Duration(i) * time.Minute)
+		podMetrics.Record(podMetricKey("local", "default", "web-1"), utils.MetricSample{Time: sampleTime, CPU: 10, Mem: 100})
+		podMetrics.Record(podMetricKey("local", "default", "web-2"), utils.MetricSample{Time: sampleTime, CPU: int64(i), Mem: 50})
+		recordComponentMetric(ctx, pods)
+	}
+	// the metrics are not refreshed since the last sample
+	recordComponentMetric(ctx, pods)
+	history := ComponentMetricHistory(ctx)
+	assert.Equal(t, history.CPU(), []int64{10, 11})
+	assert.Equal(t, history.Mem(), []int64{150, 150})
+
+	// the metrics of the pods absent from the latest list are evicted
+	evictPodMetrics(ctx, pods)
+	evictPodMetrics(ctx, pods[:1])
+	assert.NotEmpty(t, podMetrics.History(podMetricKey("local", "default", "web-1")))
+	assert.Empty(t, podMetrics.History(podMetricKey("local", "default", "web-2")))
+}
+
+func TestSampleMetrics(t *testing.T) {
+	ctx := context.WithValue(context.Background(), &CtxKeyAppName, "app")
+	ctx = context.WithValue(ctx, &CtxKeyNamespace, "default")
+	ctx = context.WithValue(ctx, &CtxKeyCluster, "local")
+	ctx = context.WithValue(ctx, &CtxKeyClusterNamespace, "default")
+	ctx = context.WithValue(ctx, &CtxKeyComponentName, "sampled")
+	sampleComponent(ctx)
+	defer func() {
+		sampledComponentsMu.Lock()
+		delete(sampledComponents, componentMetricKey(ctx))
+		sampledComponentsMu.Unlock()
+	}()
+	sampledComponentsMu.Lock()
+	compCtx := sampledComponents[componentMetricKey(ctx)]
+	sampledComponentsMu.Unlock()
+	assert.Equal(t, componentMetricKey(ctx), componentMetricKey(compCtx))
+
+	// the sampler stops once the context is done
+	samplerCtx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	done := make(chan struct{})
+	go func() {
+		SampleMetrics(samplerCtx, nil, fake.NewClientBuilder().WithScheme(common.Scheme).Build(), time.Millisecond)
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("the sampler is not stopped")
+	}
+}
+
 var _ = Describe("test pod", func() {
 	ctx := context.Background()
 	ctx = context.WithValue(ctx, &CtxKeyAppName, "first-vela-app")
@@ -0,0 +1,257 @@
+/*
+Copyright 2022 The KubeVela Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package model
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	authorizationv1 "k8s.io/api/authorization/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/yaml"
+
+	"github.com/oam-dev/kubevela/pkg/multicluster"
+)
+
+// ResourceAction is the action which can be performed on the resource in the UI
+type ResourceAction struct {
+	Verb        string
+	Subresource string
+}
+
+var (
+	// RestartAction restart the pods of the workload by patching the pod template
+	RestartAction = ResourceAction{Verb: "patch"}
+	// ScaleAction change the replicas of the workload
+	ScaleAction = ResourceAction{Verb: "patch"}
+	// DeleteAction delete the resource
+	DeleteAction = ResourceAction{Verb: "delete"}
+	// EditAction edit the manifest of the resource
+	EditAction = ResourceAction{Verb: "update"}
+	// ExecAction execute the shell in the container of the pod
+	ExecAction = ResourceAction{Verb: "create", Subresource: "exec"}
+)
+
+// restartableKinds are the kinds of workload which can be restarted by updating the pod template
+var restartableKinds = map[string]bool{"Deployment": true, "StatefulSet": true, "DaemonSet": true}
+
+// scalableKinds are the kinds of workload which have the replicas field
+var scalableKinds = map[string]bool{"Deployment": true, "StatefulSet": true, "ReplicaSet": true}
+
+const (
+	// restartedAtAnnotation is the annotation updated by `kubectl rollout restart`
+	restartedAtAnnotation = "kubectl.kubernetes.io/restartedAt"
+	// accessReviewTTL is how long the result of the access review is cached
+	accessReviewTTL = time.Minute
+)
+
+// DefaultShellCommand is the command to launch the shell in the container, the first available shell is used
+var DefaultShellCommand = []string{"sh", "-c", "clear; (bash || ash || sh)"}
+
+type accessReview struct {
+	allowed bool
+	expire  time.Time
+}
+
+var (
+	accessReviews        = map[string]accessReview{}
+	pendingAccessReviews = map[string]bool{}
+	accessReviewsMu      sync.Mutex
+)
+
+// IsRestartable return whether the resource is a workload that can be restarted
+func IsRestartable(gvr *GVR) bool {
+	return restartableKinds[gvr.R.Kind]
+}
+
+// IsScalable return whether the resource is a workload that can be scaled
+func IsScalable(gvr *GVR) bool {
+	return scalableKinds[gvr.R.Kind]
+}
+
+// CanI return whether the current user is allowed to perform the action on the kind of resource in its namespace,
+// the result is checked by the SelfSubjectAccessReview in the cluster of the resource and cached for a while. The action
+// is not allowed if the access can't be reviewed.
+func CanI(c client.Client, gvr *GVR, action ResourceAction) bool {
+	key := accessReviewKey(gvr, action)
+	accessReviewsMu.Lock()
+	review, ok := accessReviews[key]
+	accessReviewsMu.Unlock()
+	if ok && time.Now().Before(review.expire) {
+		return review.allowed
+	}
+	allowed := reviewAccess(c, gvr, action)
+	accessReviewsMu.Lock()
+	accessReviews[key] = accessReview{allowed: allowed, expire: time.Now().Add(accessReviewTTL)}
+	accessReviewsMu.Unlock()
+	return allowed
+}
+
+// CanIAsync return the cached result of CanI without blocking the caller. If the result is not cached, the access
+// is reviewed in the background and the done func is called once the result is ready, the action is not allowed
+// until then.
+func CanIAsync(c client.Client, gvr *GVR, action ResourceAction, done func()) bool {
+	key := accessReviewKey(gvr, action)
+	accessReviewsMu.Lock()
+	defer accessReviewsMu.Unlock()
+	if review, ok := accessReviews[key]; ok && time.Now().Before(review.expire) {
+		return review.allowed
+	}
+	if !pendingAccessReviews[key] {
+		pendingAccessReviews[key] = true
+		go func() {
+			CanI(c, gvr, action)
+			accessReviewsMu.Lock()
+			delete(pendingAccessReviews, key)
+			accessReviewsMu.Unlock()
+			done()
+		}()
+	}
+	return false
+}
+
+func accessReviewKey(gvr *GVR, action ResourceAction) string {
+	return fmt.Sprintf("%s/%s/%s/%s/%s/%s", gvr.R.Cluster, gvr.R.Namespace, gvr.GV, gvr.R.Kind, action.Subresource, action.Verb)
+}
+
+func reviewAccess(c client.Client, gvr *GVR, action ResourceAction) bool {
+	gv, err := schema.ParseGroupVersion(gvr.GV)
+	if err != nil {
+		return false
+	}
+	mapping, err := c.RESTMapper().RESTMapping(gv.WithKind(gvr.R.Kind).GroupKind(), gv.Version)
+	if err != nil {
+		return false
+	}
+	sar := &authorizationv1.SelfSubjectAccessReview{Spec: authorizationv1.SelfSubjectAccessReviewSpec{ResourceAttributes: &authorizationv1.ResourceAttributes{
+		Namespace:   gvr.R.Namespace,
+		Verb:        action.Verb,
+		Group:       gv.Group,
+		Version:     gv.Version,
+		Resource:    mapping.Resource.Resource,
+		Subresource: action.Subresource,
+	}}}
+	ctx := multicluster.ContextWithClusterName(context.Background(), gvr.R.Cluster)
+	if err = c.Create(ctx, sar); err != nil {
+		return false
+	}
+	return sar.Status.Allowed
+}
+
+// RestartWorkload restart the pods of the workload in the same way as `kubectl rollout restart`
+func RestartWorkload(c client.Client, gvr *GVR) error {
+	if !IsRestartable(gvr) {
+		return errors.Errorf("%s %s can't be restarted, only %s are supported", gvr.R.Kind, gvr.R.Name, "Deployment, StatefulSet and DaemonSet")
+	}
+	patch := fmt.Sprintf(`{"spec":{"template":{"metadata":{"annotations":{%q:%q}}}}}`, restartedAtAnnotation, time.Now().Format(time.RFC3339))
+	return patchResource(c, gvr, patch)
+}
+
+// ScaleWorkload change the replicas of the workload
+func ScaleWorkload(c client.Client, gvr *GVR, replicas int32) error {
+	if !IsScalable(gvr) {
+		return errors.Errorf("%s %s can't be scaled, only %s are supported", gvr.R.Kind, gvr.R.Name, "Deployment, StatefulSet and ReplicaSet")
+	}
+	if replicas < 0 {
+		return errors.Errorf("the replicas %d must not be negative", replicas)
+	}
+	return patchResource(c, gvr, fmt.Sprintf(`{"spec":{"replicas":%d}}`, replicas))
+}
+
+// WorkloadReplicas return the replicas in the spec of the workload
+func WorkloadReplicas(c client.Client, gvr *GVR) (int64, error) {
+	obj, err := getUnstructured(c, gvr)
+	if err != nil {
+		return 0, err
+	}
+	replicas, _, err := unstructured.NestedInt64(obj.Object, "spec", "replicas")
+	return replicas, err
+}
+
+// DeleteResource delete the resource
+func DeleteResource(c client.Client, gvr *GVR) error {
+	obj := new(unstructured.Unstructured)
+	obj.SetAPIVersion(gvr.GV)
+	obj.SetKind(gvr.R.Kind)
+	obj.SetName(gvr.R.Name)
+	obj.SetNamespace(gvr.R.Namespace)
+	ctx := multicluster.ContextWithClusterName(context.Background(), gvr.R.Cluster)
+	return client.IgnoreNotFound(c.Delete(ctx, obj))
+}
+
+// EditResource edit the yaml manifest of the resource with the edit func and update the resource with the
+// edited manifest. The resource version of the opened manifest is kept, so the update fails if the resource
+// has been changed by others during the editing. It returns false if the manifest is not changed.
+func EditResource(c client.Client, gvr *GVR, edit func(manifest []byte) ([]byte, error)) (bool, error) {
+	obj, err := getUnstructured(c, gvr)
+	if err != nil {
+		return false, err
+	}
+	original, err := yaml.Marshal(obj.Object)
+	if err != nil {
+		return false, err
+	}
+	edited, err := edit(original)
+	if err != nil {
+		return false, err
+	}
+	if bytes.Equal(bytes.TrimSpace(original), bytes.TrimSpace(edited)) {
+		return false, nil
+	}
+	updated := new(unstructured.Unstructured)
+	if err = yaml.Unmarshal(edited, &updated.Object); err != nil {
+		return false, errors.Wrap(err, "the edited manifest is invalid")
+	}
+	if updated.GetAPIVersion() != obj.GetAPIVersion() || updated.GetKind() != obj.GetKind() || updated.GetName() != obj.GetName() || updated.GetNamespace() != obj.GetNamespace() {
+		return false, errors.New("the apiVersion, kind, name and namespace of the resource can't be changed")
+	}
+	updated.SetResourceVersion(obj.GetResourceVersion())
+	ctx := multicluster.ContextWithClusterName(context.Background(), gvr.R.Cluster)
+	if err = c.Update(ctx, updated); err != nil {
+		if kerrors.IsConflict(err) {
+			return false, errors.Errorf("%s %s has been modified since it was opened, please edit it again", gvr.R.Kind, gvr.R.Name)
+		}
+		return false, err
+	}
+	return true, nil
+}
+
+func patchResource(c client.Client, gvr *GVR, patch string) error {
+	obj := new(unstructured.Unstructured)
+	obj.SetAPIVersion(gvr.GV)
+	obj.SetKind(gvr.R.Kind)
+	obj.SetName(gvr.R.Name)
+	obj.SetNamespace(gvr.R.Namespace)
+	ctx := multicluster.ContextWithClusterName(context.Background(), gvr.R.Cluster)
+	return c.Patch(ctx, obj, client.RawPatch(types.MergePatchType, []byte(patch)))
+}
+
+func getUnstructured(c client.Client, gvr *GVR) (*unstructured.Unstructured, error) {
+	obj, err := GetResourceObject(c, gvr)
+	if err != nil {
+		return nil, err
+	}
+	return obj.(*unstructured.Unstructured), nil
+}
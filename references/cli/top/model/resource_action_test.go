@@ -0,0 +1,143 @@
+/*
+Copyright 2022 The KubeVela Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package model
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	appsv1 "k8s.io/api/apps/v1"
+	authorizationv1 "k8s.io/api/authorization/v1"
+	v1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/pointer"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/oam-dev/kubevela/pkg/utils/common"
+)
+
+func TestResourceActions(t *testing.T) {
+	ctx := context.Background()
+	deploy := &appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "default"}, Spec: appsv1.DeploymentSpec{Replicas: pointer.Int32(1)}}
+	pod := &v1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "web-1", Namespace: "default"}}
+	c := fake.NewClientBuilder().WithScheme(common.Scheme).WithObjects(deploy, pod).Build()
+	deployGVR := &GVR{GV: "apps/v1", R: Resource{Kind: "Deployment", Name: "web", Namespace: "default"}}
+	podGVR := &GVR{GV: "v1", R: Resource{Kind: "Pod", Name: "web-1", Namespace: "default"}}
+
+	t.Run("support", func(t *testing.T) {
+		assert.True(t, IsRestartable(deployGVR))
+		assert.True(t, IsScalable(deployGVR))
+		assert.False(t, IsRestartable(podGVR))
+		assert.False(t, IsScalable(podGVR))
+		assert.Error(t, RestartWorkload(c, podGVR))
+		assert.Error(t, ScaleWorkload(c, podGVR, 1))
+	})
+
+	t.Run("access review", func(t *testing.T) {
+		mapper := meta.NewDefaultRESTMapper(nil)
+		mapper.Add(v1.SchemeGroupVersion.WithKind("Pod"), meta.RESTScopeNamespace)
+		reviewer := &accessReviewClient{Client: c, mapper: mapper, allowed: map[string]bool{"delete": true}}
+		assert.True(t, CanI(reviewer, podGVR, DeleteAction))
+		assert.False(t, CanI(reviewer, podGVR, ExecAction))
+		// the result is cached
+		reviewer.allowed["create"] = true
+		assert.False(t, CanI(reviewer, podGVR, ExecAction))
+		assert.Equal(t, 2, reviewer.reviews)
+		// the action is not allowed if the access can't be reviewed
+		assert.False(t, CanI(reviewer, deployGVR, DeleteAction))
+
+		// the access is reviewed in the background
+		otherGVR := &GVR{GV: "v1", R: Resource{Kind: "Pod", Name: "web-1", Namespace: "other"}}
+		done := make(chan struct{})
+		assert.False(t, CanIAsync(reviewer, otherGVR, DeleteAction, func() { close(done) }))
+		<-done
+		assert.True(t, CanIAsync(reviewer, otherGVR, DeleteAction, func() { t.Fatal("the result should be cached") }))
+		assert.Equal(t, 3, reviewer.reviews)
+	})
+
+	t.Run("restart", func(t *testing.T) {
+		assert.NoError(t, RestartWorkload(c, deployGVR))
+		assert.NoError(t, c.Get(ctx, client.ObjectKeyFromObject(deploy), deploy))
+		assert.NotEmpty(t, deploy.Spec.Template.Annotations[restartedAtAnnotation])
+	})
+
+	t.Run("scale", func(t *testing.T) {
+		assert.Error(t, ScaleWorkload(c, deployGVR, -1))
+		assert.NoError(t, ScaleWorkload(c, deployGVR, 3))
+		replicas, err := WorkloadReplicas(c, deployGVR)
+		assert.NoError(t, err)
+		assert.Equal(t, int64(3), replicas)
+	})
+
+	t.Run("edit", func(t *testing.T) {
+		changed, err := EditResource(c, deployGVR, func(manifest []byte) ([]byte, error) { return manifest, nil })
+		assert.NoError(t, err)
+		assert.False(t, changed)
+
+		changed, err = EditResource(c, deployGVR, func(manifest []byte) ([]byte, error) {
+			return bytes.Replace(manifest, []byte("replicas: 3"), []byte("replicas: 5"), 1), nil
+		})
+		assert.NoError(t, err)
+		assert.True(t, changed)
+		assert.NoError(t, c.Get(ctx, client.ObjectKeyFromObject(deploy), deploy))
+		assert.Equal(t, int32(5), *deploy.Spec.Replicas)
+
+		_, err = EditResource(c, deployGVR, func(manifest []byte) ([]byte, error) {
+			return bytes.Replace(manifest, []byte("name: web"), []byte("name: other"), 1), nil
+		})
+		assert.Error(t, err)
+
+		// the resource is changed by others during the editing
+		_, err = EditResource(c, deployGVR, func(manifest []byte) ([]byte, error) {
+			assert.NoError(t, ScaleWorkload(c, deployGVR, 2))
+			return bytes.Replace(manifest, []byte("replicas: 5"), []byte("replicas: 4"), 1), nil
+		})
+		assert.Contains(t, err.Error(), "has been modified since it was opened")
+	})
+
+	t.Run("delete", func(t *testing.T) {
+		assert.NoError(t, DeleteResource(c, podGVR))
+		assert.True(t, kerrors.IsNotFound(c.Get(ctx, client.ObjectKeyFromObject(pod), pod)))
+		assert.NoError(t, DeleteResource(c, podGVR))
+	})
+}
+
+// accessReviewClient reviews the access by the allowed verbs
+type accessReviewClient struct {
+	client.Client
+	mapper  meta.RESTMapper
+	allowed map[string]bool
+	reviews int
+}
+
+func (c *accessReviewClient) RESTMapper() meta.RESTMapper {
+	return c.mapper
+}
+
+func (c *accessReviewClient) Create(ctx context.Context, obj client.Object, opts ...client.CreateOption) error {
+	if sar, ok := obj.(*authorizationv1.SelfSubjectAccessReview); ok {
+		c.reviews++
+		sar.Status.Allowed = c.allowed[sar.Spec.ResourceAttributes.Verb]
+		return nil
+	}
+	return c.Client.Create(ctx, obj, opts...)
+}
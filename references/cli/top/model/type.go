@@ -66,6 +66,8 @@ var (
 	CtxKeyPod = "pod"
 	// CtxKeyContainer request context key of container
 	CtxKeyContainer = "container"
+	// CtxKeyAppLabelSelector request context key of the label selector of applications
+	CtxKeyAppLabelSelector = "appLabelSelector"
 )
 
 const (
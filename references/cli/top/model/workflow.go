@@ -0,0 +1,200 @@
+/*
+Copyright 2022 The KubeVela Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package model
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	workflowv1alpha1 "github.com/kubevela/workflow/api/v1alpha1"
+	wfUtils "github.com/kubevela/workflow/pkg/utils"
+	"github.com/pkg/errors"
+	"k8s.io/client-go/util/retry"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/oam-dev/kubevela/apis/core.oam.dev/v1beta1"
+	"github.com/oam-dev/kubevela/pkg/workflow/operation"
+	"github.com/oam-dev/kubevela/references/cli/top/utils"
+)
+
+// SubStepPrefix is the prefix of the name of sub-step in workflow view
+const SubStepPrefix = "└─ "
+
+// WorkflowStep is the step or sub-step of the workflow of application
+type WorkflowStep struct {
+	name     string
+	stepType string
+	phase    string
+	duration string
+	message  string
+	outputs  string
+}
+
+// WorkflowStepList is workflow step list
+type WorkflowStepList []WorkflowStep
+
+// ToTableBody generate body of table in workflow view
+func (l WorkflowStepList) ToTableBody() [][]string {
+	data := make([][]string, len(l))
+	for index, step := range l {
+		data[index] = []string{step.name, step.stepType, step.phase, step.duration, step.message, step.outputs}
+	}
+	return data
+}
+
+// ListWorkflowSteps list the steps and sub-steps of the workflow of the application
+func ListWorkflowSteps(ctx context.Context, c client.Client) (WorkflowStepList, error) {
+	app, err := loadWorkflowApplication(ctx, c)
+	if err != nil {
+		return WorkflowStepList{}, err
+	}
+	if app.Status.Workflow == nil {
+		return WorkflowStepList{}, nil
+	}
+	outputs := workflowStepOutputs(app)
+	list := WorkflowStepList{}
+	for _, step := range app.Status.Workflow.Steps {
+		list = append(list, loadWorkflowStep(step.StepStatus, "", outputs))
+		for _, sub := range step.SubStepsStatus {
+			list = append(list, loadWorkflowStep(sub, SubStepPrefix, outputs))
+		}
+	}
+	return list, nil
+}
+
+// WorkflowPhase return the phase of the workflow of the application
+func WorkflowPhase(ctx context.Context, c client.Client) string {
+	app, err := loadWorkflowApplication(ctx, c)
+	if err != nil || app.Status.Workflow == nil {
+		return Unknown
+	}
+	switch {
+	case app.Status.Workflow.Terminated:
+		return string(workflowv1alpha1.WorkflowStateTerminated)
+	case app.Status.Workflow.Suspend:
+		return string(workflowv1alpha1.WorkflowStateSuspending)
+	case app.Status.Workflow.Phase != "":
+		return string(app.Status.Workflow.Phase)
+	default:
+		return string(workflowv1alpha1.WorkflowStateExecuting)
+	}
+}
+
+// SuspendWorkflow suspend the workflow of the application
+func SuspendWorkflow(ctx context.Context, c client.Client) error {
+	return operateWorkflow(ctx, c, func(operator wfUtils.WorkflowOperator) error { return operator.Suspend(ctx) })
+}
+
+// ResumeWorkflow resume the suspending workflow of the application
+func ResumeWorkflow(ctx context.Context, c client.Client) error {
+	return operateWorkflow(ctx, c, func(operator wfUtils.WorkflowOperator) error { return operator.Resume(ctx) })
+}
+
+// TerminateWorkflow terminate the workflow of the application
+func TerminateWorkflow(ctx context.Context, c client.Client) error {
+	return operateWorkflow(ctx, c, func(operator wfUtils.WorkflowOperator) error { return operator.Terminate(ctx) })
+}
+
+// RestartWorkflow restart the workflow of the application from the first step
+func RestartWorkflow(ctx context.Context, c client.Client) error {
+	return operateWorkflow(ctx, c, func(operator wfUtils.WorkflowOperator) error { return operator.Restart(ctx) })
+}
+
+// RetryWorkflowStep retry the failed step or sub-step of the workflow
+func RetryWorkflowStep(ctx context.Context, c client.Client, name string) error {
+	return retry.RetryOnConflict(retry.DefaultBackoff, func() error {
+		app, err := loadWorkflowApplication(ctx, c)
+		if err != nil {
+			return err
+		}
+		return operation.RetryWorkflowStep(ctx, c, app, strings.TrimPrefix(name, SubStepPrefix))
+	})
+}
+
+func operateWorkflow(ctx context.Context, c client.Client, operate func(operator wfUtils.WorkflowOperator) error) error {
+	return retry.RetryOnConflict(retry.DefaultBackoff, func() error {
+		app, err := loadWorkflowApplication(ctx, c)
+		if err != nil {
+			return err
+		}
+		if app.Status.Workflow == nil {
+			return errors.Errorf("the workflow of application %s is not running", app.Name)
+		}
+		return operate(operation.NewApplicationWorkflowOperator(c, nil, app))
+	})
+}
+
+func loadWorkflowApplication(ctx context.Context, c client.Client) (*v1beta1.Application, error) {
+	name := ctx.Value(&CtxKeyAppName).(string)
+	namespace := ctx.Value(&CtxKeyNamespace).(string)
+	app := new(v1beta1.Application)
+	if err := c.Get(ctx, client.ObjectKey{Name: name, Namespace: namespace}, app); err != nil {
+		return nil, err
+	}
+	return app, nil
+}
+
+func loadWorkflowStep(status workflowv1alpha1.StepStatus, prefix string, outputs map[string]string) WorkflowStep {
+	step := WorkflowStep{
+		name:     prefix + status.Name,
+		stepType: status.Type,
+		phase:    string(status.Phase),
+		duration: utils.NA,
+		message:  status.Message,
+		outputs:  outputs[status.Name],
+	}
+	if step.phase == "" {
+		step.phase = string(workflowv1alpha1.WorkflowStepPhasePending)
+	}
+	if step.message == "" && status.Reason != "" {
+		step.message = status.Reason
+	}
+	if step.outputs == "" {
+		step.outputs = utils.NA
+	}
+	if !status.FirstExecuteTime.IsZero() {
+		end := status.LastExecuteTime.Time
+		if status.Phase == workflowv1alpha1.WorkflowStepPhaseRunning || end.IsZero() {
+			end = time.Now()
+		}
+		step.duration = utils.TimeFormat(end.Sub(status.FirstExecuteTime.Time))
+	}
+	return step
+}
+
+// workflowStepOutputs return the names of the outputs of each step and sub-step in the workflow spec
+func workflowStepOutputs(app *v1beta1.Application) map[string]string {
+	outputs := map[string]string{}
+	if app.Spec.Workflow == nil {
+		return outputs
+	}
+	join := func(items workflowv1alpha1.StepOutputs) string {
+		names := make([]string, len(items))
+		for i, item := range items {
+			names[i] = item.Name
+		}
+		return strings.Join(names, ",")
+	}
+	for _, step := range app.Spec.Workflow.Steps {
+		outputs[step.Name] = join(step.Outputs)
+		for _, sub := range step.SubSteps {
+			outputs[sub.Name] = join(sub.Outputs)
+		}
+	}
+	return outputs
+}
@@ -0,0 +1,89 @@
+/*
+Copyright 2022 The KubeVela Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package model
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	workflowv1alpha1 "github.com/kubevela/workflow/api/v1alpha1"
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	common2 "github.com/oam-dev/kubevela/apis/core.oam.dev/common"
+	"github.com/oam-dev/kubevela/apis/core.oam.dev/v1beta1"
+	"github.com/oam-dev/kubevela/pkg/utils/common"
+)
+
+func TestWorkflowSteps(t *testing.T) {
+	start := metav1.NewTime(time.Now().Add(-time.Minute))
+	end := metav1.NewTime(start.Add(30 * time.Second))
+	app := &v1beta1.Application{
+		ObjectMeta: metav1.ObjectMeta{Name: "app", Namespace: "default"},
+		Spec: v1beta1.ApplicationSpec{Workflow: &v1beta1.Workflow{Steps: []workflowv1alpha1.WorkflowStep{
+			{WorkflowStepBase: workflowv1alpha1.WorkflowStepBase{Name: "apply", Type: "apply-component", Outputs: workflowv1alpha1.StepOutputs{{Name: "ip"}, {Name: "port"}}}},
+			{WorkflowStepBase: workflowv1alpha1.WorkflowStepBase{Name: "group", Type: "step-group"}, SubSteps: []workflowv1alpha1.WorkflowStepBase{{Name: "notify", Type: "notification"}}},
+		}}},
+		Status: common2.AppStatus{Workflow: &common2.WorkflowStatus{Terminated: true, Steps: []workflowv1alpha1.WorkflowStepStatus{
+			{StepStatus: workflowv1alpha1.StepStatus{Name: "apply", Type: "apply-component", Phase: workflowv1alpha1.WorkflowStepPhaseSucceeded, FirstExecuteTime: start, LastExecuteTime: end}},
+			{
+				StepStatus: workflowv1alpha1.StepStatus{Name: "group", Type: "step-group", Phase: workflowv1alpha1.WorkflowStepPhaseFailed, Reason: "Terminate"},
+				SubStepsStatus: []workflowv1alpha1.StepStatus{
+					{Name: "notify", Type: "notification", Phase: workflowv1alpha1.WorkflowStepPhaseFailed, Message: "webhook unreachable"},
+				},
+			},
+		}}},
+	}
+	c := fake.NewClientBuilder().WithScheme(common.Scheme).WithObjects(app).Build()
+	ctx := context.WithValue(context.Background(), &CtxKeyAppName, "app")
+	ctx = context.WithValue(ctx, &CtxKeyNamespace, "default")
+
+	steps, err := ListWorkflowSteps(ctx, c)
+	assert.NoError(t, err)
+	assert.Equal(t, [][]string{
+		{"apply", "apply-component", "succeeded", "30s", "", "ip,port"},
+		{"group", "step-group", "failed", "N/A", "Terminate", "N/A"},
+		{"└─ notify", "notification", "failed", "N/A", "webhook unreachable", "N/A"},
+	}, steps.ToTableBody())
+	assert.Equal(t, "terminated", WorkflowPhase(ctx, c))
+
+	assert.Error(t, RetryWorkflowStep(ctx, c, "apply"))
+	assert.Error(t, RetryWorkflowStep(ctx, c, "not-exist"))
+	assert.NoError(t, RetryWorkflowStep(ctx, c, SubStepPrefix+"notify"))
+	assert.NoError(t, c.Get(ctx, client.ObjectKeyFromObject(app), app))
+	assert.False(t, app.Status.Workflow.Terminated)
+	assert.Equal(t, workflowv1alpha1.WorkflowStepPhaseRunning, app.Status.Workflow.Steps[1].Phase)
+	assert.Empty(t, app.Status.Workflow.Steps[1].SubStepsStatus)
+
+	assert.NoError(t, SuspendWorkflow(ctx, c))
+	assert.Equal(t, "suspending", WorkflowPhase(ctx, c))
+	assert.NoError(t, ResumeWorkflow(ctx, c))
+	assert.Equal(t, "executing", WorkflowPhase(ctx, c))
+	assert.NoError(t, TerminateWorkflow(ctx, c))
+	assert.NoError(t, c.Get(ctx, client.ObjectKeyFromObject(app), app))
+	assert.True(t, app.Status.Workflow.Terminated)
+	assert.Equal(t, workflowv1alpha1.WorkflowStepPhaseFailed, app.Status.Workflow.Steps[1].Phase)
+	assert.Equal(t, "Terminate", app.Status.Workflow.Steps[1].Reason)
+	assert.Error(t, ResumeWorkflow(ctx, c))
+	assert.NoError(t, RestartWorkflow(ctx, c))
+	assert.Equal(t, Unknown, WorkflowPhase(ctx, c))
+	assert.Error(t, TerminateWorkflow(ctx, c))
+	assert.Error(t, RetryWorkflowStep(ctx, c, "apply"))
+}
@@ -20,6 +20,8 @@ import (
 	"context"
 	"math"
 	"strconv"
+	"sync"
+	"time"
 
 	v1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
@@ -32,14 +34,115 @@ import (
 const (
 	// NA Not available.
 	NA = "N/A"
+	// MetricHistorySize is the number of samples kept in the rolling window of the metric history
+	MetricHistorySize = 30
 )
 
+// sparkTicks are the bars of the sparkline from the lowest to the highest
+var sparkTicks = []rune("▁▂▃▄▅▆▇█")
+
 // Metric including requests and limits metrics
 type Metric struct {
 	CPU, Mem   int64
 	Lcpu, Lmem int64
 }
 
+// MetricSample is the usage metric sampled at a time
+type MetricSample struct {
+	Time     time.Time
+	CPU, Mem int64
+}
+
+// MetricHistory is the usage samples in the order of time
+type MetricHistory []MetricSample
+
+// CPU return the cpu usage of the samples
+func (h MetricHistory) CPU() []int64 {
+	values := make([]int64, len(h))
+	for i, sample := range h {
+		values[i] = sample.CPU
+	}
+	return values
+}
+
+// Mem return the memory usage of the samples
+func (h MetricHistory) Mem() []int64 {
+	values := make([]int64, len(h))
+	for i, sample := range h {
+		values[i] = sample.Mem
+	}
+	return values
+}
+
+// MetricStore keeps a rolling window of the metric history in memory for each key, such as a pod or a component
+type MetricStore struct {
+	mu        sync.Mutex
+	size      int
+	histories map[string]MetricHistory
+}
+
+// NewMetricStore return a metric store keeping at most size samples for each key
+func NewMetricStore(size int) *MetricStore {
+	return &MetricStore{size: size, histories: map[string]MetricHistory{}}
+}
+
+// Record add the sample to the history of the key and return the history. The metrics are refreshed by the
+// metrics server periodically, so the sample no later than the latest one in the history is ignored.
+func (s *MetricStore) Record(key string, sample MetricSample) MetricHistory {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	history := s.histories[key]
+	if len(history) == 0 || sample.Time.After(history[len(history)-1].Time) {
+		history = append(history, sample)
+		if len(history) > s.size {
+			history = history[len(history)-s.size:]
+		}
+		s.histories[key] = history
+	}
+	return append(MetricHistory{}, history...)
+}
+
+// History return the history of the key
+func (s *MetricStore) History(key string) MetricHistory {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append(MetricHistory{}, s.histories[key]...)
+}
+
+// Delete remove the histories of the keys
+func (s *MetricStore) Delete(keys ...string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, key := range keys {
+		delete(s.histories, key)
+	}
+}
+
+// Sparkline render the values as a line of bars scaled between the minimum and maximum values
+func Sparkline(values []int64) string {
+	if len(values) == 0 {
+		return NA
+	}
+	lowest, highest := values[0], values[0]
+	for _, v := range values {
+		if v < lowest {
+			lowest = v
+		}
+		if v > highest {
+			highest = v
+		}
+	}
+	line := make([]rune, len(values))
+	for i, v := range values {
+		tick := 0
+		if highest > lowest {
+			tick = int((v - lowest) * int64(len(sparkTicks)-1) / (highest - lowest))
+		}
+		line[i] = sparkTicks[tick]
+	}
+	return string(line)
+}
+
 // GatherPodMX return the usage metrics of a pod and specified metric including requests and limits metrics
 func GatherPodMX(pod *v1.Pod, mx *v1beta1.PodMetrics) (c, r Metric) {
 	rcpu, rmem := podRequests(pod.Spec)
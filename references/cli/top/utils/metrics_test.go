@@ -18,6 +18,7 @@ package utils
 
 import (
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	v1 "k8s.io/api/core/v1"
@@ -72,3 +73,25 @@ func TestGatherPodMX(t *testing.T) {
 	assert.Equal(t, r.CPU, int64(100))
 	assert.Equal(t, r.Mem, int64(52428800))
 }
+
+func TestMetricStore(t *testing.T) {
+	store := NewMetricStore(3)
+	now := time.Now()
+	for i := 0; i < 4; i++ {
+		store.Record("pod", MetricSample{Time: now.Add(time.Duration(i) * time.Minute), CPU: int64(i), Mem: int64(i * 10)})
+	}
+	// the sample with the same time as the latest one is ignored
+	history := store.Record("pod", MetricSample{Time: now.Add(3 * time.Minute), CPU: 100})
+	assert.Equal(t, history.CPU(), []int64{1, 2, 3})
+	assert.Equal(t, history.Mem(), []int64{10, 20, 30})
+	assert.Equal(t, store.History("pod"), history)
+	assert.Empty(t, store.History("another"))
+	store.Delete("pod")
+	assert.Empty(t, store.History("pod"))
+}
+
+func TestSparkline(t *testing.T) {
+	assert.Equal(t, Sparkline(nil), NA)
+	assert.Equal(t, Sparkline([]int64{5, 5}), "▁▁")
+	assert.Equal(t, Sparkline([]int64{0, 7, 14, 7}), "▁▄█▄")
+}
@@ -47,6 +47,8 @@ type App struct {
 
 const (
 	delay = time.Second * 10
+	// metricSampleInterval is the interval to sample the usage metrics, it's the default resolution of the metrics server
+	metricSampleInterval = time.Second * 15
 )
 
 // NewApp return a new app object
@@ -54,6 +56,11 @@ func NewApp(c client.Client, restConfig *rest.Config, namespace string) *App {
 	conf := config.Config{
 		RestConfig: restConfig,
 	}
+	filters, err := config.LoadFilters()
+	if err != nil {
+		log.Printf("failed to load the saved filters: %s", err.Error())
+	}
+	conf.Filters = filters
 	a := &App{
 		App:    component.NewApp(),
 		client: c,
@@ -66,6 +73,11 @@ func NewApp(c client.Client, restConfig *rest.Config, namespace string) *App {
 	return a
 }
 
+// SetContainerExecutor set the executor to launch the shell in the container
+func (a *App) SetContainerExecutor(exec config.ContainerExecutor) {
+	a.config.Exec = exec
+}
+
 // Init the app
 func (a *App) Init() {
 	a.command.Init()
@@ -111,16 +123,19 @@ func (a *App) Run() error {
 		})
 	}()
 	a.Refresh()
+	// stop the background updaters once the app exits
+	defer a.cancelFunc()
 	if err := a.Application.Run(); err != nil {
 		return err
 	}
 	return nil
 }
 
-// Refresh will refresh the ui after the delay time
+// Refresh will refresh the ui after the delay time, and sample the usage metrics of the pods in the background
 func (a *App) Refresh() {
 	ctx := context.Background()
 	ctx, a.cancelFunc = context.WithCancel(ctx)
+	go model.SampleMetrics(ctx, a.config.RestConfig, a.client, metricSampleInterval)
 	// system info board component
 	board := a.Components()["info"].(*component.InfoBoard)
 	go func() {
@@ -141,6 +156,9 @@ func (a *App) Refresh() {
 // inject add a new component to the app's main view to refresh the content of the main view
 func (a *App) inject(v model.View) {
 	v.Init()
+	if fv, ok := v.(filterableView); ok {
+		fv.restoreFilter(v.Name())
+	}
 	a.content.PushView(v)
 }
 
@@ -152,6 +170,10 @@ func (a *App) bindKeys() {
 }
 
 func (a *App) keyboard(event *tcell.EventKey) *tcell.EventKey {
+	// the keys are handled by the dialog while it is shown
+	if a.dialogShown() {
+		return event
+	}
 	if action, ok := a.HasAction(component.StandardizeKey(event)); ok {
 		return action.Action(event)
 	}
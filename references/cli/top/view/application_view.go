@@ -19,8 +19,11 @@ package view
 import (
 	"context"
 	"fmt"
+	"strings"
 
 	"github.com/gdamore/tcell/v2"
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/labels"
 
 	"github.com/oam-dev/kubevela/apis/core.oam.dev/common"
 	"github.com/oam-dev/kubevela/references/cli/top/component"
@@ -91,7 +94,9 @@ func (v *ApplicationView) BuildHeader() {
 
 // BuildBody render the body of table
 func (v *ApplicationView) BuildBody() {
-	apps, err := model.ListApplications(v.ctx, v.app.client)
+	ctx := context.WithValue(v.ctx, &model.CtxKeyAppLabelSelector, v.filter.LabelSelector)
+	ctx = context.WithValue(ctx, &model.CtxKeyCluster, v.filter.Cluster)
+	apps, err := model.ListApplications(ctx, v.app.client)
 	if err != nil {
 		return
 	}
@@ -138,9 +143,33 @@ func (v *ApplicationView) bindKeys() {
 		component.KeyY: model.KeyAction{Description: "Yaml", Action: v.yamlView, Visible: true, Shared: true},
 		component.KeyR: model.KeyAction{Description: "Refresh", Action: v.Refresh, Visible: true, Shared: true},
 		component.KeyT: model.KeyAction{Description: "Topology", Action: v.topologyView, Visible: true, Shared: true},
+		component.KeyW: model.KeyAction{Description: "Workflow", Action: v.workflowView, Visible: true, Shared: true},
+		component.KeyE: model.KeyAction{Description: "Events", Action: v.eventView, Visible: true, Shared: true},
+		component.KeyL: model.KeyAction{Description: "Label Filter", Action: v.labelFilter, Visible: true, Shared: true},
+		component.KeyC: model.KeyAction{Description: "Cluster Filter", Action: v.clusterFilter, Visible: true, Shared: true},
 	})
 }
 
+// labelFilter show the prompt of the label selector to filter the applications
+func (v *ApplicationView) labelFilter(_ *tcell.EventKey) *tcell.EventKey {
+	v.app.prompt(" Filter applications by labels ", "Selector:", v.filter.LabelSelector, func(selector string) {
+		if _, err := labels.Parse(selector); err != nil {
+			v.app.alert(errors.Wrapf(err, "invalid label selector %q", selector))
+			return
+		}
+		v.updateFilter(func(filter *config.Filter) { filter.LabelSelector = selector })
+	})
+	return nil
+}
+
+// clusterFilter show the prompt of the cluster to filter the applications deployed to it
+func (v *ApplicationView) clusterFilter(_ *tcell.EventKey) *tcell.EventKey {
+	v.app.prompt(" Filter applications by cluster ", "Cluster:", v.filter.Cluster, func(cluster string) {
+		v.updateFilter(func(filter *config.Filter) { filter.Cluster = strings.TrimSpace(cluster) })
+	})
+	return nil
+}
+
 func (v *ApplicationView) managedResourceView(event *tcell.EventKey) *tcell.EventKey {
 	row, _ := v.GetSelection()
 	if row == 0 {
@@ -192,3 +221,31 @@ func (v *ApplicationView) topologyView(event *tcell.EventKey) *tcell.EventKey {
 	v.app.command.run(ctx, "topology")
 	return nil
 }
+
+func (v *ApplicationView) workflowView(event *tcell.EventKey) *tcell.EventKey {
+	row, _ := v.GetSelection()
+	if row == 0 {
+		return event
+	}
+	name, namespace := v.GetCell(row, 0).Text, v.GetCell(row, 1).Text
+
+	ctx := context.WithValue(context.Background(), &model.CtxKeyAppName, name)
+	ctx = context.WithValue(ctx, &model.CtxKeyNamespace, namespace)
+
+	v.app.command.run(ctx, "workflow")
+	return nil
+}
+
+func (v *ApplicationView) eventView(event *tcell.EventKey) *tcell.EventKey {
+	row, _ := v.GetSelection()
+	if row == 0 {
+		return event
+	}
+	name, namespace := v.GetCell(row, 0).Text, v.GetCell(row, 1).Text
+
+	ctx := context.WithValue(context.Background(), &model.CtxKeyAppName, name)
+	ctx = context.WithValue(ctx, &model.CtxKeyNamespace, namespace)
+
+	v.app.command.run(ctx, "event")
+	return nil
+}
@@ -67,7 +67,7 @@ func TestClusterView(t *testing.T) {
 	})
 
 	t.Run("hint", func(t *testing.T) {
-		assert.Equal(t, len(clusterView.Hint()), 4)
+		assert.Equal(t, len(clusterView.Hint()), 7)
 	})
 
 	t.Run("start", func(t *testing.T) {
@@ -107,6 +107,31 @@ func (v *ContainerView) bindKeys() {
 	v.Actions().Add(model.KeyActions{
 		component.KeyL: model.KeyAction{Description: "Log", Action: v.logView, Visible: true, Shared: true},
 	})
+	v.bindResourceActions(v.selectedResource, map[tcell.Key]resourceKeyAction{
+		component.KeyS: {
+			KeyAction: model.KeyAction{Description: "Shell", Action: v.execContainer, Visible: true, Shared: true},
+			action:    model.ExecAction,
+		},
+	})
+}
+
+// selectedResource return the pod of the container in the row
+func (v *ContainerView) selectedResource(row int) *model.GVR {
+	if v.GetCell(row, 0).Text == "" {
+		return nil
+	}
+	pod, _ := v.ctx.Value(&model.CtxKeyPod).(string)
+	namespace, _ := v.ctx.Value(&model.CtxKeyNamespace).(string)
+	cluster, _ := v.ctx.Value(&model.CtxKeyCluster).(string)
+	return &model.GVR{
+		GV: "v1",
+		R: model.Resource{
+			Kind:      "Pod",
+			Name:      pod,
+			Namespace: namespace,
+			Cluster:   cluster,
+		},
+	}
 }
 
 // ColorizePhaseText colorize the state column text
@@ -138,3 +163,12 @@ func (v *ContainerView) logView(event *tcell.EventKey) *tcell.EventKey {
 	v.app.command.run(ctx, "log")
 	return nil
 }
+
+func (v *ContainerView) execContainer(event *tcell.EventKey) *tcell.EventKey {
+	row, _ := v.GetSelection()
+	if row == 0 {
+		return event
+	}
+	v.CommonResourceView.execContainer(v.selectedResource(row), v.GetCell(row, 0).Text)
+	return nil
+}
@@ -0,0 +1,84 @@
+/*
+Copyright 2022 The KubeVela Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package view
+
+import (
+	"github.com/rivo/tview"
+
+	"github.com/oam-dev/kubevela/references/cli/top/config"
+)
+
+// dialogPage is the name of the page of dialog in app's main pages
+const dialogPage = "dialog"
+
+const (
+	confirmButton = "OK"
+	cancelButton  = "Cancel"
+)
+
+// confirm show the confirmation dialog with the message, the ok func is called if the user confirms
+func (a *App) confirm(message string, ok func()) {
+	modal := tview.NewModal().SetText(message).AddButtons([]string{confirmButton, cancelButton})
+	modal.SetDoneFunc(func(_ int, label string) {
+		a.closeDialog()
+		if label == confirmButton {
+			ok()
+		}
+	})
+	a.showDialog(modal)
+}
+
+// prompt show the input dialog with the label and the initial value, the ok func is called with the input value
+// if the user confirms
+func (a *App) prompt(title, label, value string, ok func(value string)) {
+	form := tview.NewForm().AddInputField(label, value, 20, nil, nil)
+	form.AddButton(confirmButton, func() {
+		value := form.GetFormItem(0).(*tview.InputField).GetText()
+		a.closeDialog()
+		ok(value)
+	})
+	form.AddButton(cancelButton, a.closeDialog)
+	form.SetCancelFunc(a.closeDialog)
+	form.SetBorder(true).SetTitle(title).SetTitleColor(config.ResourceTableTitleColor)
+	grid := tview.NewGrid().SetColumns(0, 50, 0).SetRows(0, 7, 0).AddItem(form, 1, 1, 1, 1, 0, 0, true)
+	a.showDialog(grid)
+}
+
+// alert show the error in the dialog
+func (a *App) alert(err error) {
+	modal := tview.NewModal().SetText(err.Error()).AddButtons([]string{confirmButton})
+	modal.SetDoneFunc(func(int, string) { a.closeDialog() })
+	a.showDialog(modal)
+}
+
+func (a *App) showDialog(dialog tview.Primitive) {
+	a.Main.AddPage(dialogPage, dialog, true, true)
+	a.SetFocus(dialog)
+}
+
+func (a *App) closeDialog() {
+	a.Main.RemovePage(dialogPage)
+	if top := a.content.TopView(); top != nil {
+		a.SetFocus(top)
+	}
+}
+
+// dialogShown return whether there is a dialog shown in front
+func (a *App) dialogShown() bool {
+	name, _ := a.Main.GetFrontPage()
+	return name == dialogPage
+}
@@ -0,0 +1,62 @@
+/*
+Copyright 2022 The KubeVela Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package view
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+	"github.com/stretchr/testify/assert"
+	"k8s.io/client-go/rest"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/oam-dev/kubevela/pkg/utils/common"
+	"github.com/oam-dev/kubevela/references/cli/top/component"
+)
+
+func TestDialog(t *testing.T) {
+	app := NewApp(fake.NewClientBuilder().WithScheme(common.Scheme).Build(), &rest.Config{}, "")
+	app.Main.AddPage("main", tview.NewBox(), true, true)
+	backKey := tcell.NewEventKey(tcell.KeyRune, rune(component.KeyQ), tcell.ModNone)
+
+	t.Run("confirm", func(t *testing.T) {
+		app.confirm("Delete?", func() {})
+		assert.True(t, app.dialogShown())
+		// the keys are passed to the dialog
+		assert.Equal(t, backKey, app.keyboard(backKey))
+		app.closeDialog()
+		assert.False(t, app.dialogShown())
+	})
+
+	t.Run("prompt", func(t *testing.T) {
+		var value string
+		app.prompt("Scale", "Replicas:", "1", func(v string) { value = v })
+		assert.True(t, app.dialogShown())
+		app.closeDialog()
+		assert.False(t, app.dialogShown())
+		assert.Empty(t, value)
+	})
+
+	t.Run("alert", func(t *testing.T) {
+		app.alert(errors.New("forbidden"))
+		assert.True(t, app.dialogShown())
+		app.closeDialog()
+		assert.False(t, app.dialogShown())
+	})
+}
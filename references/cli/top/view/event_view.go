@@ -0,0 +1,135 @@
+/*
+Copyright 2022 The KubeVela Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package view
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/gdamore/tcell/v2"
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/oam-dev/kubevela/references/cli/top/component"
+	"github.com/oam-dev/kubevela/references/cli/top/config"
+	"github.com/oam-dev/kubevela/references/cli/top/model"
+)
+
+// EventView is the event view, this view display the timeline of the events of application and its resources in all clusters
+type EventView struct {
+	*CommonResourceView
+	ctx context.Context
+}
+
+// Name return event view name
+func (v *EventView) Name() string {
+	return "Event"
+}
+
+// Init the event view
+func (v *EventView) Init() {
+	v.CommonResourceView.Init()
+	v.SetTitle(fmt.Sprintf("[ %s ]", v.Title()))
+	v.bindKeys()
+}
+
+// Start the event view
+func (v *EventView) Start() {
+	v.Clear()
+	v.Update()
+	v.CommonResourceView.AutoRefresh(v.Update)
+}
+
+// Stop the event view
+func (v *EventView) Stop() {
+	v.CommonResourceView.Stop()
+}
+
+// Hint return key action menu hints of the event view
+func (v *EventView) Hint() []model.MenuHint {
+	return v.Actions().Hint()
+}
+
+// InitView init a new event view
+func (v *EventView) InitView(ctx context.Context, app *App) {
+	v.ctx = ctx
+	if v.CommonResourceView == nil {
+		v.CommonResourceView = NewCommonView(app)
+	}
+}
+
+// Refresh the view content
+func (v *EventView) Refresh(_ *tcell.EventKey) *tcell.EventKey {
+	v.CommonResourceView.Refresh(true, v.Update)
+	return nil
+}
+
+// Update refresh the content of body of view
+func (v *EventView) Update() {
+	v.BuildHeader()
+	v.BuildBody()
+}
+
+// BuildHeader render the header of table
+func (v *EventView) BuildHeader() {
+	header := []string{"Last Seen", "Type", "Reason", "Object", "Cluster", "Count", "Message"}
+	v.CommonResourceView.BuildHeader(header)
+}
+
+// BuildBody render the body of table
+func (v *EventView) BuildBody() {
+	events, err := model.ListApplicationEvents(v.ctx, v.app.client)
+	if err != nil {
+		return
+	}
+	eventInfos := events.ToTableBody()
+	v.CommonResourceView.BuildBody(eventInfos)
+	rowNum := len(eventInfos)
+	v.ColorizeTypeText(rowNum)
+}
+
+// ColorizeTypeText colorize the type and reason column text, so the warnings like restarts and OOMs stand out
+func (v *EventView) ColorizeTypeText(rowNum int) {
+	for i := 1; i < rowNum+1; i++ {
+		eventType := v.Table.GetCell(i, 1).Text
+		color := ""
+		switch eventType {
+		case corev1.EventTypeWarning:
+			color = config.EventWarningTypeColor
+		case corev1.EventTypeNormal:
+			color = config.EventNormalTypeColor
+		default:
+		}
+		v.Table.GetCell(i, 1).SetText(color + eventType)
+		if eventType == corev1.EventTypeWarning {
+			v.Table.GetCell(i, 2).SetText(color + v.Table.GetCell(i, 2).Text)
+		}
+	}
+}
+
+// Title return table title of event view
+func (v *EventView) Title() string {
+	name := v.ctx.Value(&model.CtxKeyAppName).(string)
+	namespace := v.ctx.Value(&model.CtxKeyNamespace).(string)
+	return fmt.Sprintf("Event (%s/%s)", namespace, name)
+}
+
+func (v *EventView) bindKeys() {
+	v.Actions().Delete([]tcell.Key{tcell.KeyEnter})
+	v.Actions().Add(model.KeyActions{
+		component.KeyR: model.KeyAction{Description: "Refresh", Action: v.Refresh, Visible: true, Shared: true},
+	})
+}
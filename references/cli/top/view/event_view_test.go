@@ -0,0 +1,104 @@
+/*
+Copyright 2022 The KubeVela Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package view
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/rivo/tview"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/rest"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/oam-dev/kubevela/apis/core.oam.dev/v1beta1"
+	"github.com/oam-dev/kubevela/pkg/utils/common"
+	"github.com/oam-dev/kubevela/references/cli/top/model"
+)
+
+func TestEventView(t *testing.T) {
+	testApp := &v1beta1.Application{ObjectMeta: metav1.ObjectMeta{Name: "app", Namespace: "default"}}
+	testEvent := &corev1.Event{
+		ObjectMeta:     metav1.ObjectMeta{Name: "event", Namespace: "default"},
+		InvolvedObject: corev1.ObjectReference{Kind: "Application", Name: "app", Namespace: "default"},
+		Type:           corev1.EventTypeWarning,
+		Reason:         "FailedWorkflow",
+		Count:          1,
+		LastTimestamp:  metav1.NewTime(time.Now()),
+	}
+	testClient := fake.NewClientBuilder().WithScheme(common.Scheme).WithObjects(testApp, testEvent).Build()
+	app := NewApp(testClient, &rest.Config{}, "")
+
+	ctx := context.Background()
+	ctx = context.WithValue(ctx, &model.CtxKeyAppName, "app")
+	ctx = context.WithValue(ctx, &model.CtxKeyNamespace, "default")
+
+	eventView := new(EventView)
+
+	t.Run("init view", func(t *testing.T) {
+		assert.Empty(t, eventView.CommonResourceView)
+		eventView.InitView(ctx, app)
+		assert.NotEmpty(t, eventView.CommonResourceView)
+	})
+
+	t.Run("init", func(t *testing.T) {
+		eventView.Init()
+		assert.Equal(t, eventView.Table.GetTitle(), "[ Event (default/app) ]")
+	})
+
+	t.Run("refresh", func(t *testing.T) {
+		keyEvent := eventView.Refresh(nil)
+		assert.Empty(t, keyEvent)
+	})
+
+	t.Run("start", func(t *testing.T) {
+		eventView.Start()
+		assert.Equal(t, eventView.GetCell(0, 0).Text, "Last Seen")
+		assert.Equal(t, eventView.GetCell(1, 1).Text, "[red::]Warning")
+		assert.Equal(t, eventView.GetCell(1, 2).Text, "[red::]FailedWorkflow")
+		assert.Equal(t, eventView.GetCell(1, 3).Text, "Application/app")
+	})
+
+	t.Run("stop", func(t *testing.T) {
+		eventView.Stop()
+		assert.Equal(t, eventView.GetCell(0, 0).Text, "")
+	})
+
+	t.Run("colorize text", func(t *testing.T) {
+		testData := [][]string{
+			{"1m", "Normal", "Scheduled", "Pod/web", "local", "1", ""},
+			{"1m", "Warning", "BackOff", "Pod/web", "local", "3", ""},
+		}
+		for i := 0; i < len(testData); i++ {
+			for j := 0; j < len(testData[i]); j++ {
+				eventView.Table.SetCell(1+i, j, tview.NewTableCell(testData[i][j]))
+			}
+		}
+		eventView.ColorizeTypeText(2)
+		assert.Equal(t, eventView.GetCell(1, 1).Text, "[green::]Normal")
+		assert.Equal(t, eventView.GetCell(1, 2).Text, "Scheduled")
+		assert.Equal(t, eventView.GetCell(2, 1).Text, "[red::]Warning")
+		assert.Equal(t, eventView.GetCell(2, 2).Text, "[red::]BackOff")
+	})
+
+	t.Run("hint", func(t *testing.T) {
+		assert.Equal(t, len(eventView.Hint()), 6)
+	})
+}
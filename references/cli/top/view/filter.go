@@ -0,0 +1,84 @@
+/*
+Copyright 2022 The KubeVela Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package view
+
+import (
+	"github.com/gdamore/tcell/v2"
+	"github.com/pkg/errors"
+
+	"github.com/oam-dev/kubevela/references/cli/top/config"
+)
+
+// filterableView is the view whose rows can be filtered and sorted, the filter is saved by the view name
+type filterableView interface {
+	restoreFilter(name string)
+}
+
+// restoreFilter restore the saved filter of the view
+func (v *CommonResourceView) restoreFilter(name string) {
+	v.filterName = name
+	v.filter = v.app.config.Filters[name]
+	// the column of the filter is resolved with the header when the rows are filtered
+	_ = v.Table.SetFilter(v.filter.Expression)
+	v.Table.SetSort(v.filter.SortColumn, v.filter.SortDesc)
+}
+
+// updateFilter update the filter of the view and save it, the view is refreshed with the filter
+func (v *CommonResourceView) updateFilter(update func(filter *config.Filter)) {
+	update(&v.filter)
+	if err := v.Table.SetFilter(v.filter.Expression); err != nil {
+		v.app.alert(err)
+		return
+	}
+	v.Table.SetSort(v.filter.SortColumn, v.filter.SortDesc)
+	if v.filterName != "" {
+		if v.app.config.Filters == nil {
+			v.app.config.Filters = config.Filters{}
+		}
+		v.app.config.Filters[v.filterName] = v.filter
+		if err := v.app.config.Filters.Save(); err != nil {
+			v.app.alert(errors.WithMessage(err, "failed to save the filter"))
+		}
+	}
+	if v.update != nil {
+		v.Refresh(true, v.update)
+	}
+}
+
+// searchView show the prompt of the filter expression
+func (v *CommonResourceView) searchView(_ *tcell.EventKey) *tcell.EventKey {
+	v.app.prompt(" Filter (regex or column:regex) ", "Filter:", v.Table.Filter(), func(expression string) {
+		if err := v.Table.SetFilter(expression); err != nil {
+			v.app.alert(err)
+			return
+		}
+		v.updateFilter(func(filter *config.Filter) { filter.Expression = expression })
+	})
+	return nil
+}
+
+// sortByNextColumn sort the rows by the next column
+func (v *CommonResourceView) sortByNextColumn(_ *tcell.EventKey) *tcell.EventKey {
+	v.updateFilter(func(filter *config.Filter) { filter.SortColumn = v.Table.NextSortColumn() })
+	return nil
+}
+
+// invertSort toggle the order of the sorted rows
+func (v *CommonResourceView) invertSort(_ *tcell.EventKey) *tcell.EventKey {
+	v.updateFilter(func(filter *config.Filter) { filter.SortDesc = !filter.SortDesc })
+	return nil
+}
@@ -0,0 +1,65 @@
+/*
+Copyright 2022 The KubeVela Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package view
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/oam-dev/kubevela/references/cli/top/config"
+)
+
+func TestFilter(t *testing.T) {
+	t.Setenv("VELA_HOME", t.TempDir())
+	app := NewApp(nil, nil, "")
+	view := NewCommonView(app)
+	view.Init()
+	view.restoreFilter("resource")
+
+	body := [][]string{{"b", "running"}, {"a", "failed"}, {"c", "running"}}
+	build := func() {
+		view.BuildHeader([]string{"Name", "Phase"})
+		view.BuildBody(body)
+	}
+	// the update is queued in the app which isn't running, so the table is rebuilt manually
+	view.update = func() {}
+	build()
+	assert.Equal(t, view.GetRowCount(), 4)
+
+	view.updateFilter(func(filter *config.Filter) { filter.Expression = "phase:running" })
+	build()
+	assert.Equal(t, view.GetRowCount(), 3)
+	assert.Equal(t, view.GetCell(1, 0).Text, "b")
+	assert.Equal(t, view.GetCell(2, 0).Text, "c")
+
+	view.sortByNextColumn(nil)
+	view.invertSort(nil)
+	build()
+	assert.Equal(t, view.GetCell(0, 0).Text, "Name↓")
+	assert.Equal(t, view.GetCell(1, 0).Text, "c")
+	assert.Equal(t, view.GetCell(2, 0).Text, "b")
+
+	// the filter is saved and restored by the view name
+	filters, err := config.LoadFilters()
+	assert.NoError(t, err)
+	assert.Equal(t, filters["resource"], config.Filter{Expression: "phase:running", SortColumn: "Name", SortDesc: true})
+	another := NewCommonView(NewApp(nil, nil, ""))
+	another.Init()
+	another.restoreFilter("resource")
+	assert.Equal(t, another.Table.Filter(), "phase:running")
+}
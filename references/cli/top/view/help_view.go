@@ -70,6 +70,10 @@ At present, the tool has provided the following feature:
 [blue:]*[white:] Display of resource status information at Application, Managed Resource and Pod levels
 [blue:]*[white:] Application Resource Topology
 [blue:]*[white:] Resource YAML text display
+[blue:]*[white:] Application workflow steps with the suspend, resume, terminate, restart and retry actions
+[blue:]*[white:] Resource actions: restart, scale and edit the managed resources, delete, edit and shell into the pods, the actions are only available with the permission
+[blue:]*[white:] Usage trends of the pods and components in sparklines, and the timeline of the events of the application from all clusters
+[blue:]*[white:] Filter the table rows by regex with the / key, sort by the column with the O and I keys, filter the applications by labels and cluster, the filters are saved for the next launch
 
 This information panel component in UI header will display the performance information of the KubeVela system.
 
@@ -143,6 +143,40 @@ func (v *ManagedResourceView) bindKeys() {
 		component.KeyY: model.KeyAction{Description: "Yaml", Action: v.yamlView, Visible: true, Shared: true},
 		component.KeyR: model.KeyAction{Description: "Refresh", Action: v.Refresh, Visible: true, Shared: true},
 	})
+	v.bindResourceActions(v.selectedResource, map[tcell.Key]resourceKeyAction{
+		component.KeyE: {
+			KeyAction: model.KeyAction{Description: "Edit", Action: v.editResource, Visible: true, Shared: true},
+			action:    model.EditAction,
+		},
+		component.KeyS: {
+			KeyAction: model.KeyAction{Description: "Scale", Action: v.scaleResource, Visible: true, Shared: true},
+			action:    model.ScaleAction,
+			support:   model.IsScalable,
+		},
+		component.KeyT: {
+			KeyAction: model.KeyAction{Description: "Restart", Action: v.restartResource, Visible: true, Shared: true},
+			action:    model.RestartAction,
+			support:   model.IsRestartable,
+		},
+	})
+}
+
+// selectedResource return the resource of the row
+func (v *ManagedResourceView) selectedResource(row int) *model.GVR {
+	name, namespace := v.GetCell(row, 0).Text, v.GetCell(row, 1).Text
+	kind, api, cluster := v.GetCell(row, 2).Text, v.GetCell(row, 3).Text, v.GetCell(row, 4).Text
+	if name == "" {
+		return nil
+	}
+	return &model.GVR{
+		GV: api,
+		R: model.Resource{
+			Kind:      kind,
+			Name:      name,
+			Namespace: namespace,
+			Cluster:   cluster,
+		},
+	}
 }
 
 // clusterView switch managed resource view to the cluster view
@@ -192,3 +226,30 @@ func (v *ManagedResourceView) yamlView(event *tcell.EventKey) *tcell.EventKey {
 	v.app.command.run(ctx, "yaml")
 	return nil
 }
+
+func (v *ManagedResourceView) editResource(event *tcell.EventKey) *tcell.EventKey {
+	row, _ := v.GetSelection()
+	if row == 0 {
+		return event
+	}
+	v.CommonResourceView.editResource(v.selectedResource(row), v.Update)
+	return nil
+}
+
+func (v *ManagedResourceView) scaleResource(event *tcell.EventKey) *tcell.EventKey {
+	row, _ := v.GetSelection()
+	if row == 0 {
+		return event
+	}
+	v.CommonResourceView.scaleResource(v.selectedResource(row), v.Update)
+	return nil
+}
+
+func (v *ManagedResourceView) restartResource(event *tcell.EventKey) *tcell.EventKey {
+	row, _ := v.GetSelection()
+	if row == 0 {
+		return event
+	}
+	v.CommonResourceView.restartResource(v.selectedResource(row), v.Update)
+	return nil
+}
@@ -26,6 +26,7 @@ import (
 	"github.com/oam-dev/kubevela/references/cli/top/component"
 	"github.com/oam-dev/kubevela/references/cli/top/config"
 	"github.com/oam-dev/kubevela/references/cli/top/model"
+	"github.com/oam-dev/kubevela/references/cli/top/utils"
 )
 
 // PodView is the pod view, this view display info of pod belonging to component
@@ -81,11 +82,22 @@ func (v *PodView) Refresh(_ *tcell.EventKey) *tcell.EventKey {
 func (v *PodView) Update() {
 	v.BuildHeader()
 	v.BuildBody()
+	v.SetTitle(v.Title())
+}
+
+// Title return table title of pod view, the trend of the usage metrics of the component is appended once
+// there are metrics sampled
+func (v *PodView) Title() string {
+	title := fmt.Sprintf("[ %s ]", v.Name())
+	if history := model.ComponentMetricHistory(v.ctx); len(history) > 0 {
+		title = fmt.Sprintf("%s CPU %s MEM %s", title, utils.Sparkline(history.CPU()), utils.Sparkline(history.Mem()))
+	}
+	return title
 }
 
 // BuildHeader render the header of table
 func (v *PodView) BuildHeader() {
-	header := []string{"Name", "Namespace", "Cluster", "Ready", "Status", "CPU", "MEM", "CPU/R", "CPU/L", "MEM/R", "MEM/L", "IP", "Node", "Age"}
+	header := []string{"Name", "Namespace", "Cluster", "Ready", "Status", "CPU", "MEM", "CPU/R", "CPU/L", "MEM/R", "MEM/L", "CPU Trend", "MEM Trend", "IP", "Node", "Age"}
 	v.CommonResourceView.BuildHeader(header)
 }
 
@@ -128,6 +140,37 @@ func (v *PodView) bindKeys() {
 		component.KeyR: model.KeyAction{Description: "Refresh", Action: v.Refresh, Visible: true, Shared: true},
 		component.KeyL: model.KeyAction{Description: "Log", Action: v.logView, Visible: true, Shared: true},
 	})
+	v.bindResourceActions(v.selectedResource, map[tcell.Key]resourceKeyAction{
+		component.KeyD: {
+			KeyAction: model.KeyAction{Description: "Delete", Action: v.deleteResource, Visible: true, Shared: true},
+			action:    model.DeleteAction,
+		},
+		component.KeyE: {
+			KeyAction: model.KeyAction{Description: "Edit", Action: v.editResource, Visible: true, Shared: true},
+			action:    model.EditAction,
+		},
+		component.KeyS: {
+			KeyAction: model.KeyAction{Description: "Shell", Action: v.execContainer, Visible: true, Shared: true},
+			action:    model.ExecAction,
+		},
+	})
+}
+
+// selectedResource return the pod of the row
+func (v *PodView) selectedResource(row int) *model.GVR {
+	name, namespace, cluster := v.GetCell(row, 0).Text, v.GetCell(row, 1).Text, v.GetCell(row, 2).Text
+	if name == "" {
+		return nil
+	}
+	return &model.GVR{
+		GV: "v1",
+		R: model.Resource{
+			Kind:      "Pod",
+			Name:      name,
+			Namespace: namespace,
+			Cluster:   cluster,
+		},
+	}
 }
 
 func (v *PodView) yamlView(event *tcell.EventKey) *tcell.EventKey {
@@ -180,3 +223,30 @@ func (v *PodView) logView(event *tcell.EventKey) *tcell.EventKey {
 	v.app.command.run(ctx, "log")
 	return nil
 }
+
+func (v *PodView) deleteResource(event *tcell.EventKey) *tcell.EventKey {
+	row, _ := v.GetSelection()
+	if row == 0 {
+		return event
+	}
+	v.CommonResourceView.deleteResource(v.selectedResource(row), v.Update)
+	return nil
+}
+
+func (v *PodView) editResource(event *tcell.EventKey) *tcell.EventKey {
+	row, _ := v.GetSelection()
+	if row == 0 {
+		return event
+	}
+	v.CommonResourceView.editResource(v.selectedResource(row), v.Update)
+	return nil
+}
+
+func (v *PodView) execContainer(event *tcell.EventKey) *tcell.EventKey {
+	row, _ := v.GetSelection()
+	if row == 0 {
+		return event
+	}
+	v.CommonResourceView.execContainer(v.selectedResource(row), "")
+	return nil
+}
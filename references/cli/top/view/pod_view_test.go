@@ -99,6 +99,6 @@ func TestPodView(t *testing.T) {
 	})
 
 	t.Run("hint", func(t *testing.T) {
-		assert.Equal(t, len(podView.Hint()), 6)
+		assert.Equal(t, len(podView.Hint()), 9)
 	})
 }
@@ -0,0 +1,133 @@
+/*
+Copyright 2022 The KubeVela Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package view
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"strconv"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/pkg/errors"
+	"k8s.io/kubectl/pkg/cmd/util/editor"
+
+	"github.com/oam-dev/kubevela/references/cli/top/model"
+)
+
+// resourceKeyAction is the key action performed on the resource of the selected row. The key action is
+// removed if the resource doesn't support it or the current user has no permission to perform it.
+type resourceKeyAction struct {
+	model.KeyAction
+	action  model.ResourceAction
+	support func(gvr *model.GVR) bool
+}
+
+// bindResourceActions bind the resource key actions to the view and update them whenever the selection
+// changes, the selectedResource func return the resource of the row or nil if there is no resource in the row.
+// The access of the actions is reviewed in the background, and the actions are shown once they are allowed.
+func (v *CommonResourceView) bindResourceActions(selectedResource func(row int) *model.GVR, actions map[tcell.Key]resourceKeyAction) {
+	var update func(row, _ int)
+	reviewed := func() { v.app.QueueUpdateDraw(func() { update(v.GetSelection()) }) }
+	update = func(row, _ int) {
+		var gvr *model.GVR
+		if row > 0 {
+			gvr = selectedResource(row)
+		}
+		for key, a := range actions {
+			if gvr != nil && (a.support == nil || a.support(gvr)) && model.CanIAsync(v.app.client, gvr, a.action, reviewed) {
+				v.Actions().Add(model.KeyActions{key: a.KeyAction})
+			} else {
+				v.Actions().Delete([]tcell.Key{key})
+			}
+		}
+		v.app.Menu().UpdateMenu(v.Actions().Hint())
+	}
+	v.SetSelectionChangedFunc(update)
+	update(v.GetSelection())
+}
+
+// restartResource restart the workload after the confirmation
+func (v *CommonResourceView) restartResource(gvr *model.GVR, refresh func()) {
+	v.app.confirm(fmt.Sprintf("Restart %s %s/%s in cluster %s?", gvr.R.Kind, gvr.R.Namespace, gvr.R.Name, gvr.R.Cluster), func() {
+		v.doResourceAction(func() error { return model.RestartWorkload(v.app.client, gvr) }, refresh)
+	})
+}
+
+// scaleResource ask the replicas and scale the workload
+func (v *CommonResourceView) scaleResource(gvr *model.GVR, refresh func()) {
+	replicas, err := model.WorkloadReplicas(v.app.client, gvr)
+	if err != nil {
+		v.app.alert(err)
+		return
+	}
+	v.app.prompt(fmt.Sprintf(" Scale %s %s ", gvr.R.Kind, gvr.R.Name), "Replicas:", strconv.FormatInt(replicas, 10), func(value string) {
+		replicas, err := strconv.ParseInt(value, 10, 32)
+		if err != nil {
+			v.app.alert(errors.Errorf("the replicas %q is not a number", value))
+			return
+		}
+		v.doResourceAction(func() error { return model.ScaleWorkload(v.app.client, gvr, int32(replicas)) }, refresh)
+	})
+}
+
+// deleteResource delete the resource after the confirmation
+func (v *CommonResourceView) deleteResource(gvr *model.GVR, refresh func()) {
+	v.app.confirm(fmt.Sprintf("Delete %s %s/%s in cluster %s?", gvr.R.Kind, gvr.R.Namespace, gvr.R.Name, gvr.R.Cluster), func() {
+		v.doResourceAction(func() error { return model.DeleteResource(v.app.client, gvr) }, refresh)
+	})
+}
+
+// editResource open the manifest of the resource in the editor configured by KUBE_EDITOR or EDITOR, and
+// apply the manifest after the editor exits
+func (v *CommonResourceView) editResource(gvr *model.GVR, refresh func()) {
+	var err error
+	v.app.Suspend(func() {
+		_, err = model.EditResource(v.app.client, gvr, func(manifest []byte) ([]byte, error) {
+			edited, file, err := editor.NewDefaultEditor([]string{"KUBE_EDITOR", "EDITOR"}).LaunchTempFile("vela-top-", ".yaml", bytes.NewReader(manifest))
+			if file != "" {
+				_ = os.Remove(file)
+			}
+			return edited, err
+		})
+	})
+	v.doResourceAction(func() error { return err }, refresh)
+}
+
+// execContainer launch the shell in the container of the pod, the UI is suspended until the shell exits
+func (v *CommonResourceView) execContainer(gvr *model.GVR, container string) {
+	if v.app.config.Exec == nil {
+		v.app.alert(errors.New("exec is not supported"))
+		return
+	}
+	var err error
+	v.app.Suspend(func() {
+		err = v.app.config.Exec(context.Background(), gvr.R.Cluster, gvr.R.Namespace, gvr.R.Name, container, model.DefaultShellCommand)
+	})
+	if err != nil {
+		v.app.alert(errors.WithMessagef(err, "failed to exec into the pod %s", gvr.R.Name))
+	}
+}
+
+func (v *CommonResourceView) doResourceAction(action func() error, refresh func()) {
+	if err := action(); err != nil {
+		v.app.alert(err)
+		return
+	}
+	v.Refresh(true, refresh)
+}
@@ -0,0 +1,103 @@
+/*
+Copyright 2022 The KubeVela Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package view
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+	"github.com/stretchr/testify/assert"
+	appsv1 "k8s.io/api/apps/v1"
+	authorizationv1 "k8s.io/api/authorization/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/client-go/rest"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/oam-dev/kubevela/pkg/utils/common"
+	"github.com/oam-dev/kubevela/references/cli/top/component"
+	"github.com/oam-dev/kubevela/references/cli/top/model"
+)
+
+func TestResourceActions(t *testing.T) {
+	mapper := meta.NewDefaultRESTMapper(nil)
+	mapper.Add(appsv1.SchemeGroupVersion.WithKind("Deployment"), meta.RESTScopeNamespace)
+	mapper.Add(corev1.SchemeGroupVersion.WithKind("ConfigMap"), meta.RESTScopeNamespace)
+	c := &allowAllClient{Client: fake.NewClientBuilder().WithScheme(common.Scheme).Build(), mapper: mapper}
+	app := NewApp(c, &rest.Config{}, "")
+	ctx := context.WithValue(context.Background(), &model.CtxKeyAppName, "app")
+	ctx = context.WithValue(ctx, &model.CtxKeyNamespace, "default")
+	resourceView := new(ManagedResourceView)
+	resourceView.InitView(ctx, app)
+	resourceView.Init()
+
+	testData := [][]string{
+		{"web", "default", "Deployment", "apps/v1", "local", "web", "Healthy"},
+		{"config", "default", "ConfigMap", "v1", "local", "config", "Healthy"},
+	}
+	for i := 0; i < len(testData); i++ {
+		for j := 0; j < len(testData[i]); j++ {
+			resourceView.Table.SetCell(1+i, j, tview.NewTableCell(testData[i][j]))
+		}
+	}
+	hasAction := func(key tcell.Key) bool {
+		_, ok := resourceView.Actions()[key]
+		return ok
+	}
+
+	// no resource is selected
+	assert.False(t, hasAction(component.KeyE))
+
+	// the actions are hidden until the access is reviewed in the background
+	resourceView.Select(1, 0)
+	assert.False(t, hasAction(component.KeyE))
+	assert.Eventually(t, func() bool {
+		resourceView.Select(1, 0)
+		return hasAction(component.KeyE) && hasAction(component.KeyS) && hasAction(component.KeyT)
+	}, 5*time.Second, 10*time.Millisecond)
+
+	// the ConfigMap can't be scaled or restarted
+	assert.Eventually(t, func() bool {
+		resourceView.Select(2, 0)
+		return hasAction(component.KeyE) && hasAction(component.KeyR)
+	}, 5*time.Second, 10*time.Millisecond)
+	assert.False(t, hasAction(component.KeyS))
+	assert.False(t, hasAction(component.KeyT))
+	assert.True(t, hasAction(component.KeyR))
+}
+
+// allowAllClient allows all the reviewed access
+type allowAllClient struct {
+	client.Client
+	mapper meta.RESTMapper
+}
+
+func (c *allowAllClient) RESTMapper() meta.RESTMapper {
+	return c.mapper
+}
+
+func (c *allowAllClient) Create(ctx context.Context, obj client.Object, opts ...client.CreateOption) error {
+	if sar, ok := obj.(*authorizationv1.SelfSubjectAccessReview); ok {
+		sar.Status.Allowed = true
+		return nil
+	}
+	return c.Client.Create(ctx, obj, opts...)
+}
@@ -18,6 +18,7 @@ package view
 
 import (
 	"context"
+	"strings"
 	"time"
 
 	"github.com/gdamore/tcell/v2"
@@ -50,6 +51,8 @@ var ResourceViewMap = map[string]ResourceView{
 	"cns":       new(ClusterNamespaceView),
 	"pod":       new(PodView),
 	"container": new(ContainerView),
+	"workflow":  new(WorkflowView),
+	"event":     new(EventView),
 }
 
 // CommonResourceView is an abstract of resource view
@@ -57,6 +60,11 @@ type CommonResourceView struct {
 	*component.Table
 	app        *App
 	cancelFunc func()
+	// update is the func to update the content of the view
+	update func()
+	// filter is the filter of the view which is saved by filterName
+	filter     config.Filter
+	filterName string
 }
 
 // NewCommonView return a new common view
@@ -86,8 +94,14 @@ func (v *CommonResourceView) Name() string {
 
 // BuildHeader render the header of table
 func (v *CommonResourceView) BuildHeader(header []string) {
+	v.Table.SetHeader(header)
+	sortColumn, sortDesc := v.Table.Sort()
 	for i := 0; i < len(header); i++ {
-		c := tview.NewTableCell(header[i])
+		text := header[i]
+		if strings.EqualFold(text, sortColumn) {
+			text += sortIndicator(sortDesc)
+		}
+		c := tview.NewTableCell(text)
 		c.SetTextColor(config.ResourceTableHeaderColor)
 		c.SetExpansion(3)
 		v.SetCell(0, i, c)
@@ -96,6 +110,11 @@ func (v *CommonResourceView) BuildHeader(header []string) {
 
 // BuildBody render the body of table
 func (v *CommonResourceView) BuildBody(body [][]string) {
+	body = v.Table.FilterAndSort(body)
+	// remove the rows left by the last content
+	for row := v.GetRowCount() - 1; row > len(body); row-- {
+		v.RemoveRow(row)
+	}
 	rowNum := len(body)
 	for i := 0; i < rowNum; i++ {
 		columnNum := len(body[i])
@@ -124,6 +143,7 @@ func (v *CommonResourceView) Refresh(clear bool, update func()) {
 
 // AutoRefresh will refresh the view in every RefreshDelay delay
 func (v *CommonResourceView) AutoRefresh(update func()) {
+	v.update = update
 	var ctx context.Context
 	ctx, v.cancelFunc = context.WithCancel(context.Background())
 	go func() {
@@ -142,7 +162,17 @@ func (v *CommonResourceView) AutoRefresh(update func()) {
 func (v *CommonResourceView) bindKeys() {
 	v.Actions().Delete([]tcell.Key{tcell.KeyESC})
 	v.Actions().Add(model.KeyActions{
-		component.KeyQ:    model.KeyAction{Description: "Back", Action: v.app.Back, Visible: true, Shared: true},
-		component.KeyHelp: model.KeyAction{Description: "Help", Action: v.app.helpView, Visible: true, Shared: true},
+		component.KeyQ:     model.KeyAction{Description: "Back", Action: v.app.Back, Visible: true, Shared: true},
+		component.KeyHelp:  model.KeyAction{Description: "Help", Action: v.app.helpView, Visible: true, Shared: true},
+		component.KeySlash: model.KeyAction{Description: "Filter", Action: v.searchView, Visible: true, Shared: true},
+		component.KeyO:     model.KeyAction{Description: "Sort Column", Action: v.sortByNextColumn, Visible: true, Shared: true},
+		component.KeyI:     model.KeyAction{Description: "Invert Sort", Action: v.invertSort, Visible: true, Shared: true},
 	})
 }
+
+func sortIndicator(desc bool) string {
+	if desc {
+		return "↓"
+	}
+	return "↑"
+}
@@ -30,7 +30,7 @@ func TestResourceView(t *testing.T) {
 
 	view.Init()
 	assert.Equal(t, view.GetBorderColor(), tcell.ColorWhite)
-	assert.Equal(t, len(view.Hint()), 2)
+	assert.Equal(t, len(view.Hint()), 5)
 
 	view.BuildHeader([]string{"Name", "Data"})
 	assert.Equal(t, view.GetCell(0, 0).Text, "Name")
@@ -0,0 +1,193 @@
+/*
+Copyright 2022 The KubeVela Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package view
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/gdamore/tcell/v2"
+	workflowv1alpha1 "github.com/kubevela/workflow/api/v1alpha1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/oam-dev/kubevela/references/cli/top/component"
+	"github.com/oam-dev/kubevela/references/cli/top/config"
+	"github.com/oam-dev/kubevela/references/cli/top/model"
+)
+
+// WorkflowView is the workflow view, this view display the steps of the workflow of application
+type WorkflowView struct {
+	*CommonResourceView
+	ctx context.Context
+	// message is the result of the last workflow operation
+	message string
+}
+
+// Name return workflow view name
+func (v *WorkflowView) Name() string {
+	return "Workflow"
+}
+
+// Init the workflow view
+func (v *WorkflowView) Init() {
+	v.CommonResourceView.Init()
+	v.SetTitle(fmt.Sprintf("[ %s ]", v.Title()))
+	v.bindKeys()
+}
+
+// Start the workflow view
+func (v *WorkflowView) Start() {
+	v.Clear()
+	v.Update()
+	v.CommonResourceView.AutoRefresh(v.Update)
+}
+
+// Stop the workflow view
+func (v *WorkflowView) Stop() {
+	v.CommonResourceView.Stop()
+}
+
+// Hint return key action menu hints of the workflow view
+func (v *WorkflowView) Hint() []model.MenuHint {
+	return v.Actions().Hint()
+}
+
+// InitView init a new workflow view
+func (v *WorkflowView) InitView(ctx context.Context, app *App) {
+	v.ctx = ctx
+	v.message = ""
+	if v.CommonResourceView == nil {
+		v.CommonResourceView = NewCommonView(app)
+	}
+}
+
+// Refresh the view content
+func (v *WorkflowView) Refresh(_ *tcell.EventKey) *tcell.EventKey {
+	v.CommonResourceView.Refresh(true, v.Update)
+	return nil
+}
+
+// Update refresh the content of body of view
+func (v *WorkflowView) Update() {
+	v.SetTitle(fmt.Sprintf("[ %s ]", v.Title()))
+	v.BuildHeader()
+	v.BuildBody()
+}
+
+// BuildHeader render the header of table
+func (v *WorkflowView) BuildHeader() {
+	header := []string{"Name", "Type", "Phase", "Duration", "Message", "Outputs"}
+	v.CommonResourceView.BuildHeader(header)
+}
+
+// BuildBody render the body of table
+func (v *WorkflowView) BuildBody() {
+	steps, err := model.ListWorkflowSteps(v.ctx, v.app.client)
+	if err != nil {
+		return
+	}
+	stepInfos := steps.ToTableBody()
+	v.CommonResourceView.BuildBody(stepInfos)
+	rowNum := len(stepInfos)
+	v.ColorizePhaseText(rowNum)
+}
+
+// ColorizePhaseText colorize the phase column text
+func (v *WorkflowView) ColorizePhaseText(rowNum int) {
+	for i := 1; i < rowNum+1; i++ {
+		phase := v.Table.GetCell(i, 2).Text
+		switch workflowv1alpha1.WorkflowStepPhase(phase) {
+		case workflowv1alpha1.WorkflowStepPhaseSucceeded:
+			phase = config.WorkflowStepSucceededPhaseColor + phase
+		case workflowv1alpha1.WorkflowStepPhaseRunning:
+			phase = config.WorkflowStepRunningPhaseColor + phase
+		case workflowv1alpha1.WorkflowStepPhaseFailed:
+			phase = config.WorkflowStepFailedPhaseColor + phase
+		case workflowv1alpha1.WorkflowStepPhaseSkipped, workflowv1alpha1.WorkflowStepPhasePending:
+			phase = config.WorkflowStepSkippedPhaseColor + phase
+		default:
+		}
+		v.Table.GetCell(i, 2).SetText(phase)
+	}
+}
+
+// Title return table title of workflow view
+func (v *WorkflowView) Title() string {
+	name := v.ctx.Value(&model.CtxKeyAppName).(string)
+	namespace := v.ctx.Value(&model.CtxKeyNamespace).(string)
+	title := fmt.Sprintf("Workflow (%s/%s) [%s]", namespace, name, model.WorkflowPhase(v.ctx, v.app.client))
+	if v.message != "" {
+		title = fmt.Sprintf("%s %s", title, v.message)
+	}
+	return title
+}
+
+func (v *WorkflowView) bindKeys() {
+	v.Actions().Delete([]tcell.Key{tcell.KeyEnter})
+	v.Actions().Add(model.KeyActions{
+		component.KeyR: model.KeyAction{Description: "Refresh", Action: v.Refresh, Visible: true, Shared: true},
+		component.KeyS: model.KeyAction{Description: "Suspend", Action: v.suspend, Visible: true, Shared: true},
+		component.KeyU: model.KeyAction{Description: "Resume", Action: v.resume, Visible: true, Shared: true},
+		component.KeyT: model.KeyAction{Description: "Terminate", Action: v.terminate, Visible: true, Shared: true},
+		component.KeyE: model.KeyAction{Description: "Restart", Action: v.restart, Visible: true, Shared: true},
+		component.KeyY: model.KeyAction{Description: "Retry Step", Action: v.retryStep, Visible: true, Shared: true},
+	})
+}
+
+func (v *WorkflowView) suspend(_ *tcell.EventKey) *tcell.EventKey {
+	v.operate("suspend", model.SuspendWorkflow)
+	return nil
+}
+
+func (v *WorkflowView) resume(_ *tcell.EventKey) *tcell.EventKey {
+	v.operate("resume", model.ResumeWorkflow)
+	return nil
+}
+
+func (v *WorkflowView) terminate(_ *tcell.EventKey) *tcell.EventKey {
+	v.operate("terminate", model.TerminateWorkflow)
+	return nil
+}
+
+func (v *WorkflowView) restart(_ *tcell.EventKey) *tcell.EventKey {
+	v.operate("restart", model.RestartWorkflow)
+	return nil
+}
+
+func (v *WorkflowView) retryStep(event *tcell.EventKey) *tcell.EventKey {
+	row, _ := v.GetSelection()
+	if row == 0 {
+		return event
+	}
+	name := strings.TrimPrefix(v.GetCell(row, 0).Text, model.SubStepPrefix)
+	v.operate(fmt.Sprintf("retry step %s", name), func(ctx context.Context, c client.Client) error {
+		return model.RetryWorkflowStep(ctx, c, name)
+	})
+	return nil
+}
+
+// operate execute the workflow operation and show the result in the title, the view is refreshed
+// to display the latest status of the workflow
+func (v *WorkflowView) operate(action string, operation func(ctx context.Context, c client.Client) error) {
+	if err := operation(v.ctx, v.app.client); err != nil {
+		v.message = fmt.Sprintf("%sfailed to %s: %s", config.WorkflowStepFailedPhaseColor, action, err.Error())
+	} else {
+		v.message = fmt.Sprintf("%ssucceeded to %s", config.WorkflowStepSucceededPhaseColor, action)
+	}
+	v.CommonResourceView.Refresh(true, v.Update)
+}
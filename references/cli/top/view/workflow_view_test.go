@@ -0,0 +1,112 @@
+/*
+Copyright 2022 The KubeVela Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package view
+
+import (
+	"context"
+	"testing"
+
+	workflowv1alpha1 "github.com/kubevela/workflow/api/v1alpha1"
+	"github.com/rivo/tview"
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/rest"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	common2 "github.com/oam-dev/kubevela/apis/core.oam.dev/common"
+	"github.com/oam-dev/kubevela/apis/core.oam.dev/v1beta1"
+	"github.com/oam-dev/kubevela/pkg/utils/common"
+	"github.com/oam-dev/kubevela/references/cli/top/model"
+)
+
+func TestWorkflowView(t *testing.T) {
+	testApp := &v1beta1.Application{
+		ObjectMeta: metav1.ObjectMeta{Name: "app", Namespace: "default"},
+		Status: common2.AppStatus{Workflow: &common2.WorkflowStatus{Steps: []workflowv1alpha1.WorkflowStepStatus{
+			{StepStatus: workflowv1alpha1.StepStatus{Name: "apply", Type: "apply-component", Phase: workflowv1alpha1.WorkflowStepPhaseFailed}},
+		}}},
+	}
+	testClient := fake.NewClientBuilder().WithScheme(common.Scheme).WithObjects(testApp).Build()
+	app := NewApp(testClient, &rest.Config{}, "")
+
+	ctx := context.Background()
+	ctx = context.WithValue(ctx, &model.CtxKeyAppName, "app")
+	ctx = context.WithValue(ctx, &model.CtxKeyNamespace, "default")
+
+	workflowView := new(WorkflowView)
+
+	t.Run("init view", func(t *testing.T) {
+		assert.Empty(t, workflowView.CommonResourceView)
+		workflowView.InitView(ctx, app)
+		assert.NotEmpty(t, workflowView.CommonResourceView)
+	})
+
+	t.Run("init", func(t *testing.T) {
+		workflowView.Init()
+		assert.Equal(t, workflowView.Table.GetTitle(), "[ Workflow (default/app) [executing] ]")
+	})
+
+	t.Run("refresh", func(t *testing.T) {
+		keyEvent := workflowView.Refresh(nil)
+		assert.Empty(t, keyEvent)
+	})
+
+	t.Run("start", func(t *testing.T) {
+		workflowView.Start()
+		assert.Equal(t, workflowView.GetCell(0, 0).Text, "Name")
+		assert.Equal(t, workflowView.GetCell(1, 0).Text, "apply")
+		assert.Equal(t, workflowView.GetCell(1, 2).Text, "[red::]failed")
+	})
+
+	t.Run("retry step", func(t *testing.T) {
+		workflowView.Select(1, 0)
+		assert.Empty(t, workflowView.retryStep(nil))
+		assert.Contains(t, workflowView.message, "succeeded to retry step apply")
+		// the status of the retried step is reset, so it can't be retried again
+		workflowView.Table.SetCell(1, 0, tview.NewTableCell("apply"))
+		assert.Empty(t, workflowView.retryStep(nil))
+		assert.Contains(t, workflowView.message, "failed to retry step apply")
+	})
+
+	t.Run("stop", func(t *testing.T) {
+		workflowView.Stop()
+		assert.Equal(t, workflowView.GetCell(0, 0).Text, "")
+	})
+
+	t.Run("colorize text", func(t *testing.T) {
+		testData := [][]string{
+			{"step", "apply-component", "succeeded", "", "", ""},
+			{"step", "apply-component", "running", "", "", ""},
+			{"step", "apply-component", "failed", "", "", ""},
+			{"step", "apply-component", "pending", "", "", ""},
+		}
+		for i := 0; i < len(testData); i++ {
+			for j := 0; j < len(testData[i]); j++ {
+				workflowView.Table.SetCell(1+i, j, tview.NewTableCell(testData[i][j]))
+			}
+		}
+		workflowView.ColorizePhaseText(4)
+		assert.Equal(t, workflowView.GetCell(1, 2).Text, "[green::]succeeded")
+		assert.Equal(t, workflowView.GetCell(2, 2).Text, "[blue::]running")
+		assert.Equal(t, workflowView.GetCell(3, 2).Text, "[red::]failed")
+		assert.Equal(t, workflowView.GetCell(4, 2).Text, "[gray::]pending")
+	})
+
+	t.Run("hint", func(t *testing.T) {
+		assert.Equal(t, len(workflowView.Hint()), 11)
+	})
+}
@@ -0,0 +1,211 @@
+/*
+Copyright 2022 The KubeVela Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cli
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/gosuri/uitable"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/yaml"
+
+	"github.com/oam-dev/kubevela/apis/core.oam.dev/v1alpha1"
+	"github.com/oam-dev/kubevela/apis/types"
+	"github.com/oam-dev/kubevela/pkg/multicluster"
+	"github.com/oam-dev/kubevela/pkg/utils/common"
+	cmdutil "github.com/oam-dev/kubevela/pkg/utils/util"
+	"github.com/oam-dev/kubevela/pkg/velaql/providers/query"
+	querytypes "github.com/oam-dev/kubevela/pkg/velaql/providers/query/types"
+)
+
+// TopologyRuleCommandGroup the commands for managing the resource topology rules
+func TopologyRuleCommandGroup(c common.Args, order string, ioStreams cmdutil.IOStreams) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "topology-rule",
+		Short: "Manage the resource topology rules.",
+		Long:  "Manage the ResourceTopologyRules which decide how to discover the children resources in the resource tree of the applications.",
+		Annotations: map[string]string{
+			types.TagCommandOrder: order,
+			types.TagCommandType:  types.TypeExtension,
+		},
+	}
+	cmd.AddCommand(
+		NewTopologyRuleListCommand(c, ioStreams),
+		NewTopologyRuleTestCommand(c, ioStreams),
+	)
+	return cmd
+}
+
+// NewTopologyRuleListCommand list the resource topology rules
+func NewTopologyRuleListCommand(c common.Args, ioStreams cmdutil.IOStreams) *cobra.Command {
+	return &cobra.Command{
+		Use:     "list",
+		Aliases: []string{"ls"},
+		Short:   "List the resource topology rules.",
+		Long:    "List the resource topology rules in the cluster, the rules of the same parent resource type are sorted by the precedence.",
+		Example: "vela topology-rule list",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cli, err := c.GetClient()
+			if err != nil {
+				return err
+			}
+			rules := &v1alpha1.ResourceTopologyRuleList{}
+			if err = cli.List(context.Background(), rules); err != nil {
+				return errors.Wrapf(err, "failed to list the resource topology rules")
+			}
+			ioStreams.Info(printTopologyRules(rules.Items).String())
+			return nil
+		},
+	}
+}
+
+func printTopologyRules(rules []v1alpha1.ResourceTopologyRule) *uitable.Table {
+	sort.Slice(rules, func(i, j int) bool {
+		pi, pj := topologyRuleParent(rules[i]), topologyRuleParent(rules[j])
+		if pi != pj {
+			return pi < pj
+		}
+		if rules[i].Spec.Priority != rules[j].Spec.Priority {
+			return rules[i].Spec.Priority > rules[j].Spec.Priority
+		}
+		return rules[i].Name < rules[j].Name
+	})
+	table := newUITable().AddRow("NAME", "PARENT", "PRIORITY", "MODE", "CHILDREN", "STATUS")
+	for _, rule := range rules {
+		mode := rule.Spec.Mode
+		if mode == "" {
+			mode = v1alpha1.TopologyRuleModeMerge
+		}
+		var children []string
+		for _, child := range rule.Spec.ChildrenResourceTypes {
+			matcher := child.Matcher
+			if matcher == "" {
+				matcher = v1alpha1.TopologyChildMatcherOwnerReference
+			}
+			children = append(children, fmt.Sprintf("%s(%s)", schema.FromAPIVersionAndKind(child.APIVersion, child.Kind).GroupKind(), matcher))
+		}
+		// the invalid rules are ignored when building the resource tree
+		status := "Valid"
+		if err := query.ValidateResourceTopologyRule(rule.DeepCopy()); err != nil {
+			status = "Invalid: " + err.Error()
+		}
+		table.AddRow(rule.Name, topologyRuleParent(rule), rule.Spec.Priority, mode, strings.Join(children, ","), status)
+	}
+	return table
+}
+
+func topologyRuleParent(rule v1alpha1.ResourceTopologyRule) string {
+	return schema.GroupKind{Group: rule.Spec.ParentResourceType.Group, Kind: rule.Spec.ParentResourceType.Kind}.String()
+}
+
+// NewTopologyRuleTestCommand test a resource topology rule against a live resource
+func NewTopologyRuleTestCommand(c common.Args, ioStreams cmdutil.IOStreams) *cobra.Command {
+	var cluster string
+	cmd := &cobra.Command{
+		Use:   "test RULE NAME",
+		Short: "Test a resource topology rule against a resource.",
+		Long: "Test a resource topology rule against a resource and list the children resources discovered by the rule. " +
+			"The rule could be the name of the rule in the cluster or a local YAML file. The resource is of the parent resource type of the rule.",
+		Example: "  # Test the rule in the local file against the database named db in the default namespace\n" +
+			"  vela topology-rule test ./rule.yaml db -n default\n" +
+			"  # Test the rule in the cluster against the deployment named web in the cluster-1\n" +
+			"  vela topology-rule test deployment-children web -n default --cluster cluster-1",
+		Args: cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			namespace, err := GetFlagNamespaceOrEnv(cmd, c)
+			if err != nil {
+				return err
+			}
+			cli, err := c.GetClient()
+			if err != nil {
+				return err
+			}
+			ctx := context.Background()
+			rule, err := loadTopologyRule(ctx, cli, args[0])
+			if err != nil {
+				return err
+			}
+			children, err := testTopologyRule(ctx, cli, rule, cluster, namespace, args[1])
+			if err != nil {
+				return err
+			}
+			table := newUITable().AddRow("KIND", "NAMESPACE", "NAME", "HEALTH", "MESSAGE")
+			addTopologyRuleChildren(table, children, "")
+			ioStreams.Info(table.String())
+			ioStreams.Infof("\n%d children resources are discovered by the rule %s\n", len(children), rule.Name)
+			return nil
+		},
+	}
+	addNamespaceAndEnvArg(cmd)
+	cmd.Flags().StringVarP(&cluster, "cluster", "c", multicluster.ClusterLocalName, "specify the cluster of the resource")
+	return cmd
+}
+
+// loadTopologyRule loads the rule from the local file if it exists, otherwise gets the rule from the cluster
+func loadTopologyRule(ctx context.Context, cli client.Client, ref string) (*v1alpha1.ResourceTopologyRule, error) {
+	rule := &v1alpha1.ResourceTopologyRule{}
+	if _, err := os.Stat(ref); err == nil {
+		data, err := os.ReadFile(ref) // #nosec
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to read the rule file %s", ref)
+		}
+		if err = yaml.Unmarshal(data, rule); err != nil {
+			return nil, errors.Wrapf(err, "failed to parse the rule file %s", ref)
+		}
+		if rule.Name == "" {
+			rule.Name = ref
+		}
+		return rule, nil
+	}
+	if err := cli.Get(ctx, client.ObjectKey{Name: ref}, rule); err != nil {
+		return nil, errors.Wrapf(err, "failed to get the resource topology rule %s", ref)
+	}
+	return rule, nil
+}
+
+func testTopologyRule(ctx context.Context, cli client.Client, rule *v1alpha1.ResourceTopologyRule, cluster, namespace, name string) ([]*querytypes.ResourceTreeNode, error) {
+	gk := schema.GroupKind{Group: rule.Spec.ParentResourceType.Group, Kind: rule.Spec.ParentResourceType.Kind}
+	mapping, err := cli.RESTMapper().RESTMapping(gk)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to find the resource type %s", gk)
+	}
+	parent := querytypes.ResourceTreeNode{
+		Cluster:    cluster,
+		APIVersion: mapping.GroupVersionKind.GroupVersion().String(),
+		Kind:       mapping.GroupVersionKind.Kind,
+		Name:       name,
+	}
+	if mapping.Scope.Name() == meta.RESTScopeNameNamespace {
+		parent.Namespace = namespace
+	}
+	return query.ListChildrenByTopologyRule(ctx, cli, parent, rule)
+}
+
+func addTopologyRuleChildren(table *uitable.Table, children []*querytypes.ResourceTreeNode, indent string) {
+	for _, child := range children {
+		table.AddRow(indent+child.Kind, child.Namespace, child.Name, child.HealthStatus.Status, child.HealthStatus.Message)
+		addTopologyRuleChildren(table, child.LeafNodes, indent+"  ")
+	}
+}
@@ -0,0 +1,97 @@
+/*
+Copyright 2022 The KubeVela Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cli
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/oam-dev/kubevela/apis/core.oam.dev/v1alpha1"
+	common2 "github.com/oam-dev/kubevela/pkg/utils/common"
+)
+
+func TestTopologyRule(t *testing.T) {
+	r := require.New(t)
+	mapper := meta.NewDefaultRESTMapper([]schema.GroupVersion{appsv1.SchemeGroupVersion})
+	mapper.Add(appsv1.SchemeGroupVersion.WithKind("Deployment"), meta.RESTScopeNamespace)
+	rule := &v1alpha1.ResourceTopologyRule{
+		ObjectMeta: metav1.ObjectMeta{Name: "deployment-configs"},
+		Spec: v1alpha1.ResourceTopologyRuleSpec{
+			ParentResourceType: v1alpha1.TopologyParentResourceType{Group: "apps", Kind: "Deployment"},
+			ChildrenResourceTypes: []v1alpha1.TopologyChildResourceType{{
+				APIVersion: "v1", Kind: "ConfigMap", Matcher: v1alpha1.TopologyChildMatcherLabel,
+				MatchLabels: map[string]string{"app": "{{ .metadata.name }}"},
+			}},
+			Priority: 10,
+		},
+	}
+	cli := fake.NewClientBuilder().WithScheme(common2.Scheme).WithRESTMapper(mapper).WithObjects(
+		rule,
+		&appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "default"}},
+		&corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "web-config", Namespace: "default", Labels: map[string]string{"app": "web"}}},
+		&corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "web-config", Namespace: "other", Labels: map[string]string{"app": "web"}}},
+	).Build()
+	ctx := context.Background()
+
+	loaded, err := loadTopologyRule(ctx, cli, "deployment-configs")
+	r.NoError(err)
+	r.Equal(rule.Spec, loaded.Spec)
+	file := filepath.Join(t.TempDir(), "rule.yaml")
+	r.NoError(os.WriteFile(file, []byte(`apiVersion: core.oam.dev/v1alpha1
+kind: ResourceTopologyRule
+spec:
+  parentResourceType:
+    group: apps
+    kind: Deployment
+  childrenResourceTypes:
+  - apiVersion: v1
+    kind: ConfigMap
+`), 0600))
+	loaded, err = loadTopologyRule(ctx, cli, file)
+	r.NoError(err)
+	r.Equal(file, loaded.Name)
+	r.Len(loaded.Spec.ChildrenResourceTypes, 1)
+	_, err = loadTopologyRule(ctx, cli, "not-exist")
+	r.Contains(err.Error(), "failed to get the resource topology rule not-exist")
+
+	children, err := testTopologyRule(ctx, cli, rule, "local", "default", "web")
+	r.NoError(err)
+	r.Len(children, 1)
+	r.Equal("default", children[0].Namespace)
+	r.Equal("web-config", children[0].Name)
+	_, err = testTopologyRule(ctx, cli, rule, "local", "default", "api")
+	r.Error(err)
+
+	invalid := rule.DeepCopy()
+	invalid.Name = "invalid"
+	invalid.Spec.Mode = "Replace"
+	table := printTopologyRules([]v1alpha1.ResourceTopologyRule{*rule, *loaded, *invalid}).String()
+	r.Contains(table, "deployment-configs")
+	r.Regexp(`deployment-configs\s+Deployment.apps\s+10\s+Merge\s+ConfigMap\(Label\)\s+Valid`, table)
+	r.Regexp(`rule.yaml\s+Deployment.apps\s+0\s+Merge\s+ConfigMap\(OwnerReference\)\s+Valid`, table)
+	r.Regexp(`invalid\s+Deployment.apps\s+10\s+Replace\s+ConfigMap\(Label\)\s+Invalid: spec.mode: Unsupported value`, table)
+}
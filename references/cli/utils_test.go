@@ -20,6 +20,8 @@ import (
 	"strings"
 	"testing"
 
+	"github.com/pkg/errors"
+
 	"github.com/google/go-cmp/cmp"
 
 	"gotest.tools/assert"
@@ -277,3 +279,10 @@ status:
 		})
 	}
 }
+
+func TestGetExitCode(t *testing.T) {
+	assert.Equal(t, 0, GetExitCode(nil))
+	assert.Equal(t, 1, GetExitCode(errors.New("failed")))
+	assert.Equal(t, 2, GetExitCode(&ExitCodeError{Code: 2, Reason: "differences found"}))
+	assert.Equal(t, 2, GetExitCode(errors.Wrap(&ExitCodeError{Code: 2}, "wrapped")))
+}
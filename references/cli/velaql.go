@@ -22,6 +22,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"regexp"
+	"sort"
 	"strings"
 
 	"github.com/spf13/cobra"
@@ -112,6 +113,7 @@ export: "status"
 
 	// Add subcommands like `create`, to `vela ql`
 	cmd.AddCommand(NewQLApplyCommand(c))
+	cmd.AddCommand(NewQLQueryCommand(c))
 	// TODO(charlie0129): add `vela ql delete` command to delete created views (ConfigMaps)
 	// TODO(charlie0129): add `vela ql list` command to list user-created views (and views installed from addons, if that's feasible)
 
@@ -197,6 +199,102 @@ If view name cannot be inferred, or you are reading from stdin (-f -), you must
 	return cmd
 }
 
+// NewQLQueryCommand runs the ad-hoc query in the SQL-like syntax
+func NewQLQueryCommand(c common.Args) *cobra.Command {
+	var output string
+	cmd := &cobra.Command{
+		Use:   "query <statement>",
+		Short: "Run an ad-hoc query over applications",
+		Long: `Run an ad-hoc query over applications in the SQL-like syntax without writing a view:
+
+	SELECT <* | field, ...> FROM <source> [alias] [JOIN <source> [alias] ON <field> = <field>]
+	[WHERE <condition>] [ORDER BY <field> [ASC | DESC]] [LIMIT <n>]
+
+The condition is composed of "field <op> value" by AND, OR and parentheses, the op could be
+=, !=, <, <=, >, >=, LIKE and IN. The fields could be qualified by the alias of the source if
+the name is ambiguous in a join.
+
+The sources and their fields are:
+` + querySourcesUsage(),
+		Example: `List the unhealthy components:
+	vela ql query "SELECT app, name, cluster, message FROM components WHERE healthy = false"
+
+List the deployments of the applications in the namespace prod:
+	vela ql query "SELECT app, cluster, name FROM resources WHERE namespace = prod AND kind = Deployment"
+
+List the resources dispatched to the clusters of the region us-west:
+	vela ql query "SELECT r.app, r.kind, r.name, c.name FROM resources r JOIN clusters c ON r.cluster = c.name WHERE c.labels LIKE '%region=us-west%'"
+
+List the latest failed workflow steps in json:
+	vela ql query "SELECT * FROM workflow WHERE phase = failed ORDER BY lastExecuteTime DESC LIMIT 10" -o json`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if output != "" && output != "table" && output != "json" && output != "yaml" {
+				return fmt.Errorf("output format %s is not supported, only table, json and yaml are supported", output)
+			}
+			stmt, err := velaql.ParseSelect(args[0])
+			if err != nil {
+				return err
+			}
+			k8sClient, err := c.GetClient()
+			if err != nil {
+				return err
+			}
+			result, err := velaql.ExecuteSelect(context.Background(), k8sClient, stmt)
+			if err != nil {
+				return err
+			}
+			return printQueryResult(cmd, result, output)
+		},
+	}
+	cmd.Flags().StringVarP(&output, "output", "o", "table", "Output format of the query result. One of: (table, json, yaml)")
+	return cmd
+}
+
+func querySourcesUsage() string {
+	sources := velaql.QuerySources()
+	names := make([]string, 0, len(sources))
+	for name := range sources {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	usage := ""
+	for _, name := range names {
+		usage += fmt.Sprintf("	%s: %s\n", name, strings.Join(sources[name], ", "))
+	}
+	return usage
+}
+
+func printQueryResult(cmd *cobra.Command, result *velaql.QueryResult, output string) error {
+	for _, warning := range result.Warnings {
+		cmd.PrintErrf("Warning: %s\n", warning)
+	}
+	if output == "json" || output == "yaml" {
+		str, err := printObj(output, result.Records())
+		if err != nil {
+			return err
+		}
+		cmd.Println(strings.TrimSpace(str))
+		return nil
+	}
+	table := newUITable()
+	var header []interface{}
+	for _, column := range result.Columns {
+		header = append(header, strings.ToUpper(column))
+	}
+	table.AddRow(header...)
+	for _, row := range result.TableRows() {
+		var cells []interface{}
+		for _, cell := range row {
+			cells = append(cells, cell)
+		}
+		table.AddRow(cells...)
+	}
+	cmd.Println(table.String())
+	cmd.Printf("\n%d rows.\n", len(result.Rows))
+	return nil
+}
+
 // queryFromStatement print velaQL result from query statement with inner query view
 func queryFromStatement(ctx context.Context, velaC common.Args, velaQLStatement string, cmd *cobra.Command) error {
 	queryView, err := velaql.ParseVelaQL(velaQLStatement)
@@ -39,7 +39,7 @@ func main() {
 	command := cli.NewCommand()
 
 	if err := command.Execute(); err != nil {
-		os.Exit(1)
+		os.Exit(cli.GetExitCode(err))
 	}
 
 	if err := stdlib.SetupBuiltinImports(); err != nil {
@@ -0,0 +1,232 @@
+/*
+ Copyright 2022 The KubeVela Authors.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+ 	http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package docgen
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi3"
+
+	"github.com/oam-dev/kubevela/apis/types"
+	"github.com/oam-dev/kubevela/pkg/cue/script"
+)
+
+// ParameterSchema is the schema of one parameter flattened from the parameter schema of a definition,
+// the path of the nested parameter is joined by "." and the elements of array are marked by "[]"
+type ParameterSchema struct {
+	Path     string      `json:"path"`
+	Type     string      `json:"type"`
+	Required bool        `json:"required"`
+	Default  interface{} `json:"default,omitempty"`
+}
+
+// ParameterChangeType is the type of the change of a parameter between two versions of a definition
+type ParameterChangeType string
+
+const (
+	// ParameterAdded means the parameter is added
+	ParameterAdded ParameterChangeType = "Added"
+	// ParameterRemoved means the parameter is removed
+	ParameterRemoved ParameterChangeType = "Removed"
+	// ParameterTypeChanged means the type of the parameter is changed
+	ParameterTypeChanged ParameterChangeType = "TypeChanged"
+	// ParameterRequiredChanged means the parameter becomes required or optional
+	ParameterRequiredChanged ParameterChangeType = "RequiredChanged"
+	// ParameterDefaultChanged means the default value of the parameter is changed
+	ParameterDefaultChanged ParameterChangeType = "DefaultChanged"
+)
+
+// ParameterChange is the change of a parameter between two versions of a definition
+type ParameterChange struct {
+	Type ParameterChangeType `json:"type"`
+	Path string              `json:"path"`
+	Old  *ParameterSchema    `json:"old,omitempty"`
+	New  *ParameterSchema    `json:"new,omitempty"`
+}
+
+// String return the readable description of the change
+func (c ParameterChange) String() string {
+	switch c.Type {
+	case ParameterAdded:
+		if c.New.Required {
+			return fmt.Sprintf("added required parameter `%s` (%s)", c.Path, c.New.Type)
+		}
+		return fmt.Sprintf("added parameter `%s` (%s)", c.Path, c.New.Type)
+	case ParameterRemoved:
+		return fmt.Sprintf("removed parameter `%s`", c.Path)
+	case ParameterTypeChanged:
+		return fmt.Sprintf("changed the type of `%s` from %s to %s", c.Path, c.Old.Type, c.New.Type)
+	case ParameterRequiredChanged:
+		if c.New.Required {
+			return fmt.Sprintf("parameter `%s` becomes required", c.Path)
+		}
+		return fmt.Sprintf("parameter `%s` becomes optional", c.Path)
+	case ParameterDefaultChanged:
+		return fmt.Sprintf("changed the default value of `%s` from %s to %s", c.Path, printableValue(c.Old.Default), printableValue(c.New.Default))
+	default:
+		return fmt.Sprintf("%s `%s`", c.Type, c.Path)
+	}
+}
+
+// Breaking return whether the change may break the existing applications using the definition, which are
+// the removed parameters, the changed types and the new required parameters without default value.
+// Changing the default value is compatible though it may change the behavior of the rendered resources.
+func (c ParameterChange) Breaking() bool {
+	switch c.Type {
+	case ParameterRemoved, ParameterTypeChanged:
+		return true
+	case ParameterAdded:
+		return c.New.Required && c.New.Default == nil
+	case ParameterRequiredChanged:
+		return c.New.Required && c.New.Default == nil
+	default:
+		return false
+	}
+}
+
+// ParseParameterSchema parse the parameter of the CUE template of the capability into the flattened parameter schema
+func ParseParameterSchema(capability types.Capability) (map[string]ParameterSchema, error) {
+	if capability.Category != types.CUECategory {
+		return nil, fmt.Errorf("the parameter schema of %s capability %s is not supported", capability.Category, capability.Name)
+	}
+	schema, err := script.CUE(capability.CueTemplate).ParsePropertiesToSchema()
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse the parameter schema of %s: %w", capability.Name, err)
+	}
+	return FlattenParameterSchema(schema), nil
+}
+
+// FlattenParameterSchema flatten the nested properties of the schema into parameters by path
+func FlattenParameterSchema(schema *openapi3.Schema) map[string]ParameterSchema {
+	params := map[string]ParameterSchema{}
+	var walk func(prefix string, s *openapi3.Schema)
+	walk = func(prefix string, s *openapi3.Schema) {
+		required := map[string]bool{}
+		for _, name := range s.Required {
+			required[name] = true
+		}
+		for name, ref := range s.Properties {
+			if ref == nil || ref.Value == nil {
+				continue
+			}
+			property := ref.Value
+			path := name
+			if prefix != "" {
+				path = prefix + "." + name
+			}
+			params[path] = ParameterSchema{Path: path, Type: schemaType(property), Required: required[name], Default: property.Default}
+			switch {
+			case property.Type == "object":
+				walk(path, property)
+			case property.Type == "array" && property.Items != nil && property.Items.Value != nil && property.Items.Value.Type == "object":
+				walk(path+"[]", property.Items.Value)
+			}
+		}
+	}
+	if schema != nil {
+		walk("", schema)
+	}
+	return params
+}
+
+// DiffParameters compare the parameters of two versions of a definition, the changes are in the order of path.
+// The nested parameters of an added or removed parameter are not reported separately.
+func DiffParameters(oldParams, newParams map[string]ParameterSchema) []ParameterChange {
+	var changes []ParameterChange
+	for path, o := range oldParams {
+		o := o
+		n, ok := newParams[path]
+		if !ok {
+			if _, parentRemoved := oldParams[parentPath(path)]; parentRemoved && !hasPath(newParams, parentPath(path)) {
+				continue
+			}
+			changes = append(changes, ParameterChange{Type: ParameterRemoved, Path: path, Old: &o})
+			continue
+		}
+		switch {
+		case o.Type != n.Type:
+			changes = append(changes, ParameterChange{Type: ParameterTypeChanged, Path: path, Old: &o, New: &n})
+		case o.Required != n.Required:
+			changes = append(changes, ParameterChange{Type: ParameterRequiredChanged, Path: path, Old: &o, New: &n})
+		case !reflect.DeepEqual(o.Default, n.Default):
+			changes = append(changes, ParameterChange{Type: ParameterDefaultChanged, Path: path, Old: &o, New: &n})
+		}
+	}
+	for path, n := range newParams {
+		n := n
+		if _, ok := oldParams[path]; ok {
+			continue
+		}
+		if _, parentAdded := newParams[parentPath(path)]; parentAdded && !hasPath(oldParams, parentPath(path)) {
+			continue
+		}
+		changes = append(changes, ParameterChange{Type: ParameterAdded, Path: path, New: &n})
+	}
+	sort.Slice(changes, func(i, j int) bool { return changes[i].Path < changes[j].Path })
+	return changes
+}
+
+func schemaType(s *openapi3.Schema) string {
+	switch s.Type {
+	case "array":
+		if s.Items != nil && s.Items.Value != nil {
+			return "[]" + schemaType(s.Items.Value)
+		}
+		return "[]"
+	case "object":
+		if s.AdditionalProperties != nil && s.AdditionalProperties.Value != nil {
+			return "map[string]" + schemaType(s.AdditionalProperties.Value)
+		}
+		return "object"
+	case "":
+		if len(s.OneOf) > 0 || len(s.AnyOf) > 0 {
+			return "composite"
+		}
+		return "any"
+	default:
+		return s.Type
+	}
+}
+
+// parentPath return the path of the parent parameter, or empty for the top level parameter
+func parentPath(path string) string {
+	i := strings.LastIndex(path, ".")
+	if i < 0 {
+		return ""
+	}
+	return strings.TrimSuffix(path[:i], "[]")
+}
+
+func hasPath(params map[string]ParameterSchema, path string) bool {
+	_, ok := params[path]
+	return ok
+}
+
+func printableValue(v interface{}) string {
+	if v == nil {
+		return "none"
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprintf("%v", v)
+	}
+	return string(data)
+}
@@ -0,0 +1,80 @@
+/*
+ Copyright 2022 The KubeVela Authors.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+ 	http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package docgen
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/oam-dev/kubevela/apis/types"
+)
+
+func TestDiffParameters(t *testing.T) {
+	oldCap := types.Capability{Name: "worker", Category: types.CUECategory, CueTemplate: `
+parameter: {
+	image: string
+	port: *80 | int
+	cmd?: [...string]
+	env?: [...{
+		name: string
+		value?: string
+	}]
+	volumes?: {
+		path: string
+	}
+}
+`}
+	newCap := types.Capability{Name: "worker", Category: types.CUECategory, CueTemplate: `
+parameter: {
+	image: string
+	port: *8080 | int
+	cmd?: string
+	env: [...{
+		name: string
+		value?: string
+	}]
+	resources?: {
+		cpu: string
+	}
+}
+`}
+	oldParams, err := ParseParameterSchema(oldCap)
+	assert.NoError(t, err)
+	assert.Equal(t, ParameterSchema{Path: "env[].name", Type: "string", Required: true}, oldParams["env[].name"])
+	newParams, err := ParseParameterSchema(newCap)
+	assert.NoError(t, err)
+
+	var descriptions []string
+	var breaking []bool
+	for _, change := range DiffParameters(oldParams, newParams) {
+		descriptions = append(descriptions, change.String())
+		breaking = append(breaking, change.Breaking())
+	}
+	assert.Equal(t, []string{
+		"changed the type of `cmd` from []string to string",
+		"parameter `env` becomes required",
+		"changed the default value of `port` from 80 to 8080",
+		"added parameter `resources` (object)",
+		"removed parameter `volumes`",
+	}, descriptions)
+	assert.Equal(t, []bool{true, true, false, false, true}, breaking)
+	assert.Empty(t, DiffParameters(oldParams, oldParams))
+
+	_, err = ParseParameterSchema(types.Capability{Name: "tf", Category: types.TerraformCategory})
+	assert.Error(t, err)
+}
@@ -20,7 +20,9 @@ import (
 	"context"
 	"fmt"
 	"os"
+	"sort"
 	"strings"
+	"time"
 
 	"github.com/pkg/errors"
 	kerrors "k8s.io/apimachinery/pkg/api/errors"
@@ -544,8 +546,66 @@ func GetCapabilityFromDefinitionRevision(ctx context.Context, c common.Args, pd
 		return nil, fmt.Errorf("no %s with revision %d found in namespace %s or %s", defName, r, ns, types.DefaultKubeVelaNS)
 	}
 
-	rev := revs[0]
+	return capabilityFromDefinitionRevision(c, pd, revs[0])
+}
+
+// capabilityDefinitionTypes are the definition types of the capability types
+var capabilityDefinitionTypes = map[types.CapType]commontypes.DefinitionType{
+	types.TypeComponentDefinition: commontypes.ComponentType,
+	types.TypeTrait:               commontypes.TraitType,
+	types.TypePolicy:              commontypes.PolicyType,
+	types.TypeWorkflowStep:        commontypes.WorkflowStepType,
+}
+
+// CapabilityRevision is the capability of a DefinitionRevision of the definition
+type CapabilityRevision struct {
+	Revision   int64
+	Hash       string
+	CreateTime time.Time
+	Capability types.Capability
+}
+
+// ListCapabilityRevisions list the capabilities of all DefinitionRevisions of the definition of the capability type
+// in the order of revision, the revisions are searched in the vela-system namespace if not found in the given namespace
+func ListCapabilityRevisions(ctx context.Context, c common.Args, pd *packages.PackageDiscover, ns, defName string, capType types.CapType) ([]CapabilityRevision, error) {
+	defType, ok := capabilityDefinitionTypes[capType]
+	if !ok {
+		// only the definitions of the types have revisions
+		return nil, nil
+	}
+	k8sClient, err := c.GetClient()
+	if err != nil {
+		return nil, err
+	}
+	revs, err := definition.SearchDefinitionRevisions(ctx, k8sClient, ns, defName, defType, 0)
+	if err != nil {
+		return nil, err
+	}
+	if len(revs) == 0 && ns != types.DefaultKubeVelaNS {
+		revs, err = definition.SearchDefinitionRevisions(ctx, k8sClient, types.DefaultKubeVelaNS, defName, defType, 0)
+		if err != nil {
+			return nil, err
+		}
+	}
+	var capRevs []CapabilityRevision
+	for _, rev := range revs {
+		capability, err := capabilityFromDefinitionRevision(c, pd, rev)
+		if err != nil {
+			klog.Warningf("failed to get the capability of the revision %d of %s: %v", rev.Spec.Revision, defName, err)
+			continue
+		}
+		capRevs = append(capRevs, CapabilityRevision{
+			Revision:   rev.Spec.Revision,
+			Hash:       rev.Spec.RevisionHash,
+			CreateTime: rev.CreationTimestamp.Time,
+			Capability: *capability,
+		})
+	}
+	sort.Slice(capRevs, func(i, j int) bool { return capRevs[i].Revision < capRevs[j].Revision })
+	return capRevs, nil
+}
 
+func capabilityFromDefinitionRevision(c common.Args, pd *packages.PackageDiscover, rev v1beta1.DefinitionRevision) (*types.Capability, error) {
 	switch rev.Spec.DefinitionType {
 	case commontypes.ComponentType:
 		var refName string
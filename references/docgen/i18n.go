@@ -184,4 +184,68 @@ var i18nDoc = map[string]map[Language]string{
 		LangZh: "适用于组件类型",
 		LangEn: "Apply To Component Types",
 	},
+	"Definition Reference": {
+		LangZh: "定义参考",
+		LangEn: "Definition Reference",
+	},
+	"Components": {
+		LangZh: "组件",
+		LangEn: "Components",
+	},
+	"Traits": {
+		LangZh: "运维特征",
+		LangEn: "Traits",
+	},
+	"Policies": {
+		LangZh: "策略",
+		LangEn: "Policies",
+	},
+	"Workflow Steps": {
+		LangZh: "工作流步骤",
+		LangEn: "Workflow Steps",
+	},
+	"Applicable Components": {
+		LangZh: "适用的组件",
+		LangEn: "Applicable Components",
+	},
+	"Applicable Traits": {
+		LangZh: "适用的运维特征",
+		LangEn: "Applicable Traits",
+	},
+	"Version History": {
+		LangZh: "版本历史",
+		LangEn: "Version History",
+	},
+	"Revision": {
+		LangZh: "版本",
+		LangEn: "Revision",
+	},
+	"Hash": {
+		LangZh: "哈希",
+		LangEn: "Hash",
+	},
+	"Created": {
+		LangZh: "创建时间",
+		LangEn: "Created",
+	},
+	"Changelog": {
+		LangZh: "变更记录",
+		LangEn: "Changelog",
+	},
+	"Initial revision.": {
+		LangZh: "初始版本。",
+		LangEn: "Initial revision.",
+	},
+	"No parameter changes.": {
+		LangZh: "参数没有变化。",
+		LangEn: "No parameter changes.",
+	},
+	"The example has been validated by rendering": {
+		LangZh: "示例已通过渲染校验",
+		LangEn: "The example has been validated by rendering",
+	},
+	"The example failed to be validated by rendering": {
+		LangZh: "示例渲染校验失败",
+		LangEn: "The example failed to be validated by rendering",
+	},
 }
@@ -670,6 +670,9 @@ func GetBaseResourceKinds(cueStr string, pd *packages.PackageDiscover, dm discov
 	if len(GroupAndVersion) == 1 {
 		GroupAndVersion = append([]string{""}, GroupAndVersion...)
 	}
+	if dm == nil {
+		return "", errors.Errorf("no discovery mapper to get the resource of %s", kind)
+	}
 	gvr, err := dm.ResourcesFor(schema.GroupVersionKind{
 		Group:   GroupAndVersion[0],
 		Version: GroupAndVersion[1],
@@ -0,0 +1,381 @@
+/*
+ Copyright 2022 The KubeVela Authors.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+ 	http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package docgen
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+	"k8s.io/klog/v2"
+	"sigs.k8s.io/yaml"
+
+	"github.com/kubevela/workflow/pkg/cue/packages"
+
+	"github.com/oam-dev/kubevela/apis/core.oam.dev/v1beta1"
+	"github.com/oam-dev/kubevela/apis/types"
+)
+
+const (
+	// SiteIndexFile is the name of the index page of the definition site
+	SiteIndexFile = "index.md"
+	// SiteSearchIndexFile is the name of the search index of the definition site
+	SiteSearchIndexFile = "search-index.json"
+)
+
+// siteSections are the sections of the definition site, each type of definitions is in its own directory
+var siteSections = []struct {
+	Type  types.CapType
+	Dir   string
+	Title string
+}{
+	{Type: types.TypeComponentDefinition, Dir: "components", Title: "Components"},
+	{Type: types.TypeTrait, Dir: "traits", Title: "Traits"},
+	{Type: types.TypePolicy, Dir: "policies", Title: "Policies"},
+	{Type: types.TypeWorkflowStep, Dir: "workflowsteps", Title: "Workflow Steps"},
+}
+
+var yamlBlockRegexp = regexp.MustCompile("(?s)```ya?ml\\s*\\n(.*?)```")
+
+// SiteGenerator renders the definitions into a static site, with an index page, a search index, cross-links
+// between the traits and the components they apply to, the version history of the definitions and the
+// examples validated by rendering
+type SiteGenerator struct {
+	MarkdownReference
+	// Revisions return the revisions of the capability in the order of revision, the version history is
+	// not generated if it's nil
+	Revisions func(ctx context.Context, capability types.Capability) ([]CapabilityRevision, error)
+	// ValidateExample validates the application in the examples by rendering it, the examples are not
+	// validated if it's nil
+	ValidateExample func(ctx context.Context, app *v1beta1.Application) error
+}
+
+// SiteReport is the result of generating the definition site
+type SiteReport struct {
+	Pages int
+	// Examples is the number of the applications in the examples validated
+	Examples int
+	// ExampleErrors are the errors of rendering the examples by the definition name
+	ExampleErrors map[string]error
+}
+
+// SearchIndexEntry is the entry of a definition in the search index
+type SearchIndexEntry struct {
+	Name        string   `json:"name"`
+	Title       string   `json:"title"`
+	Type        string   `json:"type"`
+	Description string   `json:"description"`
+	Path        string   `json:"path"`
+	Parameters  []string `json:"parameters,omitempty"`
+	AppliesTo   []string `json:"appliesTo,omitempty"`
+}
+
+// Generate the site of the capabilities into the directory
+func (g *SiteGenerator) Generate(ctx context.Context, caps []types.Capability, pd *packages.PackageDiscover, dir string) (*SiteReport, error) {
+	if g.I18N == nil {
+		g.I18N = &En
+	}
+	g.DisplayFormat = Markdown
+	sort.Slice(caps, func(i, j int) bool { return caps[i].Name < caps[j].Name })
+	var filtered []types.Capability
+	for _, c := range caps {
+		if g.Filter != nil && !g.Filter(c) {
+			continue
+		}
+		if sitePath(c) == "" {
+			klog.Warningf("skip the %s %s which is not supported in the site", c.Type, c.Name)
+			continue
+		}
+		filtered = append(filtered, c)
+	}
+
+	report := &SiteReport{ExampleErrors: map[string]error{}}
+	var index []SearchIndexEntry
+	for _, c := range filtered {
+		c.Example = g.validateExample(ctx, c, report)
+		capDoc, err := g.GenerateMarkdownForCap(ctx, c, pd, false)
+		if err != nil {
+			return nil, err
+		}
+		capDoc += g.crossLinks(c, filtered)
+		history, err := g.versionHistory(ctx, c, pd, dir, report)
+		if err != nil {
+			return nil, err
+		}
+		capDoc += history
+		if err = writeSiteFile(dir, sitePath(c), capDoc); err != nil {
+			return nil, err
+		}
+		report.Pages++
+		index = append(index, g.searchIndexEntry(c))
+	}
+
+	if err := writeSiteFile(dir, SiteIndexFile, g.indexPage(filtered)); err != nil {
+		return nil, err
+	}
+	data, err := json.MarshalIndent(index, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	if err = writeSiteFile(dir, SiteSearchIndexFile, string(data)); err != nil {
+		return nil, err
+	}
+	return report, nil
+}
+
+// validateExample validates the applications in the example of the capability, the result is noted in the returned example
+func (g *SiteGenerator) validateExample(ctx context.Context, c types.Capability, report *SiteReport) string {
+	example := c.Example
+	if example == "" {
+		example = DefinitionDocSamples[c.Name]
+	}
+	if g.ValidateExample == nil || example == "" {
+		return example
+	}
+	apps, err := exampleApplications(example)
+	if err == nil {
+		for _, app := range apps {
+			report.Examples++
+			if err = g.ValidateExample(ctx, app); err != nil {
+				err = errors.WithMessagef(err, "render the application %s", app.Name)
+				break
+			}
+		}
+	}
+	switch {
+	case err != nil:
+		report.ExampleErrors[c.Name] = err
+		return example + "\n\n> " + g.I18N.Get("The example failed to be validated by rendering") + ": " + strings.ReplaceAll(err.Error(), "\n", " ")
+	case len(apps) > 0:
+		return example + "\n\n> " + g.I18N.Get("The example has been validated by rendering") + g.I18N.Get(".")
+	default:
+		return example
+	}
+}
+
+// crossLinks links the trait to the components it applies to, or the component to the traits applicable to it
+func (g *SiteGenerator) crossLinks(c types.Capability, caps []types.Capability) string {
+	var title string
+	var links []string
+	switch c.Type {
+	case types.TypeTrait:
+		title = "Applicable Components"
+		for _, comp := range caps {
+			if comp.Type == types.TypeComponentDefinition && traitAppliesTo(c, comp) {
+				links = append(links, g.siteLink(c, comp))
+			}
+		}
+	case types.TypeComponentDefinition:
+		title = "Applicable Traits"
+		for _, trait := range caps {
+			if trait.Type == types.TypeTrait && traitAppliesTo(trait, c) {
+				links = append(links, g.siteLink(c, trait))
+			}
+		}
+	default:
+		return ""
+	}
+	if len(links) == 0 {
+		return ""
+	}
+	return fmt.Sprintf("\n\n## %s\n\n- %s\n", g.I18N.Get(title), strings.Join(links, "\n- "))
+}
+
+// versionHistory render the revisions of the capability and the changelog of the parameters between revisions,
+// each revision is rendered into its own page as well
+func (g *SiteGenerator) versionHistory(ctx context.Context, c types.Capability, pd *packages.PackageDiscover, dir string, report *SiteReport) (string, error) {
+	if g.Revisions == nil {
+		return "", nil
+	}
+	revs, err := g.Revisions(ctx, c)
+	if err != nil {
+		klog.Warningf("failed to get the revisions of %s: %v", c.Name, err)
+		return "", nil
+	}
+	if len(revs) == 0 {
+		return "", nil
+	}
+	doc := fmt.Sprintf("\n\n## %s\n\n", g.I18N.Get("Version History"))
+	doc += fmt.Sprintf(" %s | %s | %s \n ------- | ---- | ------- \n", g.I18N.Get("Revision"), g.I18N.Get("Hash"), g.I18N.Get("Created"))
+	var previous map[string]ParameterSchema
+	for i := len(revs) - 1; i >= 0; i-- {
+		rev := revs[i]
+		revPath := revisionSitePath(c, rev.Revision)
+		revDoc, err := g.GenerateMarkdownForCap(ctx, rev.Capability, pd, false)
+		if err != nil {
+			return "", err
+		}
+		// mark the revision in the title of the page, which is the first line ending the front matter
+		revDoc = strings.Replace(revDoc, "\n---", fmt.Sprintf(" (v%d)\n---", rev.Revision), 1)
+		if err = writeSiteFile(dir, revPath, revDoc); err != nil {
+			return "", err
+		}
+		report.Pages++
+		doc += fmt.Sprintf(" [v%d](%s) | %s | %s \n", rev.Revision, relativeLink(sitePath(c), revPath), rev.Hash, rev.CreateTime.Format("2006-01-02 15:04:05"))
+	}
+	var entries []string
+	for _, rev := range revs {
+		params, err := ParseParameterSchema(rev.Capability)
+		if err != nil {
+			klog.Warningf("failed to parse the parameters of the revision %d of %s: %v", rev.Revision, c.Name, err)
+			previous = nil
+			continue
+		}
+		changes := []string{g.I18N.Get("Initial revision.")}
+		if previous != nil {
+			changes = nil
+			for _, change := range DiffParameters(previous, params) {
+				changes = append(changes, change.String())
+			}
+			if len(changes) == 0 {
+				changes = []string{g.I18N.Get("No parameter changes.")}
+			}
+		}
+		entries = append(entries, fmt.Sprintf("\n#### v%d\n\n- %s\n", rev.Revision, strings.Join(changes, "\n- ")))
+		previous = params
+	}
+	if len(entries) == 0 {
+		return doc, nil
+	}
+	changelog := fmt.Sprintf("\n\n### %s\n", g.I18N.Get("Changelog"))
+	// the latest change comes first
+	for i := len(entries) - 1; i >= 0; i-- {
+		changelog += entries[i]
+	}
+	return doc + changelog, nil
+}
+
+// indexPage render the index of the definitions grouped by type
+func (g *SiteGenerator) indexPage(caps []types.Capability) string {
+	doc := fmt.Sprintf("---\ntitle:  %s\n---\n", g.I18N.Get("Definition Reference"))
+	for _, section := range siteSections {
+		var rows string
+		for _, c := range caps {
+			if c.Type != section.Type {
+				continue
+			}
+			rows += fmt.Sprintf(" [%s](%s) | %s \n", c.Name, sitePath(c), g.formatTableString(strings.TrimSpace(g.I18N.Get(c.Description))))
+		}
+		if rows == "" {
+			continue
+		}
+		doc += fmt.Sprintf("\n## %s\n\n %s | %s \n ---- | ----------- \n%s", g.I18N.Get(section.Title), g.I18N.Get("Name"), g.I18N.Get("Description"), rows)
+	}
+	return doc
+}
+
+func (g *SiteGenerator) searchIndexEntry(c types.Capability) SearchIndexEntry {
+	entry := SearchIndexEntry{
+		Name:        c.Name,
+		Title:       g.makeReadableTitle(c.Name),
+		Type:        string(c.Type),
+		Description: c.Description,
+		Path:        sitePath(c),
+		AppliesTo:   c.AppliesTo,
+	}
+	if c.Category == types.CUECategory {
+		if params, err := ParseParameterSchema(c); err == nil {
+			for name := range params {
+				entry.Parameters = append(entry.Parameters, name)
+			}
+			sort.Strings(entry.Parameters)
+		}
+	}
+	return entry
+}
+
+func (g *SiteGenerator) siteLink(from, to types.Capability) string {
+	return fmt.Sprintf("[%s](%s)", g.makeReadableTitle(to.Name), relativeLink(sitePath(from), sitePath(to)))
+}
+
+// traitAppliesTo return whether the trait can be applied to the component, by the name of the component
+// definition or the workload it's based on
+func traitAppliesTo(trait, component types.Capability) bool {
+	if len(trait.AppliesTo) == 0 {
+		return true
+	}
+	for _, target := range trait.AppliesTo {
+		if target == AllComponentTypes || target == component.Name || (component.CrdName != "" && target == component.CrdName) {
+			return true
+		}
+	}
+	return false
+}
+
+// exampleApplications extract the applications from the yaml blocks in the markdown of the example
+func exampleApplications(example string) ([]*v1beta1.Application, error) {
+	var apps []*v1beta1.Application
+	for _, match := range yamlBlockRegexp.FindAllStringSubmatch(example, -1) {
+		for _, doc := range regexp.MustCompile(`(?m)^---\s*$`).Split(match[1], -1) {
+			if strings.TrimSpace(doc) == "" {
+				continue
+			}
+			meta := struct {
+				Kind string `json:"kind"`
+			}{}
+			if err := yaml.Unmarshal([]byte(doc), &meta); err != nil {
+				return nil, errors.Wrap(err, "invalid yaml in the example")
+			}
+			if meta.Kind != v1beta1.ApplicationKind {
+				continue
+			}
+			app := &v1beta1.Application{}
+			if err := yaml.Unmarshal([]byte(doc), app); err != nil {
+				return nil, errors.Wrap(err, "invalid application in the example")
+			}
+			apps = append(apps, app)
+		}
+	}
+	return apps, nil
+}
+
+func sitePath(c types.Capability) string {
+	for _, section := range siteSections {
+		if section.Type == c.Type {
+			return path.Join(section.Dir, c.Name+".md")
+		}
+	}
+	return ""
+}
+
+func revisionSitePath(c types.Capability, revision int64) string {
+	return path.Join(strings.TrimSuffix(sitePath(c), ".md"), fmt.Sprintf("v%d.md", revision))
+}
+
+// relativeLink return the link from the page to the target page in the site
+func relativeLink(from, to string) string {
+	link, err := filepath.Rel(path.Dir(from), to)
+	if err != nil {
+		return to
+	}
+	return filepath.ToSlash(link)
+}
+
+func writeSiteFile(dir, name, content string) error {
+	file := filepath.Join(dir, filepath.FromSlash(name))
+	if err := os.MkdirAll(filepath.Dir(file), 0750); err != nil {
+		return err
+	}
+	return os.WriteFile(file, []byte(content), 0600)
+}
@@ -0,0 +1,167 @@
+/*
+ Copyright 2022 The KubeVela Authors.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+ 	http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package docgen
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/oam-dev/kubevela/apis/core.oam.dev/common"
+	"github.com/oam-dev/kubevela/apis/core.oam.dev/v1beta1"
+	"github.com/oam-dev/kubevela/apis/types"
+	"github.com/oam-dev/kubevela/pkg/oam"
+	common2 "github.com/oam-dev/kubevela/pkg/utils/common"
+)
+
+func TestSiteGenerate(t *testing.T) {
+	workerV1 := types.Capability{Name: "worker", Type: types.TypeComponentDefinition, Category: types.CUECategory, Description: "Worker component", CueTemplate: `
+parameter: {
+	// +usage=Which image would you like to use for your service
+	image: string
+}
+`}
+	worker := workerV1
+	worker.CueTemplate = `
+parameter: {
+	// +usage=Which image would you like to use for your service
+	image: string
+	// +usage=Commands to run in the container
+	cmd?: [...string]
+}
+`
+	worker.Example = "```yaml\napiVersion: core.oam.dev/v1beta1\nkind: Application\nmetadata:\n  name: worker-app\nspec:\n  components:\n    - name: worker\n      type: worker\n      properties:\n        image: busybox\n```"
+	scaler := types.Capability{Name: "scaler", Type: types.TypeTrait, Category: types.CUECategory, Description: "Scale the component", AppliesTo: []string{"worker"}, CueTemplate: `
+parameter: {
+	// +usage=Specify the number of workload
+	replicas: *1 | int
+}
+`}
+	scaler.Example = "```yaml\napiVersion: core.oam.dev/v1beta1\nkind: Application\nmetadata:\n  name: scaler-app\nspec:\n  components: []\n```"
+	gateway := types.Capability{Name: "domain-route", Type: types.TypeTrait, Category: types.CUECategory, AppliesTo: []string{"webservice"}, CueTemplate: `
+parameter: {
+	domain: string
+}
+`}
+
+	gen := &SiteGenerator{}
+	gen.Revisions = func(ctx context.Context, c types.Capability) ([]CapabilityRevision, error) {
+		if c.Name != "worker" {
+			return nil, nil
+		}
+		return []CapabilityRevision{
+			{Revision: 1, Hash: "hash1", CreateTime: time.Now(), Capability: workerV1},
+			{Revision: 2, Hash: "hash2", CreateTime: time.Now(), Capability: worker},
+		}, nil
+	}
+	gen.ValidateExample = func(ctx context.Context, app *v1beta1.Application) error {
+		if len(app.Spec.Components) == 0 {
+			return fmt.Errorf("no components")
+		}
+		return nil
+	}
+	dir := t.TempDir()
+	report, err := gen.Generate(context.Background(), []types.Capability{worker, scaler, gateway}, nil, dir)
+	assert.NoError(t, err)
+	assert.Equal(t, 5, report.Pages)
+	assert.Equal(t, 2, report.Examples)
+	assert.Contains(t, report.ExampleErrors, "scaler")
+
+	readFile := func(name string) string {
+		data, err := os.ReadFile(filepath.Join(dir, filepath.FromSlash(name)))
+		assert.NoError(t, err)
+		return string(data)
+	}
+	workerDoc := readFile("components/worker.md")
+	assert.Contains(t, workerDoc, "## Applicable Traits\n\n- [Scaler](../traits/scaler.md)\n")
+	assert.NotContains(t, workerDoc, "domain-route")
+	assert.Contains(t, workerDoc, "The example has been validated by rendering.")
+	assert.Contains(t, workerDoc, "[v2](worker/v2.md) | hash2")
+	assert.Contains(t, workerDoc, "#### v2\n\n- added parameter `cmd` ([]string)\n")
+	assert.Contains(t, workerDoc, "#### v1\n\n- Initial revision.\n")
+	assert.Contains(t, readFile("components/worker/v1.md"), "title:  Worker (v1)")
+	assert.Contains(t, readFile("traits/scaler.md"), "## Applicable Components\n\n- [Worker](../components/worker.md)\n")
+	assert.Contains(t, readFile("traits/scaler.md"), "The example failed to be validated by rendering: render the application scaler-app: no components")
+
+	index := readFile(SiteIndexFile)
+	assert.Contains(t, index, "## Components")
+	assert.Contains(t, index, "[worker](components/worker.md) | Worker component")
+	assert.Contains(t, index, "[scaler](traits/scaler.md) | Scale the component")
+	var entries []SearchIndexEntry
+	assert.NoError(t, json.Unmarshal([]byte(readFile(SiteSearchIndexFile)), &entries))
+	assert.Equal(t, 3, len(entries))
+	assert.Equal(t, SearchIndexEntry{Name: "worker", Title: "Worker", Type: string(types.TypeComponentDefinition), Description: "Worker component", Path: "components/worker.md", Parameters: []string{"cmd", "image"}}, entries[2])
+}
+
+func TestExampleApplications(t *testing.T) {
+	apps, err := exampleApplications("```yaml\napiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: cm\n---\napiVersion: core.oam.dev/v1beta1\nkind: Application\nmetadata:\n  name: app\n```\n\n```shell\nvela up\n```")
+	assert.NoError(t, err)
+	assert.Equal(t, 1, len(apps))
+	assert.Equal(t, "app", apps[0].Name)
+	_, err = exampleApplications("```yaml\n: invalid\n```")
+	assert.Error(t, err)
+}
+
+func TestListCapabilityRevisions(t *testing.T) {
+	template := &common.Schematic{CUE: &common.CUE{Template: "parameter: {\n\timage: string\n}\n"}}
+	newRevision := func(name string, revision int64, defType common.DefinitionType, label string) *v1beta1.DefinitionRevision {
+		rev := &v1beta1.DefinitionRevision{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: types.DefaultKubeVelaNS, Labels: map[string]string{label: "web"}},
+			Spec:       v1beta1.DefinitionRevisionSpec{Revision: revision, DefinitionType: defType},
+		}
+		switch defType {
+		case common.ComponentType:
+			rev.Spec.ComponentDefinition = v1beta1.ComponentDefinition{
+				ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: types.DefaultKubeVelaNS},
+				Spec: v1beta1.ComponentDefinitionSpec{
+					Workload:  common.WorkloadTypeDescriptor{Type: types.AutoDetectWorkloadDefinition},
+					Schematic: template,
+				},
+			}
+		case common.TraitType:
+			rev.Spec.TraitDefinition = v1beta1.TraitDefinition{
+				ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: types.DefaultKubeVelaNS},
+				Spec:       v1beta1.TraitDefinitionSpec{Schematic: template},
+			}
+		}
+		return rev
+	}
+	c := common2.Args{}
+	c.SetClient(fake.NewClientBuilder().WithScheme(common2.Scheme).WithObjects(
+		newRevision("web-v1", 1, common.ComponentType, oam.LabelComponentDefinitionName),
+		newRevision("web-v2", 2, common.ComponentType, oam.LabelComponentDefinitionName),
+		newRevision("web-trait-v1", 1, common.TraitType, oam.LabelTraitDefinitionName),
+	).Build())
+
+	// the component and the trait with the same name don't mix their revisions
+	revs, err := ListCapabilityRevisions(context.Background(), c, nil, "default", "web", types.TypeComponentDefinition)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, len(revs))
+	assert.Equal(t, types.TypeComponentDefinition, revs[1].Capability.Type)
+	revs, err = ListCapabilityRevisions(context.Background(), c, nil, "default", "web", types.TypeTrait)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, len(revs))
+	assert.Equal(t, types.TypeTrait, revs[0].Capability.Type)
+}
@@ -0,0 +1,32 @@
+"deploy-window": {
+	annotations: {}
+	description: "Describe the time windows in which the deploy steps are allowed or denied, e.g. change freeze or maintenance windows."
+	labels: {}
+	attributes: {}
+	type: "policy"
+}
+
+template: {
+	#DeployWindow: {
+		// +usage=Specify whether the deploy is allowed or denied inside the window
+		kind: "allow" | "deny"
+		// +usage=Specify the start of the window in cron format, e.g. 0 22 * * 1-5
+		schedule: string
+		// +usage=Specify the length of the window, e.g. 8h
+		duration: string
+		// +usage=Specify the IANA time zone of the schedule, e.g. Asia/Shanghai. Default to UTC.
+		timeZone?: string
+	}
+
+	#DeployWindowRule: {
+		// +usage=Specify the names of the topology policies that the rule applies to. If empty, the rule applies to all deploy steps.
+		topologies?: [...string]
+		// +usage=Specify the time windows of the rule
+		windows: [...#DeployWindow]
+	}
+
+	parameter: {
+		// +usage=Specify the rules of deploy windows
+		rules: [...#DeployWindowRule]
+	}
+}
@@ -0,0 +1,40 @@
+"server-side-apply": {
+	annotations: {}
+	description: "Dispatch resources by server-side apply, which lets the fields managed by other controllers be kept and conflicts be detected."
+	labels: {}
+	attributes: {}
+	type: "policy"
+}
+
+template: {
+	#ServerSideApplyPolicyRule: {
+		// +usage=Specify how to select the targets of the rule
+		selector: #ResourcePolicyRuleSelector
+		// +usage=Specify how to handle the conflicts for the selected resources, force or abort
+		conflict?: "force" | "abort"
+	}
+
+	#ResourcePolicyRuleSelector: {
+		// +usage=Select resources by component names
+		componentNames?: [...string]
+		// +usage=Select resources by component types
+		componentTypes?: [...string]
+		// +usage=Select resources by oamTypes (COMPONENT or TRAIT)
+		oamTypes?: [...string]
+		// +usage=Select resources by trait types
+		traitTypes?: [...string]
+		// +usage=Select resources by resource types (like Deployment)
+		resourceTypes?: [...string]
+		// +usage=Select resources by their names
+		resourceNames?: [...string]
+	}
+
+	parameter: {
+		// +usage=Specify the field manager of the applied fields, default to be kubevela/<app-namespace>/<app-name>
+		fieldManager?: string
+		// +usage=Specify how to handle the conflicts with other field managers, force to override the conflicting fields or abort the apply
+		conflict: *"force" | "abort"
+		// +usage=Specify the rules for selecting the resources to use server-side apply, all resources are selected if not set
+		rules?: [...#ServerSideApplyPolicyRule]
+	}
+}